@@ -0,0 +1,75 @@
+// Command reencrypt moves every credential stored at rest onto the current
+// encryption key, so a retired key can be dropped from ENCRYPTION_OLD_KEYS.
+//
+// It reads the same environment as the server: values are decrypted with
+// whichever of ENCRYPTION_KEY, ENCRYPTION_OLD_KEYS and ENCRYPTION_LEGACY_KEY
+// wrote them and re-encrypted with ENCRYPTION_KEY under
+// ENCRYPTION_KEY_VERSION. The -old-keys and -legacy-key flags give the
+// source keys in place of their variables, e.g. while responding to a leak.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"sort"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/crypto"
+	"github.com/multi-worker/internal/storage"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would be re-encrypted and roll back")
+	oldKeys := flag.String("old-keys", "", "retired keys as comma-separated \"version:key\" pairs, instead of ENCRYPTION_OLD_KEYS")
+	legacyKey := flag.String("legacy-key", "", "key of any length values were stored under before key versioning, instead of ENCRYPTION_LEGACY_KEY")
+	flag.Parse()
+
+	cfg := config.Load()
+
+	cipher, err := crypto.New(sourceKeys(cfg.Encryption, *oldKeys, *legacyKey))
+	if err != nil {
+		log.Fatalf("Invalid encryption configuration: %v", err)
+	}
+
+	db, err := storage.NewDatabase(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	counts, err := storage.ReEncrypt(context.Background(), db, cipher, *dryRun)
+	if err != nil {
+		log.Fatalf("Re-encryption failed, nothing was changed: %v", err)
+	}
+
+	columns := make([]string, 0, len(counts))
+	for column := range counts {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	total := 0
+	for _, column := range columns {
+		log.Printf("%s: %d", column, counts[column])
+		total += counts[column]
+	}
+	if *dryRun {
+		log.Printf("Dry run: %d values would move to key version %d", total, cipher.Version())
+		return
+	}
+	log.Printf("Re-encrypted %d values with key version %d", total, cipher.Version())
+}
+
+// sourceKeys replaces the configured keys to decrypt with those given on the
+// command line. A legacy key is padded the way releases before key
+// versioning padded it, so a short key they accepted still works here.
+func sourceKeys(cfg config.EncryptionConfig, oldKeys, legacyKey string) config.EncryptionConfig {
+	if oldKeys != "" {
+		cfg.OldKeys = oldKeys
+	}
+	if legacyKey != "" {
+		cfg.LegacyKey = legacyKey
+	}
+	return cfg
+}
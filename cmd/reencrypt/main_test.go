@@ -0,0 +1,51 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/crypto"
+)
+
+func TestSourceKeysAcceptShortLegacyKey(t *testing.T) {
+	// A key releases before key versioning padded instead of refusing
+	const shortKey = "change-me"
+	block, err := aes.NewCipher(crypto.LegacyKey(shortKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	stored := base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte("bot-token"), nil))
+
+	env := config.EncryptionConfig{Key: "0123456789abcdef0123456789abcdef", KeyVersion: 2}
+	c, err := crypto.New(sourceKeys(env, "", shortKey))
+	if err != nil {
+		t.Fatalf("short legacy key refused: %v", err)
+	}
+	if got, err := c.Decrypt(stored); err != nil || got != "bot-token" {
+		t.Fatalf("Decrypt = %q, %v", got, err)
+	}
+	if encrypted, _ := c.Encrypt("bot-token"); crypto.KeyVersion(encrypted) != 2 {
+		t.Errorf("re-encrypted with version %d, want 2", crypto.KeyVersion(encrypted))
+	}
+}
+
+func TestSourceKeysKeepEnvironmentByDefault(t *testing.T) {
+	env := config.EncryptionConfig{OldKeys: "1:old", LegacyKey: "legacy"}
+	if got := sourceKeys(env, "", ""); got != env {
+		t.Errorf("sourceKeys without flags = %+v, want %+v", got, env)
+	}
+	got := sourceKeys(env, "3:other", "short")
+	if got.OldKeys != "3:other" || got.LegacyKey != "short" {
+		t.Errorf("sourceKeys with flags = %+v", got)
+	}
+}
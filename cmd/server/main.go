@@ -7,17 +7,29 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/multi-worker/internal/api"
 	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/crypto"
 	"github.com/multi-worker/internal/executor/ai"
+	"github.com/multi-worker/internal/executor/assert"
 	"github.com/multi-worker/internal/executor/discord"
+	"github.com/multi-worker/internal/executor/email"
 	"github.com/multi-worker/internal/executor/filter"
+	"github.com/multi-worker/internal/executor/limit"
+	"github.com/multi-worker/internal/executor/mastodon"
 	"github.com/multi-worker/internal/executor/rss"
 	"github.com/multi-worker/internal/executor/scraper"
+	"github.com/multi-worker/internal/executor/slack"
+	"github.com/multi-worker/internal/executor/static"
+	"github.com/multi-worker/internal/executor/telegram"
+	"github.com/multi-worker/internal/executor/transform"
+	"github.com/multi-worker/internal/executor/webhook"
 	"github.com/multi-worker/internal/middleware"
+	"github.com/multi-worker/internal/netguard"
 	"github.com/multi-worker/internal/scheduler"
 	"github.com/multi-worker/internal/storage"
 
@@ -65,12 +77,27 @@ func main() {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
+	// Initialize encryption for stored credentials
+	cipher, err := crypto.New(cfg.Encryption)
+	if err != nil {
+		log.Fatalf("Invalid encryption configuration: %v", err)
+	}
+	if cfg.Encryption.FromJWTSecret {
+		log.Println("Warning: ENCRYPTION_KEY is unset, so stored credentials are encrypted with JWT_SECRET; set a separate key and rotate to it")
+	}
+
 	// Initialize repositories
 	userRepo := storage.NewUserRepository(db)
 	taskRepo := storage.NewTaskRepository(db)
 	execRepo := storage.NewExecutionRepository(db)
 	cacheRepo := storage.NewCacheRepository(db)
-	discordRepo := storage.NewDiscordRepository(db)
+	discordRepo := storage.NewDiscordRepository(db, cipher)
+	secretRepo := storage.NewSecretRepository(db, cipher)
+	statsRepo := storage.NewAnalyticsRepository(db)
+	shareRepo := storage.NewShareRepository(db)
+	refreshRepo := storage.NewRefreshTokenRepository(db)
+	apiKeyRepo := storage.NewAPIKeyRepository(db)
+	resetRepo := storage.NewPasswordResetRepository(db)
 
 	// Create default admin user if not exists
 	ctx := context.Background()
@@ -90,13 +117,25 @@ func main() {
 	aiRegistry := ai.NewProviderRegistry(&cfg.AI)
 	log.Printf("Available AI providers: %v", aiRegistry.Available())
 
-	// Initialize executors
-	aiExecutor := ai.NewExecutor(aiRegistry)
+	// Initialize executors; those posting to user-supplied URLs share the
+	// outbound guard
+	guard := netguard.New(cfg.Outbound)
+	aiExecutor := ai.NewExecutor(aiRegistry, cacheRepo)
+	aiFilterExecutor := ai.NewFilterExecutor(aiRegistry)
 	scraperRegistry := scraper.NewRegistry(cfg.Scraper)
 	scraperExecutor := scraper.NewExecutor(scraperRegistry, cacheRepo)
 	rssExecutor := rss.NewExecutor(cacheRepo)
-	discordExecutor := discord.NewExecutor(cfg.Discord)
+	discordExecutor := discord.NewExecutor(cfg.Discord, guard)
+	slackExecutor := slack.NewExecutor(cfg.Slack, guard)
+	telegramExecutor := telegram.NewExecutor(cfg.Telegram)
+	mastodonExecutor := mastodon.NewExecutor(cfg.Mastodon, guard)
+	emailExecutor := email.NewExecutor(cfg.Email)
+	webhookExecutor := webhook.NewExecutor(guard)
 	filterExecutor := filter.NewExecutor(cacheRepo)
+	assertExecutor := assert.NewExecutor()
+	transformExecutor := transform.NewExecutor()
+	staticExecutor := static.NewExecutor()
+	limitExecutor := limit.NewExecutor()
 
 	// Initialize pipeline runner
 	runner := scheduler.NewPipelineRunner(
@@ -104,15 +143,29 @@ func main() {
 		execRepo,
 		cacheRepo,
 		discordRepo,
+		secretRepo,
+		statsRepo,
 		aiExecutor,
+		aiFilterExecutor,
 		scraperExecutor,
 		rssExecutor,
 		discordExecutor,
+		slackExecutor,
+		telegramExecutor,
+		mastodonExecutor,
+		emailExecutor,
+		webhookExecutor,
 		filterExecutor,
+		assertExecutor,
+		transformExecutor,
+		staticExecutor,
+		limitExecutor,
+		scheduler.NewErrorNotifier(cfg.Notifications),
+		scheduler.NewExecutionCallback(cfg.Notifications),
 	)
 
 	// Initialize scheduler
-	sched := scheduler.NewScheduler(taskRepo, execRepo, runner)
+	sched := scheduler.NewScheduler(taskRepo, execRepo, runner, cfg.Scheduler)
 
 	// Start scheduler
 	log.Println("Starting scheduler...")
@@ -120,15 +173,25 @@ func main() {
 		log.Fatalf("Failed to start scheduler: %v", err)
 	}
 
+	// Start background pruning of old rows
+	maintenance := scheduler.NewMaintenance(execRepo, cacheRepo, cfg.Maintenance)
+	maintenance.Start(ctx)
+
 	// Initialize auth middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.JWT, userRepo)
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWT, userRepo, refreshRepo, apiKeyRepo)
 
 	// Initialize API handlers
-	handler := api.NewHandler(userRepo, taskRepo, execRepo, sched, runner, authMiddleware)
-	discordHandler := api.NewDiscordHandler(discordRepo)
+	handler := api.NewHandler(db, userRepo, taskRepo, execRepo, secretRepo, statsRepo, shareRepo, apiKeyRepo, sched, runner, scraperRegistry, authMiddleware, resetRepo, emailExecutor, cfg.PasswordReset)
+	webhookChecker := scheduler.NewWebhookChecker(discordRepo, taskRepo, guard)
+	discordHandler := api.NewDiscordHandler(discordRepo, taskRepo, webhookChecker, guard)
+
+	// Encryption keys are rotated through config and a restart, and a webhook
+	// stored under a key that was dropped no longer decrypts, so re-verify
+	// every task's webhook at startup
+	webhookChecker.CheckAll()
 
 	// Setup router
-	router := api.NewRouter(handler, discordHandler, authMiddleware)
+	router := api.NewRouter(handler, discordHandler, authMiddleware, cfg.RateLimit)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -137,6 +200,9 @@ func main() {
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
+	// Execution streams stay open until their run ends, so close them when
+	// shutdown starts instead of waiting them out
+	server.RegisterOnShutdown(handler.CloseStreams)
 
 	// Start server in goroutine
 	go func() {
@@ -153,16 +219,73 @@ func main() {
 
 	log.Println("Shutting down...")
 
-	// Stop scheduler
-	sched.Stop()
+	// Stop scheduling while the HTTP server stops accepting requests, so
+	// running jobs get their full grace period from the signal instead of
+	// whatever the request drain leaves over. Manual runs still in flight
+	// are cancelled with the scheduled ones, so their requests end by then.
+	// Runs cancelled there report their failure in the background too, so
+	// pending notifications are flushed last, in time held back for them.
+	shutdown(cfg.Server.ShutdownTimeout,
+		[]shutdownTask{
+			{"scheduler", sched.Stop},
+			{"HTTP server", server.Shutdown},
+			{"maintenance", maintenance.Stop},
+		},
+		shutdownTask{"pending notifications", runner.Flush},
+	)
+
+	log.Println("Server stopped")
+}
+
+// shutdownTask is a named step of the graceful shutdown
+type shutdownTask struct {
+	name string
+	stop func(context.Context) error
+}
+
+// flushReserve is the part of a shutdown budget held back for flushing,
+// a quarter of it up to maxFlushReserve
+func flushReserve(timeout time.Duration) time.Duration {
+	return min(timeout/4, maxFlushReserve)
+}
 
-	// Graceful shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+const maxFlushReserve = 5 * time.Second
 
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Printf("Server shutdown error: %v", err)
+// shutdown runs the stop tasks together, then flush, all within timeout.
+// The stop tasks must end flushReserve before the deadline, so flush is
+// always attempted with at least that long left however they fare.
+func shutdown(timeout time.Duration, stops []shutdownTask, flush shutdownTask) {
+	deadline := time.Now().Add(timeout)
+	stopCtx, cancelStops := context.WithDeadline(context.Background(), deadline.Add(-flushReserve(timeout)))
+	defer cancelStops()
+
+	var wg sync.WaitGroup
+	for _, task := range stops {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shutdownStep(stopCtx, task.name, task.stop)
+		}()
 	}
+	wg.Wait()
 
-	log.Println("Server stopped")
+	flushCtx, cancelFlush := context.WithDeadline(context.Background(), deadline)
+	defer cancelFlush()
+	shutdownStep(flushCtx, flush.name, flush.stop)
+}
+
+// shutdownStep runs a single best-effort shutdown step, logging instead of
+// failing so the remaining steps still get their turn
+func shutdownStep(ctx context.Context, name string, stop func(context.Context) error) {
+	done := make(chan error, 1)
+	go func() { done <- stop(ctx) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			log.Printf("Shutdown of %s failed: %v", name, err)
+		}
+	case <-ctx.Done():
+		log.Printf("Shutdown of %s timed out", name)
+	}
 }
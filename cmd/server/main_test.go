@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShutdownStepIsTimeBoxed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	hung := make(chan struct{})
+	defer close(hung)
+
+	started := time.Now()
+	shutdownStep(ctx, "stuck step", func(context.Context) error {
+		<-hung // Ignores its context, as a stuck step would
+		return nil
+	})
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("shutdownStep waited %v on a step past its 50ms deadline", elapsed)
+	}
+
+}
+
+func TestShutdownAttemptsFlushAfterStuckSteps(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+	hung := make(chan struct{})
+	defer close(hung)
+
+	var flushLeft time.Duration
+	started := time.Now()
+	shutdown(timeout,
+		[]shutdownTask{
+			{"stuck step", func(context.Context) error { <-hung; return nil }},
+			{"quick step", func(context.Context) error { return nil }},
+		},
+		shutdownTask{"flush", func(ctx context.Context) error {
+			deadline, _ := ctx.Deadline()
+			flushLeft = time.Until(deadline)
+			return nil
+		}},
+	)
+
+	// The stuck step used its share; the flush still had its reserve
+	if want := flushReserve(timeout); flushLeft < want-20*time.Millisecond {
+		t.Errorf("flush started with %v left, want about %v", flushLeft, want)
+	}
+	if elapsed := time.Since(started); elapsed > timeout+100*time.Millisecond {
+		t.Errorf("shutdown took %v, past its %v budget", elapsed, timeout)
+	}
+}
@@ -22,8 +22,8 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/api-key/regenerate": {
-            "post": {
+        "/analytics/items": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -32,17 +32,40 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Generate a new API key for the current user",
+                "description": "Daily counts of delivered items grouped by category, source or task. Admins see all tasks; other users see their own.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Analytics"
+                ],
+                "summary": "Delivered item analytics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "category",
+                        "description": "category, source or task",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "30d",
+                        "description": "Look-back window in days, e.g. 30d",
+                        "name": "window",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Regenerate API key",
                 "responses": {
                     "200": {
-                        "description": "New API key",
+                        "description": "Item counts per day and group",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -71,39 +94,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
+        "/auth/api-key/regenerate": {
             "post": {
-                "description": "Authenticate user and return JWT token",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Generate a new API key for the current user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Authentication"
                 ],
-                "summary": "User login",
-                "parameters": [
-                    {
-                        "description": "Login credentials",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.LoginRequest"
-                        }
-                    }
-                ],
+                "summary": "Regenerate API key",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.LoginResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
+                        "description": "New API key",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -112,7 +123,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Invalid credentials",
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -132,7 +143,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/profile": {
+        "/auth/api-keys": {
             "get": {
                 "security": [
                     {
@@ -142,19 +153,20 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get the current user's profile information",
+                "description": "List the current user's scoped API keys. Keys themselves are never returned, only their prefix.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Authentication"
                 ],
-                "summary": "Get user profile",
+                "summary": "List scoped API keys",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "API keys",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -166,8 +178,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "403": {
+                        "description": "Scoped keys can't manage keys",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -176,11 +197,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/register": {
+            },
             "post": {
-                "description": "Create a new user account with email and password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a named API key limited to some scopes (tasks:read, tasks:write, tasks:trigger, discord:read, discord:write) and optionally to one task. The key is only returned once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -190,15 +217,15 @@ const docTemplate = `{
                 "tags": [
                     "Authentication"
                 ],
-                "summary": "Register a new user",
+                "summary": "Create a scoped API key",
                 "parameters": [
                     {
-                        "description": "Registration details",
+                        "description": "Key name, scopes and optional task",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.RegisterRequest"
+                            "$ref": "#/definitions/model.CreateAPIKeyRequest"
                         }
                     }
                 ],
@@ -206,7 +233,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.LoginResponse"
+                            "$ref": "#/definitions/model.CreateAPIKeyResponse"
                         }
                     },
                     "400": {
@@ -218,8 +245,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Email already registered",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Scoped keys can't manage keys",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -239,8 +275,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/discord/bots": {
-            "get": {
+        "/auth/api-keys/{keyId}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -249,28 +285,31 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get all registered Discord bots",
+                "description": "Delete one of the current user's scoped API keys so it stops working",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Bots"
+                    "Authentication"
                 ],
-                "summary": "List Discord bots",
+                "summary": "Revoke a scoped API key",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "Include inactive bots",
-                        "name": "include_inactive",
-                        "in": "query"
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "keyId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of bots",
+                        "description": "Revoked",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -282,6 +321,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Scoped keys can't manage keys",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -292,7 +349,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/change-password": {
             "post": {
                 "security": [
                     {
@@ -302,7 +361,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Register a new Discord bot with credentials",
+                "description": "Replace the current user's password, given the current one. With revoke_sessions, all refresh tokens are revoked so other sessions must log in again, and a fresh token pair is returned.",
                 "consumes": [
                     "application/json"
                 ],
@@ -310,25 +369,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Bots"
+                    "Authentication"
                 ],
-                "summary": "Create a Discord bot",
+                "summary": "Change password",
                 "parameters": [
                     {
-                        "description": "Bot credentials",
+                        "description": "Current and new password",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CreateDiscordBotRequest"
+                            "$ref": "#/definitions/model.ChangePasswordRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.DiscordBot"
+                            "$ref": "#/definitions/model.ChangePasswordResponse"
                         }
                     },
                     "400": {
@@ -341,7 +400,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Unauthorized or wrong current password",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -361,51 +420,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/discord/bots/{botId}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
-                    }
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Email a single-use, time-limited password reset token to the address if it belongs to an active user. The response is the same either way.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get bot details with associated channels",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Bots"
+                    "Authentication"
                 ],
-                "summary": "Get a Discord bot",
+                "summary": "Request a password reset",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "botId",
-                        "in": "path",
-                        "required": true
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ForgotPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.DiscordBotWithChannels"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    "202": {
+                        "description": "Reset requested",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -413,8 +454,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Bot not found",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -422,8 +463,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "503": {
+                        "description": "Email delivery not configured",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -432,17 +473,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Update bot settings or credentials",
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user and return JWT token",
                 "consumes": [
                     "application/json"
                 ],
@@ -450,24 +485,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Bots"
+                    "Authentication"
                 ],
-                "summary": "Update a Discord bot",
+                "summary": "User login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "botId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update data",
+                        "description": "Login credentials",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateDiscordBotRequest"
+                            "$ref": "#/definitions/model.LoginRequest"
                         }
                     }
                 ],
@@ -475,7 +503,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.DiscordBot"
+                            "$ref": "#/definitions/model.LoginResponse"
                         }
                     },
                     "400": {
@@ -488,7 +516,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid credentials",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -506,36 +534,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
-                    }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Revoke a refresh token so it can't be exchanged again. JWTs already issued remain valid until they expire.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Delete a bot and all associated channels",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Bots"
+                    "Authentication"
                 ],
-                "summary": "Delete a Discord bot",
+                "summary": "Log out",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "botId",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RefreshRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Deletion status",
+                        "description": "Logged out",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -552,17 +579,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Bot not found",
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -573,7 +591,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/discord/channels": {
+        "/auth/profile": {
             "get": {
                 "security": [
                     {
@@ -583,28 +601,19 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get all channels, optionally filtered by bot",
+                "description": "Get the current user's profile information",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Channels"
-                ],
-                "summary": "List Discord channels",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter by bot ID",
-                        "name": "bot_id",
-                        "in": "query"
-                    }
+                    "Authentication"
                 ],
+                "summary": "Get user profile",
                 "responses": {
                     "200": {
-                        "description": "List of channels",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.User"
                         }
                     },
                     "401": {
@@ -616,8 +625,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -626,17 +635,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/refresh": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Register a channel with webhook for a bot",
+                "description": "Exchange a refresh token for a new JWT. The refresh token is single-use; the response carries its replacement.",
                 "consumes": [
                     "application/json"
                 ],
@@ -644,25 +647,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Channels"
+                    "Authentication"
                 ],
-                "summary": "Create a Discord channel config",
+                "summary": "Refresh the access token",
                 "parameters": [
                     {
-                        "description": "Channel configuration",
+                        "description": "Refresh token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CreateDiscordChannelRequest"
+                            "$ref": "#/definitions/model.RefreshRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.DiscordChannel"
+                            "$ref": "#/definitions/model.LoginResponse"
                         }
                     },
                     "400": {
@@ -675,7 +678,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid or expired refresh token",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -695,38 +698,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/discord/channels/{channelId}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
-                    }
+        "/auth/register": {
+            "post": {
+                "description": "Create a new user account with email and password",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get channel details",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Channels"
+                    "Authentication"
                 ],
-                "summary": "Get a Discord channel",
+                "summary": "Register a new user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Channel config ID",
-                        "name": "channelId",
-                        "in": "path",
-                        "required": true
+                        "description": "Registration details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.DiscordChannel"
+                            "$ref": "#/definitions/model.LoginResponse"
                         }
                     },
                     "400": {
@@ -738,17 +738,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Channel not found",
+                    "409": {
+                        "description": "Email already registered",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -766,17 +757,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Update channel settings or webhook",
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Set a new password with a token from /auth/forgot-password. The token is used up, and the user's refresh tokens are revoked so other sessions must log in again.",
                 "consumes": [
                     "application/json"
                 ],
@@ -784,36 +769,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Channels"
+                    "Authentication"
                 ],
-                "summary": "Update a Discord channel",
+                "summary": "Reset a forgotten password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Channel config ID",
-                        "name": "channelId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update data",
+                        "description": "Reset token and new password",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateDiscordChannelRequest"
+                            "$ref": "#/definitions/model.ResetPasswordRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.DiscordChannel"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
+                        "description": "Password updated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -821,8 +793,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid request or invalid/expired token",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -840,8 +812,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/discord/bots": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -850,40 +824,48 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove a channel configuration",
+                "description": "Get a paginated list of registered Discord bots",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Discord Channels"
+                    "Discord Bots"
                 ],
-                "summary": "Delete a Discord channel",
+                "summary": "List Discord bots",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include inactive bots",
+                        "name": "include_inactive",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Channel config ID",
-                        "name": "channelId",
-                        "in": "path",
-                        "required": true
+                        "description": "Only bots whose name contains this text (case-insensitive)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of bots to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Deletion status",
+                        "description": "Bots list with pagination",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -895,8 +877,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Channel not found",
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -905,9 +887,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/discord/test": {
+            },
             "post": {
                 "security": [
                     {
@@ -917,7 +897,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Send a test message to verify webhook configuration",
+                "description": "Register a new Discord bot with credentials",
                 "consumes": [
                     "application/json"
                 ],
@@ -925,37 +905,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Discord"
+                    "Discord Bots"
                 ],
-                "summary": "Test Discord webhook",
+                "summary": "Create a Discord bot",
                 "parameters": [
                     {
-                        "description": "Test webhook request",
+                        "description": "Bot credentials",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "channel_id": {
-                                    "type": "string"
-                                },
-                                "message": {
-                                    "type": "string"
-                                },
-                                "webhook_url": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/model.CreateDiscordBotRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Test result",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.DiscordBot"
                         }
                     },
                     "400": {
@@ -977,7 +945,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Webhook error",
+                        "description": "Server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -988,7 +956,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/executions/recent": {
+        "/discord/bots/{botId}": {
             "get": {
                 "security": [
                     {
@@ -998,29 +966,37 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get the most recent executions across all tasks",
+                "description": "Get bot details with associated channels",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Executions"
+                    "Discord Bots"
                 ],
-                "summary": "Get recent executions",
+                "summary": "Get a Discord bot",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Number of executions to return",
-                        "name": "limit",
-                        "in": "query"
-                    }
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "botId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Recent executions",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DiscordBotWithChannels"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -1032,6 +1008,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Bot not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1042,31 +1027,82 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Check if the API is running",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update bot settings or credentials",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Discord Bots"
+                ],
+                "summary": "Update a Discord bot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "botId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateDiscordBotRequest"
+                        }
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
-                        "description": "Health status",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DiscordBot"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/status": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1075,20 +1111,40 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get detailed system status including task and execution counts",
+                "description": "Delete a bot and all associated channels",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Discord Bots"
+                ],
+                "summary": "Delete a Discord bot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "botId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "System status",
                 "responses": {
                     "200": {
-                        "description": "System status",
+                        "description": "Deletion status",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -1099,11 +1155,20 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "404": {
+                        "description": "Bot not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/tasks": {
+        "/discord/channels": {
             "get": {
                 "security": [
                     {
@@ -1113,19 +1178,31 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get a paginated list of tasks with optional status filter",
+                "description": "Get a paginated list of active channels, optionally filtered by bot",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Discord Channels"
                 ],
-                "summary": "List all tasks",
+                "summary": "List Discord channels",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by bot ID",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only channels whose name contains this text (case-insensitive)",
+                        "name": "q",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 50,
-                        "description": "Number of tasks to return",
+                        "description": "Number of channels to return",
                         "name": "limit",
                         "in": "query"
                     },
@@ -1135,17 +1212,11 @@ const docTemplate = `{
                         "description": "Offset for pagination",
                         "name": "offset",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by status (enabled, disabled, running)",
-                        "name": "status",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Tasks list with pagination",
+                        "description": "Channels list with pagination",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1180,7 +1251,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new scheduled task with pipeline configuration",
+                "description": "Register a channel with webhook for a bot",
                 "consumes": [
                     "application/json"
                 ],
@@ -1188,17 +1259,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Discord Channels"
                 ],
-                "summary": "Create a new task",
+                "summary": "Create a Discord channel config",
                 "parameters": [
                     {
-                        "description": "Task configuration",
+                        "description": "Channel configuration",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CreateTaskRequest"
+                            "$ref": "#/definitions/model.CreateDiscordChannelRequest"
                         }
                     }
                 ],
@@ -1206,11 +1277,11 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.Task"
+                            "$ref": "#/definitions/model.DiscordChannel"
                         }
                     },
                     "400": {
-                        "description": "Invalid request or pipeline",
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1239,7 +1310,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}": {
+        "/discord/channels/{channelId}": {
             "get": {
                 "security": [
                     {
@@ -1249,19 +1320,19 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get details of a specific task by ID",
+                "description": "Get channel details",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Discord Channels"
                 ],
-                "summary": "Get a task",
+                "summary": "Get a Discord channel",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
-                        "name": "id",
+                        "description": "Channel config ID",
+                        "name": "channelId",
                         "in": "path",
                         "required": true
                     }
@@ -1270,7 +1341,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Task"
+                            "$ref": "#/definitions/model.DiscordChannel"
                         }
                     },
                     "400": {
@@ -1292,7 +1363,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Channel not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1320,7 +1391,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update an existing task's configuration",
+                "description": "Update channel settings or webhook",
                 "consumes": [
                     "application/json"
                 ],
@@ -1328,24 +1399,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Discord Channels"
                 ],
-                "summary": "Update a task",
+                "summary": "Update a Discord channel",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
-                        "name": "id",
+                        "description": "Channel config ID",
+                        "name": "channelId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated task configuration",
+                        "description": "Update data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateTaskRequest"
+                            "$ref": "#/definitions/model.UpdateDiscordChannelRequest"
                         }
                     }
                 ],
@@ -1353,7 +1424,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Task"
+                            "$ref": "#/definitions/model.DiscordChannel"
                         }
                     },
                     "400": {
@@ -1374,15 +1445,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Task not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1403,19 +1465,19 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete a task and remove it from the scheduler",
+                "description": "Remove a channel configuration",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Discord Channels"
                 ],
-                "summary": "Delete a task",
+                "summary": "Delete a Discord channel",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
-                        "name": "id",
+                        "description": "Channel config ID",
+                        "name": "channelId",
                         "in": "path",
                         "required": true
                     }
@@ -1449,7 +1511,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Channel not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1460,8 +1522,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}/executions": {
-            "get": {
+        "/discord/channels/{channelId}/create-webhook": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1470,47 +1532,2697 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get paginated list of executions for a specific task",
+                "description": "Create a webhook in the Discord channel with the channel's bot token and store it on the channel, replacing any webhook URL set before. The bot needs the Manage Webhooks permission in the channel.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Executions"
+                    "Discord Channels"
                 ],
-                "summary": "Get task executions",
+                "summary": "Create a webhook for a channel",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
-                        "name": "id",
+                        "description": "Channel config ID",
+                        "name": "channelId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Number of executions to return",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset for pagination",
-                        "name": "offset",
+                        "description": "Optional webhook name, e.g. {\\",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook ID and masked URL",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Discord refused to create the webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/discord/interactions/{botId}": {
+            "post": {
+                "description": "Interactions endpoint URL for a bot's Discord application. Requests must carry a valid X-Signature-Ed25519 signature by the bot's public key over X-Signature-Timestamp and the body. PINGs are answered with PONG; commands get an ephemeral reply until command handling is added.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Discord Bots"
+                ],
+                "summary": "Receive Discord interactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "botId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hex Ed25519 signature",
+                        "name": "X-Signature-Ed25519",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature timestamp",
+                        "name": "X-Signature-Timestamp",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Interaction response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid request signature",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Bot not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/discord/test": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Send a test message to verify webhook configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Discord"
+                ],
+                "summary": "Test Discord webhook",
+                "parameters": [
+                    {
+                        "description": "Test webhook request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "channel_id": {
+                                    "type": "string"
+                                },
+                                "message": {
+                                    "type": "string"
+                                },
+                                "webhook_url": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Test result",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Webhook error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/executions/recent": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the most recent executions across the caller's tasks, or all tasks for admins",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Executions"
+                ],
+                "summary": "Get recent executions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of executions to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "schedule",
+                            "manual",
+                            "replay",
+                            "webhook"
+                        ],
+                        "type": "string",
+                        "description": "Only executions with this trigger type",
+                        "name": "trigger_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "running",
+                            "completed",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Only executions with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only executions started at or after this RFC 3339 time or YYYY-MM-DD date (UTC)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only executions started before this RFC 3339 time, or on or before this YYYY-MM-DD date (UTC)",
+                        "name": "until",
                         "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Executions list",
+                    "200": {
+                        "description": "Recent executions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/executions/{execId}/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events of an execution's progress. Each \"step\" event is a scheduler.StepEvent: started, completed, failed or skipped. Steps that already finished are sent first from the stored results, and a final \"done\" event carries the execution's status and error before the stream closes. An execution that already finished gets its stored results and \"done\" at once. Runs on another server instance are followed by re-reading the record every 15 seconds.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Executions"
+                ],
+                "summary": "Stream an execution's step events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Execution ID",
+                        "name": "execId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "$ref": "#/definitions/scheduler.StepEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Execution not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check that the API can reach the database; returns 503 when it can't",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "Health status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Database unreachable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/openapi.json": {
+            "get": {
+                "description": "The generated Swagger 2.0 spec of this API, for client code generation",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "OpenAPI specification",
+                "responses": {
+                    "200": {
+                        "description": "Swagger 2.0 document",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Spec not available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/estimate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Predict, per step, the upstream requests, AI tokens, cost and runtime of a pipeline without running it. Costs use the AI_PRICING config and are omitted for unpriced providers.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Estimate a pipeline's cost",
+                "parameters": [
+                    {
+                        "description": "Pipeline steps",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PipelineStep"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scheduler.PipelineEstimate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/scrapers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the sources and categories a scraper step's source, sources and category options accept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "List scraper sources",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScraperList"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/shared/{token}": {
+            "get": {
+                "description": "Public, read-only view of a task's recently delivered items. No pipeline, config or secrets are included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shared"
+                ],
+                "summary": "View a shared task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SharedTask"
+                        }
+                    },
+                    "404": {
+                        "description": "Share link not found or expired",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get detailed system status including task and execution counts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "System status",
+                "responses": {
+                    "200": {
+                        "description": "System status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of the caller's tasks, or every user's tasks for admins, with optional status filter",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "List all tasks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of tasks to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (enabled, disabled, running, archived); archived tasks are only listed when asked for",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tasks list with pagination",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new scheduled task with pipeline configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Create a new task",
+                "parameters": [
+                    {
+                        "description": "Task configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or pipeline",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Enable, disable, delete or trigger up to 100 tasks at once. Delete archives the tasks unless hard is set. Archived tasks can't be enabled or disabled. Status changes and deletes run as a single statement; triggers run concurrently. Each task gets its own result so partial failures are reported; other users' tasks are reported as not found unless the caller is an admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Apply an action to several tasks",
+                "parameters": [
+                    {
+                        "description": "Action and task IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "API key lacks the tasks:trigger scope",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/dry-run": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Execute a pipeline without saving a task or execution. Delivery steps (discord, slack, telegram, mastodon, email, webhook) aren't sent; they return what would be sent. Items are not deduplicated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Dry-run a pipeline",
+                "parameters": [
+                    {
+                        "description": "Pipeline steps",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PipelineStep"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scheduler.DryRunResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many executions in progress",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get details of a specific task by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Get a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update an existing task's configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Update a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated task configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Task is archived",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Archive a task, removing it from the scheduler and task listings while keeping its executions, or with hard=true delete it and its executions permanently",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Delete a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete permanently instead of archiving",
+                        "name": "hard",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deletion status: archived or deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Copy a task's name (with a \" (copy)\" suffix), description, schedules, settings, pipeline and Discord config into a new disabled task owned by the caller. Secrets and run history are not copied.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Clone a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/executions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get paginated list of executions for a specific task",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Executions"
+                ],
+                "summary": "Get task executions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of executions to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination; prefer before for deep pages",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page's next_cursor; overrides offset",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "schedule",
+                            "manual",
+                            "replay",
+                            "webhook"
+                        ],
+                        "type": "string",
+                        "description": "Only executions with this trigger type",
+                        "name": "trigger_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "running",
+                            "completed",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Only executions with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only executions started at or after this RFC 3339 time or YYYY-MM-DD date (UTC)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only executions started before this RFC 3339 time, or on or before this YYYY-MM-DD date (UTC)",
+                        "name": "until",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Executions list with next_cursor, empty on the last page",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/executions/{execId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get details of a specific execution",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Executions"
+                ],
+                "summary": "Get execution details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Execution ID",
+                        "name": "execId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Execution"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task or execution not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/executions/{execId}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Re-run a task's pipeline from the given step, using the output captured from the previous step of an earlier execution as input. The previous step must have capture_output enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Executions"
+                ],
+                "summary": "Resume an execution from a step",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Execution ID",
+                        "name": "execId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1-based step number to resume from",
+                        "name": "from_step",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Execution"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task or execution not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Task is already running or archived",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many executions in progress",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Execution error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/executions/{execId}/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events of an execution's progress. Each \"step\" event is a scheduler.StepEvent: started, completed, failed or skipped. Steps that already finished are sent first from the stored results, and a final \"done\" event carries the execution's status and error before the stream closes. An execution that already finished gets its stored results and \"done\" at once. Runs on another server instance are followed by re-reading the record every 15 seconds.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Executions"
+                ],
+                "summary": "Stream an execution's step events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID (on the task-scoped route)",
+                        "name": "id",
+                        "in": "path"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Execution ID",
+                        "name": "execId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "$ref": "#/definitions/scheduler.StepEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Execution not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Download what the task last produced: the output of the last step with capture_output in its most recent completed execution that captured any. CSV has one row per scraped or RSS item, with a column per field and per key of the items' extra fields; other outputs, such as AI text, can only be exported as JSON. The X-Execution-ID header names the execution exported.",
+                "produces": [
+                    "text/csv",
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Export a task's latest results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported items",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown format, or output can't be CSV",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task or captured output not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/next-runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the next times the task's schedules fire, in the task's timezone, with the schedules as the scheduler reads them (shortcuts expanded, a seconds field added to five-field expressions). Paused tasks are previewed as if enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Preview upcoming runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of runs to list (max 50)",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.NextRuns"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Disable a task's schedule without touching its pipeline",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Pause a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New task status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Task is archived",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Bring an archived task back as disabled; resume it to schedule it again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Restore an archived task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Task is not archived",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Re-enable a paused task's schedule without touching its pipeline",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Resume a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New task status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Task is archived",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/run": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Execute a task immediately regardless of its schedule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Trigger a task manually",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fetch sources unconditionally instead of using cached responses",
+                        "name": "force_refresh",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Deliver items even if the task has already seen them",
+                        "name": "bypass_dedup",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Execution"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Task is already running or archived",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many executions in progress",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Execution error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/secrets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the names of a task's secrets. Values are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "List task secrets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored secret names",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create, update or delete (empty value) encrypted secrets for a task. Steps reference them by name with the secret template function; values are never returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Set task secrets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Secrets by name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetTaskSecretsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored secret names",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/secrets/{name}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Delete a task secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Secret name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deletion status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/share": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List a task's share links, including expired ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "List share links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Share links",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Generate a read-only token that exposes the task's recently delivered items without authentication",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Create a share link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional expiry",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateTaskShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TaskShare"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/share/{token}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a share link so its token stops working",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Revoke a share link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revoked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task or share link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/webhook": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Generate the secret that lets POST /tasks/{id}/webhook/{secret} run the task without authentication. Any earlier secret stops working. The secret is only shown in this response.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Create an inbound webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Secret and webhook path",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke the task's webhook secret so inbound webhooks can no longer run it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Delete an inbound webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revoked",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1527,6 +4239,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Task or webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1539,7 +4260,91 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}/executions/{execId}": {
+        "/tasks/{id}/webhook/{secret}": {
+            "post": {
+                "description": "Public endpoint authenticated by the secret in its path. Runs the task's pipeline and returns the execution. A JSON body becomes the first step's input as parsed JSON, any other body as text; an empty body gives the first step no input. The run continues if the sender disconnects. Disabled and archived tasks aren't run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Run a task from an inbound webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook secret",
+                        "name": "secret",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Execution"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or secret not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Task disabled, archived or already running",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many executions in progress",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{taskId}/discord": {
             "get": {
                 "security": [
                     {
@@ -1549,35 +4354,111 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get details of a specific execution",
+                "description": "Get the Discord configuration for a task",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Executions"
+                    "Task Discord Config"
                 ],
-                "summary": "Get execution details",
+                "summary": "Get task Discord config",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Task ID",
-                        "name": "id",
+                        "name": "taskId",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TaskDiscordConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task or config not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Configure which bot/channel/webhook a task uses for notifications. Set verify to check the webhook is reachable before saving.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Task Discord Config"
+                ],
+                "summary": "Set task Discord config",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Execution ID",
-                        "name": "execId",
+                        "description": "Task ID",
+                        "name": "taskId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Discord configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetTaskDiscordConfigRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Execution"
+                            "$ref": "#/definitions/model.TaskDiscordConfig"
                         }
                     },
                     "400": {
@@ -1599,7 +4480,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Execution not found",
+                        "description": "Task not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1617,9 +4498,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/tasks/{id}/run": {
+            },
             "post": {
                 "security": [
                     {
@@ -1629,28 +4508,40 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Execute a task immediately regardless of its schedule",
+                "description": "Configure which bot/channel/webhook a task uses for notifications. Set verify to check the webhook is reachable before saving.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Task Discord Config"
                 ],
-                "summary": "Trigger a task manually",
+                "summary": "Set task Discord config",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Task ID",
-                        "name": "id",
+                        "name": "taskId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Discord configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetTaskDiscordConfigRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Execution"
+                            "$ref": "#/definitions/model.TaskDiscordConfig"
                         }
                     },
                     "400": {
@@ -1671,8 +4562,17 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
-                        "description": "Execution error",
+                        "description": "Server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1681,10 +4581,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/tasks/{taskId}/discord": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1693,14 +4591,14 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get the Discord configuration for a task",
+                "description": "Remove the Discord configuration for a task",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Task Discord Config"
                 ],
-                "summary": "Get task Discord config",
+                "summary": "Delete task Discord config",
                 "parameters": [
                     {
                         "type": "string",
@@ -1712,9 +4610,12 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Deletion status",
                         "schema": {
-                            "$ref": "#/definitions/model.TaskDiscordConfig"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -1736,7 +4637,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Config not found",
+                        "description": "Task not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1754,8 +4655,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/tasks/{taskId}/discord/test": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1764,7 +4667,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Configure which bot/channel/webhook a task uses for notifications",
+                "description": "Send a sample embed to the webhook the task's discord steps resolve at runtime (task config webhook, then its channel, then the default bot's first channel) and report which level provided it: task_webhook, task_channel or default_bot",
                 "consumes": [
                     "application/json"
                 ],
@@ -1774,7 +4677,7 @@ const docTemplate = `{
                 "tags": [
                     "Task Discord Config"
                 ],
-                "summary": "Set task Discord config",
+                "summary": "Test a task's Discord webhook",
                 "parameters": [
                     {
                         "type": "string",
@@ -1784,24 +4687,29 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Discord configuration",
+                        "description": "Optional message text",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.SetTaskDiscordConfigRequest"
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Test result with the webhook source",
                         "schema": {
-                            "$ref": "#/definitions/model.TaskDiscordConfig"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid request or no webhook resolved",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1818,6 +4726,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1826,10 +4743,21 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "502": {
+                        "description": "Discord returned an error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/users/{id}/active": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1838,31 +4766,40 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Remove the Discord configuration for a task",
+                "description": "Admin only. A disabled user can't log in, use API keys or refresh tokens, and disabling revokes all their refresh tokens. JWTs already issued stay valid until they expire.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Task Discord Config"
+                    "Authentication"
                 ],
-                "summary": "Delete task Discord config",
+                "summary": "Enable or disable a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
-                        "name": "taskId",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New state",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetUserActiveRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Deletion status",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.User"
                         }
                     },
                     "400": {
@@ -1883,6 +4820,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1897,6 +4852,148 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "model.BulkTaskRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "enable, disable, delete or trigger",
+                    "type": "string"
+                },
+                "hard": {
+                    "description": "With delete: remove the tasks and their executions instead of archiving them",
+                    "type": "boolean"
+                },
+                "task_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.BulkTaskResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkTaskResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkTaskResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "execution_id": {
+                    "description": "Set for trigger",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "task_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChangePasswordRequest": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                },
+                "revoke_sessions": {
+                    "description": "RevokeSessions logs out every other session by revoking all refresh\ntokens; the response then carries a fresh token pair",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.ChangePasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "session": {
+                    "description": "New tokens when sessions were revoked",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.LoginResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.CreateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "task_id": {
+                    "description": "Restrict the key to this task",
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "First characters of the key, to tell keys apart",
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
         "model.CreateDiscordBotRequest": {
             "type": "object",
             "required": [
@@ -1965,10 +5062,15 @@ const docTemplate = `{
             "type": "object",
             "required": [
                 "name",
-                "pipeline",
-                "schedule"
+                "pipeline"
             ],
             "properties": {
+                "allow_overlap": {
+                    "type": "boolean"
+                },
+                "catch_up": {
+                    "type": "boolean"
+                },
                 "description": {
                     "type": "string",
                     "maxLength": 500
@@ -1986,6 +5088,50 @@ const docTemplate = `{
                     }
                 },
                 "schedule": {
+                    "description": "Required unless schedules is set",
+                    "type": "string"
+                },
+                "schedules": {
+                    "description": "Extra cron expressions, e.g. [\"0 9 * * *\", \"0 17 * * *\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeout_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateTaskShareRequest": {
+            "type": "object",
+            "properties": {
+                "expires_in_hours": {
+                    "description": "0 means the link never expires",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DeliveredItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
@@ -2136,6 +5282,10 @@ const docTemplate = `{
                 "finished_at": {
                     "type": "string"
                 },
+                "forced": {
+                    "description": "run bypassed cached responses or dedup",
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -2157,6 +5307,9 @@ const docTemplate = `{
                 "task_name": {
                     "type": "string"
                 },
+                "trigger_type": {
+                    "$ref": "#/definitions/model.TriggerType"
+                },
                 "triggered_by": {
                     "description": "\"schedule\" or \"manual\" or user_id",
                     "type": "string"
@@ -2178,6 +5331,14 @@ const docTemplate = `{
                 "ExecutionStatusFailed"
             ]
         },
+        "model.ForgotPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
         "model.LoginRequest": {
             "type": "object",
             "required": [
@@ -2199,6 +5360,12 @@ const docTemplate = `{
                 "expires_at": {
                     "type": "integer"
                 },
+                "refresh_expires_at": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
                 "token": {
                     "type": "string"
                 },
@@ -2207,13 +5374,46 @@ const docTemplate = `{
                 }
             }
         },
+        "model.NextRuns": {
+            "type": "object",
+            "properties": {
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schedules": {
+                    "description": "Normalized six-field expressions, seconds first",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Zone the schedules are evaluated in",
+                    "type": "string"
+                }
+            }
+        },
         "model.PipelineStep": {
             "type": "object",
             "properties": {
+                "capture_output": {
+                    "description": "Keep the full output so later steps can be resumed from it",
+                    "type": "boolean"
+                },
                 "config": {
                     "type": "object",
                     "additionalProperties": true
                 },
+                "debug": {
+                    "description": "Store a redacted snapshot of the step's output data",
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -2222,6 +5422,14 @@ const docTemplate = `{
                 }
             }
         },
+        "model.RefreshRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
         "model.RegisterRequest": {
             "type": "object",
             "required": [
@@ -2244,6 +5452,52 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ResetPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ScraperList": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ScraperSource"
+                    }
+                }
+            }
+        },
+        "model.ScraperSource": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "streaming": {
+                    "description": "Fetched page by page; large limits are cheap",
+                    "type": "boolean"
+                }
+            }
+        },
         "model.SetTaskDiscordConfigRequest": {
             "type": "object",
             "properties": {
@@ -2265,11 +5519,57 @@ const docTemplate = `{
                 "username": {
                     "type": "string"
                 },
+                "verify": {
+                    "description": "Check the resolved webhook is reachable before saving",
+                    "type": "boolean"
+                },
                 "webhook_url": {
                     "type": "string"
                 }
             }
         },
+        "model.SetTaskSecretsRequest": {
+            "type": "object",
+            "required": [
+                "secrets"
+            ],
+            "properties": {
+                "secrets": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.SetUserActiveRequest": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.SharedTask": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DeliveredItem"
+                    }
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "model.StepResult": {
             "type": "object",
             "properties": {
@@ -2297,6 +5597,14 @@ const docTemplate = `{
         "model.Task": {
             "type": "object",
             "properties": {
+                "allow_overlap": {
+                    "description": "Start runs even while the previous one is going",
+                    "type": "boolean"
+                },
+                "catch_up": {
+                    "description": "Run once on startup if a fire time was missed while down",
+                    "type": "boolean"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -2328,11 +5636,34 @@ const docTemplate = `{
                     "description": "Cron expression",
                     "type": "string"
                 },
+                "schedules": {
+                    "description": "Extra cron expressions, each firing the task",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "status": {
                     "$ref": "#/definitions/model.TaskStatus"
                 },
+                "timeout_seconds": {
+                    "description": "0 uses the scheduler default",
+                    "type": "integer"
+                },
+                "timezone": {
+                    "description": "IANA name; empty means server local time",
+                    "type": "string"
+                },
+                "trigger_webhook": {
+                    "description": "An inbound webhook secret can run the task",
+                    "type": "boolean"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "webhook_error": {
+                    "description": "Set when the task's Discord webhook failed re-verification",
+                    "type": "string"
                 }
             }
         },
@@ -2371,17 +5702,63 @@ const docTemplate = `{
                 }
             }
         },
+        "model.TaskShare": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "model.TaskStatus": {
             "type": "string",
             "enum": [
                 "enabled",
                 "disabled",
-                "running"
+                "running",
+                "archived"
+            ],
+            "x-enum-comments": {
+                "TaskStatusArchived": "Deleted without losing its executions; restorable"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "Deleted without losing its executions; restorable"
             ],
             "x-enum-varnames": [
                 "TaskStatusEnabled",
                 "TaskStatusDisabled",
-                "TaskStatusRunning"
+                "TaskStatusRunning",
+                "TaskStatusArchived"
+            ]
+        },
+        "model.TriggerType": {
+            "type": "string",
+            "enum": [
+                "schedule",
+                "manual",
+                "replay",
+                "webhook"
+            ],
+            "x-enum-varnames": [
+                "TriggerTypeSchedule",
+                "TriggerTypeManual",
+                "TriggerTypeReplay",
+                "TriggerTypeWebhook"
             ]
         },
         "model.UpdateDiscordBotRequest": {
@@ -2424,6 +5801,12 @@ const docTemplate = `{
         "model.UpdateTaskRequest": {
             "type": "object",
             "properties": {
+                "allow_overlap": {
+                    "type": "boolean"
+                },
+                "catch_up": {
+                    "type": "boolean"
+                },
                 "description": {
                     "type": "string",
                     "maxLength": 500
@@ -2442,8 +5825,22 @@ const docTemplate = `{
                 "schedule": {
                     "type": "string"
                 },
+                "schedules": {
+                    "description": "Replaces the extra schedules; [] clears them",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "status": {
                     "$ref": "#/definitions/model.TaskStatus"
+                },
+                "timeout_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "timezone": {
+                    "type": "string"
                 }
             }
         },
@@ -2483,6 +5880,143 @@ const docTemplate = `{
                 "UserRoleAdmin",
                 "UserRoleUser"
             ]
+        },
+        "scheduler.DryRunResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "preview of the data the pipeline ended with"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scheduler.DryRunStep"
+                    }
+                }
+            }
+        },
+        "scheduler.DryRunStep": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "item_count": {
+                    "type": "integer"
+                },
+                "output": {},
+                "status": {
+                    "type": "string"
+                },
+                "step_name": {
+                    "type": "string"
+                },
+                "step_type": {
+                    "type": "string"
+                },
+                "would_send": {
+                    "description": "delivery steps only"
+                }
+            }
+        },
+        "scheduler.PipelineEstimate": {
+            "type": "object",
+            "properties": {
+                "cost_usd": {
+                    "description": "Unset when any AI step is unpriced",
+                    "type": "number"
+                },
+                "input_tokens": {
+                    "type": "integer"
+                },
+                "output_tokens": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "runtime_ms": {
+                    "type": "integer"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scheduler.StepEstimate"
+                    }
+                }
+            }
+        },
+        "scheduler.StepEstimate": {
+            "type": "object",
+            "properties": {
+                "cost_usd": {
+                    "description": "Unset when the provider has no configured price",
+                    "type": "number"
+                },
+                "input_tokens": {
+                    "type": "integer"
+                },
+                "items_out": {
+                    "description": "Upper bound on items passed on",
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "output_tokens": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "description": "Upstream or outgoing HTTP requests",
+                    "type": "integer"
+                },
+                "runtime_ms": {
+                    "type": "integer"
+                },
+                "step_name": {
+                    "type": "string"
+                },
+                "step_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "scheduler.StepEvent": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "item_count": {
+                    "description": "Completed steps only",
+                    "type": "integer"
+                },
+                "step": {
+                    "description": "1-based position in the execution's step results",
+                    "type": "integer"
+                },
+                "step_name": {
+                    "type": "string"
+                },
+                "step_type": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
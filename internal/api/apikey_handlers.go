@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/multi-worker/internal/middleware"
+	"github.com/multi-worker/internal/model"
+)
+
+// CreateAPIKey godoc
+// @Summary Create a scoped API key
+// @Description Create a named API key limited to some scopes (tasks:read, tasks:write, tasks:trigger, discord:read, discord:write) and optionally to one task. The key is only returned once.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.CreateAPIKeyRequest true "Key name, scopes and optional task"
+// @Success 201 {object} model.CreateAPIKeyResponse
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Scoped keys can't manage keys"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /auth/api-keys [post]
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req model.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if len(req.Scopes) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one scope is required")
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !model.ValidScope(scope) {
+			respondError(w, http.StatusBadRequest, "unknown scope: "+scope)
+			return
+		}
+	}
+
+	if req.TaskID != nil {
+		// Keys can only be restricted to tasks the caller may reach
+		var task *model.Task
+		var err error
+		if owner := taskOwner(claims); owner != "" {
+			task, err = h.taskRepo.FindByIDForUser(r.Context(), *req.TaskID, owner)
+		} else {
+			task, err = h.taskRepo.FindByID(r.Context(), *req.TaskID)
+		}
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid task_id")
+			return
+		}
+		if task == nil {
+			respondError(w, http.StatusBadRequest, "task not found")
+			return
+		}
+	}
+
+	key, apiKey, err := h.apiKeyRepo.Create(r.Context(), claims.UserID, &req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to create API key")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, model.CreateAPIKeyResponse{APIKey: *apiKey, Key: key})
+}
+
+// ListAPIKeys godoc
+// @Summary List scoped API keys
+// @Description List the current user's scoped API keys. Keys themselves are never returned, only their prefix.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} map[string]interface{} "API keys"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Scoped keys can't manage keys"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /auth/api-keys [get]
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	keys, err := h.apiKeyRepo.ListByUser(r.Context(), claims.UserID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list API keys")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"api_keys": keys,
+		"count":    len(keys),
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke a scoped API key
+// @Description Delete one of the current user's scoped API keys so it stops working
+// @Tags Authentication
+// @Produce json
+// @Param keyId path string true "API key ID"
+// @Success 200 {object} map[string]string "Revoked"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Scoped keys can't manage keys"
+// @Failure 404 {object} map[string]string "API key not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /auth/api-keys/{keyId} [delete]
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	revoked, err := h.apiKeyRepo.Delete(r.Context(), r.PathValue("keyId"), claims.UserID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke API key")
+		return
+	}
+	if !revoked {
+		respondError(w, http.StatusNotFound, "API key not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "revoked"})
+}
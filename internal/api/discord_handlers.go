@@ -2,23 +2,41 @@ package api
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/multi-worker/internal/executor/discord"
 	"github.com/multi-worker/internal/middleware"
 	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+	"github.com/multi-worker/internal/scheduler"
 	"github.com/multi-worker/internal/storage"
 )
 
 // DiscordHandler handles Discord bot and channel API endpoints
 type DiscordHandler struct {
 	discordRepo *storage.DiscordRepository
+	taskRepo    *storage.TaskRepository
+	checker     *scheduler.WebhookChecker
+	guard       *netguard.Guard
+	client      *http.Client // Sends test messages and calls Discord; refuses internal addresses
 }
 
 // NewDiscordHandler creates a new Discord handler
-func NewDiscordHandler(discordRepo *storage.DiscordRepository) *DiscordHandler {
-	return &DiscordHandler{discordRepo: discordRepo}
+func NewDiscordHandler(discordRepo *storage.DiscordRepository, taskRepo *storage.TaskRepository, checker *scheduler.WebhookChecker, guard *netguard.Guard) *DiscordHandler {
+	return &DiscordHandler{
+		discordRepo: discordRepo,
+		taskRepo:    taskRepo,
+		checker:     checker,
+		guard:       guard,
+		client:      guard.Client(10 * time.Second),
+	}
 }
 
 // Bot handlers
@@ -54,6 +72,12 @@ func (h *DiscordHandler) CreateBot(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "name, application_id, token, and client_id are required")
 		return
 	}
+	if req.PublicKey != "" {
+		if _, err := parsePublicKey(req.PublicKey); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
 	bot, err := h.discordRepo.CreateBot(r.Context(), &req, claims.UserID)
 	if err != nil {
@@ -66,11 +90,14 @@ func (h *DiscordHandler) CreateBot(w http.ResponseWriter, r *http.Request) {
 
 // ListBots godoc
 // @Summary List Discord bots
-// @Description Get all registered Discord bots
+// @Description Get a paginated list of registered Discord bots
 // @Tags Discord Bots
 // @Produce json
 // @Param include_inactive query bool false "Include inactive bots"
-// @Success 200 {object} map[string]interface{} "List of bots"
+// @Param q query string false "Only bots whose name contains this text (case-insensitive)"
+// @Param limit query int false "Number of bots to return" default(50)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {object} map[string]interface{} "Bots list with pagination"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
@@ -78,16 +105,22 @@ func (h *DiscordHandler) CreateBot(w http.ResponseWriter, r *http.Request) {
 // @Router /discord/bots [get]
 func (h *DiscordHandler) ListBots(w http.ResponseWriter, r *http.Request) {
 	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+	search := r.URL.Query().Get("q")
+	limit, offset := pageParams(r, 50)
 
-	bots, err := h.discordRepo.ListBots(r.Context(), includeInactive)
+	bots, err := h.discordRepo.ListBots(r.Context(), includeInactive, search, limit, offset)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	total, _ := h.discordRepo.CountBots(r.Context(), includeInactive, search)
+
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"bots":  bots,
-		"total": len(bots),
+		"bots":   bots,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
 	})
 }
 
@@ -229,6 +262,12 @@ func (h *DiscordHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "bot_id, channel_id, and name are required")
 		return
 	}
+	if req.WebhookURL != "" {
+		if err := h.guard.CheckDiscordWebhook(req.WebhookURL); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
 	channel, err := h.discordRepo.CreateChannel(r.Context(), &req, claims.UserID)
 	if err != nil {
@@ -241,11 +280,14 @@ func (h *DiscordHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
 
 // ListChannels godoc
 // @Summary List Discord channels
-// @Description Get all channels, optionally filtered by bot
+// @Description Get a paginated list of active channels, optionally filtered by bot
 // @Tags Discord Channels
 // @Produce json
 // @Param bot_id query string false "Filter by bot ID"
-// @Success 200 {object} map[string]interface{} "List of channels"
+// @Param q query string false "Only channels whose name contains this text (case-insensitive)"
+// @Param limit query int false "Number of channels to return" default(50)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {object} map[string]interface{} "Channels list with pagination"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
@@ -253,24 +295,22 @@ func (h *DiscordHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
 // @Router /discord/channels [get]
 func (h *DiscordHandler) ListChannels(w http.ResponseWriter, r *http.Request) {
 	botID := r.URL.Query().Get("bot_id")
+	search := r.URL.Query().Get("q")
+	limit, offset := pageParams(r, 50)
 
-	var channels []model.DiscordChannel
-	var err error
-
-	if botID != "" {
-		channels, err = h.discordRepo.ListChannelsByBot(r.Context(), botID)
-	} else {
-		channels, err = h.discordRepo.ListAllChannels(r.Context())
-	}
-
+	channels, err := h.discordRepo.ListAllChannels(r.Context(), botID, search, limit, offset)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	total, _ := h.discordRepo.CountChannels(r.Context(), botID, search)
+
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"channels": channels,
-		"total":    len(channels),
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
 	})
 }
 
@@ -335,6 +375,12 @@ func (h *DiscordHandler) UpdateChannel(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	if req.WebhookURL != nil && *req.WebhookURL != "" {
+		if err := h.guard.CheckDiscordWebhook(*req.WebhookURL); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
 	channel, err := h.discordRepo.UpdateChannel(r.Context(), channelID, &req)
 	if err != nil {
@@ -342,9 +388,91 @@ func (h *DiscordHandler) UpdateChannel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Tasks delivering through this channel may now point at a dead webhook
+	if req.WebhookURL != nil || req.IsActive != nil {
+		h.checker.CheckChannel(channelID)
+	}
+
 	respondJSON(w, http.StatusOK, channel)
 }
 
+// CreateChannelWebhook godoc
+// @Summary Create a webhook for a channel
+// @Description Create a webhook in the Discord channel with the channel's bot token and store it on the channel, replacing any webhook URL set before. The bot needs the Manage Webhooks permission in the channel.
+// @Tags Discord Channels
+// @Accept json
+// @Produce json
+// @Param channelId path string true "Channel config ID"
+// @Param request body object false "Optional webhook name, e.g. {\"name\": \"Job Alerts\"} (default Multi-Worker)"
+// @Success 201 {object} map[string]string "Webhook ID and masked URL"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Channel not found"
+// @Failure 502 {object} map[string]string "Discord refused to create the webhook"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /discord/channels/{channelId}/create-webhook [post]
+func (h *DiscordHandler) CreateChannelWebhook(w http.ResponseWriter, r *http.Request) {
+	channelID := r.PathValue("channelId")
+	if channelID == "" {
+		respondError(w, http.StatusBadRequest, "channel ID required")
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	if req.Name == "" {
+		req.Name = "Multi-Worker"
+	}
+
+	channel, err := h.discordRepo.GetChannel(r.Context(), channelID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if channel == nil {
+		respondError(w, http.StatusNotFound, "channel not found")
+		return
+	}
+
+	bot, err := h.discordRepo.GetBotWithCredentials(r.Context(), channel.BotID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if bot == nil || bot.Token == "" {
+		respondError(w, http.StatusBadRequest, "the channel's bot is inactive or has no token")
+		return
+	}
+
+	webhookID, webhookURL, err := discord.CreateWebhook(r.Context(), h.client, bot.Token, channel.ChannelID, req.Name)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "failed to create webhook: "+err.Error())
+		return
+	}
+
+	if _, err := h.discordRepo.SetChannelWebhook(r.Context(), channelID, webhookURL, webhookID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Tasks delivering through this channel now use the new webhook
+	h.checker.CheckChannel(channelID)
+
+	respondJSON(w, http.StatusCreated, map[string]string{
+		"status":      "created",
+		"webhook_id":  webhookID,
+		"webhook_url": "https://discord.com/api/webhooks/" + webhookID + "/***",
+	})
+}
+
 // DeleteChannel godoc
 // @Summary Delete a Discord channel
 // @Description Remove a channel configuration
@@ -377,7 +505,7 @@ func (h *DiscordHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
 
 // SetTaskDiscordConfig godoc
 // @Summary Set task Discord config
-// @Description Configure which bot/channel/webhook a task uses for notifications
+// @Description Configure which bot/channel/webhook a task uses for notifications. Set verify to check the webhook is reachable before saving.
 // @Tags Task Discord Config
 // @Accept json
 // @Produce json
@@ -386,22 +514,53 @@ func (h *DiscordHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} model.TaskDiscordConfig
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
 // @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
 // @Security ApiKeyAuth
 // @Router /tasks/{taskId}/discord [put]
+// @Router /tasks/{taskId}/discord [post]
 func (h *DiscordHandler) SetTaskDiscordConfig(w http.ResponseWriter, r *http.Request) {
 	taskID := r.PathValue("taskId")
 	if taskID == "" {
 		respondError(w, http.StatusBadRequest, "task ID required")
 		return
 	}
+	if findOwnedTask(w, r, h.taskRepo, taskID) == nil {
+		return
+	}
 
 	var req model.SetTaskDiscordConfigRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	if req.WebhookURL != "" {
+		if err := h.guard.CheckDiscordWebhook(req.WebhookURL); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if req.Verify {
+		webhookURL := req.WebhookURL
+		if webhookURL == "" && req.ChannelID != nil {
+			channel, err := h.discordRepo.GetChannelWithWebhook(r.Context(), *req.ChannelID)
+			if err != nil || channel == nil {
+				respondError(w, http.StatusBadRequest, "channel not found")
+				return
+			}
+			webhookURL = channel.WebhookURL
+		}
+		if webhookURL == "" {
+			respondError(w, http.StatusBadRequest, "verify requires a webhook_url or a channel with a webhook")
+			return
+		}
+		if err := discord.VerifyWebhook(r.Context(), h.client, webhookURL); err != nil {
+			respondError(w, http.StatusBadRequest, "webhook verification failed: "+err.Error())
+			return
+		}
+	}
 
 	config, err := h.discordRepo.SetTaskConfig(r.Context(), taskID, &req)
 	if err != nil {
@@ -409,6 +568,9 @@ func (h *DiscordHandler) SetTaskDiscordConfig(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Clear or set the task's webhook flag for the new config
+	h.checker.CheckTasks(taskID)
+
 	respondJSON(w, http.StatusOK, config)
 }
 
@@ -421,7 +583,7 @@ func (h *DiscordHandler) SetTaskDiscordConfig(w http.ResponseWriter, r *http.Req
 // @Success 200 {object} model.TaskDiscordConfig
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Config not found"
+// @Failure 404 {object} map[string]string "Task or config not found"
 // @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -432,6 +594,9 @@ func (h *DiscordHandler) GetTaskDiscordConfig(w http.ResponseWriter, r *http.Req
 		respondError(w, http.StatusBadRequest, "task ID required")
 		return
 	}
+	if findOwnedTask(w, r, h.taskRepo, taskID) == nil {
+		return
+	}
 
 	config, err := h.discordRepo.GetTaskConfig(r.Context(), taskID)
 	if err != nil {
@@ -455,6 +620,7 @@ func (h *DiscordHandler) GetTaskDiscordConfig(w http.ResponseWriter, r *http.Req
 // @Success 200 {object} map[string]string "Deletion status"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
 // @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -465,6 +631,9 @@ func (h *DiscordHandler) DeleteTaskDiscordConfig(w http.ResponseWriter, r *http.
 		respondError(w, http.StatusBadRequest, "task ID required")
 		return
 	}
+	if findOwnedTask(w, r, h.taskRepo, taskID) == nil {
+		return
+	}
 
 	if err := h.discordRepo.DeleteTaskConfig(r.Context(), taskID); err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
@@ -519,16 +688,100 @@ func (h *DiscordHandler) TestWebhook(w http.ResponseWriter, r *http.Request) {
 
 	message := req.Message
 	if message == "" {
-		message = "🧪 Test message from Multi-Worker scheduler!"
+		message = defaultTestMessage
+	}
+
+	if !h.sendTestMessage(w, webhookURL, message, "This is a test message to verify webhook configuration.") {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "sent",
+		"message": message,
+	})
+}
+
+// TestTaskWebhook godoc
+// @Summary Test a task's Discord webhook
+// @Description Send a sample embed to the webhook the task's discord steps resolve at runtime (task config webhook, then its channel, then the default bot's first channel) and report which level provided it: task_webhook, task_channel or default_bot
+// @Tags Task Discord Config
+// @Accept json
+// @Produce json
+// @Param taskId path string true "Task ID"
+// @Param request body object{message=string} false "Optional message text"
+// @Success 200 {object} map[string]interface{} "Test result with the webhook source"
+// @Failure 400 {object} map[string]string "Invalid request or no webhook resolved"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Failure 502 {object} map[string]string "Discord returned an error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{taskId}/discord/test [post]
+func (h *DiscordHandler) TestTaskWebhook(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("taskId")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+	if findOwnedTask(w, r, h.taskRepo, taskID) == nil {
+		return
+	}
+
+	var req struct {
+		Message string `json:"message"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	webhookURL, source, err := h.discordRepo.ResolveWebhookForTask(r.Context(), taskID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if webhookURL == "" {
+		respondError(w, http.StatusBadRequest, "no Discord webhook configured for this task and no default bot channel with a webhook")
+		return
+	}
+
+	message := req.Message
+	if message == "" {
+		message = defaultTestMessage
+	}
+
+	if !h.sendTestMessage(w, webhookURL, message, "This is a test message to verify the webhook this task delivers to ("+source+").") {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "sent",
+		"source":  source,
+		"message": message,
+	})
+}
+
+// defaultTestMessage is sent by the webhook tests when no message is given
+const defaultTestMessage = "🧪 Test message from Multi-Worker scheduler!"
+
+// sendTestMessage posts a sample embed to webhookURL. On failure it responds
+// and returns false.
+func (h *DiscordHandler) sendTestMessage(w http.ResponseWriter, webhookURL, message, description string) bool {
+	// Stored webhooks may predate the host check, so check them here too
+	if err := h.guard.CheckDiscordWebhook(webhookURL); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return false
 	}
 
-	// Send test message to Discord webhook
 	payload := map[string]interface{}{
 		"content": message,
 		"embeds": []map[string]interface{}{
 			{
 				"title":       "Webhook Test",
-				"description": "This is a test message to verify webhook configuration.",
+				"description": description,
 				"color":       5814783,
 				"footer": map[string]string{
 					"text": "Multi-Worker Scheduler",
@@ -538,21 +791,127 @@ func (h *DiscordHandler) TestWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	payloadBytes, _ := json.Marshal(payload)
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(payloadBytes))
+	resp, err := h.client.Post(webhookURL, "application/json", bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to send test message: "+err.Error())
-		return
+		return false
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
 		respondError(w, http.StatusBadGateway, "Discord returned error: "+string(body))
+		return false
+	}
+	return true
+}
+
+// Interaction handlers
+
+// Interaction types and callback types used by HandleInteraction
+const (
+	interactionPing               = 1
+	interactionApplicationCommand = 2
+	callbackPong                  = 1
+	callbackChannelMessage        = 4
+	messageFlagEphemeral          = 1 << 6
+)
+
+// maxInteractionAge rejects signed requests replayed long after they were sent
+const maxInteractionAge = 5 * time.Minute
+
+// HandleInteraction godoc
+// @Summary Receive Discord interactions
+// @Description Interactions endpoint URL for a bot's Discord application. Requests must carry a valid X-Signature-Ed25519 signature by the bot's public key over X-Signature-Timestamp and the body. PINGs are answered with PONG; commands get an ephemeral reply until command handling is added.
+// @Tags Discord Bots
+// @Accept json
+// @Produce json
+// @Param botId path string true "Bot ID"
+// @Param X-Signature-Ed25519 header string true "Hex Ed25519 signature"
+// @Param X-Signature-Timestamp header string true "Signature timestamp"
+// @Success 200 {object} map[string]interface{} "Interaction response"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Invalid request signature"
+// @Failure 404 {object} map[string]string "Bot not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /discord/interactions/{botId} [post]
+func (h *DiscordHandler) HandleInteraction(w http.ResponseWriter, r *http.Request) {
+	botID := r.PathValue("botId")
+
+	bot, err := h.discordRepo.GetBot(r.Context(), botID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch bot")
+		return
+	}
+	if bot == nil || !bot.IsActive {
+		respondError(w, http.StatusNotFound, "bot not found")
+		return
+	}
+	publicKey, err := parsePublicKey(bot.PublicKey)
+	if err != nil {
+		respondError(w, http.StatusNotFound, "bot has no valid public key configured")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"status":  "sent",
-		"message": message,
-	})
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+
+	// Discord probes the endpoint with bad signatures and expects a 401
+	if !verifyInteraction(publicKey, r.Header.Get("X-Signature-Ed25519"), r.Header.Get("X-Signature-Timestamp"), body) {
+		respondError(w, http.StatusUnauthorized, "invalid request signature")
+		return
+	}
+
+	var interaction struct {
+		Type int `json:"type"`
+	}
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid interaction body")
+		return
+	}
+
+	switch interaction.Type {
+	case interactionPing:
+		respondJSON(w, http.StatusOK, map[string]interface{}{"type": callbackPong})
+	case interactionApplicationCommand:
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"type": callbackChannelMessage,
+			"data": map[string]interface{}{
+				"content": "This bot doesn't handle commands yet.",
+				"flags":   messageFlagEphemeral,
+			},
+		})
+	default:
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("unsupported interaction type %d", interaction.Type))
+	}
+}
+
+// parsePublicKey decodes a Discord application's hex public key
+func parsePublicKey(hexKey string) (ed25519.PublicKey, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public_key must be the application's %d-character hex public key", ed25519.PublicKeySize*2)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// verifyInteraction checks Discord's signature over timestamp+body and that
+// the timestamp is recent
+func verifyInteraction(publicKey ed25519.PublicKey, signatureHex, timestamp string, body []byte) bool {
+	signature, err := hex.DecodeString(signatureHex)
+	if err != nil || len(signature) != ed25519.SignatureSize || timestamp == "" {
+		return false
+	}
+	if !ed25519.Verify(publicKey, append([]byte(timestamp), body...), signature) {
+		return false
+	}
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(sent, 0))
+	return age < maxInteractionAge && age > -maxInteractionAge
 }
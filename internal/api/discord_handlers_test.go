@@ -0,0 +1,276 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/crypto"
+	"github.com/multi-worker/internal/middleware"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+	"github.com/multi-worker/internal/scheduler"
+	"github.com/multi-worker/internal/storage"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+const testEncryptionKey = "0123456789abcdef0123456789abcdef"
+
+// newTestDiscordHandler builds a DiscordHandler on db that accepts webhooks
+// on 127.0.0.1, where tests run their fake Discord
+func newTestDiscordHandler(t *testing.T, db *storage.Database) *DiscordHandler {
+	t.Helper()
+	cipher, err := crypto.New(config.EncryptionConfig{Key: testEncryptionKey, KeyVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	discordRepo := storage.NewDiscordRepository(db, cipher)
+	taskRepo := storage.NewTaskRepository(db)
+	guard := netguard.New(config.OutboundConfig{AllowedHosts: []string{"127.0.0.1"}})
+	return NewDiscordHandler(discordRepo, taskRepo, scheduler.NewWebhookChecker(discordRepo, taskRepo, guard), guard)
+}
+
+// asUser sends req through handler as user, with the given path values
+func asUser(handler http.HandlerFunc, req *http.Request, user *model.User, pathValues map[string]string) *httptest.ResponseRecorder {
+	claims := &model.TokenClaims{UserID: user.ID, Email: user.Email, Role: user.Role}
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+	for k, v := range pathValues {
+		req.SetPathValue(k, v)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func jsonBody(t *testing.T, v interface{}) *bytes.Reader {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(b)
+}
+
+func TestTaskDiscordConfigOwnership(t *testing.T) {
+	db := storagetest.Open(t)
+	h := newTestDiscordHandler(t, db)
+
+	owner := storagetest.CreateUser(t, db, model.UserRoleUser)
+	other := storagetest.CreateUser(t, db, model.UserRoleUser)
+	admin := storagetest.CreateUser(t, db, model.UserRoleAdmin)
+	task := storagetest.CreateTask(t, db, owner.ID)
+	path := map[string]string{"taskId": task.ID}
+
+	setConfig := func(user *model.User, webhookURL string) int {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/"+task.ID+"/discord",
+			jsonBody(t, model.SetTaskDiscordConfigRequest{WebhookURL: webhookURL}))
+		return asUser(h.SetTaskDiscordConfig, req, user, path).Code
+	}
+	getConfig := func(user *model.User) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/"+task.ID+"/discord", nil)
+		return asUser(h.GetTaskDiscordConfig, req, user, path)
+	}
+	deleteConfig := func(user *model.User) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/"+task.ID+"/discord", nil)
+		return asUser(h.DeleteTaskDiscordConfig, req, user, path).Code
+	}
+
+	ownerWebhook := "https://127.0.0.1:1/api/webhooks/1/owner"
+	if code := setConfig(owner, ownerWebhook); code != http.StatusOK {
+		t.Fatalf("owner set config: got %d", code)
+	}
+
+	// Someone else's task looks like it doesn't exist
+	if code := getConfig(other).Code; code != http.StatusNotFound {
+		t.Errorf("other user get config: got %d, want 404", code)
+	}
+	if code := setConfig(other, "https://127.0.0.1:1/api/webhooks/2/attacker"); code != http.StatusNotFound {
+		t.Errorf("other user set config: got %d, want 404", code)
+	}
+	if code := deleteConfig(other); code != http.StatusNotFound {
+		t.Errorf("other user delete config: got %d, want 404", code)
+	}
+
+	// The owner's webhook is untouched
+	if code := getConfig(owner).Code; code != http.StatusOK {
+		t.Fatalf("owner get config: got %d", code)
+	}
+	cfg, err := h.discordRepo.GetTaskConfig(context.Background(), task.ID)
+	if err != nil || cfg == nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.WebhookURL != ownerWebhook {
+		t.Errorf("webhook after other user's attempts = %q, want %q", cfg.WebhookURL, ownerWebhook)
+	}
+
+	// Admins reach every task
+	if code := getConfig(admin).Code; code != http.StatusOK {
+		t.Errorf("admin get config: got %d, want 200", code)
+	}
+	if code := deleteConfig(owner); code != http.StatusOK {
+		t.Errorf("owner delete config: got %d, want 200", code)
+	}
+}
+
+func TestTestTaskWebhookResolvesHierarchy(t *testing.T) {
+	db := storagetest.Open(t)
+	h := newTestDiscordHandler(t, db)
+	ctx := context.Background()
+
+	// A fake Discord recording which webhook each test message went to
+	var mu sync.Mutex
+	var hits []string
+	sent := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		out := hits
+		hits = nil
+		return out
+	}
+	discord := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits = append(hits, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer discord.Close()
+	h.client = discord.Client()
+
+	owner := storagetest.CreateUser(t, db, model.UserRoleUser)
+	other := storagetest.CreateUser(t, db, model.UserRoleUser)
+
+	bot, err := h.discordRepo.CreateBot(ctx, &model.CreateDiscordBotRequest{
+		Name: "Default", ApplicationID: "app-1", Token: "token", ClientID: "client", IsDefault: true,
+	}, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = h.discordRepo.CreateChannel(ctx, &model.CreateDiscordChannelRequest{
+		BotID: bot.ID, ChannelID: "100", Name: "default", WebhookURL: discord.URL + "/api/webhooks/1/default",
+	}, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskChannel, err := h.discordRepo.CreateChannel(ctx, &model.CreateDiscordChannelRequest{
+		BotID: bot.ID, ChannelID: "200", Name: "task", WebhookURL: discord.URL + "/api/webhooks/2/channel",
+	}, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		config     *model.SetTaskDiscordConfigRequest
+		wantSource string
+		wantPath   string
+	}{
+		{"default bot", nil, model.WebhookSourceDefault, "/api/webhooks/1/default"},
+		{"task channel", &model.SetTaskDiscordConfigRequest{ChannelID: &taskChannel.ID}, model.WebhookSourceChannel, "/api/webhooks/2/channel"},
+		{"task webhook", &model.SetTaskDiscordConfigRequest{ChannelID: &taskChannel.ID, WebhookURL: discord.URL + "/api/webhooks/3/task"}, model.WebhookSourceTask, "/api/webhooks/3/task"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := storagetest.CreateTask(t, db, owner.ID)
+			if tt.config != nil {
+				if _, err := h.discordRepo.SetTaskConfig(ctx, task.ID, tt.config); err != nil {
+					t.Fatal(err)
+				}
+			}
+			path := map[string]string{"taskId": task.ID}
+			sent()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+task.ID+"/discord/test", nil)
+			rec := asUser(h.TestTaskWebhook, req, owner, path)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("owner test: got %d: %s", rec.Code, rec.Body.String())
+			}
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp["source"] != tt.wantSource {
+				t.Errorf("source = %v, want %s", resp["source"], tt.wantSource)
+			}
+			if got := sent(); len(got) != 1 || got[0] != tt.wantPath {
+				t.Errorf("messages sent to %v, want [%s]", got, tt.wantPath)
+			}
+
+			// Another user can neither resolve nor post to the task's webhook
+			req = httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+task.ID+"/discord/test", nil)
+			if code := asUser(h.TestTaskWebhook, req, other, path).Code; code != http.StatusNotFound {
+				t.Errorf("other user test: got %d, want 404", code)
+			}
+			if got := sent(); len(got) != 0 {
+				t.Errorf("other user's test sent messages to %v", got)
+			}
+		})
+	}
+}
+
+func TestSetTaskDiscordConfigVerify(t *testing.T) {
+	db := storagetest.Open(t)
+	h := newTestDiscordHandler(t, db)
+
+	var gets int
+	discord := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		if r.URL.Path != "/api/webhooks/1/valid" {
+			http.Error(w, `{"message": "Unknown Webhook"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer discord.Close()
+	h.client = discord.Client()
+
+	owner := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, owner.ID)
+	path := map[string]string{"taskId": task.ID}
+
+	set := func(webhookURL string, verify bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/"+task.ID+"/discord",
+			jsonBody(t, model.SetTaskDiscordConfigRequest{WebhookURL: webhookURL, Verify: verify}))
+		return asUser(h.SetTaskDiscordConfig, req, owner, path)
+	}
+	saved := func() string {
+		cfg, err := h.discordRepo.GetTaskConfig(context.Background(), task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg == nil {
+			return ""
+		}
+		return cfg.WebhookURL
+	}
+
+	// An invalid webhook is refused and nothing is saved
+	invalid := discord.URL + "/api/webhooks/2/deleted"
+	if rec := set(invalid, true); rec.Code != http.StatusBadRequest || !bytes.Contains(rec.Body.Bytes(), []byte("404")) {
+		t.Errorf("invalid webhook: got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := saved(); got != "" {
+		t.Errorf("invalid webhook was saved as %q", got)
+	}
+
+	valid := discord.URL + "/api/webhooks/1/valid"
+	if rec := set(valid, true); rec.Code != http.StatusOK {
+		t.Fatalf("valid webhook: got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := saved(); got != valid {
+		t.Errorf("saved webhook = %q, want %q", got, valid)
+	}
+
+	// Verification is opt-in, so transient Discord problems don't block saving
+	gets = 0
+	if rec := set(invalid, false); rec.Code != http.StatusOK {
+		t.Errorf("unverified webhook: got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gets != 0 {
+		t.Errorf("saving without verify made %d requests", gets)
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// Export formats for GET /tasks/{id}/export
+const (
+	exportCSV  = "csv"
+	exportJSON = "json"
+)
+
+// unsafeFileChars are replaced when a task name becomes an export file name
+var unsafeFileChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ExportTask godoc
+// @Summary Export a task's latest results
+// @Description Download what the task last produced: the output of the last step with capture_output in its most recent completed execution that captured any. CSV has one row per scraped or RSS item, with a column per field and per key of the items' extra fields; other outputs, such as AI text, can only be exported as JSON. The X-Execution-ID header names the execution exported.
+// @Tags Tasks
+// @Produce text/csv
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param format query string false "csv (default) or json"
+// @Success 200 {file} file "Exported items"
+// @Failure 400 {object} map[string]string "Unknown format, or output can't be CSV"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task or captured output not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/export [get]
+func (h *Handler) ExportTask(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportCSV
+	}
+	if format != exportCSV && format != exportJSON {
+		respondError(w, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+
+	task := h.findTask(w, r, r.PathValue("id"))
+	if task == nil {
+		return
+	}
+
+	output, execID, err := h.runner.LatestOutput(r.Context(), task.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load captured output")
+		return
+	}
+	if output == nil {
+		respondError(w, http.StatusNotFound, "no completed execution captured output; enable capture_output on the step to export")
+		return
+	}
+
+	// Check before writing anything, so an unsuitable output still gets a
+	// proper error response
+	if format == exportCSV {
+		switch output.Data.(type) {
+		case []model.ScrapedItem, []model.RSSItem:
+		default:
+			respondError(w, http.StatusBadRequest, "the captured output isn't scraped or RSS items; export it with format=json")
+			return
+		}
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, exportFileName(*task, format)))
+	w.Header().Set("X-Execution-ID", execID)
+	if format == exportJSON {
+		respondJSON(w, http.StatusOK, output.Data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := model.WriteItemsCSV(w, output.Data); err != nil {
+		log.Printf("Export of task %s failed: %v", task.ID, err)
+	}
+}
+
+// exportFileName names an export after the task and today's date
+func exportFileName(task model.Task, format string) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(task.Name), "-"), "-")
+	if name == "" {
+		name = "task"
+	}
+	return fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("2006-01-02"), format)
+}
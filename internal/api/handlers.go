@@ -1,46 +1,104 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/executor/email"
+	"github.com/multi-worker/internal/executor/scraper"
 	"github.com/multi-worker/internal/middleware"
 	"github.com/multi-worker/internal/model"
 	"github.com/multi-worker/internal/scheduler"
 	"github.com/multi-worker/internal/storage"
+	"github.com/swaggo/swag"
 )
 
 // Handler contains all API handlers
 type Handler struct {
-	userRepo  *storage.UserRepository
-	taskRepo  *storage.TaskRepository
-	execRepo  *storage.ExecutionRepository
-	scheduler *scheduler.Scheduler
-	runner    *scheduler.PipelineRunner
-	auth      *middleware.AuthMiddleware
+	db         *storage.Database
+	userRepo   *storage.UserRepository
+	taskRepo   *storage.TaskRepository
+	execRepo   *storage.ExecutionRepository
+	secretRepo *storage.SecretRepository
+	statsRepo  *storage.AnalyticsRepository
+	shareRepo  *storage.ShareRepository
+	apiKeyRepo *storage.APIKeyRepository
+	scheduler  *scheduler.Scheduler
+	runner     *scheduler.PipelineRunner
+	scrapers   *scraper.Registry
+	auth       *middleware.AuthMiddleware
+	resetRepo  *storage.PasswordResetRepository
+	mailer     *email.Executor
+	resetCfg   config.PasswordResetConfig
+
+	streams      chan struct{} // Closed by CloseStreams to end open event streams
+	closeStreams sync.Once
 }
 
 // NewHandler creates a new API handler
 func NewHandler(
+	db *storage.Database,
 	userRepo *storage.UserRepository,
 	taskRepo *storage.TaskRepository,
 	execRepo *storage.ExecutionRepository,
+	secretRepo *storage.SecretRepository,
+	statsRepo *storage.AnalyticsRepository,
+	shareRepo *storage.ShareRepository,
+	apiKeyRepo *storage.APIKeyRepository,
 	sched *scheduler.Scheduler,
 	runner *scheduler.PipelineRunner,
+	scrapers *scraper.Registry,
 	auth *middleware.AuthMiddleware,
+	resetRepo *storage.PasswordResetRepository,
+	mailer *email.Executor,
+	resetCfg config.PasswordResetConfig,
 ) *Handler {
 	return &Handler{
-		userRepo:  userRepo,
-		taskRepo:  taskRepo,
-		execRepo:  execRepo,
-		scheduler: sched,
-		runner:    runner,
-		auth:      auth,
+		db:         db,
+		userRepo:   userRepo,
+		taskRepo:   taskRepo,
+		execRepo:   execRepo,
+		secretRepo: secretRepo,
+		statsRepo:  statsRepo,
+		shareRepo:  shareRepo,
+		apiKeyRepo: apiKeyRepo,
+		scheduler:  sched,
+		runner:     runner,
+		scrapers:   scrapers,
+		auth:       auth,
+		resetRepo:  resetRepo,
+		mailer:     mailer,
+		resetCfg:   resetCfg,
+		streams:    make(chan struct{}),
 	}
 }
 
+// CloseStreams ends every open execution stream. The server calls it on
+// shutdown, where streams would otherwise hold the drain until its deadline.
+func (h *Handler) CloseStreams() {
+	h.closeStreams.Do(func() {
+		if h.streams != nil {
+			close(h.streams)
+		}
+	})
+}
+
+// secretNamePattern restricts secret names to what {{secret "name"}} can reference
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,100}$`)
+
 // Response helpers
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.WriteHeader(status)
@@ -105,17 +163,13 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, expiresAt, err := h.auth.GenerateToken(user)
+	resp, err := h.auth.IssueTokens(r.Context(), user)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to generate token")
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, model.LoginResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		User:      user,
-	})
+	respondJSON(w, http.StatusCreated, resp)
 }
 
 // Login godoc
@@ -149,12 +203,12 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !h.userRepo.ValidatePassword(user, req.Password) {
+	if !h.userRepo.ValidatePassword(user, req.Password) || !user.IsActive {
 		respondError(w, http.StatusUnauthorized, "invalid credentials")
 		return
 	}
 
-	token, expiresAt, err := h.auth.GenerateToken(user)
+	resp, err := h.auth.IssueTokens(r.Context(), user)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to generate token")
 		return
@@ -162,11 +216,191 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 
 	h.userRepo.UpdateLastLogin(r.Context(), user.ID)
 
-	respondJSON(w, http.StatusOK, model.LoginResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		User:      user,
-	})
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// RefreshToken godoc
+// @Summary Refresh the access token
+// @Description Exchange a refresh token for a new JWT. The refresh token is single-use; the response carries its replacement.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.RefreshRequest true "Refresh token"
+// @Success 200 {object} model.LoginResponse
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Invalid or expired refresh token"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /auth/refresh [post]
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req model.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		respondError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	resp, err := h.auth.Refresh(r.Context(), req.RefreshToken)
+	if errors.Is(err, middleware.ErrInvalidRefreshToken) {
+		respondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to refresh token")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revoke a refresh token so it can't be exchanged again. JWTs already issued remain valid until they expire.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.RefreshRequest true "Refresh token"
+// @Success 200 {object} map[string]string "Logged out"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /auth/logout [post]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req model.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		respondError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	if err := h.auth.RevokeRefreshToken(r.Context(), req.RefreshToken); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to log out")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "logged out"})
+}
+
+// forgotPasswordResponse is returned whether or not the email is registered,
+// so the endpoint can't be used to discover accounts
+var forgotPasswordResponse = map[string]string{"message": "if that email is registered, a password reset link has been sent"}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a single-use, time-limited password reset token to the address if it belongs to an active user. The response is the same either way.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.ForgotPasswordRequest true "Account email"
+// @Success 202 {object} map[string]string "Reset requested"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 503 {object} map[string]string "Email delivery not configured"
+// @Router /auth/forgot-password [post]
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req model.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" {
+		respondError(w, http.StatusBadRequest, "email is required")
+		return
+	}
+	if !h.mailer.Configured() {
+		respondError(w, http.StatusServiceUnavailable, "password reset by email is not configured")
+		return
+	}
+
+	user, err := h.userRepo.FindByEmail(r.Context(), req.Email)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to look up user")
+		return
+	}
+	if user == nil || !user.IsActive {
+		respondJSON(w, http.StatusAccepted, forgotPasswordResponse)
+		return
+	}
+
+	token, err := h.resetRepo.Create(r.Context(), user.ID, time.Now().Add(h.resetCfg.TokenTTL))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to create reset token")
+		return
+	}
+
+	// Send in the background so the response time doesn't reveal whether
+	// the email is registered
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		subject, body := h.passwordResetEmail(user, token)
+		if err := h.mailer.SendHTML(ctx, []string{user.Email}, subject, body); err != nil {
+			log.Printf("Failed to send password reset email to user %s: %v", user.ID, err)
+		}
+	}()
+
+	respondJSON(w, http.StatusAccepted, forgotPasswordResponse)
+}
+
+// passwordResetEmail renders the subject and HTML body carrying a reset
+// token, linking to PASSWORD_RESET_URL when one is configured
+func (h *Handler) passwordResetEmail(user *model.User, token string) (string, string) {
+	instructions := fmt.Sprintf(`<p>Send this token with your new password to <code>POST /api/v1/auth/reset-password</code>:</p>
+<p><code>%s</code></p>`, token)
+	if h.resetCfg.URL != "" {
+		sep := "?"
+		if strings.Contains(h.resetCfg.URL, "?") {
+			sep = "&"
+		}
+		link := h.resetCfg.URL + sep + "token=" + url.QueryEscape(token)
+		instructions = fmt.Sprintf(`<p><a href="%s">Choose a new password</a></p>`, html.EscapeString(link))
+	}
+
+	body := fmt.Sprintf(`<p>Hi %s,</p>
+<p>Someone asked to reset the password of your Multi-Worker account.</p>
+%s
+<p>The token can be used once and expires in %s. If you didn't ask for this, you can ignore this email.</p>`,
+		html.EscapeString(user.Name), instructions, h.resetCfg.TokenTTL)
+	return "Reset your Multi-Worker password", body
+}
+
+// ResetPassword godoc
+// @Summary Reset a forgotten password
+// @Description Set a new password with a token from /auth/forgot-password. The token is used up, and the user's refresh tokens are revoked so other sessions must log in again.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} map[string]string "Password updated"
+// @Failure 400 {object} map[string]string "Invalid request or invalid/expired token"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /auth/reset-password [post]
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req model.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		respondError(w, http.StatusBadRequest, "token and password are required")
+		return
+	}
+	if len(req.Password) < 8 {
+		respondError(w, http.StatusBadRequest, "password must be at least 8 characters")
+		return
+	}
+
+	userID, err := h.resetRepo.Consume(r.Context(), req.Token)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to check reset token")
+		return
+	}
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "invalid or expired reset token")
+		return
+	}
+
+	if err := h.userRepo.UpdatePassword(r.Context(), userID, req.Password); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to update password")
+		return
+	}
+
+	// Other tokens mailed earlier and existing sessions stop working
+	if err := h.resetRepo.RevokeAllForUser(r.Context(), userID); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if err := h.auth.RevokeUserSessions(r.Context(), userID); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "password updated"})
 }
 
 // GetProfile godoc
@@ -223,6 +457,118 @@ func (h *Handler) RegenerateAPIKey(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{"api_key": apiKey})
 }
 
+// SetUserActive godoc
+// @Summary Enable or disable a user
+// @Description Admin only. A disabled user can't log in, use API keys or refresh tokens, and disabling revokes all their refresh tokens. JWTs already issued stay valid until they expire.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body model.SetUserActiveRequest true "New state"
+// @Success 200 {object} model.User
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not an admin"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/active [put]
+func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req model.SetUserActiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsActive == nil {
+		respondError(w, http.StatusBadRequest, "is_active is required")
+		return
+	}
+
+	userID := r.PathValue("id")
+	if userID == claims.UserID && !*req.IsActive {
+		respondError(w, http.StatusBadRequest, "you can't disable your own account")
+		return
+	}
+
+	user, err := h.userRepo.SetActive(r.Context(), userID, *req.IsActive)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to update user")
+		return
+	}
+	if user == nil {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Replace the current user's password, given the current one. With revoke_sessions, all refresh tokens are revoked so other sessions must log in again, and a fresh token pair is returned.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body model.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} model.ChangePasswordResponse
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized or wrong current password"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /auth/change-password [post]
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req model.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CurrentPassword == "" || req.NewPassword == "" {
+		respondError(w, http.StatusBadRequest, "current_password and new_password are required")
+		return
+	}
+	if len(req.NewPassword) < 8 {
+		respondError(w, http.StatusBadRequest, "password must be at least 8 characters")
+		return
+	}
+
+	user, err := h.userRepo.FindByID(r.Context(), claims.UserID)
+	if err != nil || user == nil {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if !h.userRepo.ValidatePassword(user, req.CurrentPassword) {
+		respondError(w, http.StatusUnauthorized, "current password is incorrect")
+		return
+	}
+
+	if err := h.userRepo.UpdatePassword(r.Context(), user.ID, req.NewPassword); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to update password")
+		return
+	}
+
+	resp := model.ChangePasswordResponse{Message: "password updated"}
+	if req.RevokeSessions {
+		if err := h.auth.RevokeUserSessions(r.Context(), user.ID); err != nil {
+			respondError(w, http.StatusInternalServerError, "password updated, but failed to revoke sessions")
+			return
+		}
+		session, err := h.auth.IssueTokens(r.Context(), user)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "password updated, but failed to generate token")
+			return
+		}
+		resp.Session = session
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
 // Task handlers
 
 // CreateTask godoc
@@ -257,14 +603,26 @@ func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "task name is required")
 		return
 	}
-	if req.Schedule == "" {
+	if req.Schedule == "" && len(req.Schedules) == 0 {
 		respondError(w, http.StatusBadRequest, "schedule is required")
 		return
 	}
+	if err := validateSchedules(req.Schedule, req.Schedules); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if len(req.Pipeline) == 0 {
 		respondError(w, http.StatusBadRequest, "at least one pipeline step is required")
 		return
 	}
+	if req.TimeoutSeconds < 0 {
+		respondError(w, http.StatusBadRequest, "timeout_seconds must not be negative")
+		return
+	}
+	if err := scheduler.ValidateTimezone(req.Timezone); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Validate pipeline
 	if errs := h.runner.ValidatePipeline(req.Pipeline); len(errs) > 0 {
@@ -279,19 +637,66 @@ func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Add to scheduler
-	h.scheduler.AddTask(*task)
+	if err := h.scheduler.AddTask(*task); err != nil {
+		log.Printf("Warning: failed to schedule task %s: %v", task.ID, err)
+	}
+
+	respondJSON(w, http.StatusCreated, task)
+}
+
+// CloneTask godoc
+// @Summary Clone a task
+// @Description Copy a task's name (with a " (copy)" suffix), description, schedules, settings, pipeline and Discord config into a new disabled task owned by the caller. Secrets and run history are not copied.
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 201 {object} model.Task
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/clone [post]
+func (h *Handler) CloneTask(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
+
+	task, err := h.taskRepo.Clone(r.Context(), taskID, claims.UserID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to clone task")
+		return
+	}
+	if task == nil {
+		respondError(w, http.StatusNotFound, "task not found")
+		return
+	}
 
+	// The clone starts disabled, so there is nothing to schedule yet
 	respondJSON(w, http.StatusCreated, task)
 }
 
 // GetTasks godoc
 // @Summary List all tasks
-// @Description Get a paginated list of tasks with optional status filter
+// @Description Get a paginated list of the caller's tasks, or every user's tasks for admins, with optional status filter
 // @Tags Tasks
 // @Produce json
 // @Param limit query int false "Number of tasks to return" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
-// @Param status query string false "Filter by status (enabled, disabled, running)"
+// @Param status query string false "Filter by status (enabled, disabled, running, archived); archived tasks are only listed when asked for"
 // @Success 200 {object} map[string]interface{} "Tasks list with pagination"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Server error"
@@ -299,19 +704,7 @@ func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
 // @Security ApiKeyAuth
 // @Router /tasks [get]
 func (h *Handler) GetTasks(w http.ResponseWriter, r *http.Request) {
-	limit := 50
-	offset := 0
-
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if v, err := strconv.Atoi(l); err == nil {
-			limit = v
-		}
-	}
-	if o := r.URL.Query().Get("offset"); o != "" {
-		if v, err := strconv.Atoi(o); err == nil {
-			offset = v
-		}
-	}
+	limit, offset := pageParams(r, 50)
 
 	var status *model.TaskStatus
 	if s := r.URL.Query().Get("status"); s != "" {
@@ -319,7 +712,20 @@ func (h *Handler) GetTasks(w http.ResponseWriter, r *http.Request) {
 		status = &st
 	}
 
-	tasks, err := h.taskRepo.FindAll(r.Context(), status, limit, offset)
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	owner := taskOwner(claims)
+
+	var tasks []model.Task
+	var err error
+	if owner != "" {
+		tasks, err = h.taskRepo.FindAllForUser(r.Context(), owner, status, limit, offset)
+	} else {
+		tasks, err = h.taskRepo.FindAll(r.Context(), status, limit, offset)
+	}
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to fetch tasks")
 		return
@@ -332,7 +738,12 @@ func (h *Handler) GetTasks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	total, _ := h.taskRepo.Count(r.Context(), status)
+	var total int
+	if owner != "" {
+		total, _ = h.taskRepo.CountForUser(r.Context(), owner, status)
+	} else {
+		total, _ = h.taskRepo.Count(r.Context(), status)
+	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"tasks":  tasks,
@@ -342,6 +753,48 @@ func (h *Handler) GetTasks(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// taskOwner returns the user whose tasks the caller may reach, or "" for
+// admins, who reach every task
+func taskOwner(claims *model.TokenClaims) string {
+	if claims.Role == model.UserRoleAdmin {
+		return ""
+	}
+	return claims.UserID
+}
+
+// findTask loads a task the caller may reach. Tasks owned by someone else
+// are reported as not found so their existence isn't revealed. On failure
+// it responds and returns nil.
+func (h *Handler) findTask(w http.ResponseWriter, r *http.Request, taskID string) *model.Task {
+	return findOwnedTask(w, r, h.taskRepo, taskID)
+}
+
+// findOwnedTask is findTask for handlers other than Handler
+func findOwnedTask(w http.ResponseWriter, r *http.Request, taskRepo *storage.TaskRepository, taskID string) *model.Task {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return nil
+	}
+
+	var task *model.Task
+	var err error
+	if owner := taskOwner(claims); owner != "" {
+		task, err = taskRepo.FindByIDForUser(r.Context(), taskID, owner)
+	} else {
+		task, err = taskRepo.FindByID(r.Context(), taskID)
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch task")
+		return nil
+	}
+	if task == nil {
+		respondError(w, http.StatusNotFound, "task not found")
+		return nil
+	}
+	return task
+}
+
 // GetTask godoc
 // @Summary Get a task
 // @Description Get details of a specific task by ID
@@ -363,13 +816,8 @@ func (h *Handler) GetTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.taskRepo.FindByID(r.Context(), taskID)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "failed to fetch task")
-		return
-	}
+	task := h.findTask(w, r, taskID)
 	if task == nil {
-		respondError(w, http.StatusNotFound, "task not found")
 		return
 	}
 
@@ -380,22 +828,87 @@ func (h *Handler) GetTask(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, task)
 }
 
-// UpdateTask godoc
-// @Summary Update a task
-// @Description Update an existing task's configuration
+// maxNextRuns caps the count accepted by GetTaskNextRuns
+const maxNextRuns = 50
+
+// GetTaskNextRuns godoc
+// @Summary Preview upcoming runs
+// @Description List the next times the task's schedules fire, in the task's timezone, with the schedules as the scheduler reads them (shortcuts expanded, a seconds field added to five-field expressions). Paused tasks are previewed as if enabled.
 // @Tags Tasks
-// @Accept json
 // @Produce json
 // @Param id path string true "Task ID"
-// @Param request body model.UpdateTaskRequest true "Updated task configuration"
-// @Success 200 {object} model.Task
+// @Param count query int false "Number of runs to list (max 50)" default(5)
+// @Success 200 {object} model.NextRuns
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 404 {object} map[string]string "Task not found"
 // @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
 // @Security ApiKeyAuth
-// @Router /tasks/{id} [put]
+// @Router /tasks/{id}/next-runs [get]
+func (h *Handler) GetTaskNextRuns(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+
+	count := 5
+	if c := r.URL.Query().Get("count"); c != "" {
+		v, err := strconv.Atoi(c)
+		if err != nil || v < 1 || v > maxNextRuns {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("count must be between 1 and %d", maxNextRuns))
+			return
+		}
+		count = v
+	}
+
+	task := h.findTask(w, r, taskID)
+	if task == nil {
+		return
+	}
+
+	runs, err := scheduler.NextRuns(*task, count, time.Now())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, runs)
+}
+
+// validateSchedules checks a task's schedule, if set, and each of its extra schedules
+func validateSchedules(schedule string, schedules []string) error {
+	if schedule != "" {
+		if err := scheduler.ValidateSchedule(schedule); err != nil {
+			return err
+		}
+	}
+	for _, expr := range schedules {
+		if strings.TrimSpace(expr) == "" {
+			return errors.New("schedules must not contain empty expressions")
+		}
+	}
+	return scheduler.ValidateSchedule(schedules...)
+}
+
+// UpdateTask godoc
+// @Summary Update a task
+// @Description Update an existing task's configuration
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body model.UpdateTaskRequest true "Updated task configuration"
+// @Success 200 {object} model.Task
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 409 {object} map[string]string "Task is archived"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id} [put]
 func (h *Handler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	taskID := r.PathValue("id")
 	if taskID == "" {
@@ -403,104 +916,656 @@ func (h *Handler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req model.UpdateTaskRequest
+	var req model.UpdateTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.TimeoutSeconds != nil && *req.TimeoutSeconds < 0 {
+		respondError(w, http.StatusBadRequest, "timeout_seconds must not be negative")
+		return
+	}
+	if req.Status != nil && *req.Status == model.TaskStatusArchived {
+		respondError(w, http.StatusBadRequest, "archive a task by deleting it")
+		return
+	}
+	schedule := ""
+	if req.Schedule != nil {
+		schedule = *req.Schedule
+	}
+	if err := validateSchedules(schedule, req.Schedules); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Timezone != nil {
+		if err := scheduler.ValidateTimezone(*req.Timezone); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// Validate pipeline if provided
+	if req.Pipeline != nil {
+		if errs := h.runner.ValidatePipeline(req.Pipeline); len(errs) > 0 {
+			respondError(w, http.StatusBadRequest, errs[0].Error())
+			return
+		}
+	}
+
+	existing := h.findTask(w, r, taskID)
+	if existing == nil {
+		return
+	}
+	if existing.Status == model.TaskStatusArchived {
+		respondError(w, http.StatusConflict, scheduler.ErrTaskArchived.Error())
+		return
+	}
+
+	task, err := h.taskRepo.Update(r.Context(), taskID, &req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to update task")
+		return
+	}
+	if task == nil {
+		respondError(w, http.StatusNotFound, "task not found")
+		return
+	}
+
+	// Update scheduler
+	if err := h.scheduler.UpdateTask(*task); err != nil {
+		log.Printf("Warning: failed to reschedule task %s: %v", task.ID, err)
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
+
+// DryRunPipeline godoc
+// @Summary Dry-run a pipeline
+// @Description Execute a pipeline without saving a task or execution. Delivery steps (discord, slack, telegram, mastodon, email, webhook) aren't sent; they return what would be sent. Items are not deduplicated.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param request body []model.PipelineStep true "Pipeline steps"
+// @Success 200 {object} scheduler.DryRunResult
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 429 {object} map[string]string "Too many executions in progress"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/dry-run [post]
+func (h *Handler) DryRunPipeline(w http.ResponseWriter, r *http.Request) {
+	var pipeline []model.PipelineStep
+	if err := json.NewDecoder(r.Body).Decode(&pipeline); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(pipeline) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one pipeline step is required")
+		return
+	}
+	if errs := h.runner.ValidatePipeline(pipeline); len(errs) > 0 {
+		respondError(w, http.StatusBadRequest, errs[0].Error())
+		return
+	}
+
+	result, err := h.scheduler.DryRun(r.Context(), pipeline)
+	if errors.Is(err, scheduler.ErrTooManyExecutions) {
+		respondError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// EstimatePipeline godoc
+// @Summary Estimate a pipeline's cost
+// @Description Predict, per step, the upstream requests, AI tokens, cost and runtime of a pipeline without running it. Costs use the AI_PRICING config and are omitted for unpriced providers.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param request body []model.PipelineStep true "Pipeline steps"
+// @Success 200 {object} scheduler.PipelineEstimate
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pipeline/estimate [post]
+func (h *Handler) EstimatePipeline(w http.ResponseWriter, r *http.Request) {
+	var pipeline []model.PipelineStep
+	if err := json.NewDecoder(r.Body).Decode(&pipeline); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(pipeline) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one pipeline step is required")
+		return
+	}
+	if errs := h.runner.ValidatePipeline(pipeline); len(errs) > 0 {
+		respondError(w, http.StatusBadRequest, errs[0].Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.runner.Estimate(pipeline))
+}
+
+// PauseTask godoc
+// @Summary Pause a task
+// @Description Disable a task's schedule without touching its pipeline
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} map[string]string "New task status"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 409 {object} map[string]string "Task is archived"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/pause [post]
+func (h *Handler) PauseTask(w http.ResponseWriter, r *http.Request) {
+	h.setTaskStatus(w, r, model.TaskStatusDisabled)
+}
+
+// ResumeTask godoc
+// @Summary Resume a task
+// @Description Re-enable a paused task's schedule without touching its pipeline
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} map[string]string "New task status"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 409 {object} map[string]string "Task is archived"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/resume [post]
+func (h *Handler) ResumeTask(w http.ResponseWriter, r *http.Request) {
+	h.setTaskStatus(w, r, model.TaskStatusEnabled)
+}
+
+// setTaskStatus flips a task between enabled and disabled and reschedules it
+func (h *Handler) setTaskStatus(w http.ResponseWriter, r *http.Request, status model.TaskStatus) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+
+	task := h.findTask(w, r, taskID)
+	if task == nil {
+		return
+	}
+	if task.Status == model.TaskStatusArchived {
+		respondError(w, http.StatusConflict, scheduler.ErrTaskArchived.Error())
+		return
+	}
+
+	if err := h.taskRepo.UpdateStatus(r.Context(), taskID, status); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to update task status")
+		return
+	}
+	task.Status = status
+
+	if status == model.TaskStatusEnabled {
+		if err := h.scheduler.UpdateTask(*task); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		h.scheduler.RemoveTask(taskID)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": string(status)})
+}
+
+// DeleteTask godoc
+// @Summary Delete a task
+// @Description Archive a task, removing it from the scheduler and task listings while keeping its executions, or with hard=true delete it and its executions permanently
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param hard query bool false "Delete permanently instead of archiving"
+// @Success 200 {object} map[string]string "Deletion status: archived or deleted"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id} [delete]
+func (h *Handler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+	hard, err := queryBool(r, "hard")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
+
+	status := "deleted"
+	if hard {
+		if err := h.taskRepo.Delete(r.Context(), taskID); err != nil {
+			respondError(w, http.StatusNotFound, "task not found")
+			return
+		}
+	} else {
+		found, err := h.taskRepo.Archive(r.Context(), taskID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to archive task")
+			return
+		}
+		if !found {
+			respondError(w, http.StatusNotFound, "task not found")
+			return
+		}
+		status = string(model.TaskStatusArchived)
+	}
+
+	// Remove from scheduler
+	h.scheduler.RemoveTask(taskID)
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": status})
+}
+
+// RestoreTask godoc
+// @Summary Restore an archived task
+// @Description Bring an archived task back as disabled; resume it to schedule it again
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} model.Task
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 409 {object} map[string]string "Task is not archived"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/restore [post]
+func (h *Handler) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
+
+	task, err := h.taskRepo.Restore(r.Context(), taskID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to restore task")
+		return
+	}
+	if task == nil {
+		respondError(w, http.StatusConflict, "task is not archived")
+		return
+	}
+
+	// Restored tasks start disabled, so there is nothing to schedule yet
+	respondJSON(w, http.StatusOK, task)
+}
+
+// TriggerTask godoc
+// @Summary Trigger a task manually
+// @Description Execute a task immediately regardless of its schedule
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param force_refresh query bool false "Fetch sources unconditionally instead of using cached responses"
+// @Param bypass_dedup query bool false "Deliver items even if the task has already seen them"
+// @Success 200 {object} model.Execution
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 409 {object} map[string]string "Task is already running or archived"
+// @Failure 429 {object} map[string]string "Too many executions in progress"
+// @Failure 500 {object} map[string]string "Execution error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/run [post]
+func (h *Handler) TriggerTask(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
+
+	claims := middleware.GetUserFromContext(r.Context())
+	triggeredBy := "api"
+	if claims != nil {
+		triggeredBy = claims.UserID
+	}
+
+	forceRefresh, err := queryBool(r, "force_refresh")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	bypassDedup, err := queryBool(r, "bypass_dedup")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts := scheduler.RunOptions{ForceRefresh: forceRefresh, BypassDedup: bypassDedup}
+
+	execution, err := h.scheduler.TriggerTask(r.Context(), taskID, triggeredBy, opts)
+	if errors.Is(err, scheduler.ErrTaskRunning) || errors.Is(err, scheduler.ErrTaskArchived) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, scheduler.ErrTooManyExecutions) {
+		respondError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, execution)
+}
+
+// maxBulkTasks caps how many tasks one bulk request may touch
+const maxBulkTasks = 100
+
+// uuidPattern matches task IDs, so malformed ones are reported per task
+// instead of failing the whole statement
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// BulkTasks godoc
+// @Summary Apply an action to several tasks
+// @Description Enable, disable, delete or trigger up to 100 tasks at once. Delete archives the tasks unless hard is set. Archived tasks can't be enabled or disabled. Status changes and deletes run as a single statement; triggers run concurrently. Each task gets its own result so partial failures are reported; other users' tasks are reported as not found unless the caller is an admin.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param request body model.BulkTaskRequest true "Action and task IDs"
+// @Success 200 {object} model.BulkTaskResponse
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "API key lacks the tasks:trigger scope"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/bulk [post]
+func (h *Handler) BulkTasks(w http.ResponseWriter, r *http.Request) {
+	var req model.BulkTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// Keep the first occurrence of each ID
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range req.TaskIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		respondError(w, http.StatusBadRequest, "task_ids are required")
+		return
+	}
+	if len(ids) > maxBulkTasks {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("at most %d task_ids per request", maxBulkTasks))
+		return
+	}
+
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	results := make(map[string]*model.BulkTaskResult, len(ids))
+	var valid []string
+	for _, id := range ids {
+		results[id] = &model.BulkTaskResult{TaskID: id}
+		if uuidPattern.MatchString(id) {
+			valid = append(valid, id)
+		} else {
+			results[id].Error = "invalid task ID"
+		}
+	}
+
+	// Other users' tasks are left out and reported as not found
+	if owner := taskOwner(claims); owner != "" && len(valid) > 0 {
+		owned, err := h.taskRepo.FilterOwned(r.Context(), valid, owner)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to fetch tasks")
+			return
+		}
+		valid = owned
+	}
+
+	switch req.Action {
+	case model.BulkActionEnable, model.BulkActionDisable:
+		status := model.TaskStatusEnabled
+		if req.Action == model.BulkActionDisable {
+			status = model.TaskStatusDisabled
+		}
+		tasks, err := h.taskRepo.SetStatusMany(r.Context(), valid, status)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to update tasks")
+			return
+		}
+		for _, task := range tasks {
+			res := results[task.ID]
+			res.Success = true
+			if status == model.TaskStatusEnabled {
+				if err := h.scheduler.UpdateTask(task); err != nil {
+					res.Success = false
+					res.Error = err.Error()
+				}
+			} else {
+				h.scheduler.RemoveTask(task.ID)
+			}
+		}
+	case model.BulkActionDelete:
+		// Archive unless asked to delete permanently, like DeleteTask
+		var deleted []string
+		var err error
+		if req.Hard {
+			deleted, err = h.taskRepo.DeleteMany(r.Context(), valid)
+		} else {
+			deleted, err = h.taskRepo.ArchiveMany(r.Context(), valid)
+		}
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to delete tasks")
+			return
+		}
+		for _, id := range deleted {
+			results[id].Success = true
+			h.scheduler.RemoveTask(id)
+		}
+	case model.BulkActionTrigger:
+		if !claims.HasScope(model.ScopeTasksTrigger) {
+			respondError(w, http.StatusForbidden, "API key lacks the "+model.ScopeTasksTrigger+" scope")
+			return
+		}
+		triggeredBy := claims.UserID
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		for _, id := range valid {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				execution, err := h.scheduler.TriggerTask(r.Context(), id, triggeredBy, scheduler.RunOptions{})
+
+				mu.Lock()
+				defer mu.Unlock()
+				res := results[id]
+				if execution != nil {
+					res.ExecutionID = execution.ID
+				}
+				if err != nil {
+					res.Error = err.Error()
+					return
+				}
+				res.Success = true
+			}(id)
+		}
+		wg.Wait()
+	default:
+		respondError(w, http.StatusBadRequest, "action must be one of enable, disable, delete, trigger")
+		return
+	}
+
+	resp := model.BulkTaskResponse{Action: req.Action, Results: make([]model.BulkTaskResult, 0, len(ids))}
+	for _, id := range ids {
+		res := results[id]
+		if !res.Success && res.Error == "" {
+			res.Error = "task not found"
+		}
+		if res.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+		resp.Results = append(resp.Results, *res)
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// Execution handlers
+
+// SetTaskSecrets godoc
+// @Summary Set task secrets
+// @Description Create, update or delete (empty value) encrypted secrets for a task. Steps reference them by name with the secret template function; values are never returned.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body model.SetTaskSecretsRequest true "Secrets by name"
+// @Success 200 {object} map[string]interface{} "Stored secret names"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/secrets [put]
+func (h *Handler) SetTaskSecrets(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+
+	var req model.SetTaskSecretsRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
-
-	// Validate pipeline if provided
-	if req.Pipeline != nil {
-		if errs := h.runner.ValidatePipeline(req.Pipeline); len(errs) > 0 {
-			respondError(w, http.StatusBadRequest, errs[0].Error())
+	if len(req.Secrets) == 0 {
+		respondError(w, http.StatusBadRequest, "secrets are required")
+		return
+	}
+	for name := range req.Secrets {
+		if !secretNamePattern.MatchString(name) {
+			respondError(w, http.StatusBadRequest, "secret names may only contain letters, digits, '_', '-' and '.'")
 			return
 		}
 	}
 
-	task, err := h.taskRepo.Update(r.Context(), taskID, &req)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "failed to update task")
-		return
-	}
+	task := h.findTask(w, r, taskID)
 	if task == nil {
-		respondError(w, http.StatusNotFound, "task not found")
 		return
 	}
 
-	// Update scheduler
-	h.scheduler.UpdateTask(*task)
+	if err := h.secretRepo.Set(r.Context(), taskID, req.Secrets); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to store secrets")
+		return
+	}
 
-	respondJSON(w, http.StatusOK, task)
+	h.respondTaskSecrets(w, r, taskID)
 }
 
-// DeleteTask godoc
-// @Summary Delete a task
-// @Description Delete a task and remove it from the scheduler
+// GetTaskSecrets godoc
+// @Summary List task secrets
+// @Description List the names of a task's secrets. Values are never returned.
 // @Tags Tasks
 // @Produce json
 // @Param id path string true "Task ID"
-// @Success 200 {object} map[string]string "Deletion status"
-// @Failure 400 {object} map[string]string "Invalid request"
+// @Success 200 {object} map[string]interface{} "Stored secret names"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
 // @Security ApiKeyAuth
-// @Router /tasks/{id} [delete]
-func (h *Handler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+// @Router /tasks/{id}/secrets [get]
+func (h *Handler) GetTaskSecrets(w http.ResponseWriter, r *http.Request) {
 	taskID := r.PathValue("id")
-	if taskID == "" {
-		respondError(w, http.StatusBadRequest, "task ID required")
-		return
-	}
-
-	if err := h.taskRepo.Delete(r.Context(), taskID); err != nil {
-		respondError(w, http.StatusNotFound, "task not found")
+	if h.findTask(w, r, taskID) == nil {
 		return
 	}
 
-	// Remove from scheduler
-	h.scheduler.RemoveTask(taskID)
-
-	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+	h.respondTaskSecrets(w, r, taskID)
 }
 
-// TriggerTask godoc
-// @Summary Trigger a task manually
-// @Description Execute a task immediately regardless of its schedule
+// DeleteTaskSecret godoc
+// @Summary Delete a task secret
 // @Tags Tasks
 // @Produce json
 // @Param id path string true "Task ID"
-// @Success 200 {object} model.Execution
-// @Failure 400 {object} map[string]string "Invalid request"
+// @Param name path string true "Secret name"
+// @Success 200 {object} map[string]string "Deletion status"
 // @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 500 {object} map[string]string "Execution error"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
 // @Security ApiKeyAuth
-// @Router /tasks/{id}/run [post]
-func (h *Handler) TriggerTask(w http.ResponseWriter, r *http.Request) {
+// @Router /tasks/{id}/secrets/{name} [delete]
+func (h *Handler) DeleteTaskSecret(w http.ResponseWriter, r *http.Request) {
 	taskID := r.PathValue("id")
-	if taskID == "" {
-		respondError(w, http.StatusBadRequest, "task ID required")
+	if h.findTask(w, r, taskID) == nil {
 		return
 	}
 
-	claims := middleware.GetUserFromContext(r.Context())
-	triggeredBy := "api"
-	if claims != nil {
-		triggeredBy = claims.UserID
+	if err := h.secretRepo.Delete(r.Context(), taskID, r.PathValue("name")); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to delete secret")
+		return
 	}
 
-	execution, err := h.scheduler.TriggerTask(r.Context(), taskID, triggeredBy)
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (h *Handler) respondTaskSecrets(w http.ResponseWriter, r *http.Request, taskID string) {
+	secrets, err := h.secretRepo.List(r.Context(), taskID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondError(w, http.StatusInternalServerError, "failed to list secrets")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, execution)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"secrets": secrets,
+		"total":   len(secrets),
+	})
 }
 
-// Execution handlers
-
 // GetTaskExecutions godoc
 // @Summary Get task executions
 // @Description Get paginated list of executions for a specific task
@@ -508,10 +1573,16 @@ func (h *Handler) TriggerTask(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "Task ID"
 // @Param limit query int false "Number of executions to return" default(20)
-// @Param offset query int false "Offset for pagination" default(0)
-// @Success 200 {object} map[string]interface{} "Executions list"
+// @Param offset query int false "Offset for pagination; prefer before for deep pages" default(0)
+// @Param before query string false "Cursor from a previous page's next_cursor; overrides offset"
+// @Param trigger_type query string false "Only executions with this trigger type" Enums(schedule, manual, replay, webhook)
+// @Param status query string false "Only executions with this status" Enums(pending, running, completed, failed)
+// @Param since query string false "Only executions started at or after this RFC 3339 time or YYYY-MM-DD date (UTC)"
+// @Param until query string false "Only executions started before this RFC 3339 time, or on or before this YYYY-MM-DD date (UTC)"
+// @Success 200 {object} map[string]interface{} "Executions list with next_cursor, empty on the last page"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
 // @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -522,6 +1593,9 @@ func (h *Handler) GetTaskExecutions(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "task ID required")
 		return
 	}
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
 
 	limit := 20
 	offset := 0
@@ -537,19 +1611,43 @@ func (h *Handler) GetTaskExecutions(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	executions, err := h.execRepo.FindByTaskID(r.Context(), taskID, limit, offset)
+	filter, ok := parseExecutionFilter(w, r)
+	if !ok {
+		return
+	}
+	if b := r.URL.Query().Get("before"); b != "" {
+		cursor, err := model.ParseExecutionCursor(b)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Before = cursor
+		offset = 0
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	executions, err := h.execRepo.FindByTaskIDFiltered(r.Context(), taskID, filter)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to fetch executions")
 		return
 	}
 
-	total, _ := h.execRepo.CountByTaskID(r.Context(), taskID)
+	filter.TaskID = taskID
+	total, _ := h.execRepo.CountFiltered(r.Context(), filter)
+
+	// A full page may have more after it
+	nextCursor := ""
+	if limit > 0 && len(executions) == limit {
+		nextCursor = model.CursorAfter(executions[len(executions)-1]).String()
+	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"executions": executions,
-		"total":      total,
-		"limit":      limit,
-		"offset":     offset,
+		"executions":  executions,
+		"total":       total,
+		"limit":       limit,
+		"offset":      offset,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -563,24 +1661,28 @@ func (h *Handler) GetTaskExecutions(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} model.Execution
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Execution not found"
+// @Failure 404 {object} map[string]string "Task or execution not found"
 // @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
 // @Security ApiKeyAuth
 // @Router /tasks/{id}/executions/{execId} [get]
 func (h *Handler) GetExecution(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
 	execID := r.PathValue("execId")
 	if execID == "" {
 		respondError(w, http.StatusBadRequest, "execution ID required")
 		return
 	}
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
 
 	execution, err := h.execRepo.FindByID(r.Context(), execID)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to fetch execution")
 		return
 	}
-	if execution == nil {
+	if execution == nil || execution.TaskID != taskID {
 		respondError(w, http.StatusNotFound, "execution not found")
 		return
 	}
@@ -588,13 +1690,99 @@ func (h *Handler) GetExecution(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, execution)
 }
 
+// ResumeExecution godoc
+// @Summary Resume an execution from a step
+// @Description Re-run a task's pipeline from the given step, using the output captured from the previous step of an earlier execution as input. The previous step must have capture_output enabled.
+// @Tags Executions
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param execId path string true "Execution ID"
+// @Param from_step query int true "1-based step number to resume from"
+// @Success 200 {object} model.Execution
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task or execution not found"
+// @Failure 409 {object} map[string]string "Task is already running or archived"
+// @Failure 429 {object} map[string]string "Too many executions in progress"
+// @Failure 500 {object} map[string]string "Execution error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/executions/{execId}/resume [post]
+func (h *Handler) ResumeExecution(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	execID := r.PathValue("execId")
+	if taskID == "" || execID == "" {
+		respondError(w, http.StatusBadRequest, "task ID and execution ID required")
+		return
+	}
+
+	task := h.findTask(w, r, taskID)
+	if task == nil {
+		return
+	}
+
+	execution, err := h.execRepo.FindByID(r.Context(), execID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch execution")
+		return
+	}
+	if execution == nil || execution.TaskID != taskID {
+		respondError(w, http.StatusNotFound, "execution not found")
+		return
+	}
+
+	// Steps are numbered from 1 in the API; the first step has no prior output
+	fromStep, err := strconv.Atoi(r.URL.Query().Get("from_step"))
+	if err != nil || fromStep < 2 || fromStep > len(task.Pipeline) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("from_step must be between 2 and %d", len(task.Pipeline)))
+		return
+	}
+
+	input, err := h.runner.CapturedOutput(r.Context(), execID, fromStep-2)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load captured output")
+		return
+	}
+	if input == nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("no captured output for step %d; enable capture_output on that step", fromStep-1))
+		return
+	}
+
+	claims := middleware.GetUserFromContext(r.Context())
+	triggeredBy := "api"
+	if claims != nil {
+		triggeredBy = claims.UserID
+	}
+
+	resumed, err := h.scheduler.ResumeTask(r.Context(), *task, triggeredBy, fromStep-1, input)
+	if errors.Is(err, scheduler.ErrTaskRunning) || errors.Is(err, scheduler.ErrTaskArchived) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, scheduler.ErrTooManyExecutions) {
+		respondError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resumed)
+}
+
 // GetRecentExecutions godoc
 // @Summary Get recent executions
-// @Description Get the most recent executions across all tasks
+// @Description Get the most recent executions across the caller's tasks, or all tasks for admins
 // @Tags Executions
 // @Produce json
 // @Param limit query int false "Number of executions to return" default(20)
+// @Param trigger_type query string false "Only executions with this trigger type" Enums(schedule, manual, replay, webhook)
+// @Param status query string false "Only executions with this status" Enums(pending, running, completed, failed)
+// @Param since query string false "Only executions started at or after this RFC 3339 time or YYYY-MM-DD date (UTC)"
+// @Param until query string false "Only executions started before this RFC 3339 time, or on or before this YYYY-MM-DD date (UTC)"
 // @Success 200 {object} map[string]interface{} "Recent executions"
+// @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Server error"
 // @Security BearerAuth
@@ -608,7 +1796,16 @@ func (h *Handler) GetRecentExecutions(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	executions, err := h.execRepo.FindRecent(r.Context(), limit)
+	filter, ok := parseExecutionFilter(w, r)
+	if !ok {
+		return
+	}
+	filter.Limit = limit
+	if claims := middleware.GetUserFromContext(r.Context()); claims != nil {
+		filter.OwnerID = taskOwner(claims)
+	}
+
+	executions, err := h.execRepo.FindRecentFiltered(r.Context(), filter)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to fetch executions")
 		return
@@ -619,22 +1816,288 @@ func (h *Handler) GetRecentExecutions(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// pageParams reads the limit and offset query parameters, ignoring values
+// that aren't numbers
+func pageParams(r *http.Request, defaultLimit int) (int, int) {
+	limit := defaultLimit
+	offset := 0
+
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil {
+			limit = v
+		}
+	}
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if v, err := strconv.Atoi(o); err == nil {
+			offset = v
+		}
+	}
+	return limit, offset
+}
+
+// queryBool reads an optional boolean query parameter, false when absent
+func queryBool(r *http.Request, name string) (bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return b, nil
+}
+
+// parseTriggerType reads the optional trigger_type filter, responding with
+// 400 and returning false if it isn't a known trigger type
+func parseTriggerType(w http.ResponseWriter, r *http.Request) (model.TriggerType, bool) {
+	triggerType := model.TriggerType(r.URL.Query().Get("trigger_type"))
+	if triggerType != "" && !triggerType.Valid() {
+		respondError(w, http.StatusBadRequest, "trigger_type must be one of schedule, manual, replay, webhook")
+		return "", false
+	}
+	return triggerType, true
+}
+
+// parseExecutionFilter reads the trigger_type, status, since and until
+// query parameters, writing a 400 and returning false if one is invalid
+func parseExecutionFilter(w http.ResponseWriter, r *http.Request) (model.ExecutionFilter, bool) {
+	var filter model.ExecutionFilter
+	var ok bool
+	if filter.TriggerType, ok = parseTriggerType(w, r); !ok {
+		return filter, false
+	}
+
+	filter.Status = model.ExecutionStatus(r.URL.Query().Get("status"))
+	if filter.Status != "" && !filter.Status.Valid() {
+		respondError(w, http.StatusBadRequest, "status must be one of pending, running, completed, failed")
+		return filter, false
+	}
+
+	var err error
+	if filter.Since, err = parseTimeBound(r.URL.Query().Get("since"), false); err != nil {
+		respondError(w, http.StatusBadRequest, "since must be an RFC 3339 time or a YYYY-MM-DD date")
+		return filter, false
+	}
+	if filter.Until, err = parseTimeBound(r.URL.Query().Get("until"), true); err != nil {
+		respondError(w, http.StatusBadRequest, "until must be an RFC 3339 time or a YYYY-MM-DD date")
+		return filter, false
+	}
+	if filter.Since != nil && filter.Until != nil && !filter.Until.After(*filter.Since) {
+		respondError(w, http.StatusBadRequest, "until must be after since")
+		return filter, false
+	}
+
+	return filter, true
+}
+
+// parseTimeBound parses an RFC 3339 time or a YYYY-MM-DD date in UTC, nil
+// when empty. An upper date bound moves to the next midnight so it includes
+// that whole day.
+func parseTimeBound(v string, upper bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		day, dayErr := time.Parse("2006-01-02", v)
+		if dayErr != nil {
+			return nil, dayErr
+		}
+		t = day
+		if upper {
+			t = day.AddDate(0, 0, 1)
+		}
+	}
+	return &t, nil
+}
+
+// GetItemAnalytics godoc
+// @Summary Delivered item analytics
+// @Description Daily counts of delivered items grouped by category, source or task. Admins see all tasks; other users see their own.
+// @Tags Analytics
+// @Produce json
+// @Param group_by query string false "category, source or task" default(category)
+// @Param window query string false "Look-back window in days, e.g. 30d" default(30d)
+// @Success 200 {object} map[string]interface{} "Item counts per day and group"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /analytics/items [get]
+func (h *Handler) GetItemAnalytics(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" {
+		groupBy = "category"
+	}
+	if groupBy != "category" && groupBy != "source" && groupBy != "task" {
+		respondError(w, http.StatusBadRequest, "group_by must be category, source or task")
+		return
+	}
+
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = "30d"
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(window, "d"))
+	if err != nil || days < 1 || days > 365 {
+		respondError(w, http.StatusBadRequest, "window must be between 1d and 365d")
+		return
+	}
+	since := time.Now().AddDate(0, 0, -(days - 1))
+
+	// Admins see every task; everyone else only their own
+	ownerID := claims.UserID
+	if claims.Role == model.UserRoleAdmin {
+		ownerID = ""
+	}
+
+	stats, err := h.statsRepo.ItemCounts(r.Context(), groupBy, since, ownerID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch analytics")
+		return
+	}
+
+	totals := make(map[string]int)
+	for _, s := range stats {
+		totals[s.Group] += s.Count
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"group_by": groupBy,
+		"window":   fmt.Sprintf("%dd", days),
+		"series":   stats,
+		"totals":   totals,
+	})
+}
+
 // Health and status handlers
 
+// healthCheckTimeout bounds the database ping so a hung connection fails
+// the check instead of stalling the load balancer's probe
+const healthCheckTimeout = 2 * time.Second
+
+// databaseUp pings the database within healthCheckTimeout
+func (h *Handler) databaseUp(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	if err := h.db.Ping(ctx); err != nil {
+		log.Printf("Health check: database ping failed: %v", err)
+		return false
+	}
+	return true
+}
+
 // Health godoc
 // @Summary Health check
-// @Description Check if the API is running
+// @Description Check that the API can reach the database; returns 503 when it can't
 // @Tags System
 // @Produce json
 // @Success 200 {object} map[string]interface{} "Health status"
+// @Failure 503 {object} map[string]interface{} "Database unreachable"
 // @Router /health [get]
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
+	if !h.databaseUp(r.Context()) {
+		respondJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"status":    "degraded",
+			"database":  "down",
+			"scheduler": h.scheduler.IsRunning(),
+		})
+		return
+	}
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"status":    "ok",
+		"database":  "up",
 		"scheduler": h.scheduler.IsRunning(),
 	})
 }
 
+// Liveness reports that the process is up and serving requests, without
+// checking dependencies, so an orchestrator doesn't restart it over a
+// database outage. Served at /healthz.
+func (h *Handler) Liveness(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readiness reports whether the instance should receive traffic: the
+// database must answer and the scheduler must be running, so an instance
+// shutting down is taken out of rotation. Served at /readyz.
+func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
+	database := "up"
+	if !h.databaseUp(r.Context()) {
+		database = "down"
+	}
+	running := h.scheduler.IsRunning()
+
+	status, code := "ready", http.StatusOK
+	if database == "down" || !running {
+		status, code = "not_ready", http.StatusServiceUnavailable
+	}
+	respondJSON(w, code, map[string]interface{}{
+		"status":    status,
+		"database":  database,
+		"scheduler": running,
+	})
+}
+
+// OpenAPISpec godoc
+// @Summary OpenAPI specification
+// @Description The generated Swagger 2.0 spec of this API, for client code generation
+// @Tags System
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Swagger 2.0 document"
+// @Failure 500 {object} map[string]string "Spec not available"
+// @Router /openapi.json [get]
+func (h *Handler) OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	spec, err := swag.ReadDoc()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "API specification not available")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(spec))
+}
+
+// ListScrapers godoc
+// @Summary List scraper sources
+// @Description List the sources and categories a scraper step's source, sources and category options accept
+// @Tags System
+// @Produce json
+// @Success 200 {object} model.ScraperList
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /scrapers [get]
+func (h *Handler) ListScrapers(w http.ResponseWriter, r *http.Request) {
+	categories := h.scrapers.AvailableByCategory()
+	for _, names := range categories {
+		sort.Strings(names)
+	}
+
+	names := h.scrapers.Available()
+	sort.Strings(names)
+	sources := make([]model.ScraperSource, 0, len(names))
+	for _, name := range names {
+		source, err := h.scrapers.Get(name)
+		if err != nil {
+			continue
+		}
+		_, streaming := source.(scraper.StreamingSource)
+		sources = append(sources, model.ScraperSource{Name: name, Category: source.Category(), Streaming: streaming})
+	}
+
+	respondJSON(w, http.StatusOK, model.ScraperList{Sources: sources, Categories: categories})
+}
+
 // Status godoc
 // @Summary System status
 // @Description Get detailed system status including task and execution counts
@@ -655,11 +2118,15 @@ func (h *Handler) Status(w http.ResponseWriter, r *http.Request) {
 	runningCount, _ := h.execRepo.CountByStatus(r.Context(), runningStatus)
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"scheduler_running": h.scheduler.IsRunning(),
-		"total_tasks":       taskCount,
-		"enabled_tasks":     enabledCount,
-		"scheduled_tasks":   len(scheduledTasks),
-		"running_executions": runningCount,
+		"scheduler_running":         h.scheduler.IsRunning(),
+		"total_tasks":               taskCount,
+		"enabled_tasks":             enabledCount,
+		"scheduled_tasks":           len(scheduledTasks),
+		"running_executions":        runningCount,
+		"scheduler_active":          h.scheduler.RunningCount(),
+		"max_concurrent":            h.scheduler.MaxConcurrent(),
+		"executions_in_flight":      h.scheduler.InFlightCount(),
+		"max_concurrent_executions": h.scheduler.MaxExecutions(),
 	})
 }
 
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/executor/rss"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/scheduler"
+	"github.com/multi-worker/internal/storage"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+const testFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Jobs</title>
+<item><title>Go developer</title><link>https://example.com/jobs/1</link><guid>1</guid></item>
+<item><title>Rust developer</title><link>https://example.com/jobs/2</link><guid>2</guid></item>
+</channel></rss>`
+
+func TestTriggerTaskForceRefresh(t *testing.T) {
+	db := storagetest.Open(t)
+	h := newTestHandler(t, db)
+	cacheRepo := storage.NewCacheRepository(db)
+	h.runner = scheduler.NewPipelineRunner(h.taskRepo, h.execRepo, cacheRepo, nil, h.secretRepo, h.statsRepo,
+		nil, nil, nil, rss.NewExecutor(cacheRepo), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	h.scheduler = scheduler.NewScheduler(h.taskRepo, h.execRepo, h.runner, config.SchedulerConfig{})
+
+	// The feed answers conditional requests with 304, like most feed hosts
+	var mu sync.Mutex
+	var fetches, conditional int
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(testFeed))
+	}))
+	defer feed.Close()
+
+	owner := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, owner.ID, model.PipelineStep{
+		Type:   "rss",
+		Config: map[string]interface{}{"urls": []interface{}{feed.URL}},
+	})
+
+	// trigger runs the task and returns the execution with its source's item count
+	trigger := func(query string) (*model.Execution, int) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+task.ID+"/run"+query, nil)
+		rec := asUser(h.TriggerTask, req, owner, map[string]string{"id": task.ID})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("trigger%s: got %d: %s", query, rec.Code, rec.Body.String())
+		}
+		var execution model.Execution
+		if err := json.Unmarshal(rec.Body.Bytes(), &execution); err != nil {
+			t.Fatal(err)
+		}
+		if len(execution.StepResults) != 1 {
+			t.Fatalf("trigger%s: %d step results, want 1", query, len(execution.StepResults))
+		}
+		output, _ := execution.StepResults[0].Output.(map[string]interface{})
+		count, _ := output["item_count"].(float64)
+		return &execution, int(count)
+	}
+	hits := func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return fetches, conditional
+	}
+
+	if _, items := trigger(""); items != 2 {
+		t.Fatalf("first run found %d items, want 2", items)
+	}
+
+	// A normal run trusts the cached validators, and nothing is new
+	execution, items := trigger("")
+	if f, c := hits(); f != 2 || c != 1 || items != 0 || execution.Forced {
+		t.Errorf("normal run: %d fetches (%d conditional), %d items, forced %v; want a 304 and nothing new", f, c, items, execution.Forced)
+	}
+
+	// force_refresh refetches the feed, but dedup still drops what was sent
+	execution, items = trigger("?force_refresh=true")
+	if f, c := hits(); f != 3 || c != 1 || items != 0 || !execution.Forced {
+		t.Errorf("forced refresh: %d fetches (%d conditional), %d items, forced %v; want an unconditional fetch", f, c, items, execution.Forced)
+	}
+
+	// With bypass_dedup as well, the user sees everything currently available
+	execution, items = trigger("?force_refresh=true&bypass_dedup=true")
+	if f, c := hits(); f != 4 || c != 1 || items != 2 || !execution.Forced {
+		t.Errorf("forced run: %d fetches (%d conditional), %d items, forced %v; want both items again", f, c, items, execution.Forced)
+	}
+
+	// Bypassing dedup doesn't record the items as seen
+	if _, items := trigger("?force_refresh=true"); items != 0 {
+		t.Errorf("run after a forced run found %d items, want 0", items)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+task.ID+"/run?force_refresh=maybe", nil)
+	if rec := asUser(h.TriggerTask, req, owner, map[string]string{"id": task.ID}); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid force_refresh: got %d, want 400", rec.Code)
+	}
+}
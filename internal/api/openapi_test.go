@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	_ "github.com/multi-worker/docs"
+)
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&Handler{}).OpenAPISpec(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("got %d (%s)", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	var spec struct {
+		Swagger  string                            `json:"swagger"`
+		BasePath string                            `json:"basePath"`
+		Paths    map[string]map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+	if spec.Swagger != "2.0" || spec.BasePath != "/api/v1" {
+		t.Errorf("swagger %q, basePath %q", spec.Swagger, spec.BasePath)
+	}
+
+	// Every API route the router registers is documented with its method
+	src, err := os.ReadFile("router.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := regexp.MustCompile(`"(GET|POST|PUT|PATCH|DELETE) /api/v1(/[^"]*)"`).FindAllStringSubmatch(string(src), -1)
+	if len(routes) < 50 {
+		t.Fatalf("found only %d routes in router.go", len(routes))
+	}
+	for _, route := range routes {
+		method, path := strings.ToLower(route[1]), route[2]
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("%s /api/v1%s is not in the spec", route[1], path)
+		}
+	}
+}
@@ -3,14 +3,20 @@ package api
 import (
 	"net/http"
 
+	"github.com/multi-worker/internal/config"
 	"github.com/multi-worker/internal/middleware"
+	"github.com/multi-worker/internal/model"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 // NewRouter creates a new HTTP router with all routes
-func NewRouter(h *Handler, dh *DiscordHandler, auth *middleware.AuthMiddleware) http.Handler {
+func NewRouter(h *Handler, dh *DiscordHandler, auth *middleware.AuthMiddleware, limits config.RateLimitConfig) http.Handler {
 	mux := http.NewServeMux()
 
+	// Credential endpoints get a stricter limit against password guessing,
+	// on top of the global one
+	authLimit := middleware.RateLimit(limits.AuthRPS, limits.AuthBurst)
+
 	// Swagger documentation
 	mux.Handle("/swagger/", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
@@ -20,120 +26,126 @@ func NewRouter(h *Handler, dh *DiscordHandler, auth *middleware.AuthMiddleware)
 	))
 
 	// Public routes
-	mux.HandleFunc("POST /api/v1/auth/register", h.Register)
-	mux.HandleFunc("POST /api/v1/auth/login", h.Login)
+	mux.Handle("POST /api/v1/auth/register", authLimit(http.HandlerFunc(h.Register)))
+	mux.Handle("POST /api/v1/auth/login", authLimit(http.HandlerFunc(h.Login)))
+	mux.Handle("POST /api/v1/auth/refresh", authLimit(http.HandlerFunc(h.RefreshToken)))
+	mux.Handle("POST /api/v1/auth/forgot-password", authLimit(http.HandlerFunc(h.ForgotPassword)))
+	mux.Handle("POST /api/v1/auth/reset-password", authLimit(http.HandlerFunc(h.ResetPassword)))
+	mux.HandleFunc("POST /api/v1/auth/logout", h.Logout)
 	mux.HandleFunc("GET /api/v1/health", h.Health)
-
-	// Mount protected routes with authentication
+	mux.HandleFunc("GET /healthz", h.Liveness)
+	mux.HandleFunc("GET /readyz", h.Readiness)
+	mux.HandleFunc("GET /api/v1/openapi.json", h.OpenAPISpec)
+	mux.HandleFunc("GET /api/v1/shared/{token}", h.GetSharedTask)
+
+	// Inbound webhooks authenticate by the task's secret in the path
+	mux.HandleFunc("POST /api/v1/tasks/{id}/webhook/{secret}", h.HandleTaskWebhook)
+
+	// Discord interactions authenticate by the bot's request signature
+	mux.HandleFunc("POST /api/v1/discord/interactions/{botId}", dh.HandleInteraction)
+
+	// Mount protected routes with authentication. Scoped API keys reach a
+	// route only with its scope; JWTs and personal API keys reach them all.
+	// Once validated, each API key also gets a bucket of its own.
+	keyLimit := middleware.RateLimitAPIKeys(limits.RPS, limits.Burst)
+	authenticated := func(handler http.Handler) http.Handler {
+		return auth.Authenticate(keyLimit(handler))
+	}
+	scoped := func(scope string, handler http.HandlerFunc) http.Handler {
+		return authenticated(auth.RequireScope(scope)(handler))
+	}
+	fullAccess := func(handler http.HandlerFunc) http.Handler {
+		return authenticated(auth.RequireFullAccess(handler))
+	}
 
 	// User routes
-	mux.Handle("/api/v1/auth/profile", auth.Authenticate(http.HandlerFunc(h.GetProfile)))
-	mux.Handle("/api/v1/auth/api-key/regenerate", auth.Authenticate(http.HandlerFunc(h.RegenerateAPIKey)))
+	mux.Handle("/api/v1/auth/profile", authenticated(http.HandlerFunc(h.GetProfile)))
+	mux.Handle("/api/v1/auth/api-key/regenerate", fullAccess(h.RegenerateAPIKey))
+	mux.Handle("POST /api/v1/auth/change-password", authLimit(fullAccess(h.ChangePassword)))
+	mux.Handle("PUT /api/v1/users/{id}/active", authenticated(auth.RequireAdmin(auth.RequireFullAccess(http.HandlerFunc(h.SetUserActive)))))
+
+	// Scoped API key routes
+	mux.Handle("POST /api/v1/auth/api-keys", fullAccess(h.CreateAPIKey))
+	mux.Handle("GET /api/v1/auth/api-keys", fullAccess(h.ListAPIKeys))
+	mux.Handle("DELETE /api/v1/auth/api-keys/{keyId}", fullAccess(h.RevokeAPIKey))
 
 	// Task routes
-	mux.Handle("/api/v1/tasks", auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			h.CreateTask(w, r)
-		case http.MethodGet:
-			h.GetTasks(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})))
-
-	mux.Handle("/api/v1/tasks/{id}", auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			h.GetTask(w, r)
-		case http.MethodPut:
-			h.UpdateTask(w, r)
-		case http.MethodDelete:
-			h.DeleteTask(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})))
-
-	mux.Handle("/api/v1/tasks/{id}/run", auth.Authenticate(http.HandlerFunc(h.TriggerTask)))
-	mux.Handle("/api/v1/tasks/{id}/executions", auth.Authenticate(http.HandlerFunc(h.GetTaskExecutions)))
-	mux.Handle("/api/v1/tasks/{id}/executions/{execId}", auth.Authenticate(http.HandlerFunc(h.GetExecution)))
+	mux.Handle("POST /api/v1/tasks", scoped(model.ScopeTasksWrite, h.CreateTask))
+	mux.Handle("GET /api/v1/tasks", scoped(model.ScopeTasksRead, h.GetTasks))
+	mux.Handle("GET /api/v1/tasks/{id}", scoped(model.ScopeTasksRead, h.GetTask))
+	mux.Handle("PUT /api/v1/tasks/{id}", scoped(model.ScopeTasksWrite, h.UpdateTask))
+	mux.Handle("DELETE /api/v1/tasks/{id}", scoped(model.ScopeTasksWrite, h.DeleteTask))
+
+	mux.Handle("POST /api/v1/tasks/bulk", scoped(model.ScopeTasksWrite, h.BulkTasks))
+	mux.Handle("POST /api/v1/tasks/dry-run", scoped(model.ScopeTasksWrite, h.DryRunPipeline))
+	mux.Handle("POST /api/v1/pipeline/estimate", scoped(model.ScopeTasksWrite, h.EstimatePipeline))
+	mux.Handle("/api/v1/tasks/{id}/run", scoped(model.ScopeTasksTrigger, h.TriggerTask))
+	mux.Handle("POST /api/v1/tasks/{id}/clone", scoped(model.ScopeTasksWrite, h.CloneTask))
+	mux.Handle("POST /api/v1/tasks/{id}/restore", scoped(model.ScopeTasksWrite, h.RestoreTask))
+	mux.Handle("GET /api/v1/tasks/{id}/next-runs", scoped(model.ScopeTasksRead, h.GetTaskNextRuns))
+	mux.Handle("POST /api/v1/tasks/{id}/pause", scoped(model.ScopeTasksWrite, h.PauseTask))
+	mux.Handle("POST /api/v1/tasks/{id}/resume", scoped(model.ScopeTasksWrite, h.ResumeTask))
+	mux.Handle("/api/v1/tasks/{id}/executions", scoped(model.ScopeTasksRead, h.GetTaskExecutions))
+	mux.Handle("/api/v1/tasks/{id}/executions/{execId}", scoped(model.ScopeTasksRead, h.GetExecution))
+	mux.Handle("POST /api/v1/tasks/{id}/executions/{execId}/resume", scoped(model.ScopeTasksTrigger, h.ResumeExecution))
+	mux.Handle("GET /api/v1/tasks/{id}/executions/{execId}/stream", scoped(model.ScopeTasksRead, h.StreamExecution))
+	mux.Handle("GET /api/v1/tasks/{id}/export", scoped(model.ScopeTasksRead, h.ExportTask))
+
+	// Task secret routes
+	mux.Handle("GET /api/v1/tasks/{id}/secrets", scoped(model.ScopeTasksRead, h.GetTaskSecrets))
+	mux.Handle("PUT /api/v1/tasks/{id}/secrets", scoped(model.ScopeTasksWrite, h.SetTaskSecrets))
+	mux.Handle("DELETE /api/v1/tasks/{id}/secrets/{name}", scoped(model.ScopeTasksWrite, h.DeleteTaskSecret))
+
+	// Task share link routes
+	mux.Handle("POST /api/v1/tasks/{id}/share", scoped(model.ScopeTasksWrite, h.CreateTaskShare))
+	mux.Handle("GET /api/v1/tasks/{id}/share", scoped(model.ScopeTasksRead, h.GetTaskShares))
+	mux.Handle("DELETE /api/v1/tasks/{id}/share/{token}", scoped(model.ScopeTasksWrite, h.RevokeTaskShare))
+
+	// Task inbound webhook routes
+	mux.Handle("POST /api/v1/tasks/{id}/webhook", scoped(model.ScopeTasksWrite, h.CreateTaskWebhook))
+	mux.Handle("DELETE /api/v1/tasks/{id}/webhook", scoped(model.ScopeTasksWrite, h.DeleteTaskWebhook))
 
 	// Task Discord config routes
-	mux.Handle("/api/v1/tasks/{taskId}/discord", auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			dh.GetTaskDiscordConfig(w, r)
-		case http.MethodPut, http.MethodPost:
-			dh.SetTaskDiscordConfig(w, r)
-		case http.MethodDelete:
-			dh.DeleteTaskDiscordConfig(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})))
+	mux.Handle("GET /api/v1/tasks/{taskId}/discord", scoped(model.ScopeDiscordRead, dh.GetTaskDiscordConfig))
+	mux.Handle("PUT /api/v1/tasks/{taskId}/discord", scoped(model.ScopeDiscordWrite, dh.SetTaskDiscordConfig))
+	mux.Handle("POST /api/v1/tasks/{taskId}/discord", scoped(model.ScopeDiscordWrite, dh.SetTaskDiscordConfig))
+	mux.Handle("DELETE /api/v1/tasks/{taskId}/discord", scoped(model.ScopeDiscordWrite, dh.DeleteTaskDiscordConfig))
+	mux.Handle("POST /api/v1/tasks/{taskId}/discord/test", scoped(model.ScopeDiscordWrite, dh.TestTaskWebhook))
 
 	// Execution routes
-	mux.Handle("/api/v1/executions/recent", auth.Authenticate(http.HandlerFunc(h.GetRecentExecutions)))
+	mux.Handle("/api/v1/executions/recent", scoped(model.ScopeTasksRead, h.GetRecentExecutions))
+	mux.Handle("GET /api/v1/executions/{execId}/stream", scoped(model.ScopeTasksRead, h.StreamExecution))
+
+	// Analytics routes
+	mux.Handle("GET /api/v1/analytics/items", scoped(model.ScopeTasksRead, h.GetItemAnalytics))
+
+	// Scraper catalogue
+	mux.Handle("GET /api/v1/scrapers", authenticated(http.HandlerFunc(h.ListScrapers)))
 
 	// Status routes
-	mux.Handle("/api/v1/status", auth.Authenticate(http.HandlerFunc(h.Status)))
+	mux.Handle("/api/v1/status", scoped(model.ScopeTasksRead, h.Status))
 
 	// Discord Bot routes
-	mux.Handle("/api/v1/discord/bots", auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			dh.CreateBot(w, r)
-		case http.MethodGet:
-			dh.ListBots(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})))
-
-	mux.Handle("/api/v1/discord/bots/{botId}", auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			dh.GetBot(w, r)
-		case http.MethodPut:
-			dh.UpdateBot(w, r)
-		case http.MethodDelete:
-			dh.DeleteBot(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})))
+	mux.Handle("POST /api/v1/discord/bots", scoped(model.ScopeDiscordWrite, dh.CreateBot))
+	mux.Handle("GET /api/v1/discord/bots", scoped(model.ScopeDiscordRead, dh.ListBots))
+	mux.Handle("GET /api/v1/discord/bots/{botId}", scoped(model.ScopeDiscordRead, dh.GetBot))
+	mux.Handle("PUT /api/v1/discord/bots/{botId}", scoped(model.ScopeDiscordWrite, dh.UpdateBot))
+	mux.Handle("DELETE /api/v1/discord/bots/{botId}", scoped(model.ScopeDiscordWrite, dh.DeleteBot))
 
 	// Discord Channel routes
-	mux.Handle("/api/v1/discord/channels", auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			dh.CreateChannel(w, r)
-		case http.MethodGet:
-			dh.ListChannels(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})))
-
-	mux.Handle("/api/v1/discord/channels/{channelId}", auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			dh.GetChannel(w, r)
-		case http.MethodPut:
-			dh.UpdateChannel(w, r)
-		case http.MethodDelete:
-			dh.DeleteChannel(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})))
+	mux.Handle("POST /api/v1/discord/channels", scoped(model.ScopeDiscordWrite, dh.CreateChannel))
+	mux.Handle("GET /api/v1/discord/channels", scoped(model.ScopeDiscordRead, dh.ListChannels))
+	mux.Handle("GET /api/v1/discord/channels/{channelId}", scoped(model.ScopeDiscordRead, dh.GetChannel))
+	mux.Handle("PUT /api/v1/discord/channels/{channelId}", scoped(model.ScopeDiscordWrite, dh.UpdateChannel))
+	mux.Handle("DELETE /api/v1/discord/channels/{channelId}", scoped(model.ScopeDiscordWrite, dh.DeleteChannel))
+	mux.Handle("POST /api/v1/discord/channels/{channelId}/create-webhook", scoped(model.ScopeDiscordWrite, dh.CreateChannelWebhook))
 
 	// Discord Test webhook
-	mux.Handle("/api/v1/discord/test", auth.Authenticate(http.HandlerFunc(dh.TestWebhook)))
+	mux.Handle("/api/v1/discord/test", scoped(model.ScopeDiscordWrite, dh.TestWebhook))
 
-	// Apply global middleware
-	handler := middleware.CORS(middleware.JSON(middleware.Logger(mux)))
+	// Apply global middleware; the global limit is per client IP
+	rateLimit := middleware.RateLimit(limits.RPS, limits.Burst)
+	handler := middleware.CORS(middleware.JSON(middleware.Logger(rateLimit(mux))))
 
 	return handler
 }
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/multi-worker/internal/middleware"
+	"github.com/multi-worker/internal/model"
+)
+
+// sharedItemsLimit is how many delivered items a share link shows
+const sharedItemsLimit = 50
+
+// CreateTaskShare godoc
+// @Summary Create a share link
+// @Description Generate a read-only token that exposes the task's recently delivered items without authentication
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body model.CreateTaskShareRequest false "Optional expiry"
+// @Success 201 {object} model.TaskShare
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/share [post]
+func (h *Handler) CreateTaskShare(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+
+	var req model.CreateTaskShareRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	if req.ExpiresInHours < 0 {
+		respondError(w, http.StatusBadRequest, "expires_in_hours must not be negative")
+		return
+	}
+
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
+	claims := middleware.GetUserFromContext(r.Context())
+
+	var expiresAt *time.Time
+	if req.ExpiresInHours > 0 {
+		t := time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		expiresAt = &t
+	}
+
+	share, err := h.shareRepo.Create(r.Context(), taskID, claims.UserID, expiresAt)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to create share link")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, share)
+}
+
+// GetTaskShares godoc
+// @Summary List share links
+// @Description List a task's share links, including expired ones
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} map[string]interface{} "Share links"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/share [get]
+func (h *Handler) GetTaskShares(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		respondError(w, http.StatusBadRequest, "task ID required")
+		return
+	}
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
+
+	shares, err := h.shareRepo.FindByTaskID(r.Context(), taskID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list share links")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"shares": shares,
+	})
+}
+
+// RevokeTaskShare godoc
+// @Summary Revoke a share link
+// @Description Delete a share link so its token stops working
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param token path string true "Share token"
+// @Success 200 {object} map[string]string "Revoked"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task or share link not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/share/{token} [delete]
+func (h *Handler) RevokeTaskShare(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	token := r.PathValue("token")
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
+
+	revoked, err := h.shareRepo.Revoke(r.Context(), taskID, token)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke share link")
+		return
+	}
+	if !revoked {
+		respondError(w, http.StatusNotFound, "share link not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "revoked"})
+}
+
+// GetSharedTask godoc
+// @Summary View a shared task
+// @Description Public, read-only view of a task's recently delivered items. No pipeline, config or secrets are included.
+// @Tags Shared
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} model.SharedTask
+// @Failure 404 {object} map[string]string "Share link not found or expired"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /shared/{token} [get]
+func (h *Handler) GetSharedTask(w http.ResponseWriter, r *http.Request) {
+	share, err := h.shareRepo.FindValid(r.Context(), r.PathValue("token"))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load share link")
+		return
+	}
+	if share == nil {
+		respondError(w, http.StatusNotFound, "share link not found or expired")
+		return
+	}
+
+	task, err := h.taskRepo.FindByID(r.Context(), share.TaskID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get task")
+		return
+	}
+	if task == nil {
+		respondError(w, http.StatusNotFound, "share link not found or expired")
+		return
+	}
+
+	items, err := h.statsRepo.RecentDeliveredItems(r.Context(), task.ID, sharedItemsLimit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load items")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, model.SharedTask{
+		Name:        task.Name,
+		Description: task.Description,
+		LastRunAt:   task.LastRunAt,
+		Items:       items,
+	})
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/crypto"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/storage"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+// newTestHandler builds a Handler on db with the repositories share links use
+func newTestHandler(t *testing.T, db *storage.Database) *Handler {
+	t.Helper()
+	cipher, err := crypto.New(config.EncryptionConfig{Key: testEncryptionKey, KeyVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Handler{
+		db:         db,
+		taskRepo:   storage.NewTaskRepository(db),
+		execRepo:   storage.NewExecutionRepository(db),
+		secretRepo: storage.NewSecretRepository(db, cipher),
+		statsRepo:  storage.NewAnalyticsRepository(db),
+		shareRepo:  storage.NewShareRepository(db),
+	}
+}
+
+// getShared fetches a share link the way an anonymous visitor would
+func getShared(h *Handler, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/shared/"+token, nil)
+	req.SetPathValue("token", token)
+	rec := httptest.NewRecorder()
+	h.GetSharedTask(rec, req)
+	return rec
+}
+
+func TestSharedTaskAccessAndRevocation(t *testing.T) {
+	db := storagetest.Open(t)
+	h := newTestHandler(t, db)
+	ctx := context.Background()
+
+	const (
+		secret     = "sk-live/4f9a"
+		webhookURL = "https://discord.com/api/webhooks/1/private-token"
+	)
+	owner := storagetest.CreateUser(t, db, model.UserRoleUser)
+	other := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, owner.ID, model.PipelineStep{
+		Type: "webhook",
+		Config: map[string]interface{}{
+			"url":     webhookURL,
+			"headers": map[string]interface{}{"Authorization": `Bearer {{secret "api_key"}}`},
+		},
+	})
+	if err := h.secretRepo.Set(ctx, task.ID, map[string]string{"api_key": secret}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.statsRepo.RecordDeliveredItems(ctx, task.ID, []model.DeliveredItem{
+		{Title: "Go developer", URL: "https://example.com/jobs/1", Source: "remoteok"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	path := map[string]string{"id": task.ID}
+
+	// Only the task's owner can share it
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+task.ID+"/share", nil)
+	if rec := asUser(h.CreateTaskShare, req, other, path); rec.Code != http.StatusNotFound {
+		t.Errorf("another user sharing the task: got %d, want 404", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+task.ID+"/share", nil)
+	rec := asUser(h.CreateTaskShare, req, owner, path)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create share: got %d: %s", rec.Code, rec.Body.String())
+	}
+	var share model.TaskShare
+	if err := json.Unmarshal(rec.Body.Bytes(), &share); err != nil {
+		t.Fatal(err)
+	}
+	if len(share.Token) < 32 || share.ExpiresAt != nil {
+		t.Errorf("share = %+v, want a long token that never expires", share)
+	}
+
+	rec = getShared(h, share.Token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("shared view: got %d: %s", rec.Code, rec.Body.String())
+	}
+	var shared model.SharedTask
+	if err := json.Unmarshal(rec.Body.Bytes(), &shared); err != nil {
+		t.Fatal(err)
+	}
+	if shared.Name != task.Name || len(shared.Items) != 1 || shared.Items[0].Title != "Go developer" {
+		t.Errorf("shared view = %+v, want the task's delivered item", shared)
+	}
+	body := rec.Body.String()
+	for _, leak := range []string{secret, "private-token", "api_key", "pipeline", "config", owner.ID, owner.Email} {
+		if strings.Contains(body, leak) {
+			t.Errorf("shared view leaks %q: %s", leak, body)
+		}
+	}
+
+	if rec := getShared(h, "not-a-token"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown token: got %d, want 404", rec.Code)
+	}
+
+	// Revoking needs the owner, after which the token stops working
+	revoke := func(user *model.User) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/"+task.ID+"/share/"+share.Token, nil)
+		return asUser(h.RevokeTaskShare, req, user, map[string]string{"id": task.ID, "token": share.Token}).Code
+	}
+	if code := revoke(other); code != http.StatusNotFound {
+		t.Errorf("another user revoking: got %d, want 404", code)
+	}
+	if rec := getShared(h, share.Token); rec.Code != http.StatusOK {
+		t.Errorf("share stopped working after a refused revoke: got %d", rec.Code)
+	}
+	if code := revoke(owner); code != http.StatusOK {
+		t.Fatalf("revoke: got %d", code)
+	}
+	if rec := getShared(h, share.Token); rec.Code != http.StatusNotFound {
+		t.Errorf("revoked share: got %d, want 404", rec.Code)
+	}
+	if code := revoke(owner); code != http.StatusNotFound {
+		t.Errorf("revoking twice: got %d, want 404", code)
+	}
+}
+
+func TestSharedTaskExpiry(t *testing.T) {
+	db := storagetest.Open(t)
+	h := newTestHandler(t, db)
+	ctx := context.Background()
+
+	owner := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, owner.ID)
+	path := map[string]string{"id": task.ID}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+task.ID+"/share",
+		jsonBody(t, model.CreateTaskShareRequest{ExpiresInHours: 2}))
+	rec := asUser(h.CreateTaskShare, req, owner, path)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create share: got %d: %s", rec.Code, rec.Body.String())
+	}
+	var share model.TaskShare
+	if err := json.Unmarshal(rec.Body.Bytes(), &share); err != nil {
+		t.Fatal(err)
+	}
+	if share.ExpiresAt == nil || time.Until(*share.ExpiresAt) < time.Hour {
+		t.Errorf("expires_at = %v, want about 2 hours from now", share.ExpiresAt)
+	}
+	if rec := getShared(h, share.Token); rec.Code != http.StatusOK {
+		t.Errorf("unexpired share: got %d", rec.Code)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	expired, err := h.shareRepo.Create(ctx, task.ID, owner.ID, &past)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := getShared(h, expired.Token); rec.Code != http.StatusNotFound {
+		t.Errorf("expired share: got %d, want 404", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+task.ID+"/share",
+		jsonBody(t, model.CreateTaskShareRequest{ExpiresInHours: -1}))
+	if rec := asUser(h.CreateTaskShare, req, owner, path); rec.Code != http.StatusBadRequest {
+		t.Errorf("negative expiry: got %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/scheduler"
+)
+
+// streamPollInterval is how often a stream re-reads the execution record,
+// catching up on runs in another process, and sends a keep-alive
+const streamPollInterval = 15 * time.Second
+
+// StreamExecution godoc
+// @Summary Stream an execution's step events
+// @Description Server-Sent Events of an execution's progress. Each "step" event is a scheduler.StepEvent: started, completed, failed or skipped. Steps that already finished are sent first from the stored results, and a final "done" event carries the execution's status and error before the stream closes. An execution that already finished gets its stored results and "done" at once. Runs on another server instance are followed by re-reading the record every 15 seconds.
+// @Tags Executions
+// @Produce text/event-stream
+// @Param id path string false "Task ID (on the task-scoped route)"
+// @Param execId path string true "Execution ID"
+// @Success 200 {object} scheduler.StepEvent "Event stream"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Execution not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /executions/{execId}/stream [get]
+// @Router /tasks/{id}/executions/{execId}/stream [get]
+func (h *Handler) StreamExecution(w http.ResponseWriter, r *http.Request) {
+	execID := r.PathValue("execId")
+	if !uuidPattern.MatchString(execID) {
+		respondError(w, http.StatusNotFound, "execution not found")
+		return
+	}
+
+	// Subscribe before reading the record so no event between the two is lost
+	events, stop := h.runner.Events().Subscribe(execID)
+	defer stop()
+
+	execution, err := h.execRepo.FindByID(r.Context(), execID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch execution")
+		return
+	}
+	if execution == nil {
+		respondError(w, http.StatusNotFound, "execution not found")
+		return
+	}
+	if taskID := r.PathValue("id"); taskID != "" && taskID != execution.TaskID {
+		respondError(w, http.StatusNotFound, "execution not found")
+		return
+	}
+	if h.findTask(w, r, execution.TaskID) == nil {
+		return
+	}
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	s := &eventStream{w: w, rc: rc}
+	if s.catchUp(execution) {
+		return
+	}
+
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-h.streams:
+			// Shutting down; clients reconnect and catch up from the record
+			return
+
+		case event, ok := <-events:
+			if !ok {
+				// Finished here; the record now holds the final results
+				if execution, err := h.execRepo.FindByID(r.Context(), execID); err == nil && execution != nil {
+					s.catchUp(execution)
+				}
+				return
+			}
+			if event.Step <= s.sent {
+				continue
+			}
+			if event.Event != scheduler.StepEventStarted {
+				s.sent = event.Step
+			}
+			if s.send("step", event) != nil {
+				return
+			}
+
+		case <-ticker.C:
+			execution, err := h.execRepo.FindByID(r.Context(), execID)
+			if err != nil || execution == nil {
+				return
+			}
+			if s.catchUp(execution) {
+				return
+			}
+			if s.comment("keep-alive") != nil {
+				return
+			}
+		}
+	}
+}
+
+// eventStream writes Server-Sent Events, remembering how many finished
+// steps it has sent so stored and live events aren't repeated
+type eventStream struct {
+	w    http.ResponseWriter
+	rc   *http.ResponseController
+	sent int
+}
+
+// catchUp sends the stored results of steps not sent yet and, once the
+// execution has finished, the "done" event. It reports whether the stream
+// is over.
+func (s *eventStream) catchUp(execution *model.Execution) bool {
+	for _, event := range scheduler.StoredStepEvents(execution.StepResults) {
+		if event.Step <= s.sent {
+			continue
+		}
+		if s.send("step", event) != nil {
+			return true
+		}
+		s.sent = event.Step
+	}
+
+	if execution.Status == model.ExecutionStatusRunning || execution.Status == model.ExecutionStatusPending {
+		return false
+	}
+	done := map[string]interface{}{"status": execution.Status}
+	if execution.Error != nil {
+		done["error"] = *execution.Error
+	}
+	s.send("done", done)
+	return true
+}
+
+func (s *eventStream) send(name string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", name, payload); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+func (s *eventStream) comment(text string) error {
+	if _, err := fmt.Fprintf(s.w, ": %s\n\n", text); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/scheduler"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+func TestStreamExecutionEndsOnCloseStreams(t *testing.T) {
+	db := storagetest.Open(t)
+	h := newTestHandler(t, db)
+	h.streams = make(chan struct{})
+	h.runner = scheduler.NewPipelineRunner(h.taskRepo, h.execRepo, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	user := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, user.ID, model.PipelineStep{Type: "static"})
+	execution, err := h.execRepo.Create(context.Background(), task.ID, task.Name, "test", model.TriggerTypeManual, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The execution never finishes, so only closing the streams ends this one
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/executions/"+execution.ID+"/stream", nil)
+		done <- asUser(h.StreamExecution, req, user, map[string]string{"execId": execution.ID})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	h.CloseStreams()
+	h.CloseStreams() // Safe to call again
+
+	select {
+	case rec := <-done:
+		if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/event-stream") {
+			t.Errorf("got %d %q, want an event stream", rec.Code, rec.Header().Get("Content-Type"))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream still open after CloseStreams")
+	}
+}
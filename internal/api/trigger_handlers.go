@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/scheduler"
+)
+
+// maxWebhookBody caps the request body an inbound webhook passes to a task
+const maxWebhookBody = 1 << 20
+
+// CreateTaskWebhook godoc
+// @Summary Create an inbound webhook
+// @Description Generate the secret that lets POST /tasks/{id}/webhook/{secret} run the task without authentication. Any earlier secret stops working. The secret is only shown in this response.
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 201 {object} map[string]string "Secret and webhook path"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/webhook [post]
+func (h *Handler) CreateTaskWebhook(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
+
+	secret, err := h.taskRepo.RotateTriggerSecret(r.Context(), taskID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to create webhook secret")
+		return
+	}
+	if secret == "" {
+		respondError(w, http.StatusNotFound, "task not found")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]string{
+		"secret": secret,
+		"path":   "/api/v1/tasks/" + taskID + "/webhook/" + secret,
+	})
+}
+
+// DeleteTaskWebhook godoc
+// @Summary Delete an inbound webhook
+// @Description Revoke the task's webhook secret so inbound webhooks can no longer run it
+// @Tags Tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} map[string]string "Revoked"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Task or webhook not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tasks/{id}/webhook [delete]
+func (h *Handler) DeleteTaskWebhook(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if h.findTask(w, r, taskID) == nil {
+		return
+	}
+
+	cleared, err := h.taskRepo.ClearTriggerSecret(r.Context(), taskID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke webhook secret")
+		return
+	}
+	if !cleared {
+		respondError(w, http.StatusNotFound, "task has no webhook")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "revoked"})
+}
+
+// HandleTaskWebhook godoc
+// @Summary Run a task from an inbound webhook
+// @Description Public endpoint authenticated by the secret in its path. Runs the task's pipeline and returns the execution. A JSON body becomes the first step's input as parsed JSON, any other body as text; an empty body gives the first step no input. The run continues if the sender disconnects. Disabled and archived tasks aren't run.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param secret path string true "Webhook secret"
+// @Success 200 {object} model.Execution
+// @Failure 404 {object} map[string]string "Task or secret not found"
+// @Failure 409 {object} map[string]string "Task disabled, archived or already running"
+// @Failure 413 {object} map[string]string "Body too large"
+// @Failure 429 {object} map[string]string "Too many executions in progress"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /tasks/{id}/webhook/{secret} [post]
+func (h *Handler) HandleTaskWebhook(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if !uuidPattern.MatchString(taskID) {
+		respondError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+
+	task, err := h.taskRepo.FindByTriggerSecret(r.Context(), taskID, r.PathValue("secret"))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get task")
+		return
+	}
+	if task == nil {
+		respondError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		respondError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+
+	// Senders often give up long before a pipeline finishes; the run
+	// shouldn't be cancelled when they do
+	ctx := context.WithoutCancel(r.Context())
+	execution, err := h.scheduler.TriggerWebhook(ctx, *task, webhookInput(body))
+	if errors.Is(err, scheduler.ErrTaskRunning) || errors.Is(err, scheduler.ErrTaskArchived) || errors.Is(err, scheduler.ErrTaskDisabled) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, scheduler.ErrTooManyExecutions) {
+		respondError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, execution)
+}
+
+// webhookInput turns a webhook body into the first step's input: parsed
+// JSON when the body is JSON, otherwise its text. An empty body is no input.
+func webhookInput(body []byte) *model.ExecutorResult {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var data interface{} = string(body)
+	if json.Valid(body) {
+		_ = json.Unmarshal(body, &data)
+	}
+
+	count := 1
+	if list, ok := data.([]interface{}); ok {
+		count = len(list)
+	}
+	return &model.ExecutorResult{
+		Data:      data,
+		ItemCount: count,
+		Metadata: map[string]interface{}{
+			"source": "webhook",
+		},
+	}
+}
@@ -3,23 +3,37 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	AI       AIConfig
-	Discord  DiscordConfig
-	Scraper  ScraperConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	JWT        JWTConfig
+	Encryption EncryptionConfig
+	Scheduler  SchedulerConfig
+	AI         AIConfig
+	Discord    DiscordConfig
+	Slack      SlackConfig
+	Telegram   TelegramConfig
+	Mastodon   MastodonConfig
+	Email      EmailConfig
+	Scraper    ScraperConfig
+
+	Notifications NotificationConfig
+	Maintenance   MaintenanceConfig
+	RateLimit     RateLimitConfig
+	PasswordReset PasswordResetConfig
+	Outbound      OutboundConfig
 }
 
 type ServerConfig struct {
-	Host         string
-	Port         int
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	Host            string
+	Port            int
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	ShutdownTimeout time.Duration
 }
 
 type DatabaseConfig struct {
@@ -34,8 +48,61 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Secret                string
+	ExpirationHours       int
+	RefreshExpirationDays int // Lifetime of refresh tokens
+}
+
+type SchedulerConfig struct {
+	MaxConcurrent int  // 0 means unlimited
+	SkipWhenFull  bool // Skip instead of queueing runs when the pool is full
+
+	// Global cap on in-flight executions, scheduled and manual; 0 means unlimited
+	MaxExecutions int
+	// How long a manual trigger waits for a free execution slot
+	TriggerWait time.Duration
+	// How long shutdown waits for in-flight runs before cancelling them
+	ShutdownGrace time.Duration
+}
+
+type NotificationConfig struct {
+	ErrorWebhook  string        // Posted to when an execution fails; empty disables
+	ErrorThrottle time.Duration // Minimum time between notifications for the same task
+
+	ExecutionCallbackURL string // Posted a summary of every finished execution; empty disables
+}
+
+type MaintenanceConfig struct {
+	CleanupIntervalHours   int // How often old rows are pruned
+	ExecutionRetentionDays int // Executions older than this are pruned; 0 keeps them forever
+	CacheRetentionDays     int // Content cache entries older than this are pruned; 0 keeps them forever
+}
+
+type PasswordResetConfig struct {
+	TokenTTL time.Duration // How long a mailed reset token stays valid
+	URL      string        // Optional page the token is appended to as ?token=
+}
+
+type RateLimitConfig struct {
+	RPS       float64 // Requests per second per client IP or API key; 0 disables the limit
+	Burst     int     // Requests a client may make at once before being limited
+	AuthRPS   float64 // Stricter per-IP limit on login, registration and token refresh
+	AuthBurst int
+}
+
+type EncryptionConfig struct {
+	Key        string
+	KeyVersion int
+	// OldKeys lists retired keys still needed for decryption, as
+	// comma-separated "version:key" pairs
+	OldKeys string
+	// LegacyKey is the key values were encrypted with before key versioning,
+	// of any length, padded the way that code padded it. It only decrypts
+	// unversioned values, so short keys can be moved off with reencrypt.
+	LegacyKey string
+	// FromJWTSecret is set when ENCRYPTION_KEY is unset and Key is the JWT
+	// secret
+	FromJWTSecret bool
 }
 
 type AIConfig struct {
@@ -45,6 +112,16 @@ type AIConfig struct {
 	Google          GoogleConfig
 	OpenRouter      OpenRouterConfig
 	DeepSeek        DeepSeekConfig
+	Groq            GroqConfig
+	XAI             XAIConfig
+	Ollama          OllamaConfig
+	Pricing         map[string]TokenPrice // By provider name, for cost estimates
+}
+
+// TokenPrice is a provider's price in USD per million tokens
+type TokenPrice struct {
+	Input  float64
+	Output float64
 }
 
 type OpenAIConfig struct {
@@ -69,8 +146,8 @@ type OpenRouterConfig struct {
 	APIKey   string
 	Model    string
 	BaseURL  string
-	SiteURL  string // Optional. For rankings on openrouter.ai
-	SiteName string // Optional. For rankings on openrouter.ai
+	SiteURL  string // Optional. For rankings on openrouter.ai, sent as HTTP-Referer
+	SiteName string // Optional. For rankings on openrouter.ai, sent as X-Title
 }
 
 type DeepSeekConfig struct {
@@ -79,26 +156,87 @@ type DeepSeekConfig struct {
 	BaseURL string
 }
 
+type GroqConfig struct {
+	APIKey  string
+	Model   string
+	BaseURL string
+}
+
+type XAIConfig struct {
+	APIKey  string
+	Model   string
+	BaseURL string
+}
+
+type OllamaConfig struct {
+	Model   string // Provider is only registered when a model is set
+	BaseURL string
+}
+
 type DiscordConfig struct {
 	DefaultWebhook string
 	RateLimitMs    int
 }
 
+type SlackConfig struct {
+	DefaultWebhook string
+	RateLimitMs    int
+}
+
+type TelegramConfig struct {
+	BotToken    string
+	APIURL      string
+	RateLimitMs int
+}
+
+type MastodonConfig struct {
+	Instance string // Host name or base URL, e.g. mastodon.social
+	Token    string
+}
+
+type EmailConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	From     string // Sender address; defaults to User
+	TLSMode  string // "starttls" (default), "tls" for implicit TLS, or "none"
+}
+
 type ScraperConfig struct {
 	UserAgent       string
 	RequestTimeout  time.Duration
 	RateLimitMs     int
 	MaxRetries      int
 	ProxyURL        string
+	ProxyURLs       []string // Rotated per request, together with ProxyURL
+	GitHubToken     string   // Optional, raises the github source's API rate limit
+	SourcesFile     string   // Optional JSON file of per-source settings such as headers
+}
+
+// OutboundConfig limits where user-configured webhooks may send requests
+type OutboundConfig struct {
+	// Hosts allowed besides Discord's for Discord webhooks, and allowed to
+	// resolve to private or loopback addresses for any webhook
+	AllowedHosts []string
 }
 
 func Load() *Config {
+	jwtSecret := getEnv("JWT_SECRET", "change-me-in-production-please")
+
+	encryptionKey := getEnv("ENCRYPTION_KEY", "")
+	keyFromJWT := encryptionKey == ""
+	if keyFromJWT {
+		encryptionKey = jwtSecret // Fallback to JWT secret
+	}
+
 	return &Config{
 		Server: ServerConfig{
-			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
-			Port:         getEnvAsInt("SERVER_PORT", 8080),
-			ReadTimeout:  time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT", 30)) * time.Second,
-			WriteTimeout: time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 30)) * time.Second,
+			Host:            getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:            getEnvAsInt("SERVER_PORT", 8080),
+			ReadTimeout:     time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT", 30)) * time.Second,
+			WriteTimeout:    time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 30)) * time.Second,
+			ShutdownTimeout: time.Duration(getEnvAsInt("SERVER_SHUTDOWN_TIMEOUT", 30)) * time.Second,
 		},
 		Database: DatabaseConfig{
 			Host:         getEnv("DB_HOST", "localhost"),
@@ -111,11 +249,27 @@ func Load() *Config {
 			MaxIdleConns: getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
 		},
 		JWT: JWTConfig{
-			Secret:          getEnv("JWT_SECRET", "change-me-in-production-please"),
-			ExpirationHours: getEnvAsInt("JWT_EXPIRATION_HOURS", 72),
+			Secret:                jwtSecret,
+			ExpirationHours:       getEnvAsInt("JWT_EXPIRATION_HOURS", 72),
+			RefreshExpirationDays: getEnvAsInt("JWT_REFRESH_EXPIRATION_DAYS", 30),
+		},
+		Encryption: EncryptionConfig{
+			Key:           encryptionKey,
+			KeyVersion:    getEnvAsInt("ENCRYPTION_KEY_VERSION", 1),
+			OldKeys:       getEnv("ENCRYPTION_OLD_KEYS", ""),
+			LegacyKey:     getEnv("ENCRYPTION_LEGACY_KEY", ""),
+			FromJWTSecret: keyFromJWT,
+		},
+		Scheduler: SchedulerConfig{
+			MaxConcurrent: getEnvAsInt("SCHEDULER_MAX_CONCURRENT", 0),
+			SkipWhenFull:  getEnv("SCHEDULER_SKIP_WHEN_FULL", "false") == "true",
+			MaxExecutions: getEnvAsInt("MAX_CONCURRENT_EXECUTIONS", 0),
+			TriggerWait:   time.Duration(getEnvAsInt("EXECUTION_TRIGGER_WAIT", 5)) * time.Second,
+			ShutdownGrace: time.Duration(getEnvAsInt("SCHEDULER_SHUTDOWN_GRACE", 20)) * time.Second,
 		},
 		AI: AIConfig{
 			DefaultProvider: getEnv("AI_DEFAULT_PROVIDER", "openai"),
+			Pricing:         getEnvAsPricing("AI_PRICING"),
 			OpenAI: OpenAIConfig{
 				APIKey:  getEnv("OPENAI_API_KEY", ""),
 				Model:   getEnv("OPENAI_MODEL", "gpt-4o-mini"),
@@ -136,24 +290,86 @@ func Load() *Config {
 				Model:    getEnv("OPENROUTER_MODEL", "openai/gpt-4o-mini"),
 				BaseURL:  getEnv("OPENROUTER_BASE_URL", "https://openrouter.ai/api/v1"),
 				SiteURL:  getEnv("OPENROUTER_SITE_URL", "https://github.com/multi-worker"),
-				SiteName: getEnv("OPENROUTER_SITE_NAME", "Multi-Worker Scheduler"),
+				SiteName: getEnv("OPENROUTER_APP_NAME", getEnv("OPENROUTER_SITE_NAME", "Multi-Worker Scheduler")),
 			},
 			DeepSeek: DeepSeekConfig{
 				APIKey:  getEnv("DEEPSEEK_API_KEY", ""),
 				Model:   getEnv("DEEPSEEK_MODEL", "deepseek-chat"),
 				BaseURL: getEnv("DEEPSEEK_BASE_URL", "https://api.deepseek.com/v1"),
 			},
+			Groq: GroqConfig{
+				APIKey:  getEnv("GROQ_API_KEY", ""),
+				Model:   getEnv("GROQ_MODEL", "llama-3.3-70b-versatile"),
+				BaseURL: getEnv("GROQ_BASE_URL", "https://api.groq.com/openai/v1"),
+			},
+			XAI: XAIConfig{
+				APIKey:  getEnv("XAI_API_KEY", ""),
+				Model:   getEnv("XAI_MODEL", "grok-3-mini"),
+				BaseURL: getEnv("XAI_BASE_URL", "https://api.x.ai/v1"),
+			},
+			Ollama: OllamaConfig{
+				Model:   getEnv("OLLAMA_MODEL", ""),
+				BaseURL: getEnv("OLLAMA_BASE_URL", "http://localhost:11434"),
+			},
 		},
 		Discord: DiscordConfig{
 			DefaultWebhook: getEnv("DISCORD_DEFAULT_WEBHOOK", ""),
 			RateLimitMs:    getEnvAsInt("DISCORD_RATE_LIMIT_MS", 1000),
 		},
+		Slack: SlackConfig{
+			DefaultWebhook: getEnv("SLACK_DEFAULT_WEBHOOK", ""),
+			RateLimitMs:    getEnvAsInt("SLACK_RATE_LIMIT_MS", 1000),
+		},
+		Telegram: TelegramConfig{
+			BotToken:    getEnv("TELEGRAM_BOT_TOKEN", ""),
+			APIURL:      getEnv("TELEGRAM_API_URL", "https://api.telegram.org"),
+			RateLimitMs: getEnvAsInt("TELEGRAM_RATE_LIMIT_MS", 1000),
+		},
+		Mastodon: MastodonConfig{
+			Instance: getEnv("MASTODON_INSTANCE", ""),
+			Token:    getEnv("MASTODON_TOKEN", ""),
+		},
+		Email: EmailConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnvAsInt("SMTP_PORT", 587),
+			User:     getEnv("SMTP_USER", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", ""),
+			TLSMode:  getEnv("SMTP_TLS", "starttls"),
+		},
 		Scraper: ScraperConfig{
 			UserAgent:      getEnv("SCRAPER_USER_AGENT", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
 			RequestTimeout: time.Duration(getEnvAsInt("SCRAPER_REQUEST_TIMEOUT", 30)) * time.Second,
 			RateLimitMs:    getEnvAsInt("SCRAPER_RATE_LIMIT_MS", 2000),
 			MaxRetries:     getEnvAsInt("SCRAPER_MAX_RETRIES", 3),
 			ProxyURL:       getEnv("SCRAPER_PROXY_URL", ""),
+			ProxyURLs:      getEnvAsList("SCRAPER_PROXY_URLS"),
+			GitHubToken:    getEnv("GITHUB_TOKEN", ""),
+			SourcesFile:    getEnv("SCRAPER_SOURCES_FILE", ""),
+		},
+		Notifications: NotificationConfig{
+			ErrorWebhook:  getEnv("ERROR_NOTIFICATION_WEBHOOK", ""),
+			ErrorThrottle: time.Duration(getEnvAsInt("ERROR_NOTIFICATION_THROTTLE", 900)) * time.Second,
+
+			ExecutionCallbackURL: getEnv("EXECUTION_CALLBACK_URL", ""),
+		},
+		Maintenance: MaintenanceConfig{
+			CleanupIntervalHours:   getEnvAsInt("CLEANUP_INTERVAL_HOURS", 24),
+			ExecutionRetentionDays: getEnvAsInt("EXECUTION_RETENTION_DAYS", 0),
+			CacheRetentionDays:     getEnvAsInt("CACHE_RETENTION_DAYS", 0),
+		},
+		PasswordReset: PasswordResetConfig{
+			TokenTTL: time.Duration(getEnvAsInt("PASSWORD_RESET_TTL_MINUTES", 60)) * time.Minute,
+			URL:      getEnv("PASSWORD_RESET_URL", ""),
+		},
+		RateLimit: RateLimitConfig{
+			RPS:       getEnvAsFloat("RATE_LIMIT_RPS", 10),
+			Burst:     getEnvAsInt("RATE_LIMIT_BURST", 20),
+			AuthRPS:   getEnvAsFloat("RATE_LIMIT_AUTH_RPS", 0.1),
+			AuthBurst: getEnvAsInt("RATE_LIMIT_AUTH_BURST", 5),
+		},
+		Outbound: OutboundConfig{
+			AllowedHosts: getEnvAsList("OUTBOUND_ALLOWED_HOSTS"),
 		},
 	}
 }
@@ -174,6 +390,40 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvAsList splits a comma-separated value, dropping empty entries
+func getEnvAsList(key string) []string {
+	var list []string
+	for _, entry := range strings.Split(getEnv(key, ""), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// getEnvAsPricing parses "provider=input/output,..." prices in USD per
+// million tokens, skipping malformed entries
+func getEnvAsPricing(key string) map[string]TokenPrice {
+	pricing := make(map[string]TokenPrice)
+	for _, entry := range strings.Split(getEnv(key, ""), ",") {
+		name, prices, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		in, out, ok := strings.Cut(prices, "/")
+		if !ok {
+			continue
+		}
+		inPrice, err1 := strconv.ParseFloat(strings.TrimSpace(in), 64)
+		outPrice, err2 := strconv.ParseFloat(strings.TrimSpace(out), 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		pricing[strings.TrimSpace(name)] = TokenPrice{Input: inPrice, Output: outPrice}
+	}
+	return pricing
+}
+
 func getEnvAsInt(key string, defaultValue int) int {
 	if value, exists := os.LookupEnv(key); exists {
 		if intValue, err := strconv.Atoi(value); err == nil {
@@ -182,3 +432,12 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,208 @@
+// Package crypto provides the AES-GCM helpers used to store credentials at rest
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/multi-worker/internal/config"
+)
+
+// KeySize is the minimum key length; keys are used as AES-256 keys
+const KeySize = 32
+
+// Cipher encrypts and decrypts short secrets with AES-256-GCM.
+//
+// Ciphertexts are written as "v<version>:<base64(nonce|sealed)>" so the key
+// can be rotated while older values stay readable. Values written before
+// versioning have no prefix and are tried against every known key, and
+// the legacy key if one is set.
+type Cipher struct {
+	keys    map[int][]byte
+	current int
+	legacy  []byte // Decrypts unversioned values only; nil when unset
+}
+
+// New creates a cipher from the encryption config, rejecting keys shorter
+// than KeySize bytes
+func New(cfg config.EncryptionConfig) (*Cipher, error) {
+	if cfg.KeyVersion < 1 {
+		return nil, fmt.Errorf("encryption key version must be at least 1")
+	}
+
+	c := &Cipher{
+		keys:    make(map[int][]byte),
+		current: cfg.KeyVersion,
+	}
+
+	if err := c.addKey(cfg.KeyVersion, cfg.Key); err != nil {
+		// Earlier releases padded short keys, so an upgrade lands here
+		hint := "; if values were stored under it, move it to ENCRYPTION_LEGACY_KEY and set a new key"
+		if cfg.FromJWTSecret {
+			return nil, fmt.Errorf("ENCRYPTION_KEY is unset and JWT_SECRET, used in its place: %w%s", err, hint)
+		}
+		return nil, fmt.Errorf("ENCRYPTION_KEY: %w%s", err, hint)
+	}
+
+	for _, entry := range strings.Split(cfg.OldKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		versionStr, key, ok := strings.Cut(entry, ":")
+		version, err := strconv.Atoi(versionStr)
+		if !ok || err != nil || version < 1 {
+			return nil, fmt.Errorf("ENCRYPTION_OLD_KEYS: entries must be \"version:key\"")
+		}
+		if _, exists := c.keys[version]; exists {
+			return nil, fmt.Errorf("ENCRYPTION_OLD_KEYS: duplicate key version %d", version)
+		}
+		if err := c.addKey(version, key); err != nil {
+			return nil, fmt.Errorf("ENCRYPTION_OLD_KEYS: version %d: %w", version, err)
+		}
+	}
+
+	if cfg.LegacyKey != "" {
+		c.legacy = LegacyKey(cfg.LegacyKey)
+	}
+
+	return c, nil
+}
+
+// LegacyKey derives an AES key the way releases before key versioning did:
+// short keys are padded with '0' characters and long ones cut to KeySize
+// bytes. Such keys may be weak, so they are only ever used to decrypt.
+func LegacyKey(key string) []byte {
+	return []byte((key + strings.Repeat("0", KeySize))[:KeySize])
+}
+
+func (c *Cipher) addKey(version int, key string) error {
+	if len(key) < KeySize {
+		return fmt.Errorf("key must be at least %d bytes, got %d", KeySize, len(key))
+	}
+	c.keys[version] = []byte(key[:KeySize])
+	return nil
+}
+
+// Encrypt returns the versioned ciphertext for plaintext using the current
+// key. Empty input is stored as an empty string.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	gcm, err := newGCM(c.keys[c.current])
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return "v" + strconv.Itoa(c.current) + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt, using whichever key version the value was
+// written with. Tampered values fail authentication and return an error.
+func (c *Cipher) Decrypt(ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+
+	version, payload, versioned := splitVersion(ciphertext)
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", err
+	}
+
+	if versioned {
+		key, ok := c.keys[version]
+		if !ok {
+			return "", fmt.Errorf("unknown encryption key version %d", version)
+		}
+		return open(key, data)
+	}
+
+	// Legacy value: try the current key first, then retired ones
+	if plaintext, err := open(c.keys[c.current], data); err == nil {
+		return plaintext, nil
+	}
+	for v, key := range c.keys {
+		if v == c.current {
+			continue
+		}
+		if plaintext, err := open(key, data); err == nil {
+			return plaintext, nil
+		}
+	}
+	if c.legacy != nil {
+		if plaintext, err := open(c.legacy, data); err == nil {
+			return plaintext, nil
+		}
+	}
+	return "", errors.New("failed to decrypt value with any known key")
+}
+
+// Version returns the version of the key new values are encrypted with
+func (c *Cipher) Version() int {
+	return c.current
+}
+
+// KeyVersion returns the version a ciphertext was written with, or 0 for
+// values written before versioning
+func KeyVersion(ciphertext string) int {
+	version, _, _ := splitVersion(ciphertext)
+	return version
+}
+
+// splitVersion parses the "v<version>:" prefix. Base64 never contains ':',
+// so an unprefixed legacy value can't be mistaken for a versioned one.
+func splitVersion(ciphertext string) (int, string, bool) {
+	prefix, payload, ok := strings.Cut(ciphertext, ":")
+	if !ok || !strings.HasPrefix(prefix, "v") {
+		return 0, ciphertext, false
+	}
+	version, err := strconv.Atoi(prefix[1:])
+	if err != nil {
+		return 0, ciphertext, false
+	}
+	return version, payload, true
+}
+
+func open(key, data []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("ciphertext too short")
+	}
+
+	nonce, cipherData := data[:nonceSize], data[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, cipherData, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,157 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/multi-worker/internal/config"
+)
+
+const (
+	keyV1 = "0123456789abcdef0123456789abcdef"
+	keyV2 = "fedcba9876543210fedcba9876543210"
+)
+
+func mustNew(t *testing.T, cfg config.EncryptionConfig) *Cipher {
+	t.Helper()
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// sealUnversioned encrypts the way releases before key versioning did,
+// padding the key and writing no version prefix
+func sealUnversioned(t *testing.T, key, plaintext string) string {
+	t.Helper()
+	padded := key
+	if len(padded) < 32 {
+		padded += "00000000000000000000000000000000"
+	}
+	block, err := aes.NewCipher([]byte(padded[:32]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), nil))
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	c := mustNew(t, config.EncryptionConfig{Key: keyV1, KeyVersion: 1})
+
+	encrypted, err := c.Encrypt("bot-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encrypted, "v1:") || KeyVersion(encrypted) != 1 {
+		t.Errorf("ciphertext %q lacks its key version", encrypted)
+	}
+	if got, err := c.Decrypt(encrypted); err != nil || got != "bot-token" {
+		t.Errorf("Decrypt = %q, %v", got, err)
+	}
+
+	if encrypted, _ := c.Encrypt(""); encrypted != "" {
+		t.Errorf("empty plaintext encrypted to %q", encrypted)
+	}
+}
+
+func TestDecryptRejectsTampering(t *testing.T) {
+	c := mustNew(t, config.EncryptionConfig{Key: keyV1, KeyVersion: 1})
+	encrypted, _ := c.Encrypt("bot-token")
+
+	raw, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, "v1:"))
+	raw[len(raw)-1] ^= 1
+	if _, err := c.Decrypt("v1:" + base64.StdEncoding.EncodeToString(raw)); err == nil {
+		t.Error("tampered ciphertext decrypted")
+	}
+}
+
+func TestRotationKeepsOldValuesReadable(t *testing.T) {
+	old := mustNew(t, config.EncryptionConfig{Key: keyV1, KeyVersion: 1})
+	encrypted, _ := old.Encrypt("webhook")
+
+	rotated := mustNew(t, config.EncryptionConfig{Key: keyV2, KeyVersion: 2, OldKeys: "1:" + keyV1})
+	if got, err := rotated.Decrypt(encrypted); err != nil || got != "webhook" {
+		t.Errorf("Decrypt of a v1 value after rotation = %q, %v", got, err)
+	}
+	if fresh, _ := rotated.Encrypt("webhook"); KeyVersion(fresh) != 2 {
+		t.Errorf("new values written with version %d, want 2", KeyVersion(fresh))
+	}
+
+	// Once the old key is dropped its values can't be read
+	dropped := mustNew(t, config.EncryptionConfig{Key: keyV2, KeyVersion: 2})
+	if _, err := dropped.Decrypt(encrypted); err == nil {
+		t.Error("v1 value decrypted without the v1 key")
+	}
+}
+
+func TestNewRejectsBadKeys(t *testing.T) {
+	for name, cfg := range map[string]config.EncryptionConfig{
+		"short key":          {Key: "too-short", KeyVersion: 1},
+		"zero version":       {Key: keyV1, KeyVersion: 0},
+		"short old key":      {Key: keyV2, KeyVersion: 2, OldKeys: "1:too-short"},
+		"malformed old key":  {Key: keyV2, KeyVersion: 2, OldKeys: keyV1},
+		"duplicate version":  {Key: keyV2, KeyVersion: 2, OldKeys: "2:" + keyV1},
+		"short JWT fallback": {Key: "change-me-in-production-please", KeyVersion: 1, FromJWTSecret: true},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("%s: New accepted %+v", name, cfg)
+		}
+	}
+
+	_, err := New(config.EncryptionConfig{Key: "too-short", KeyVersion: 1})
+	if !strings.Contains(err.Error(), "ENCRYPTION_LEGACY_KEY") {
+		t.Errorf("short key error %q doesn't point to the upgrade path", err)
+	}
+}
+
+func TestLegacyKeyDecryptsUnversionedValues(t *testing.T) {
+	const shortKey = "change-me-in-production-please"
+	stored := sealUnversioned(t, shortKey, "bot-token")
+
+	c := mustNew(t, config.EncryptionConfig{Key: keyV1, KeyVersion: 1, LegacyKey: shortKey})
+	if got, err := c.Decrypt(stored); err != nil || got != "bot-token" {
+		t.Fatalf("Decrypt of a padded-key value = %q, %v", got, err)
+	}
+
+	// The legacy key never encrypts
+	encrypted, _ := c.Encrypt("bot-token")
+	if KeyVersion(encrypted) != 1 {
+		t.Errorf("new value written with version %d, want 1", KeyVersion(encrypted))
+	}
+	withoutLegacy := mustNew(t, config.EncryptionConfig{Key: keyV1, KeyVersion: 1})
+	if got, err := withoutLegacy.Decrypt(encrypted); err != nil || got != "bot-token" {
+		t.Errorf("new value needs the legacy key: %q, %v", got, err)
+	}
+
+	if _, err := withoutLegacy.Decrypt(stored); err == nil {
+		t.Error("padded-key value decrypted without the legacy key")
+	}
+}
+
+func TestLegacyKeyMatchesOldDerivation(t *testing.T) {
+	long := keyV1 + "-and-more"
+	if got := string(LegacyKey(long)); got != keyV1 {
+		t.Errorf("LegacyKey(long) = %q, want the first 32 bytes", got)
+	}
+	if got := string(LegacyKey("abc")); got != "abc"+strings.Repeat("0", 29) {
+		t.Errorf("LegacyKey(short) = %q", got)
+	}
+
+	// A long key used unversioned is read by the current key alone too
+	stored := sealUnversioned(t, keyV1, "webhook")
+	c := mustNew(t, config.EncryptionConfig{Key: keyV1, KeyVersion: 1})
+	if got, err := c.Decrypt(stored); err != nil || got != "webhook" {
+		t.Errorf("Decrypt of an unversioned value under the current key = %q, %v", got, err)
+	}
+}
@@ -65,6 +65,16 @@ func (p *AnthropicProvider) Name() string {
 	return "anthropic"
 }
 
+func (p *AnthropicProvider) Model() string {
+	return p.model
+}
+
+func (p *AnthropicProvider) WithModel(model string) Provider {
+	c := *p
+	c.model = model
+	return &c
+}
+
 func (p *AnthropicProvider) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
 	reqBody := anthropicRequest{
 		Model:     p.model,
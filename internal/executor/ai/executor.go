@@ -4,37 +4,106 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/storage"
 )
 
 // Executor handles AI processing in pipelines
 type Executor struct {
 	registry *ProviderRegistry
+	cache    *storage.CacheRepository
 }
 
-// NewExecutor creates a new AI executor
-func NewExecutor(registry *ProviderRegistry) *Executor {
-	return &Executor{registry: registry}
+// NewExecutor creates a new AI executor. Completions are cached in cache
+// for steps that set ai_cache_ttl_seconds.
+func NewExecutor(registry *ProviderRegistry, cache *storage.CacheRepository) *Executor {
+	return &Executor{registry: registry, cache: cache}
 }
 
 func (e *Executor) Type() string {
 	return "ai_processor"
 }
 
+// Cost prices a completion on the step's provider, reporting false when no
+// price is configured for it
+func (e *Executor) Cost(config map[string]interface{}, inputTokens, outputTokens int) (float64, bool) {
+	providerName, _ := config["provider"].(string)
+	_, providers, mode, err := mergeConfig(config)
+	if err != nil || providers == nil {
+		return e.registry.Cost(providerName, inputTokens, outputTokens)
+	}
+
+	// A merge pays every provider, and the combining provider once more
+	// for reading all drafts
+	total := 0.0
+	for _, name := range providers {
+		cost, ok := e.registry.Cost(name, inputTokens, outputTokens)
+		if !ok {
+			return 0, false
+		}
+		total += cost
+	}
+	if mode == MergeCombine {
+		cost, ok := e.registry.Cost(providerName, inputTokens+len(providers)*outputTokens, outputTokens)
+		if !ok {
+			return 0, false
+		}
+		total += cost
+	}
+	return total, true
+}
+
+// Requests returns how many completions a step makes when every provider answers
+func (e *Executor) Requests(config map[string]interface{}) int {
+	_, providers, mode, err := mergeConfig(config)
+	if err != nil || providers == nil {
+		return 1
+	}
+	if mode == MergeCombine {
+		return len(providers) + 1
+	}
+	return len(providers)
+}
+
 func (e *Executor) Validate(config map[string]interface{}) error {
 	if _, ok := config["prompt"]; !ok {
 		return fmt.Errorf("ai_processor requires 'prompt' in config")
 	}
+	if _, err := fallbackProviders(config); err != nil {
+		return err
+	}
+	if _, _, _, err := mergeConfig(config); err != nil {
+		return err
+	}
+	if _, err := stepModel(config); err != nil {
+		return err
+	}
+	if _, err := cacheTTL(config); err != nil {
+		return err
+	}
+	if _, _, _, err := jsonConfig(config); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
-	// Get provider
 	providerName, _ := config["provider"].(string)
-	provider, err := e.registry.Get(providerName)
+	strategy, mergeProviders, mergeMode, err := mergeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	jsonMode, schema, jsonRetry, err := jsonConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	modelName, err := stepModel(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get AI provider: %w", err)
+		return nil, err
 	}
 
 	// Get prompt configuration
@@ -48,7 +117,7 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 	// Convert input data to string for processing
 	var inputStr string
 	if input != nil && input.Data != nil {
-		inputBytes, err := json.MarshalIndent(input.Data, "", "  ")
+		inputBytes, err := json.MarshalIndent(promptData(input.Data), "", "  ")
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal input: %w", err)
 		}
@@ -61,23 +130,69 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 		fullPrompt = fmt.Sprintf("%s\n\nData to process:\n%s", promptTemplate, inputStr)
 	}
 
-	// Call AI provider
-	response, err := provider.Complete(ctx, fullPrompt, systemPrompt)
+	ttl, err := cacheTTL(config)
 	if err != nil {
-		return nil, fmt.Errorf("AI processing failed: %w", err)
+		return nil, err
+	}
+	var cacheKey string
+	if ttl > 0 && e.cache != nil {
+		cacheKey = e.cacheKey(providerName, modelName, strategy, mergeProviders, mergeMode, formatKey(jsonMode, schema), systemPrompt, fullPrompt)
+	}
+
+	// A forced refresh skips cached completions but still stores the new one
+	var response string
+	var metadata map[string]interface{}
+	hit := false
+	if forceRefresh, _ := config["force_refresh"].(bool); cacheKey != "" && !forceRefresh {
+		response, metadata, hit, err = e.cache.GetCompletion(ctx, cacheKey)
+		if err != nil {
+			log.Printf("Warning: AI completion cache lookup failed: %v", err)
+		}
+	}
+
+	if !hit {
+		response, metadata, err = e.complete(ctx, config, providerName, modelName, strategy, mergeProviders, mergeMode, jsonMode, fullPrompt, systemPrompt)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Try to parse response as JSON, otherwise return as string
 	var responseData interface{}
-	if err := json.Unmarshal([]byte(response), &responseData); err != nil {
+	if jsonMode {
+		// JSON responses must parse (and match the schema); one retry tells
+		// the model what was wrong with its first answer
+		responseData, err = parseJSONResponse(response, schema)
+		if err != nil && jsonRetry && !hit {
+			log.Printf("AI response rejected, retrying once: %v", err)
+			retryPrompt := fmt.Sprintf("%s\n\nYour previous response was rejected: %v\nRespond again with only JSON that fixes this.", fullPrompt, err)
+			response, metadata, err = e.complete(ctx, config, providerName, modelName, strategy, mergeProviders, mergeMode, jsonMode, retryPrompt, systemPrompt)
+			if err != nil {
+				return nil, err
+			}
+			metadata["json_retried"] = true
+			responseData, err = parseJSONResponse(response, schema)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("AI %w", err)
+		}
+	} else if err := json.Unmarshal([]byte(response), &responseData); err != nil {
+		// Try to parse response as JSON, otherwise return as string
 		responseData = response
 	}
 
-	// Build metadata with nil-safe input access
-	metadata := map[string]interface{}{
-		"provider":    provider.Name(),
-		"prompt_used": promptTemplate,
+	// Only cache completions that were accepted, and not from dry runs,
+	// whose prompts are still being worked on
+	if dryRun, _ := config["dry_run"].(bool); cacheKey != "" && !hit && !dryRun {
+		if err := e.cache.SaveCompletion(ctx, cacheKey, response, metadata, ttl); err != nil {
+			log.Printf("Warning: failed to cache AI completion: %v", err)
+		}
+	}
+	if cacheKey != "" {
+		metadata["cache_hit"] = hit
 	}
+
+	// Build metadata with nil-safe input access
+	metadata["prompt_used"] = promptTemplate
 	if input != nil {
 		metadata["input_items"] = input.ItemCount
 	}
@@ -95,6 +210,200 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 	}, nil
 }
 
+// complete asks the step's provider, its fallbacks or its merge providers
+// for a completion, returning it with metadata on who answered. A step's
+// model applies to its provider only; fallbacks use their own.
+func (e *Executor) complete(ctx context.Context, config map[string]interface{}, providerName, modelName, strategy string, mergeProviders []string, mergeMode string, jsonMode bool, prompt, systemPrompt string) (string, map[string]interface{}, error) {
+	if strategy == StrategyMerge {
+		// Call every provider and merge their answers
+		response, metadata, err := e.completeMerged(ctx, mergeProviders, mergeMode, providerName, jsonMode, prompt, systemPrompt)
+		if err != nil {
+			return "", nil, fmt.Errorf("AI processing failed: %w", err)
+		}
+		return response, metadata, nil
+	}
+
+	provider, err := e.registry.Get(providerName)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get AI provider: %w", err)
+	}
+	if modelName != "" {
+		provider = provider.WithModel(modelName)
+	}
+	fallbacks, err := fallbackProviders(config)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Call AI provider, falling back in order if it fails
+	response, used, failed, err := e.completeWithFallback(ctx, provider, fallbacks, jsonMode, prompt, systemPrompt)
+	if err != nil {
+		return "", nil, fmt.Errorf("AI processing failed: %w", err)
+	}
+	metadata := map[string]interface{}{"provider": used.Name(), "model": used.Model()}
+	if len(failed) > 0 {
+		metadata["failed_providers"] = failed
+	}
+	return response, metadata, nil
+}
+
+// cacheKey hashes everything that shapes a completion: the providers and
+// models asked, the response format, the system prompt and the full prompt.
+// Fallbacks are left out, so a cached answer is reused whichever provider
+// gave it.
+func (e *Executor) cacheKey(providerName, modelName, strategy string, mergeProviders []string, mergeMode, format, systemPrompt, prompt string) string {
+	var parts []string
+	if strategy == StrategyMerge {
+		parts = append(parts, StrategyMerge, mergeMode)
+		for _, name := range mergeProviders {
+			parts = append(parts, e.providerModel(name))
+		}
+		if mergeMode == MergeCombine {
+			parts = append(parts, e.providerModel(providerName))
+		}
+	} else if modelName != "" {
+		parts = append(parts, e.providerName(providerName)+"/"+modelName)
+	} else {
+		parts = append(parts, e.providerModel(providerName))
+	}
+	if format != "" {
+		parts = append(parts, format)
+	}
+	parts = append(parts, systemPrompt, prompt)
+	return e.cache.HashContent(strings.Join(parts, "\x00"))
+}
+
+// formatKey identifies a JSON response format and its schema for cacheKey;
+// text responses add nothing, so their keys are unchanged
+func formatKey(jsonMode bool, schema map[string]interface{}) string {
+	if !jsonMode {
+		return ""
+	}
+	key := FormatJSON
+	if schema != nil {
+		// Marshalled maps have sorted keys, so equal schemas give equal keys
+		if b, err := json.Marshal(schema); err == nil {
+			key += ":" + string(b)
+		}
+	}
+	return key
+}
+
+// providerModel identifies a provider and the model it is configured with
+func (e *Executor) providerModel(name string) string {
+	provider, err := e.registry.Get(name)
+	if err != nil {
+		return name
+	}
+	return provider.Name() + "/" + provider.Model()
+}
+
+// providerName resolves an empty name to the default provider's
+func (e *Executor) providerName(name string) string {
+	provider, err := e.registry.Get(name)
+	if err != nil {
+		return name
+	}
+	return provider.Name()
+}
+
+// stepModel reads the optional 'model', which replaces the provider's
+// configured model for this step
+func stepModel(config map[string]interface{}) (string, error) {
+	raw, ok := config["model"]
+	if !ok {
+		return "", nil
+	}
+	name, ok := raw.(string)
+	if !ok || name == "" {
+		return "", fmt.Errorf("ai_processor 'model' must be a model name")
+	}
+	if strategy, _ := config["strategy"].(string); strategy == StrategyMerge {
+		return "", fmt.Errorf("ai_processor 'model' can't be combined with the merge strategy")
+	}
+	return name, nil
+}
+
+// cacheTTL reads the optional ai_cache_ttl_seconds; zero disables caching
+func cacheTTL(config map[string]interface{}) (time.Duration, error) {
+	raw, ok := config["ai_cache_ttl_seconds"]
+	if !ok {
+		return 0, nil
+	}
+	seconds, ok := raw.(float64)
+	if !ok || seconds < 0 {
+		return 0, fmt.Errorf("ai_processor 'ai_cache_ttl_seconds' must be a non-negative number of seconds")
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// completeWithFallback calls the primary provider and then each fallback in
+// order until one succeeds. It returns the provider that answered and the
+// names of those that failed, or the last error if all of them failed.
+func (e *Executor) completeWithFallback(ctx context.Context, primary Provider, fallbacks []string, jsonMode bool, prompt, systemPrompt string) (string, Provider, []string, error) {
+	response, err := ask(ctx, primary, jsonMode, prompt, systemPrompt)
+	if err == nil {
+		return response, primary, nil, nil
+	}
+	failed := []string{primary.Name()}
+
+	for _, name := range fallbacks {
+		// A cancelled or timed-out run won't succeed on another provider
+		if ctx.Err() != nil {
+			break
+		}
+		if name == primary.Name() {
+			continue
+		}
+
+		provider, getErr := e.registry.Get(name)
+		if getErr != nil {
+			err = getErr
+			failed = append(failed, name)
+			continue
+		}
+
+		log.Printf("AI provider %s failed, falling back to %s: %v", failed[len(failed)-1], name, err)
+		response, err = ask(ctx, provider, jsonMode, prompt, systemPrompt)
+		if err == nil {
+			return response, provider, failed, nil
+		}
+		failed = append(failed, name)
+	}
+
+	return "", nil, failed, err
+}
+
+// ask requests a completion, through CompleteWithJSON in JSON mode
+func ask(ctx context.Context, provider Provider, jsonMode bool, prompt, systemPrompt string) (string, error) {
+	if jsonMode {
+		return provider.CompleteWithJSON(ctx, prompt, systemPrompt)
+	}
+	return provider.Complete(ctx, prompt, systemPrompt)
+}
+
+// fallbackProviders reads the optional fallback_providers list from config
+func fallbackProviders(config map[string]interface{}) ([]string, error) {
+	raw, ok := config["fallback_providers"]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ai_processor 'fallback_providers' must be an array of provider names")
+	}
+
+	names := make([]string, 0, len(list))
+	for _, v := range list {
+		name, ok := v.(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("ai_processor 'fallback_providers' must be an array of provider names")
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
 // ProcessItems processes a list of items through AI
 func (e *Executor) ProcessItems(ctx context.Context, items []model.ScrapedItem, config map[string]interface{}) (string, error) {
 	providerName, _ := config["provider"].(string)
@@ -110,7 +419,7 @@ func (e *Executor) ProcessItems(ctx context.Context, items []model.ScrapedItem,
 		systemPrompt = "You are a helpful assistant that summarizes job listings and news. Be concise, highlight key information like salary, requirements, and benefits."
 	}
 
-	itemsJSON, err := json.MarshalIndent(items, "", "  ")
+	itemsJSON, err := json.MarshalIndent(promptData(items), "", "  ")
 	if err != nil {
 		return "", err
 	}
@@ -120,6 +429,39 @@ func (e *Executor) ProcessItems(ctx context.Context, items []model.ScrapedItem,
 	return provider.Complete(ctx, fullPrompt, systemPrompt)
 }
 
+// promptData reduces scraped items to the fields relevant to their category,
+// so a news prompt isn't padded with empty salary fields and a job's details
+// aren't buried in extra. Other data is passed through unchanged.
+func promptData(data interface{}) interface{} {
+	items, ok := data.([]model.ScrapedItem)
+	if !ok {
+		return data
+	}
+
+	reduced := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		m := map[string]string{
+			"title":  item.Title,
+			"url":    item.URL,
+			"source": item.Source,
+		}
+		if item.Description != "" {
+			m["description"] = item.Description
+		}
+		if item.Category != "" {
+			m["category"] = item.Category
+		}
+		if item.PostedAt != "" {
+			m["posted_at"] = item.PostedAt
+		}
+		for _, f := range item.RelevantFields() {
+			m[f.Key] = f.Value
+		}
+		reduced = append(reduced, m)
+	}
+	return reduced
+}
+
 // Summarize creates a summary of the input data
 func (e *Executor) Summarize(ctx context.Context, data interface{}, providerName string) (string, error) {
 	provider, err := e.registry.Get(providerName)
@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/storage"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+func TestDryRunsDontCacheCompletions(t *testing.T) {
+	db := storagetest.Open(t)
+	provider := &fakeProvider{respond: func(string) (string, error) { return "A summary", nil }}
+	e := NewExecutor(&ProviderRegistry{
+		providers:       map[string]Provider{"fake": provider},
+		defaultProvider: "fake",
+	}, storage.NewCacheRepository(db))
+
+	// The cache is shared by the whole database, so the prompt is unique
+	prompt := fmt.Sprintf("Summarize (%s %d)", t.Name(), time.Now().UnixNano())
+	run := func(dryRun bool) bool {
+		t.Helper()
+		config := map[string]interface{}{"prompt": prompt, "ai_cache_ttl_seconds": float64(3600)}
+		if dryRun {
+			config["dry_run"] = true
+		}
+		result, err := e.Execute(context.Background(), &model.ExecutorResult{Data: "input", ItemCount: 1}, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.Metadata["cache_hit"] == true
+	}
+
+	if run(true) || run(true) {
+		t.Error("a dry run hit the cache before anything was cached")
+	}
+	if len(provider.prompts) != 2 {
+		t.Errorf("provider called %d times, want 2: dry runs must not cache", len(provider.prompts))
+	}
+
+	if run(false) {
+		t.Error("a normal run hit the cache after dry runs only")
+	}
+	// Dry runs still reuse what real runs cached
+	if !run(true) || !run(false) {
+		t.Error("runs missed the completion a normal run cached")
+	}
+	if len(provider.prompts) != 3 {
+		t.Errorf("provider called %d times, want 3", len(provider.prompts))
+	}
+}
@@ -0,0 +1,204 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/multi-worker/internal/model"
+)
+
+const defaultFilterBatchSize = 10
+
+// FilterExecutor keeps or drops items based on an AI classification
+type FilterExecutor struct {
+	registry *ProviderRegistry
+}
+
+// NewFilterExecutor creates a new AI filter executor
+func NewFilterExecutor(registry *ProviderRegistry) *FilterExecutor {
+	return &FilterExecutor{registry: registry}
+}
+
+// filterDecision is the per-item verdict returned by the model
+type filterDecision struct {
+	Index  int    `json:"index"`
+	Keep   bool   `json:"keep"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (e *FilterExecutor) Type() string {
+	return "ai_filter"
+}
+
+// Cost prices a completion on the step's provider, reporting false when no
+// price is configured for it
+func (e *FilterExecutor) Cost(config map[string]interface{}, inputTokens, outputTokens int) (float64, bool) {
+	providerName, _ := config["provider"].(string)
+	return e.registry.Cost(providerName, inputTokens, outputTokens)
+}
+
+// BatchSize returns how many items the step classifies per request
+func (e *FilterExecutor) BatchSize(config map[string]interface{}) int {
+	if b, ok := config["batch_size"].(float64); ok && b >= 1 {
+		return int(b)
+	}
+	return defaultFilterBatchSize
+}
+
+func (e *FilterExecutor) Validate(config map[string]interface{}) error {
+	criteria, _ := config["criteria"].(string)
+	if criteria == "" {
+		return fmt.Errorf("ai_filter requires 'criteria' in config")
+	}
+	if b, ok := config["batch_size"].(float64); ok && b < 1 {
+		return fmt.Errorf("ai_filter 'batch_size' must be at least 1")
+	}
+	return nil
+}
+
+func (e *FilterExecutor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	if input == nil || input.Data == nil {
+		return input, nil
+	}
+
+	providerName, _ := config["provider"].(string)
+	provider, err := e.registry.Get(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI provider: %w", err)
+	}
+
+	criteria, _ := config["criteria"].(string)
+	batchSize := e.BatchSize(config)
+
+	var filtered interface{}
+	var kept, total, batches int
+
+	switch v := input.Data.(type) {
+	case []model.ScrapedItem:
+		var items []model.ScrapedItem
+		for start := 0; start < len(v); start += batchSize {
+			end := min(start+batchSize, len(v))
+			decisions, err := e.classify(ctx, provider, criteria, v[start:end])
+			if err != nil {
+				return nil, err
+			}
+			batches++
+			for i, item := range v[start:end] {
+				d, ok := decisions[i]
+				if !ok || !d.Keep {
+					continue
+				}
+				if d.Reason != "" {
+					// Extra is shared with the input, which earlier steps may
+					// still hold, so the reason goes on a copy
+					extra := make(map[string]interface{}, len(item.Extra)+1)
+					for k, val := range item.Extra {
+						extra[k] = val
+					}
+					extra["ai_reason"] = d.Reason
+					item.Extra = extra
+				}
+				items = append(items, item)
+			}
+		}
+		filtered, kept, total = items, len(items), len(v)
+
+	case []model.RSSItem:
+		var items []model.RSSItem
+		for start := 0; start < len(v); start += batchSize {
+			end := min(start+batchSize, len(v))
+			decisions, err := e.classify(ctx, provider, criteria, v[start:end])
+			if err != nil {
+				return nil, err
+			}
+			batches++
+			for i, item := range v[start:end] {
+				d, ok := decisions[i]
+				if !ok || !d.Keep {
+					continue
+				}
+				item.AIReason = d.Reason
+				items = append(items, item)
+			}
+		}
+		filtered, kept, total = items, len(items), len(v)
+
+	default:
+		return input, nil
+	}
+
+	metadata := map[string]interface{}{
+		"provider":    provider.Name(),
+		"criteria":    criteria,
+		"input_items": total,
+		"kept_items":  kept,
+		"ai_batches":  batches,
+	}
+
+	return &model.ExecutorResult{
+		Data:      filtered,
+		Metadata:  metadata,
+		ItemCount: kept,
+	}, nil
+}
+
+// classify asks the provider for a keep/drop decision on each item in the batch,
+// returning decisions keyed by the item's index within the batch
+func (e *FilterExecutor) classify(ctx context.Context, provider Provider, criteria string, batch interface{}) (map[int]filterDecision, error) {
+	itemsJSON, err := json.MarshalIndent(batch, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal items: %w", err)
+	}
+
+	systemPrompt := "You are a strict classifier. For each item decide whether it matches the user's criteria. " +
+		`Respond with a JSON array of objects of the form {"index": <int>, "keep": <bool>, "reason": "<short reason>"}, one per item, using the zero-based item index.`
+	prompt := fmt.Sprintf("Criteria:\n%s\n\nItems:\n%s", criteria, string(itemsJSON))
+
+	response, err := provider.CompleteWithJSON(ctx, prompt, systemPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("AI classification failed: %w", err)
+	}
+
+	decisions, err := parseFilterDecisions(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AI classification: %w", err)
+	}
+
+	result := make(map[int]filterDecision, len(decisions))
+	for _, d := range decisions {
+		result[d.Index] = d
+	}
+	return result, nil
+}
+
+// parseFilterDecisions accepts either a bare JSON array or an object wrapping it
+// under "decisions", tolerating markdown code fences around the payload
+func parseFilterDecisions(response string) ([]filterDecision, error) {
+	response = stripCodeFence(response)
+
+	var decisions []filterDecision
+	if err := json.Unmarshal([]byte(response), &decisions); err == nil {
+		return decisions, nil
+	}
+
+	var wrapped struct {
+		Decisions []filterDecision `json:"decisions"`
+	}
+	if err := json.Unmarshal([]byte(response), &wrapped); err != nil {
+		return nil, err
+	}
+	return wrapped.Decisions, nil
+}
+
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	s = strings.TrimPrefix(s, "json")
+	s = strings.TrimSuffix(strings.TrimSpace(s), "```")
+	return strings.TrimSpace(s)
+}
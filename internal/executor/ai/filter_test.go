@@ -0,0 +1,146 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// fakeProvider answers every completion through respond, recording prompts
+type fakeProvider struct {
+	respond func(prompt string) (string, error)
+	prompts []string
+}
+
+func (p *fakeProvider) Name() string                    { return "fake" }
+func (p *fakeProvider) Model() string                   { return "fake-model" }
+func (p *fakeProvider) WithModel(model string) Provider { return p }
+
+func (p *fakeProvider) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	return p.respond(prompt)
+}
+
+func (p *fakeProvider) CompleteWithJSON(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+	return p.Complete(ctx, prompt, systemPrompt)
+}
+
+func newFakeFilter(provider Provider) *FilterExecutor {
+	return NewFilterExecutor(&ProviderRegistry{
+		providers:       map[string]Provider{"fake": provider},
+		defaultProvider: "fake",
+	})
+}
+
+// keepTitled keeps the items of each batch whose title contains word
+func keepTitled(word string) func(prompt string) (string, error) {
+	return func(prompt string) (string, error) {
+		var items []struct {
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal([]byte(prompt[strings.Index(prompt, "["):]), &items); err != nil {
+			return "", err
+		}
+		decisions := make([]filterDecision, len(items))
+		for i, item := range items {
+			keep := strings.Contains(item.Title, word)
+			decisions[i] = filterDecision{Index: i, Keep: keep, Reason: fmt.Sprintf("mentions %s: %v", word, keep)}
+		}
+		b, err := json.Marshal(decisions)
+		return "```json\n" + string(b) + "\n```", err
+	}
+}
+
+func TestFilterKeepsChosenScrapedItems(t *testing.T) {
+	provider := &fakeProvider{respond: keepTitled("Go")}
+	input := []model.ScrapedItem{
+		{ID: "1", Title: "Go developer", Extra: map[string]interface{}{"team": "platform"}},
+		{ID: "2", Title: "PHP developer"},
+		{ID: "3", Title: "Senior Go engineer"},
+	}
+
+	result, err := newFakeFilter(provider).Execute(context.Background(),
+		&model.ExecutorResult{Data: input, ItemCount: len(input)},
+		map[string]interface{}{"criteria": "Go jobs", "batch_size": float64(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := result.Data.([]model.ScrapedItem)
+	if len(items) != 2 || items[0].ID != "1" || items[1].ID != "3" || result.ItemCount != 2 {
+		t.Fatalf("kept %+v (count %d), want items 1 and 3", items, result.ItemCount)
+	}
+	if len(provider.prompts) != 2 || result.Metadata["ai_batches"] != 2 {
+		t.Errorf("made %d requests (ai_batches %v), want 2 batches", len(provider.prompts), result.Metadata["ai_batches"])
+	}
+	if items[0].Extra["ai_reason"] != "mentions Go: true" || items[0].Extra["team"] != "platform" {
+		t.Errorf("extra = %v, want the reason added to the existing fields", items[0].Extra)
+	}
+
+	// Earlier steps may still hold the input, so it must be left as it was
+	if _, ok := input[0].Extra["ai_reason"]; ok {
+		t.Errorf("input item was given a reason: %v", input[0].Extra)
+	}
+	if input[2].Extra != nil {
+		t.Errorf("input item was given extra fields: %v", input[2].Extra)
+	}
+}
+
+func TestFilterAttachesReasonToRSSItems(t *testing.T) {
+	provider := &fakeProvider{respond: keepTitled("release")}
+	input := []model.RSSItem{
+		{ID: "a", Title: "Go 1.24 release"},
+		{ID: "b", Title: "Conference recap"},
+	}
+
+	result, err := newFakeFilter(provider).Execute(context.Background(),
+		&model.ExecutorResult{Data: input}, map[string]interface{}{"criteria": "releases"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := result.Data.([]model.RSSItem)
+	if len(items) != 1 || items[0].ID != "a" {
+		t.Fatalf("kept %+v, want item a", items)
+	}
+	if items[0].AIReason != "mentions release: true" {
+		t.Errorf("ai_reason = %q", items[0].AIReason)
+	}
+	if input[0].AIReason != "" {
+		t.Errorf("input item was given a reason: %q", input[0].AIReason)
+	}
+}
+
+func TestFilterDropsItemsWithoutDecision(t *testing.T) {
+	// The model only answered for the second item, under a "decisions" key
+	provider := &fakeProvider{respond: func(string) (string, error) {
+		return `{"decisions": [{"index": 1, "keep": true}]}`, nil
+	}}
+	input := []model.ScrapedItem{{ID: "1"}, {ID: "2"}}
+
+	result, err := newFakeFilter(provider).Execute(context.Background(),
+		&model.ExecutorResult{Data: input}, map[string]interface{}{"criteria": "anything"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := result.Data.([]model.ScrapedItem)
+	if len(items) != 1 || items[0].ID != "2" || items[0].Extra != nil {
+		t.Errorf("kept %+v, want only item 2 without a reason", items)
+	}
+}
+
+func TestFilterFailsOnProviderError(t *testing.T) {
+	provider := &fakeProvider{respond: func(string) (string, error) {
+		return "", fmt.Errorf("rate limited")
+	}}
+
+	_, err := newFakeFilter(provider).Execute(context.Background(),
+		&model.ExecutorResult{Data: []model.ScrapedItem{{ID: "1"}}}, map[string]interface{}{"criteria": "anything"})
+	if err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("err = %v, want the provider's error", err)
+	}
+}
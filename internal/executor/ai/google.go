@@ -70,6 +70,16 @@ func (p *GoogleProvider) Name() string {
 	return "google"
 }
 
+func (p *GoogleProvider) Model() string {
+	return p.model
+}
+
+func (p *GoogleProvider) WithModel(model string) Provider {
+	c := *p
+	c.model = model
+	return &c
+}
+
 func (p *GoogleProvider) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
 	reqBody := googleRequest{
 		Contents: []googleContent{
@@ -0,0 +1,11 @@
+package ai
+
+import (
+	"github.com/multi-worker/internal/config"
+)
+
+// NewGroqProvider creates a Groq provider (OpenAI-compatible API)
+// See: https://console.groq.com/docs/openai
+func NewGroqProvider(cfg config.GroqConfig) *OpenAICompatibleProvider {
+	return NewOpenAICompatibleProvider("groq", "Groq", cfg.APIKey, cfg.BaseURL, cfg.Model)
+}
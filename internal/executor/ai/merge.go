@@ -0,0 +1,171 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Strategies for running an ai_processor step
+const (
+	StrategyFallback = "fallback" // One provider, then fallback_providers in order (default)
+	StrategyMerge    = "merge"    // Every listed provider, outputs merged
+)
+
+// Ways a merge step combines its drafts
+const (
+	MergeConcat  = "concat"  // Drafts one after another under provider headings (default)
+	MergeCombine = "combine" // A final pass asks the step's provider to combine the drafts
+)
+
+const combineSystemPrompt = "You merge drafts written by different assistants from the same instructions into one answer. Keep every distinct fact, drop repetition, and follow the format the drafts share."
+
+// draft is one provider's answer in a merge step
+type draft struct {
+	provider string
+	response string
+	latency  time.Duration
+	err      error
+}
+
+// mergeConfig reads strategy, providers and merge_mode. The providers list
+// is only returned, and required, for the merge strategy.
+func mergeConfig(config map[string]interface{}) (string, []string, string, error) {
+	strategy, _ := config["strategy"].(string)
+	switch strategy {
+	case "", StrategyFallback:
+		return StrategyFallback, nil, "", nil
+	case StrategyMerge:
+	default:
+		return "", nil, "", fmt.Errorf("ai_processor 'strategy' must be '%s' or '%s'", StrategyFallback, StrategyMerge)
+	}
+
+	list, _ := config["providers"].([]interface{})
+	if len(list) < 2 {
+		return "", nil, "", fmt.Errorf("ai_processor merge strategy requires a 'providers' array of at least two provider names")
+	}
+	providers := make([]string, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		name, ok := v.(string)
+		if !ok || name == "" {
+			return "", nil, "", fmt.Errorf("ai_processor 'providers' must be an array of provider names")
+		}
+		if seen[name] {
+			return "", nil, "", fmt.Errorf("ai_processor 'providers' lists %s twice", name)
+		}
+		seen[name] = true
+		providers = append(providers, name)
+	}
+
+	mode, _ := config["merge_mode"].(string)
+	switch mode {
+	case "":
+		mode = MergeConcat
+	case MergeConcat, MergeCombine:
+	default:
+		return "", nil, "", fmt.Errorf("ai_processor 'merge_mode' must be '%s' or '%s'", MergeConcat, MergeCombine)
+	}
+
+	return StrategyMerge, providers, mode, nil
+}
+
+// completeMerged runs the prompt through every provider concurrently and
+// merges the answers. Providers that fail are left out; the step only fails
+// when all of them do.
+func (e *Executor) completeMerged(ctx context.Context, providers []string, mode, combineWith string, jsonMode bool, prompt, systemPrompt string) (string, map[string]interface{}, error) {
+	drafts := make([]draft, len(providers))
+
+	var wg sync.WaitGroup
+	for i, name := range providers {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			drafts[i].provider = name
+			provider, err := e.registry.Get(name)
+			if err != nil {
+				drafts[i].err = err
+				return
+			}
+			start := time.Now()
+			drafts[i].response, drafts[i].err = ask(ctx, provider, jsonMode, prompt, systemPrompt)
+			drafts[i].latency = time.Since(start)
+		}(i, name)
+	}
+	wg.Wait()
+
+	latencies := make(map[string]int64, len(drafts))
+	var succeeded []draft
+	var failed []string
+	var lastErr error
+	for _, d := range drafts {
+		if d.err != nil {
+			failed = append(failed, d.provider)
+			lastErr = d.err
+			continue
+		}
+		latencies[d.provider] = d.latency.Milliseconds()
+		succeeded = append(succeeded, d)
+	}
+
+	metadata := map[string]interface{}{
+		"strategy":            StrategyMerge,
+		"merge_mode":          mode,
+		"provider_latency_ms": latencies,
+	}
+	if len(failed) > 0 {
+		metadata["failed_providers"] = failed
+	}
+	if len(succeeded) == 0 {
+		return "", metadata, fmt.Errorf("all %d providers failed, last error: %w", len(providers), lastErr)
+	}
+
+	used := make([]string, 0, len(succeeded))
+	for _, d := range succeeded {
+		used = append(used, d.provider)
+	}
+	metadata["providers"] = used
+
+	// A single surviving draft has nothing to be merged with
+	if mode == MergeConcat || len(succeeded) == 1 {
+		return concatDrafts(succeeded), metadata, nil
+	}
+
+	combiner, err := e.registry.Get(combineWith)
+	if err != nil {
+		return "", metadata, fmt.Errorf("failed to get AI provider for combining: %w", err)
+	}
+	start := time.Now()
+	response, err := ask(ctx, combiner, jsonMode, combinePrompt(prompt, succeeded), combineSystemPrompt)
+	if err != nil {
+		return "", metadata, fmt.Errorf("combining %d drafts failed: %w", len(succeeded), err)
+	}
+	metadata["combine_provider"] = combiner.Name()
+	metadata["combine_latency_ms"] = time.Since(start).Milliseconds()
+	return response, metadata, nil
+}
+
+func concatDrafts(drafts []draft) string {
+	if len(drafts) == 1 {
+		return drafts[0].response
+	}
+	var sb strings.Builder
+	for i, d := range drafts {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		fmt.Fprintf(&sb, "## %s\n\n%s", d.provider, strings.TrimSpace(d.response))
+	}
+	return sb.String()
+}
+
+func combinePrompt(prompt string, drafts []draft) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Combine these %d drafts into a single answer to the original instructions.\n\nOriginal instructions:\n%s", len(drafts), prompt)
+	for i, d := range drafts {
+		fmt.Fprintf(&sb, "\n\n--- Draft %d ---\n%s", i+1, strings.TrimSpace(d.response))
+	}
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+)
+
+// OllamaProvider talks to a local Ollama server, so no API key is needed
+// and prompts never leave the machine
+type OllamaProvider struct {
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   string          `json:"format,omitempty"`
+	Options  *ollamaOptions  `json:"options,omitempty"`
+}
+
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature"`
+}
+
+type ollamaResponse struct {
+	Message openAIMessage `json:"message"`
+	Done    bool          `json:"done"`
+	Error   string        `json:"error,omitempty"`
+}
+
+func NewOllamaProvider(cfg config.OllamaConfig) *OllamaProvider {
+	return &OllamaProvider{
+		model:   cfg.Model,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		client: &http.Client{
+			// Local models on modest hardware can be slow
+			Timeout: 5 * time.Minute,
+		},
+	}
+}
+
+func (p *OllamaProvider) Name() string {
+	return "ollama"
+}
+
+func (p *OllamaProvider) Model() string {
+	return p.model
+}
+
+func (p *OllamaProvider) WithModel(model string) Provider {
+	c := *p
+	c.model = model
+	return &c
+}
+
+func (p *OllamaProvider) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+	return p.doRequest(ctx, p.buildRequest(prompt, systemPrompt, ""))
+}
+
+func (p *OllamaProvider) CompleteWithJSON(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+	return p.doRequest(ctx, p.buildRequest(prompt, systemPrompt+" Respond only with valid JSON.", "json"))
+}
+
+func (p *OllamaProvider) buildRequest(prompt, systemPrompt, format string) ollamaRequest {
+	messages := []openAIMessage{}
+
+	if systemPrompt != "" {
+		messages = append(messages, openAIMessage{
+			Role:    "system",
+			Content: systemPrompt,
+		})
+	}
+
+	messages = append(messages, openAIMessage{
+		Role:    "user",
+		Content: prompt,
+	})
+
+	return ollamaRequest{
+		Model:    p.model,
+		Messages: messages,
+		Stream:   false,
+		Format:   format,
+		Options:  &ollamaOptions{Temperature: 0.7},
+	}
+}
+
+func (p *OllamaProvider) doRequest(ctx context.Context, reqBody ollamaRequest) (string, error) {
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result ollamaResponse
+	if resp.StatusCode != http.StatusOK {
+		if err := json.Unmarshal(body, &result); err == nil && result.Error != "" {
+			return "", fmt.Errorf("Ollama API error (HTTP %d): %s", resp.StatusCode, result.Error)
+		}
+		return "", fmt.Errorf("Ollama API error: HTTP %d - %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if result.Error != "" {
+		return "", fmt.Errorf("Ollama API error: %s", result.Error)
+	}
+
+	if result.Message.Content == "" {
+		return "", fmt.Errorf("no response from Ollama")
+	}
+
+	return result.Message.Content, nil
+}
@@ -12,10 +12,16 @@ import (
 	"github.com/multi-worker/internal/config"
 )
 
-type OpenAIProvider struct {
+// OpenAICompatibleProvider talks to any API implementing OpenAI's chat
+// completions endpoint. OpenAI itself, DeepSeek, OpenRouter, Groq and xAI
+// differ only in name, base URL, key, model and extra headers.
+type OpenAICompatibleProvider struct {
+	name    string
+	label   string // Shown in error messages, e.g. "DeepSeek"
 	apiKey  string
 	model   string
 	baseURL string
+	headers map[string]string // Sent with every request
 	client  *http.Client
 }
 
@@ -50,22 +56,41 @@ type openAIResponse struct {
 	} `json:"error,omitempty"`
 }
 
-func NewOpenAIProvider(cfg config.OpenAIConfig) *OpenAIProvider {
-	return &OpenAIProvider{
-		apiKey:  cfg.APIKey,
-		model:   cfg.Model,
-		baseURL: cfg.BaseURL,
+// NewOpenAICompatibleProvider creates a provider registered as name, using
+// label in error messages
+func NewOpenAICompatibleProvider(name, label, apiKey, baseURL, model string) *OpenAICompatibleProvider {
+	return &OpenAICompatibleProvider{
+		name:    name,
+		label:   label,
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: baseURL,
+		headers: make(map[string]string),
 		client: &http.Client{
 			Timeout: 60 * time.Second,
 		},
 	}
 }
 
-func (p *OpenAIProvider) Name() string {
-	return "openai"
+func NewOpenAIProvider(cfg config.OpenAIConfig) *OpenAICompatibleProvider {
+	return NewOpenAICompatibleProvider("openai", "OpenAI", cfg.APIKey, cfg.BaseURL, cfg.Model)
 }
 
-func (p *OpenAIProvider) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+func (p *OpenAICompatibleProvider) Name() string {
+	return p.name
+}
+
+func (p *OpenAICompatibleProvider) Model() string {
+	return p.model
+}
+
+func (p *OpenAICompatibleProvider) WithModel(model string) Provider {
+	c := *p
+	c.model = model
+	return &c
+}
+
+func (p *OpenAICompatibleProvider) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
 	messages := []openAIMessage{}
 
 	if systemPrompt != "" {
@@ -90,11 +115,11 @@ func (p *OpenAIProvider) Complete(ctx context.Context, prompt string, systemProm
 	return p.doRequest(ctx, reqBody)
 }
 
-func (p *OpenAIProvider) CompleteWithJSON(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+func (p *OpenAICompatibleProvider) CompleteWithJSON(ctx context.Context, prompt string, systemPrompt string) (string, error) {
 	return p.Complete(ctx, prompt, systemPrompt+" Respond only with valid JSON.")
 }
 
-func (p *OpenAIProvider) doRequest(ctx context.Context, reqBody openAIRequest) (string, error) {
+func (p *OpenAICompatibleProvider) doRequest(ctx context.Context, reqBody openAIRequest) (string, error) {
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal request: %w", err)
@@ -107,6 +132,9 @@ func (p *OpenAIProvider) doRequest(ctx context.Context, reqBody openAIRequest) (
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
 
 	resp, err := p.client.Do(req)
 	if err != nil {
@@ -123,9 +151,9 @@ func (p *OpenAIProvider) doRequest(ctx context.Context, reqBody openAIRequest) (
 	if resp.StatusCode != http.StatusOK {
 		var result openAIResponse
 		if err := json.Unmarshal(body, &result); err == nil && result.Error != nil {
-			return "", fmt.Errorf("OpenAI API error (HTTP %d): %s", resp.StatusCode, result.Error.Message)
+			return "", fmt.Errorf("%s API error (HTTP %d): %s", p.label, resp.StatusCode, result.Error.Message)
 		}
-		return "", fmt.Errorf("OpenAI API error: HTTP %d - %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("%s API error: HTTP %d - %s", p.label, resp.StatusCode, string(body))
 	}
 
 	var result openAIResponse
@@ -134,11 +162,11 @@ func (p *OpenAIProvider) doRequest(ctx context.Context, reqBody openAIRequest) (
 	}
 
 	if result.Error != nil {
-		return "", fmt.Errorf("OpenAI API error: %s", result.Error.Message)
+		return "", fmt.Errorf("%s API error: %s", p.label, result.Error.Message)
 	}
 
 	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
+		return "", fmt.Errorf("no response from %s", p.label)
 	}
 
 	return result.Choices[0].Message.Content, nil
@@ -10,14 +10,19 @@ import (
 // Provider represents an AI provider interface
 type Provider interface {
 	Name() string
+	Model() string
 	Complete(ctx context.Context, prompt string, systemPrompt string) (string, error)
 	CompleteWithJSON(ctx context.Context, prompt string, systemPrompt string) (string, error)
+	// WithModel returns the provider asking model instead of its configured
+	// one, as a step's 'model' asks
+	WithModel(model string) Provider
 }
 
 // ProviderRegistry manages all AI providers
 type ProviderRegistry struct {
 	providers       map[string]Provider
 	defaultProvider string
+	pricing         map[string]config.TokenPrice
 }
 
 // NewProviderRegistry creates a new provider registry with all configured providers
@@ -25,6 +30,7 @@ func NewProviderRegistry(cfg *config.AIConfig) *ProviderRegistry {
 	registry := &ProviderRegistry{
 		providers:       make(map[string]Provider),
 		defaultProvider: cfg.DefaultProvider,
+		pricing:         cfg.Pricing,
 	}
 
 	// Register OpenAI
@@ -52,6 +58,21 @@ func NewProviderRegistry(cfg *config.AIConfig) *ProviderRegistry {
 		registry.providers["deepseek"] = NewDeepSeekProvider(cfg.DeepSeek)
 	}
 
+	// Register Groq
+	if cfg.Groq.APIKey != "" {
+		registry.providers["groq"] = NewGroqProvider(cfg.Groq)
+	}
+
+	// Register xAI
+	if cfg.XAI.APIKey != "" {
+		registry.providers["xai"] = NewXAIProvider(cfg.XAI)
+	}
+
+	// Register Ollama (local, no API key)
+	if cfg.Ollama.Model != "" {
+		registry.providers["ollama"] = NewOllamaProvider(cfg.Ollama)
+	}
+
 	return registry
 }
 
@@ -69,6 +90,19 @@ func (r *ProviderRegistry) Get(name string) (Provider, error) {
 	return provider, nil
 }
 
+// Cost prices a completion on the named (or default) provider, reporting
+// false when no price is configured for it
+func (r *ProviderRegistry) Cost(name string, inputTokens, outputTokens int) (float64, bool) {
+	if name == "" {
+		name = r.defaultProvider
+	}
+	price, ok := r.pricing[name]
+	if !ok {
+		return 0, false
+	}
+	return (float64(inputTokens)*price.Input + float64(outputTokens)*price.Output) / 1e6, true
+}
+
 // GetDefault returns the default provider
 func (r *ProviderRegistry) GetDefault() (Provider, error) {
 	return r.Get(r.defaultProvider)
@@ -0,0 +1,255 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Response formats of an ai_processor step
+const (
+	FormatText = "text" // Parsed as JSON when possible, otherwise kept as text (default)
+	FormatJSON = "json" // Requested through CompleteWithJSON and required to parse
+)
+
+// schemaTypes are the JSON Schema types json_schema understands
+var schemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// jsonConfig reads response_format, json_schema and json_retry. A schema
+// implies the JSON format.
+func jsonConfig(config map[string]interface{}) (bool, map[string]interface{}, bool, error) {
+	format, _ := config["response_format"].(string)
+	switch format {
+	case "", FormatText, FormatJSON:
+	default:
+		return false, nil, false, fmt.Errorf("ai_processor 'response_format' must be '%s' or '%s'", FormatText, FormatJSON)
+	}
+
+	var schema map[string]interface{}
+	if raw, ok := config["json_schema"]; ok {
+		schema, ok = raw.(map[string]interface{})
+		if !ok {
+			return false, nil, false, fmt.Errorf("ai_processor 'json_schema' must be an object")
+		}
+		if format == FormatText {
+			return false, nil, false, fmt.Errorf("ai_processor 'json_schema' needs response_format '%s'", FormatJSON)
+		}
+		if err := checkSchemaDefinition(schema, "json_schema"); err != nil {
+			return false, nil, false, fmt.Errorf("ai_processor %w", err)
+		}
+	}
+
+	jsonMode := format == FormatJSON || schema != nil
+	if strategy, _ := config["strategy"].(string); jsonMode && strategy == StrategyMerge {
+		// Concatenated drafts under provider headings are never one JSON value
+		if mode, _ := config["merge_mode"].(string); mode != MergeCombine {
+			return false, nil, false, fmt.Errorf("ai_processor JSON responses with the merge strategy need merge_mode '%s'", MergeCombine)
+		}
+	}
+
+	retry, _ := config["json_retry"].(bool)
+	return jsonMode, schema, retry, nil
+}
+
+// checkSchemaDefinition rejects schemas using types or nesting json_schema
+// can't check. Keywords other than type, properties, required, items, enum,
+// additionalProperties, minItems and maxItems are ignored.
+func checkSchemaDefinition(schema map[string]interface{}, path string) error {
+	for _, t := range schemaTypeList(schema) {
+		if !schemaTypes[t] {
+			return fmt.Errorf("%s: unknown type %q", path, t)
+		}
+	}
+	if raw, ok := schema["type"]; ok && len(schemaTypeList(schema)) == 0 {
+		return fmt.Errorf("%s: 'type' must be a string or an array of strings, got %v", path, raw)
+	}
+	if raw, ok := schema["properties"]; ok {
+		props, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: 'properties' must be an object", path)
+		}
+		for name, sub := range props {
+			subSchema, ok := sub.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s.properties.%s must be an object", path, name)
+			}
+			if err := checkSchemaDefinition(subSchema, path+".properties."+name); err != nil {
+				return err
+			}
+		}
+	}
+	if raw, ok := schema["items"]; ok {
+		items, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: 'items' must be an object", path)
+		}
+		if err := checkSchemaDefinition(items, path+".items"); err != nil {
+			return err
+		}
+	}
+	if raw, ok := schema["required"]; ok {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: 'required' must be an array of property names", path)
+		}
+		for _, v := range list {
+			if _, ok := v.(string); !ok {
+				return fmt.Errorf("%s: 'required' must be an array of property names", path)
+			}
+		}
+	}
+	return nil
+}
+
+// parseJSONResponse parses a JSON-mode response, tolerating a surrounding
+// markdown code fence, and checks it against schema when one is set
+func parseJSONResponse(response string, schema map[string]interface{}) (interface{}, error) {
+	text := strings.TrimSpace(response)
+	if strings.HasPrefix(text, "```") && strings.HasSuffix(text, "```") {
+		text = strings.TrimSuffix(text, "```")
+		if i := strings.Index(text, "\n"); i >= 0 {
+			text = text[i+1:] // Drops the fence and its language tag
+		} else {
+			text = strings.TrimPrefix(text, "```")
+		}
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	if schema != nil {
+		if err := matchSchema(data, schema, "$"); err != nil {
+			return nil, fmt.Errorf("response does not match json_schema: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// matchSchema checks value against schema, naming the first offending path
+func matchSchema(value interface{}, schema map[string]interface{}, path string) error {
+	if types := schemaTypeList(schema); types != nil {
+		actual := jsonType(value)
+		matched := false
+		for _, t := range types {
+			if t == actual || (t == "number" && actual == "integer") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(types, " or "), actual)
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if jsonEqual(value, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of the allowed values", path, value)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				name, _ := r.(string)
+				if _, ok := v[name]; !ok {
+					return fmt.Errorf("%s: missing required property %q", path, name)
+				}
+			}
+		}
+
+		// Sorted so the reported path doesn't vary between runs
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			sub, ok := props[key].(map[string]interface{})
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return fmt.Errorf("%s: unexpected property %q", path, key)
+				}
+				continue
+			}
+			if err := matchSchema(v[key], sub, path+"."+key); err != nil {
+				return err
+			}
+		}
+
+	case []interface{}:
+		if min, ok := schema["minItems"].(float64); ok && float64(len(v)) < min {
+			return fmt.Errorf("%s: expected at least %v items, got %d", path, min, len(v))
+		}
+		if max, ok := schema["maxItems"].(float64); ok && float64(len(v)) > max {
+			return fmt.Errorf("%s: expected at most %v items, got %d", path, max, len(v))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := matchSchema(item, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypeList reads 'type' as a list, or nil when it's absent or malformed
+func schemaTypeList(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			s, ok := v.(string)
+			if !ok {
+				return nil
+			}
+			types = append(types, s)
+		}
+		return types
+	}
+	return nil
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func jsonEqual(a, b interface{}) bool {
+	aj, errA := json.Marshal(a)
+	bj, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aj) == string(bj)
+}
@@ -0,0 +1,11 @@
+package ai
+
+import (
+	"github.com/multi-worker/internal/config"
+)
+
+// NewXAIProvider creates an xAI Grok provider (OpenAI-compatible API)
+// See: https://docs.x.ai/docs/api-reference
+func NewXAIProvider(cfg config.XAIConfig) *OpenAICompatibleProvider {
+	return NewOpenAICompatibleProvider("xai", "xAI", cfg.APIKey, cfg.BaseURL, cfg.Model)
+}
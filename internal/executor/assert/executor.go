@@ -0,0 +1,270 @@
+package assert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/multi-worker/internal/executor/filter"
+	"github.com/multi-worker/internal/model"
+)
+
+// Operators on the item count and on item fields
+var (
+	countOps = map[string]string{"eq": "=", "ne": "!=", "lt": "<", "lte": "<=", "gt": ">", "gte": ">="}
+	textOps  = map[string]bool{"contains": true, "not_contains": true, "equals": true, "not_equals": true}
+)
+
+// condition is one check of an assert step. Field "item_count" compares the
+// number of items; any other field is read from each item (or is "text" for
+// AI output) and compared case-insensitively.
+type condition struct {
+	Field string
+	Op    string
+	Count float64
+	Text  string
+}
+
+// Executor checks conditions over the current result. When they hold the
+// input passes on unchanged (or the run stops quietly, for watchers); when
+// they don't, a model.Alert is passed on for the delivery step to send.
+type Executor struct{}
+
+// NewExecutor creates a new assert executor
+func NewExecutor() *Executor {
+	return &Executor{}
+}
+
+func (e *Executor) Type() string {
+	return "assert"
+}
+
+func (e *Executor) Validate(config map[string]interface{}) error {
+	if _, err := parseConditions(config); err != nil {
+		return err
+	}
+	if mode, ok := config["mode"].(string); ok && mode != "all" && mode != "any" {
+		return fmt.Errorf("assert 'mode' must be 'all' or 'any'")
+	}
+	if onPass, ok := config["on_pass"].(string); ok && onPass != "continue" && onPass != "stop" {
+		return fmt.Errorf("assert 'on_pass' must be 'continue' or 'stop'")
+	}
+	return nil
+}
+
+func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	conditions, err := parseConditions(config)
+	if err != nil {
+		return nil, err
+	}
+	mode, _ := config["mode"].(string)
+	onPass, _ := config["on_pass"].(string)
+	title, _ := config["title"].(string)
+	if title == "" {
+		title = "Assertion failed"
+	}
+
+	var data interface{}
+	if input != nil {
+		data = input.Data
+	}
+	count := itemCount(data)
+
+	var failures []string
+	for _, c := range conditions {
+		if failure := c.check(data, count); failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+
+	passed := len(failures) == 0
+	if mode == "any" {
+		passed = len(failures) < len(conditions)
+	}
+
+	if passed {
+		if onPass == "stop" {
+			return nil, filter.NewSkipPipelineError("assertions passed")
+		}
+		result := &model.ExecutorResult{
+			Data:     data,
+			Metadata: map[string]interface{}{"assertions_passed": true},
+		}
+		if input != nil {
+			result.ItemCount = input.ItemCount
+		}
+		return result, nil
+	}
+
+	return &model.ExecutorResult{
+		Data:      model.Alert{Title: title, Failures: failures, ItemCount: count},
+		ItemCount: 1,
+		Metadata: map[string]interface{}{
+			"assertions_passed": false,
+			"failures":          failures,
+		},
+	}, nil
+}
+
+func parseConditions(config map[string]interface{}) ([]condition, error) {
+	raw, ok := config["conditions"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("assert requires a non-empty 'conditions' array")
+	}
+
+	conditions := make([]condition, 0, len(raw))
+	for i, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("assert condition %d must be an object", i+1)
+		}
+		field, _ := m["field"].(string)
+		op, _ := m["op"].(string)
+		if field == "" {
+			return nil, fmt.Errorf("assert condition %d requires 'field'", i+1)
+		}
+
+		c := condition{Field: field, Op: op}
+		if field == "item_count" {
+			if _, ok := countOps[op]; !ok {
+				return nil, fmt.Errorf("assert condition %d: item_count 'op' must be one of eq, ne, lt, lte, gt, gte", i+1)
+			}
+			if c.Count, ok = m["value"].(float64); !ok {
+				return nil, fmt.Errorf("assert condition %d: item_count 'value' must be a number", i+1)
+			}
+		} else {
+			if !textOps[op] {
+				return nil, fmt.Errorf("assert condition %d: 'op' must be one of contains, not_contains, equals, not_equals", i+1)
+			}
+			if c.Text, ok = m["value"].(string); !ok {
+				return nil, fmt.Errorf("assert condition %d: 'value' must be a string", i+1)
+			}
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+// check returns why the condition doesn't hold, or "" when it does
+func (c condition) check(data interface{}, count int) string {
+	if c.Field == "item_count" {
+		n := float64(count)
+		var ok bool
+		switch c.Op {
+		case "eq":
+			ok = n == c.Count
+		case "ne":
+			ok = n != c.Count
+		case "lt":
+			ok = n < c.Count
+		case "lte":
+			ok = n <= c.Count
+		case "gt":
+			ok = n > c.Count
+		case "gte":
+			ok = n >= c.Count
+		}
+		if ok {
+			return ""
+		}
+		return fmt.Sprintf("item count is %d, expected %s %g", count, countOps[c.Op], c.Count)
+	}
+
+	want := strings.ToLower(c.Text)
+	var match func(v string) bool
+	switch c.Op {
+	case "contains", "not_contains":
+		match = func(v string) bool { return strings.Contains(strings.ToLower(v), want) }
+	default:
+		match = func(v string) bool { return strings.ToLower(v) == want }
+	}
+
+	// contains/equals need some item to match; their negations need none to
+	var matched string
+	found := false
+	for _, v := range fieldValues(data, c.Field) {
+		if match(v) {
+			matched, found = v, true
+			break
+		}
+	}
+
+	verb := strings.TrimPrefix(c.Op, "not_")
+	if strings.HasPrefix(c.Op, "not_") {
+		if !found {
+			return ""
+		}
+		return fmt.Sprintf("%s %s %q: %s", c.Field, verb, c.Text, truncate(matched, 200))
+	}
+	if found {
+		return ""
+	}
+	return fmt.Sprintf("no %s %s %q", c.Field, verb, c.Text)
+}
+
+func itemCount(data interface{}) int {
+	switch v := data.(type) {
+	case []model.ScrapedItem:
+		return len(v)
+	case []model.RSSItem:
+		return len(v)
+	case string:
+		if v != "" {
+			return 1
+		}
+	}
+	return 0
+}
+
+// fieldValues reads a field from every item; AI output has a single "text" field
+func fieldValues(data interface{}, field string) []string {
+	var values []string
+	switch v := data.(type) {
+	case string:
+		if field == "text" {
+			values = append(values, v)
+		}
+	case []model.ScrapedItem:
+		for _, item := range v {
+			switch field {
+			case "title":
+				values = append(values, item.Title)
+			case "description":
+				values = append(values, item.Description)
+			case "url":
+				values = append(values, item.URL)
+			case "source":
+				values = append(values, item.Source)
+			case "category":
+				values = append(values, item.Category)
+			default:
+				values = append(values, item.Field(field))
+			}
+		}
+	case []model.RSSItem:
+		for _, item := range v {
+			switch field {
+			case "title":
+				values = append(values, item.Title)
+			case "description":
+				values = append(values, item.Description)
+			case "url", "link":
+				values = append(values, item.Link)
+			case "source":
+				values = append(values, item.Source)
+			case "author":
+				values = append(values, item.Author)
+			case "tags", "categories":
+				values = append(values, strings.Join(item.Categories, ", "))
+			}
+		}
+	}
+	return values
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
@@ -0,0 +1,135 @@
+package assert
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/multi-worker/internal/executor/filter"
+	"github.com/multi-worker/internal/model"
+)
+
+// statusPage is what a status-page scraper returns when all is well
+var statusPage = []model.ScrapedItem{
+	{Title: "API", Description: "Operational", Source: "status"},
+	{Title: "Dashboard", Description: "Operational", Source: "status"},
+}
+
+func conditions(c ...map[string]interface{}) []interface{} {
+	out := make([]interface{}, len(c))
+	for i := range c {
+		out[i] = c[i]
+	}
+	return out
+}
+
+func run(t *testing.T, data interface{}, config map[string]interface{}) (*model.ExecutorResult, error) {
+	t.Helper()
+	e := NewExecutor()
+	if err := e.Validate(config); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+	return e.Execute(context.Background(), &model.ExecutorResult{Data: data, ItemCount: itemCount(data)}, config)
+}
+
+func TestAssertionsPass(t *testing.T) {
+	config := map[string]interface{}{"conditions": conditions(
+		map[string]interface{}{"field": "item_count", "op": "eq", "value": float64(2)},
+		map[string]interface{}{"field": "description", "op": "not_contains", "value": "outage"},
+		map[string]interface{}{"field": "title", "op": "equals", "value": "api"},
+	)}
+
+	result, err := run(t, statusPage, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, ok := result.Data.([]model.ScrapedItem)
+	if !ok || len(items) != 2 || result.ItemCount != 2 || result.Metadata["assertions_passed"] != true {
+		t.Errorf("result = %+v, want the input passed on", result)
+	}
+
+	// Watchers stop quietly instead of delivering the unchanged input
+	config["on_pass"] = "stop"
+	if _, err := run(t, statusPage, config); !errors.As(err, new(filter.SkipPipelineError)) {
+		t.Errorf("err = %v, want the pipeline skipped", err)
+	}
+}
+
+func TestAssertionsTriggerAlert(t *testing.T) {
+	degraded := []model.ScrapedItem{
+		{Title: "API", Description: "Major outage"},
+		{Title: "Dashboard", Description: "Operational"},
+	}
+	config := map[string]interface{}{
+		"title": "Status page changed",
+		"conditions": conditions(
+			map[string]interface{}{"field": "description", "op": "not_contains", "value": "outage"},
+			map[string]interface{}{"field": "item_count", "op": "gte", "value": float64(3)},
+			map[string]interface{}{"field": "title", "op": "contains", "value": "dashboard"},
+		),
+	}
+
+	result, err := run(t, degraded, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert, ok := result.Data.(model.Alert)
+	if !ok {
+		t.Fatalf("data = %T, want an alert", result.Data)
+	}
+	if alert.Title != "Status page changed" || alert.ItemCount != 2 || result.ItemCount != 1 {
+		t.Errorf("alert = %+v (count %d)", alert, result.ItemCount)
+	}
+	// One line per failed condition; the passing one isn't mentioned
+	want := []string{`description contains "outage": Major outage`, "item count is 2, expected >= 3"}
+	if strings.Join(alert.Failures, "|") != strings.Join(want, "|") {
+		t.Errorf("failures = %q, want %q", alert.Failures, want)
+	}
+	if result.Metadata["assertions_passed"] != false {
+		t.Errorf("metadata = %v", result.Metadata)
+	}
+
+	// With mode any, one holding condition is enough
+	config["mode"] = "any"
+	if result, err := run(t, degraded, config); err != nil || result.Metadata["assertions_passed"] != true {
+		t.Errorf("mode any: %+v, %v; want a pass", result, err)
+	}
+}
+
+func TestAssertionsOnAIText(t *testing.T) {
+	config := map[string]interface{}{"conditions": conditions(
+		map[string]interface{}{"field": "text", "op": "contains", "value": "all systems operational"},
+	)}
+	if result, err := run(t, "All systems operational.", config); err != nil || result.Metadata["assertions_passed"] != true {
+		t.Errorf("matching text: %+v, %v", result, err)
+	}
+	result, err := run(t, "Degraded performance in eu-west.", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert, ok := result.Data.(model.Alert); !ok || alert.Failures[0] != `no text contains "all systems operational"` {
+		t.Errorf("data = %+v, want an alert", result.Data)
+	}
+}
+
+func TestValidateAssertConfig(t *testing.T) {
+	e := NewExecutor()
+	invalid := []map[string]interface{}{
+		{},
+		{"conditions": []interface{}{}},
+		{"conditions": []interface{}{"item_count > 1"}},
+		{"conditions": conditions(map[string]interface{}{"op": "eq", "value": float64(1)})},
+		{"conditions": conditions(map[string]interface{}{"field": "item_count", "op": "contains", "value": float64(1)})},
+		{"conditions": conditions(map[string]interface{}{"field": "item_count", "op": "eq", "value": "1"})},
+		{"conditions": conditions(map[string]interface{}{"field": "title", "op": "gt", "value": "x"})},
+		{"conditions": conditions(map[string]interface{}{"field": "title", "op": "contains", "value": float64(1)})},
+		{"conditions": conditions(map[string]interface{}{"field": "title", "op": "contains", "value": "x"}), "mode": "most"},
+		{"conditions": conditions(map[string]interface{}{"field": "title", "op": "contains", "value": "x"}), "on_pass": "alert"},
+	}
+	for i, config := range invalid {
+		if err := e.Validate(config); err == nil {
+			t.Errorf("config %d was accepted: %v", i+1, config)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// Date formats accepted in the step's date_format option; anything else is
+// used as a Go time layout
+const (
+	DateRelative = "relative" // "2 hours ago"
+	DateAbsolute = "absolute" // "15 Jan 2025 09:00 WIB"
+
+	absoluteLayout = "02 Jan 2006 15:04 MST"
+)
+
+// dateFormatter renders dates for humans in the configured zone
+type dateFormatter struct {
+	format string
+	loc    *time.Location
+	now    func() time.Time
+}
+
+// newDateFormatter reads date_format and timezone from the step config,
+// falling back to the task's timezone and then to UTC
+func newDateFormatter(config map[string]interface{}) (*dateFormatter, error) {
+	format, _ := config["date_format"].(string)
+
+	tz, _ := config["timezone"].(string)
+	if tz == "" {
+		tz, _ = config["task_timezone"].(string)
+	}
+	loc := time.UTC
+	if tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+	}
+
+	return &dateFormatter{format: format, loc: loc, now: time.Now}, nil
+}
+
+// Enabled reports whether the step asked for human-readable dates
+func (f *dateFormatter) Enabled() bool {
+	return f.format != ""
+}
+
+// Format renders a date string in the configured format, defaulting to the
+// absolute format. Unparseable input is returned unchanged.
+func (f *dateFormatter) Format(dateStr string) string {
+	t, ok := parseDate(dateStr)
+	if !ok {
+		return dateStr
+	}
+
+	switch f.format {
+	case DateRelative:
+		return f.relative(t)
+	case "", DateAbsolute:
+		return t.In(f.loc).Format(absoluteLayout)
+	default:
+		return t.In(f.loc).Format(f.format)
+	}
+}
+
+// Relative renders a date string relative to now regardless of date_format
+func (f *dateFormatter) Relative(dateStr string) string {
+	t, ok := parseDate(dateStr)
+	if !ok {
+		return dateStr
+	}
+	return f.relative(t)
+}
+
+func (f *dateFormatter) relative(t time.Time) string {
+	d := f.now().Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var amount string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		amount = plural(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		amount = plural(int(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		amount = plural(int(d/(24*time.Hour)), "day")
+	default:
+		// Past a month a calendar date is more useful than "7 weeks ago"
+		return t.In(f.loc).Format(absoluteLayout)
+	}
+
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
+
+// funcs returns the date helpers available to message templates
+func (f *dateFormatter) funcs() map[string]interface{} {
+	return map[string]interface{}{
+		"formatDate":   f.Format,
+		"relativeDate": f.Relative,
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func parseDate(dateStr string) (time.Time, bool) {
+	return model.ParseDate(dateStr)
+}
+
+// parseAndFormatDate converts a feed date to the ISO 8601 timestamp Discord
+// expects in embeds; Discord itself renders it in the viewer's local time
+func parseAndFormatDate(dateStr string) string {
+	if t, ok := parseDate(dateStr); ok {
+		return t.Format(time.RFC3339)
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+)
+
+func fixedFormatter(t *testing.T, config map[string]interface{}, now time.Time) *dateFormatter {
+	t.Helper()
+	f, err := newDateFormatter(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.now = func() time.Time { return now }
+	return f
+}
+
+func TestRelativeDates(t *testing.T) {
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	f := fixedFormatter(t, map[string]interface{}{"date_format": DateRelative}, now)
+
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2025-01-15T08:59:30Z", "just now"},
+		{"2025-01-15T08:59:00Z", "1 minute ago"},
+		{"2025-01-15T07:00:00Z", "2 hours ago"},
+		{"2025-01-15T12:00:00+05:00", "2 hours ago"}, // The offset is honoured
+		{"2025-01-12T09:00:00Z", "3 days ago"},
+		{"2025-01-15T10:30:00Z", "in 1 hour"},
+		{"2024-11-01T09:00:00Z", "01 Nov 2024 09:00 UTC"}, // Past a month, the calendar date
+		{"last tuesday", "last tuesday"},
+	}
+	for _, tt := range tests {
+		if got := f.Format(tt.date); got != tt.want {
+			t.Errorf("Format(%q) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestAbsoluteDatesInZone(t *testing.T) {
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	const date = "2025-01-15T02:00:00Z"
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{"step timezone", map[string]interface{}{"date_format": DateAbsolute, "timezone": "Asia/Jakarta"}, "15 Jan 2025 09:00 WIB"},
+		{"task timezone", map[string]interface{}{"date_format": DateAbsolute, "task_timezone": "Asia/Jakarta"}, "15 Jan 2025 09:00 WIB"},
+		{"step overrides task", map[string]interface{}{"date_format": DateAbsolute, "timezone": "Europe/London", "task_timezone": "Asia/Jakarta"}, "15 Jan 2025 02:00 GMT"},
+		{"utc by default", map[string]interface{}{"date_format": DateAbsolute}, "15 Jan 2025 02:00 UTC"},
+		{"go layout", map[string]interface{}{"date_format": "Mon 2 Jan 15:04", "timezone": "America/New_York"}, "Tue 14 Jan 21:00"},
+	}
+	for _, tt := range tests {
+		f := fixedFormatter(t, tt.config, now)
+		if got := f.Format(date); got != tt.want {
+			t.Errorf("%s: Format = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := newDateFormatter(map[string]interface{}{"timezone": "Mars/Olympus"}); err == nil {
+		t.Error("unknown timezone was accepted")
+	}
+}
+
+func TestTemplateDateFuncs(t *testing.T) {
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	f := fixedFormatter(t, map[string]interface{}{"timezone": "Asia/Jakarta"}, now)
+
+	got, err := executeTemplate(`{{range .}}{{formatDate .PubDate}} ({{relativeDate .PubDate}}){{end}}`,
+		[]model.RSSItem{{PubDate: "Wed, 15 Jan 2025 07:00:00 +0000"}}, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "15 Jan 2025 14:00 WIB (2 hours ago)"; got != want {
+		t.Errorf("template = %q, want %q", got, want)
+	}
+}
+
+func TestEmbedFooterShowsZonedDate(t *testing.T) {
+	items := scrapedItems(1)
+	items[0].PostedAt = "2025-01-15T02:00:00Z"
+
+	messages := preview(t, items, map[string]interface{}{"date_format": DateAbsolute, "timezone": "Asia/Jakarta"})
+	embed := messages[0].Embeds[0]
+	if embed.Footer == nil || embed.Footer.Text != "remoteok · 15 Jan 2025 09:00 WIB" {
+		t.Errorf("footer = %+v", embed.Footer)
+	}
+	// Discord renders the timestamp itself, so it stays machine-readable
+	if embed.Timestamp != "2025-01-15T02:00:00Z" {
+		t.Errorf("timestamp = %q", embed.Timestamp)
+	}
+
+	messages = preview(t, items, map[string]interface{}{})
+	if footer := messages[0].Embeds[0].Footer; footer == nil || footer.Text != "remoteok" {
+		t.Errorf("footer without date_format = %+v", footer)
+	}
+}
@@ -0,0 +1,271 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// Display modes for scraped and RSS items
+const (
+	DisplayDetailed = "detailed" // One embed per item
+	DisplayCompact  = "compact"  // One embed with a bullet list of links
+	DisplayText     = "text"     // Plain numbered list, no embeds
+)
+
+// Image styles for items that carry an image, e.g. RSS media thumbnails
+const (
+	ImageThumbnail = "thumbnail" // Small image beside the embed text (default)
+	ImageLarge     = "image"     // Full-width image below the embed text
+	ImageNone      = "none"      // Leave images out
+)
+
+// setEmbedImage shows an item's image on its embed in the given style.
+// Discord rejects the whole message over an embed image that isn't an
+// absolute http(s) URL, so others are left out.
+func setEmbedImage(embed *model.DiscordEmbed, imageURL, style string) {
+	if !strings.HasPrefix(imageURL, "https://") && !strings.HasPrefix(imageURL, "http://") {
+		return
+	}
+	switch style {
+	case ImageNone:
+	case ImageLarge:
+		embed.Image = &model.DiscordEmbedImage{URL: imageURL}
+	default:
+		embed.Thumbnail = &model.DiscordEmbedImage{URL: imageURL}
+	}
+}
+
+// Discord message limits
+const (
+	maxEmbedsPerMessage = 10
+	maxEmbedTotalChars  = 6000
+	maxEmbedDescription = 4096
+	maxContentLength    = 2000
+
+	defaultCompactItems = 25
+)
+
+// listItem is the title/link pair rendered by the compact and text modes
+type listItem struct {
+	Title string
+	URL   string
+}
+
+func listItems(data interface{}) ([]listItem, error) {
+	var items []listItem
+	switch v := data.(type) {
+	case []model.ScrapedItem:
+		for _, item := range v {
+			items = append(items, listItem{Title: item.Title, URL: item.URL})
+		}
+	case []model.RSSItem:
+		for _, item := range v {
+			items = append(items, listItem{Title: item.Title, URL: item.Link})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported data type for %s display", DisplayCompact)
+	}
+	return items, nil
+}
+
+// embedMessages packs embeds into as few messages as Discord's per-message
+// embed count and total character limits allow
+func embedMessages(embeds []model.DiscordEmbed) []*model.DiscordMessage {
+	var messages []*model.DiscordMessage
+	var current *model.DiscordMessage
+	var chars int
+
+	for _, embed := range embeds {
+		size := embedChars(embed)
+		if current == nil || len(current.Embeds) >= maxEmbedsPerMessage || chars+size > maxEmbedTotalChars {
+			current = &model.DiscordMessage{}
+			messages = append(messages, current)
+			chars = 0
+		}
+		current.Embeds = append(current.Embeds, embed)
+		chars += size
+	}
+
+	return messages
+}
+
+func embedChars(embed model.DiscordEmbed) int {
+	n := len(embed.Title) + len(embed.Description)
+	for _, f := range embed.Fields {
+		n += len(f.Name) + len(f.Value)
+	}
+	if embed.Footer != nil {
+		n += len(embed.Footer.Text)
+	}
+	return n
+}
+
+// compactMessages renders up to maxItems items as bullet-point links, starting
+// a new embed whenever the description limit would be exceeded
+func compactMessages(data interface{}, color, maxItems int) ([]*model.DiscordMessage, error) {
+	items, err := listItems(data)
+	if err != nil {
+		return nil, err
+	}
+	items, more := capItems(items, maxItems)
+
+	var embeds []model.DiscordEmbed
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			embeds = append(embeds, model.DiscordEmbed{Description: b.String(), Color: color})
+			b.Reset()
+		}
+	}
+
+	for _, item := range items {
+		line := "• " + truncate(item.Title, 256)
+		if item.URL != "" {
+			line = "• [" + truncate(item.Title, 256) + "](" + item.URL + ")"
+		}
+		if b.Len()+len(line)+1 > maxEmbedDescription {
+			flush()
+		}
+		b.WriteString(line + "\n")
+	}
+	if more > 0 {
+		line := fmt.Sprintf("…and %d more", more)
+		if b.Len()+len(line) > maxEmbedDescription {
+			flush()
+		}
+		b.WriteString(line)
+	}
+	flush()
+
+	if len(embeds) > 0 {
+		embeds[0].Title = fmt.Sprintf("%d new items", len(items)+more)
+	}
+	return embedMessages(embeds), nil
+}
+
+// textMessages renders up to maxItems items as a plain numbered list, split
+// across messages at Discord's content limit
+func textMessages(data interface{}, maxItems int) ([]*model.DiscordMessage, error) {
+	items, err := listItems(data)
+	if err != nil {
+		return nil, err
+	}
+	items, more := capItems(items, maxItems)
+
+	var lines []string
+	for i, item := range items {
+		line := fmt.Sprintf("%d. %s", i+1, truncate(item.Title, 256))
+		if item.URL != "" {
+			// Angle brackets suppress link previews
+			line += " <" + item.URL + ">"
+		}
+		lines = append(lines, line)
+	}
+	if more > 0 {
+		lines = append(lines, fmt.Sprintf("…and %d more", more))
+	}
+
+	var messages []*model.DiscordMessage
+	var b strings.Builder
+	for _, line := range lines {
+		line = truncate(line, maxContentLength)
+		if b.Len() > 0 && b.Len()+len(line)+1 > maxContentLength {
+			messages = append(messages, &model.DiscordMessage{Content: b.String()})
+			b.Reset()
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(line)
+	}
+	if b.Len() > 0 {
+		messages = append(messages, &model.DiscordMessage{Content: b.String()})
+	}
+	return messages, nil
+}
+
+func capItems(items []listItem, maxItems int) ([]listItem, int) {
+	if len(items) <= maxItems {
+		return items, 0
+	}
+	return items[:maxItems], len(items) - maxItems
+}
+
+// alertColor is the embed color of failed assertions
+const alertColor = 0xE74C3C
+
+// alertMessage renders a failed assertion as a single red embed
+func alertMessage(alert model.Alert) *model.DiscordMessage {
+	var lines []string
+	for _, f := range alert.Failures {
+		lines = append(lines, "• "+f)
+	}
+	return &model.DiscordMessage{Embeds: []model.DiscordEmbed{{
+		Title:       truncate("⚠️ "+alert.Title, 256),
+		Description: truncate(strings.Join(lines, "\n"), 4096),
+		Color:       alertColor,
+	}}}
+}
+
+// contentMessages sends long text as several messages split at natural
+// boundaries instead of cutting it off at the content limit
+func contentMessages(content string) []*model.DiscordMessage {
+	var messages []*model.DiscordMessage
+	for _, part := range splitForDiscord(content, maxContentLength) {
+		messages = append(messages, &model.DiscordMessage{Content: part})
+	}
+	return messages
+}
+
+// codeBlockMessages sends text as fenced code blocks, split like
+// contentMessages so every block fits in one message with its fences
+func codeBlockMessages(text, lang string) []*model.DiscordMessage {
+	fenceOpen, fenceClose := "```"+lang+"\n", "\n```"
+	var messages []*model.DiscordMessage
+	for _, part := range splitForDiscord(text, maxContentLength-len(fenceOpen)-len(fenceClose)) {
+		messages = append(messages, &model.DiscordMessage{Content: fenceOpen + part + fenceClose})
+	}
+	return messages
+}
+
+// splitBoundaries are the places text is preferably split at, best first
+var splitBoundaries = []string{"\n\n", "\n", ". ", "! ", "? ", "; ", ", ", " "}
+
+// splitForDiscord splits s into parts of at most max bytes, breaking at the
+// last paragraph, line, sentence or word boundary that keeps a part at least
+// a third full. Only text without any such boundary is cut mid-word.
+func splitForDiscord(s string, max int) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	var parts []string
+	for len(s) > max {
+		cut := -1
+		for _, sep := range splitBoundaries {
+			if i := strings.LastIndex(s[:max], sep); i >= max/3 {
+				// Keep sentence punctuation with the sentence it ends
+				cut = i + len(strings.TrimRight(sep, " \n"))
+				break
+			}
+		}
+		if cut <= 0 {
+			cut = max
+			for cut > 0 && !utf8.RuneStart(s[cut]) {
+				cut--
+			}
+		}
+
+		parts = append(parts, strings.TrimRightFunc(s[:cut], unicode.IsSpace))
+		s = strings.TrimLeftFunc(s[cut:], unicode.IsSpace)
+	}
+	if s != "" {
+		parts = append(parts, s)
+	}
+	return parts
+}
@@ -0,0 +1,231 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+)
+
+func newTestExecutor() *Executor {
+	return NewExecutor(config.DiscordConfig{}, netguard.New(config.OutboundConfig{}))
+}
+
+func scrapedItems(n int) []model.ScrapedItem {
+	items := make([]model.ScrapedItem, n)
+	for i := range items {
+		items[i] = model.ScrapedItem{
+			Title:  fmt.Sprintf("Job %d", i+1),
+			URL:    fmt.Sprintf("https://example.com/jobs/%d", i+1),
+			Source: "remoteok",
+		}
+	}
+	return items
+}
+
+func preview(t *testing.T, items []model.ScrapedItem, config map[string]interface{}) []*model.DiscordMessage {
+	t.Helper()
+	out, err := newTestExecutor().Preview(&model.ExecutorResult{Data: items, ItemCount: len(items)}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.([]*model.DiscordMessage)
+}
+
+func TestDetailedModeSendsAnEmbedPerItem(t *testing.T) {
+	messages := preview(t, scrapedItems(12), map[string]interface{}{})
+
+	// Ten embeds fit in a message, so twelve items take two
+	if len(messages) != 2 || len(messages[0].Embeds) != 10 || len(messages[1].Embeds) != 2 {
+		t.Fatalf("got %d messages, want 10 embeds then 2", len(messages))
+	}
+	embed := messages[0].Embeds[0]
+	if embed.Title != "Job 1" || embed.URL != "https://example.com/jobs/1" {
+		t.Errorf("first embed = %+v", embed)
+	}
+	if messages[0].Content != "" {
+		t.Errorf("detailed mode sent content %q", messages[0].Content)
+	}
+}
+
+func TestCompactModeListsLinksInOneEmbed(t *testing.T) {
+	messages := preview(t, scrapedItems(30), map[string]interface{}{
+		"display_mode": DisplayCompact,
+		"max_items":    float64(20),
+		"username":     "Jobs bot",
+	})
+
+	if len(messages) != 1 || len(messages[0].Embeds) != 1 {
+		t.Fatalf("got %d messages, want a single embed", len(messages))
+	}
+	embed := messages[0].Embeds[0]
+	if embed.Title != "30 new items" {
+		t.Errorf("title = %q", embed.Title)
+	}
+	lines := strings.Split(strings.TrimSpace(embed.Description), "\n")
+	if len(lines) != 21 {
+		t.Fatalf("got %d lines, want 20 links and a remainder line", len(lines))
+	}
+	if lines[0] != "• [Job 1](https://example.com/jobs/1)" || lines[20] != "…and 10 more" {
+		t.Errorf("lines = %q ... %q", lines[0], lines[20])
+	}
+	if messages[0].Username != "Jobs bot" {
+		t.Errorf("username = %q", messages[0].Username)
+	}
+}
+
+func TestCompactModeSplitsAtDescriptionLimit(t *testing.T) {
+	items := scrapedItems(60)
+	for i := range items {
+		items[i].Title = strings.Repeat("x", 200)
+	}
+	messages := preview(t, items, map[string]interface{}{"display_mode": DisplayCompact, "max_items": float64(60)})
+
+	var embeds []model.DiscordEmbed
+	for _, m := range messages {
+		embeds = append(embeds, m.Embeds...)
+		if embedTotal(m) > maxEmbedTotalChars {
+			t.Errorf("message carries %d embed characters, over Discord's limit", embedTotal(m))
+		}
+	}
+	if len(embeds) < 2 {
+		t.Fatalf("got %d embeds, want the list split", len(embeds))
+	}
+	links := 0
+	for _, e := range embeds {
+		if len(e.Description) > maxEmbedDescription {
+			t.Errorf("embed description is %d characters", len(e.Description))
+		}
+		links += strings.Count(e.Description, "• ")
+	}
+	if links != 60 {
+		t.Errorf("listed %d items across embeds, want 60", links)
+	}
+}
+
+func TestTextModeSendsNumberedContent(t *testing.T) {
+	messages := preview(t, scrapedItems(3), map[string]interface{}{"display_mode": DisplayText})
+
+	if len(messages) != 1 || len(messages[0].Embeds) != 0 {
+		t.Fatalf("got %+v, want one message without embeds", messages)
+	}
+	want := "1. Job 1 <https://example.com/jobs/1>\n2. Job 2 <https://example.com/jobs/2>\n3. Job 3 <https://example.com/jobs/3>"
+	if messages[0].Content != want {
+		t.Errorf("content = %q, want %q", messages[0].Content, want)
+	}
+}
+
+func TestTextModeSplitsAtContentLimit(t *testing.T) {
+	items := scrapedItems(100)
+	for i := range items {
+		items[i].Title = strings.Repeat("y", 100)
+	}
+	messages := preview(t, items, map[string]interface{}{"display_mode": DisplayText, "max_items": float64(100)})
+
+	if len(messages) < 2 {
+		t.Fatalf("got %d messages, want the list split", len(messages))
+	}
+	lines := 0
+	for _, m := range messages {
+		if len(m.Content) > maxContentLength {
+			t.Errorf("message is %d characters", len(m.Content))
+		}
+		lines += len(strings.Split(m.Content, "\n"))
+	}
+	if lines != 100 {
+		t.Errorf("sent %d lines, want 100", lines)
+	}
+}
+
+func embedTotal(m *model.DiscordMessage) int {
+	n := 0
+	for _, e := range m.Embeds {
+		n += embedChars(e)
+	}
+	return n
+}
+
+func TestSplitForDiscordBreaksAtBoundaries(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		max  int
+		want []string
+	}{
+		{"fits", "One sentence.", 20, []string{"One sentence."}},
+		{"paragraph", "First paragraph.\n\nSecond paragraph.", 30, []string{"First paragraph.", "Second paragraph."}},
+		{"sentence", "Go 1.24 is out. It ships generic aliases. Upgrade soon.", 45, []string{"Go 1.24 is out. It ships generic aliases.", "Upgrade soon."}},
+		{"question", "Is it fast? Yes, very fast indeed.", 22, []string{"Is it fast?", "Yes, very fast indeed."}},
+		{"word", "alpha beta gamma delta", 12, []string{"alpha beta", "gamma delta"}},
+		{"no boundary", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"multibyte", "ééééé", 5, []string{"éé", "éé", "é"}},
+		{"blank", "  \n ", 10, nil},
+	}
+	for _, tt := range tests {
+		got := splitForDiscord(tt.text, tt.max)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("%s: split = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLongAISummarySplitsBetweenSentences(t *testing.T) {
+	var b strings.Builder
+	for i := 1; b.Len() < 3*maxContentLength; i++ {
+		fmt.Fprintf(&b, "Sentence number %d of the summary ends here. ", i)
+		if i%12 == 0 {
+			b.WriteString("\n\n")
+		}
+	}
+	summary := strings.TrimSpace(b.String())
+
+	out, err := newTestExecutor().Preview(&model.ExecutorResult{Data: summary}, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := out.([]*model.DiscordMessage)
+	if len(messages) < 3 {
+		t.Fatalf("got %d messages, want the summary split over several", len(messages))
+	}
+
+	var rejoined []string
+	for i, m := range messages {
+		if len(m.Content) > maxContentLength {
+			t.Errorf("message %d is %d bytes, over the limit", i+1, len(m.Content))
+		}
+		if !strings.HasSuffix(m.Content, "ends here.") || strings.HasSuffix(m.Content, "...") {
+			t.Errorf("message %d is cut mid-sentence: ...%q", i+1, m.Content[len(m.Content)-20:])
+		}
+		rejoined = append(rejoined, m.Content)
+	}
+
+	// Nothing is lost and the parts are in order
+	normalize := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+	if normalize(strings.Join(rejoined, " ")) != normalize(summary) {
+		t.Error("split messages don't add up to the summary")
+	}
+}
+
+func TestAlertIsSentAsOneRedEmbed(t *testing.T) {
+	alert := model.Alert{Title: "Status page changed", Failures: []string{"item count is 2, expected >= 3"}, ItemCount: 2}
+
+	// Whatever the display mode, an alert is never rendered as items
+	for _, mode := range []string{DisplayDetailed, DisplayCompact, DisplayText} {
+		out, err := newTestExecutor().Preview(&model.ExecutorResult{Data: alert, ItemCount: 1}, map[string]interface{}{"display_mode": mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+		messages := out.([]*model.DiscordMessage)
+		if len(messages) != 1 || len(messages[0].Embeds) != 1 {
+			t.Fatalf("%s: got %d messages, want one embed", mode, len(messages))
+		}
+		embed := messages[0].Embeds[0]
+		if embed.Color != alertColor || !strings.Contains(embed.Title, "Status page changed") ||
+			embed.Description != "• item count is 2, expected >= 3" {
+			t.Errorf("%s: embed = %+v", mode, embed)
+		}
+	}
+}
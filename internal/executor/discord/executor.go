@@ -7,32 +7,37 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
-	"sync"
+	"strconv"
 	"text/template"
 	"time"
 
 	"github.com/multi-worker/internal/config"
 	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+)
+
+// Retries of a message Discord rate-limited (HTTP 429). A wait longer than
+// maxRetryAfter fails the send instead, rather than stalling the run.
+const (
+	maxRateLimitRetries = 3
+	maxRetryAfter       = 60 * time.Second
 )
 
 // Executor handles Discord notifications in pipelines
 type Executor struct {
 	defaultWebhook string
-	rateLimit      time.Duration
-	lastSend       time.Time
-	mu             sync.Mutex
+	rateLimit      time.Duration // Minimum gap between sends to the same webhook
 	client         *http.Client
+	guard          *netguard.Guard
 }
 
 // NewExecutor creates a new Discord executor
-func NewExecutor(cfg config.DiscordConfig) *Executor {
+func NewExecutor(cfg config.DiscordConfig, guard *netguard.Guard) *Executor {
 	return &Executor{
 		defaultWebhook: cfg.DefaultWebhook,
 		rateLimit:      time.Duration(cfg.RateLimitMs) * time.Millisecond,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		client:         guard.Client(10 * time.Second),
+		guard:          guard,
 	}
 }
 
@@ -47,7 +52,29 @@ func (e *Executor) Validate(config map[string]interface{}) error {
 	// 3. Bot/channel configuration in database (resolved at runtime)
 	// 4. Task-specific discord config (resolved at runtime)
 	// So we don't strictly validate here - runtime will resolve
-	return nil
+	if webhookURL, _ := config["webhook_url"].(string); webhookURL != "" {
+		if err := e.guard.CheckDiscordWebhook(webhookURL); err != nil {
+			return fmt.Errorf("discord 'webhook_url': %w", err)
+		}
+	}
+	if mode, ok := config["display_mode"].(string); ok {
+		switch mode {
+		case "", DisplayDetailed, DisplayCompact, DisplayText:
+		default:
+			return fmt.Errorf("discord 'display_mode' must be one of: %s, %s, %s", DisplayDetailed, DisplayCompact, DisplayText)
+		}
+	}
+	if style, ok := config["image_style"].(string); ok {
+		switch style {
+		case "", ImageThumbnail, ImageLarge, ImageNone:
+		default:
+			return fmt.Errorf("discord 'image_style' must be one of: %s, %s, %s", ImageThumbnail, ImageLarge, ImageNone)
+		}
+	}
+	if _, err := newDateFormatter(config); err != nil {
+		return fmt.Errorf("discord %w", err)
+	}
+	return validateFileConfig(config)
 }
 
 // SetWebhook allows runtime injection of webhook URL (from database config)
@@ -73,43 +100,58 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 	if webhookURL == "" {
 		return nil, fmt.Errorf("no Discord webhook URL configured: set webhook_url in pipeline config, task discord config, or DISCORD_DEFAULT_WEBHOOK environment variable")
 	}
+	if err := e.guard.CheckDiscordWebhook(webhookURL); err != nil {
+		return nil, err
+	}
 
-	// Get template
-	tmplStr, _ := config["template"].(string)
-
-	// Get username and avatar
-	username, _ := config["username"].(string)
-	avatarURL, _ := config["avatar_url"].(string)
-
-	// Get embed color
-	color := 0x5865F2 // Discord blurple
-	if c, ok := config["color"].(float64); ok {
-		color = int(c)
+	if asFile, _ := config["as_file"].(bool); asFile {
+		return e.executeFile(ctx, webhookURL, input, config)
 	}
 
-	// Format the message
-	message, err := e.formatMessage(input, tmplStr, username, avatarURL, color)
+	messages, displayMode, err := e.buildMessages(input, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to format message: %w", err)
+		return nil, err
 	}
 
-	// Apply rate limiting with mutex protection
-	e.mu.Lock()
-	if elapsed := time.Since(e.lastSend); elapsed < e.rateLimit {
-		e.mu.Unlock()
-		time.Sleep(e.rateLimit - elapsed)
-		e.mu.Lock()
+	for _, message := range messages {
+		// Rate limit per webhook, shared with every other execution
+		if err := webhookLimits.Wait(ctx, webhookKey(webhookURL), e.rateLimit); err != nil {
+			return nil, fmt.Errorf("failed to send Discord message: %w", err)
+		}
+
+		// Send to Discord
+		if err := e.send(ctx, webhookURL, message); err != nil {
+			return nil, fmt.Errorf("failed to send Discord message: %w", err)
+		}
 	}
-	e.mu.Unlock()
 
-	// Send to Discord
-	if err := e.send(ctx, webhookURL, message); err != nil {
-		return nil, fmt.Errorf("failed to send Discord message: %w", err)
+	return &model.ExecutorResult{
+		Data: map[string]interface{}{
+			"status":  "sent",
+			"webhook": maskWebhook(webhookURL),
+		},
+		ItemCount: input.ItemCount,
+		Metadata: map[string]interface{}{
+			"items_sent":    input.ItemCount,
+			"messages_sent": len(messages),
+			"display_mode":  displayMode,
+		},
+	}, nil
+}
+
+// executeFile uploads the input as a single file instead of messages
+func (e *Executor) executeFile(ctx context.Context, webhookURL string, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	file, err := buildAttachment(input.Data, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Discord file: %w", err)
 	}
 
-	e.mu.Lock()
-	e.lastSend = time.Now()
-	e.mu.Unlock()
+	if err := webhookLimits.Wait(ctx, webhookKey(webhookURL), e.rateLimit); err != nil {
+		return nil, fmt.Errorf("failed to send Discord file: %w", err)
+	}
+	if err := e.sendFile(ctx, webhookURL, fileMessage(input, config), file); err != nil {
+		return nil, fmt.Errorf("failed to send Discord file: %w", err)
+	}
 
 	return &model.ExecutorResult{
 		Data: map[string]interface{}{
@@ -118,102 +160,167 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 		},
 		ItemCount: input.ItemCount,
 		Metadata: map[string]interface{}{
-			"items_sent": input.ItemCount,
+			"items_sent":    input.ItemCount,
+			"messages_sent": 1,
+			"file_name":     file.Name,
+			"file_bytes":    len(file.Content),
 		},
 	}, nil
 }
 
-func (e *Executor) formatMessage(input *model.ExecutorResult, tmplStr, username, avatarURL string, color int) (*model.DiscordMessage, error) {
-	message := &model.DiscordMessage{
-		Username:  username,
-		AvatarURL: avatarURL,
+// fileMessage is the message a file is posted with: the step's username and
+// avatar, and the item count as its text
+func fileMessage(input *model.ExecutorResult, config map[string]interface{}) *model.DiscordMessage {
+	username, _ := config["username"].(string)
+	avatarURL, _ := config["avatar_url"].(string)
+	message := &model.DiscordMessage{Username: username, AvatarURL: avatarURL}
+	if input.ItemCount > 1 {
+		message.Content = fmt.Sprintf("%d items", input.ItemCount)
+	}
+	return message
+}
+
+// Preview returns the messages Execute would send, without sending them
+func (e *Executor) Preview(input *model.ExecutorResult, config map[string]interface{}) (interface{}, error) {
+	if input == nil {
+		return nil, fmt.Errorf("discord executor requires input data")
+	}
+	if asFile, _ := config["as_file"].(bool); asFile {
+		file, err := buildAttachment(input.Data, config)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"message":      fileMessage(input, config),
+			"file_name":    file.Name,
+			"content_type": file.ContentType,
+			"content":      string(file.Content),
+		}, nil
+	}
+	messages, _, err := e.buildMessages(input, config)
+	return messages, err
+}
+
+// buildMessages renders the input into messages according to the step config
+func (e *Executor) buildMessages(input *model.ExecutorResult, config map[string]interface{}) ([]*model.DiscordMessage, string, error) {
+	// Get template
+	tmplStr, _ := config["template"].(string)
+
+	// Get username and avatar
+	username, _ := config["username"].(string)
+	avatarURL, _ := config["avatar_url"].(string)
+
+	// Get embed color
+	color := 0x5865F2 // Discord blurple
+	if c, ok := config["color"].(float64); ok {
+		color = int(c)
+	}
+
+	// Get display mode
+	displayMode, _ := config["display_mode"].(string)
+	if displayMode == "" {
+		displayMode = DisplayDetailed
+	}
+	maxItems := defaultCompactItems
+	if m, ok := config["max_items"].(float64); ok && m >= 1 {
+		maxItems = int(m)
+	}
+	imageStyle, _ := config["image_style"].(string)
+
+	// Get date formatting
+	dates, err := newDateFormatter(config)
+	if err != nil {
+		return nil, "", err
 	}
 
+	// Format the messages
+	messages, err := e.formatMessages(input, tmplStr, displayMode, imageStyle, color, maxItems, dates)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to format message: %w", err)
+	}
+
+	for _, message := range messages {
+		message.Username = username
+		message.AvatarURL = avatarURL
+	}
+
+	return messages, displayMode, nil
+}
+
+func (e *Executor) formatMessages(input *model.ExecutorResult, tmplStr, displayMode, imageStyle string, color, maxItems int, dates *dateFormatter) ([]*model.DiscordMessage, error) {
 	// If input is a string (from AI processor), use it directly
 	if str, ok := input.Data.(string); ok {
-		// Split long messages
-		if len(str) > 2000 {
-			str = str[:1997] + "..."
-		}
-		message.Content = str
-		return message, nil
+		return contentMessages(str), nil
 	}
 
 	// If template provided, use it
 	if tmplStr != "" {
-		content, err := executeTemplate(tmplStr, input.Data)
+		content, err := executeTemplate(tmplStr, input.Data, dates)
 		if err != nil {
 			return nil, err
 		}
-		if len(content) > 2000 {
-			content = content[:1997] + "..."
+		return contentMessages(content), nil
+	}
+
+	// A failed assertion is always one alert embed, whatever the display mode
+	if alert, ok := input.Data.(model.Alert); ok {
+		return []*model.DiscordMessage{alertMessage(alert)}, nil
+	}
+
+	var messages []*model.DiscordMessage
+	var err error
+	switch displayMode {
+	case DisplayCompact:
+		messages, err = compactMessages(input.Data, color, maxItems)
+	case DisplayText:
+		messages, err = textMessages(input.Data, maxItems)
+	default:
+		var embeds []model.DiscordEmbed
+		embeds, err = e.createEmbeds(input.Data, color, imageStyle, dates)
+		if err == nil {
+			messages = embedMessages(embeds)
 		}
-		message.Content = content
-		return message, nil
 	}
 
-	// Create embeds for scraped items
-	embeds, err := e.createEmbeds(input.Data, color)
 	if err != nil {
 		// Fallback to JSON representation
 		jsonBytes, _ := json.MarshalIndent(input.Data, "", "  ")
-		content := string(jsonBytes)
-		if len(content) > 2000 {
-			content = content[:1997] + "..."
-		}
-		message.Content = "```json\n" + content + "\n```"
-		return message, nil
+		return codeBlockMessages(string(jsonBytes), "json"), nil
 	}
 
-	message.Embeds = embeds
-	return message, nil
+	return messages, nil
 }
 
-func (e *Executor) createEmbeds(data interface{}, color int) ([]model.DiscordEmbed, error) {
+func (e *Executor) createEmbeds(data interface{}, color int, imageStyle string, dates *dateFormatter) ([]model.DiscordEmbed, error) {
 	var embeds []model.DiscordEmbed
 
 	switch v := data.(type) {
 	case []model.ScrapedItem:
-		for i, item := range v {
-			if i >= 10 { // Discord limit
-				break
-			}
+		for _, item := range v {
 			embed := model.DiscordEmbed{
 				Title:       truncate(item.Title, 256),
 				Description: truncate(item.Description, 4096),
 				URL:         item.URL,
 				Color:       color,
 				Footer: &model.DiscordEmbedFooter{
-					Text: item.Source,
+					Text: footerText(item.Source, item.PostedAt, dates),
 				},
 			}
 
-			var fields []model.DiscordEmbedField
-			if item.Company != "" {
-				fields = append(fields, model.DiscordEmbedField{
-					Name:   "Company",
-					Value:  item.Company,
-					Inline: true,
-				})
-			}
-			if item.Salary != "" {
-				fields = append(fields, model.DiscordEmbedField{
-					Name:   "Salary",
-					Value:  item.Salary,
-					Inline: true,
-				})
-			}
-			if item.Location != "" {
-				fields = append(fields, model.DiscordEmbedField{
-					Name:   "Location",
-					Value:  item.Location,
-					Inline: true,
-				})
+			if item.PostedAt != "" {
+				embed.Timestamp = parseAndFormatDate(item.PostedAt)
 			}
-			if len(item.Tags) > 0 {
+			imageURL, _ := item.Extra["image_url"].(string)
+			setEmbedImage(&embed, imageURL, imageStyle)
+
+			// Only the fields that matter for the item's category, e.g.
+			// salary for jobs, points and comments for news
+			var fields []model.DiscordEmbedField
+			for _, f := range item.RelevantFields() {
 				fields = append(fields, model.DiscordEmbedField{
-					Name:  "Tags",
-					Value: strings.Join(item.Tags, ", "),
+					Name:   f.Label,
+					Value:  truncate(f.Value, 1024),
+					Inline: f.Key != "tags",
 				})
 			}
 
@@ -222,23 +329,21 @@ func (e *Executor) createEmbeds(data interface{}, color int) ([]model.DiscordEmb
 		}
 
 	case []model.RSSItem:
-		for i, item := range v {
-			if i >= 10 {
-				break
-			}
+		for _, item := range v {
 			embed := model.DiscordEmbed{
 				Title:       truncate(item.Title, 256),
 				Description: truncate(item.Description, 4096),
 				URL:         item.Link,
 				Color:       color,
 				Footer: &model.DiscordEmbedFooter{
-					Text: item.Source,
+					Text: footerText(item.Source, item.PubDate, dates),
 				},
 			}
 
 			if item.PubDate != "" {
 				embed.Timestamp = parseAndFormatDate(item.PubDate)
 			}
+			setEmbedImage(&embed, item.ImageURL, imageStyle)
 
 			embeds = append(embeds, embed)
 		}
@@ -255,26 +360,85 @@ func (e *Executor) send(ctx context.Context, webhookURL string, message *model.D
 	if err != nil {
 		return err
 	}
+	return e.postWithRetry(ctx, webhookURL, "application/json", jsonBody)
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(jsonBody))
+// postWithRetry posts a webhook request, retrying while Discord rate-limits it
+func (e *Executor) postWithRetry(ctx context.Context, webhookURL, contentType string, body []byte) error {
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := e.post(ctx, webhookURL, contentType, body)
+		if err == nil || retryAfter == 0 {
+			return err
+		}
+		if attempt >= maxRateLimitRetries || retryAfter > maxRetryAfter {
+			return err
+		}
+
+		// Discord says exactly how long the webhook's bucket needs
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// post sends one webhook request. On a 429 it also returns how long Discord
+// asked us to wait before retrying.
+func (e *Executor) post(ctx context.Context, webhookURL, contentType string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(body))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 
 	resp, err := e.client.Do(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		body, _ := io.ReadAll(resp.Body)
+		return retryAfter(resp.Header, body), fmt.Errorf("Discord API error %d: %s", resp.StatusCode, string(body))
+	}
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Discord API error %d: %s", resp.StatusCode, string(body))
+		return 0, fmt.Errorf("Discord API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	return 0, nil
+}
+
+// retryAfter reads the wait of a 429 response from the Retry-After header or
+// the retry_after field of the JSON body, both in (possibly fractional)
+// seconds. It falls back to one second when neither can be read.
+func retryAfter(header http.Header, body []byte) time.Duration {
+	if v := header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return rateLimitWait(secs)
+		}
 	}
 
-	return nil
+	var payload struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.RetryAfter > 0 {
+		return rateLimitWait(payload.RetryAfter)
+	}
+	return time.Second
+}
+
+func rateLimitWait(secs float64) time.Duration {
+	d := time.Duration(secs * float64(time.Second))
+	if d < 10*time.Millisecond {
+		// Wait a little even when told 0, so the retry isn't a tight loop
+		d = 10 * time.Millisecond
+	}
+	return d
 }
 
 // SendSimple sends a simple text message
@@ -289,8 +453,8 @@ func (e *Executor) SendEmbed(ctx context.Context, webhookURL string, embed model
 	return e.send(ctx, webhookURL, message)
 }
 
-func executeTemplate(tmplStr string, data interface{}) (string, error) {
-	tmpl, err := template.New("discord").Parse(tmplStr)
+func executeTemplate(tmplStr string, data interface{}, dates *dateFormatter) (string, error) {
+	tmpl, err := template.New("discord").Funcs(dates.funcs()).Parse(tmplStr)
 	if err != nil {
 		return "", err
 	}
@@ -303,6 +467,18 @@ func executeTemplate(tmplStr string, data interface{}) (string, error) {
 	return buf.String(), nil
 }
 
+// footerText appends the item's date to its source when the step asked for
+// human-readable dates
+func footerText(source, date string, dates *dateFormatter) string {
+	if date == "" || !dates.Enabled() {
+		return source
+	}
+	if source == "" {
+		return dates.Format(date)
+	}
+	return source + " · " + dates.Format(date)
+}
+
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -316,21 +492,3 @@ func maskWebhook(url string) string {
 	}
 	return url[:30] + "***"
 }
-
-func parseAndFormatDate(dateStr string) string {
-	formats := []string{
-		time.RFC1123,
-		time.RFC1123Z,
-		time.RFC3339,
-		"Mon, 02 Jan 2006 15:04:05 -0700",
-		"2006-01-02T15:04:05Z",
-	}
-
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t.Format(time.RFC3339)
-		}
-	}
-
-	return ""
-}
@@ -0,0 +1,60 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/multi-worker/internal/model"
+)
+
+func TestEmbedsShowCategoryFields(t *testing.T) {
+	items := []model.ScrapedItem{
+		{
+			Title: "Go developer", URL: "https://example.com/jobs/1", Category: "jobs",
+			Company: "Acme", Salary: "$120k", Location: "Remote",
+			Extra: map[string]interface{}{"points": float64(10)},
+		},
+		{
+			Title: "Show HN: a new database", URL: "https://example.com/news/1", Category: "news",
+			Salary: "$0", Tags: []string{"db"},
+			Extra: map[string]interface{}{"author": "pg", "points": float64(312), "comments": float64(48)},
+		},
+	}
+
+	messages := preview(t, items, map[string]interface{}{})
+	fields := func(embed model.DiscordEmbed) map[string]model.DiscordEmbedField {
+		out := make(map[string]model.DiscordEmbedField)
+		for _, f := range embed.Fields {
+			out[f.Name] = f
+		}
+		return out
+	}
+
+	job := fields(messages[0].Embeds[0])
+	if len(job) != 3 || job["Company"].Value != "Acme" || job["Salary"].Value != "$120k" || job["Location"].Value != "Remote" {
+		t.Errorf("job fields = %v, want company, salary and location", job)
+	}
+	if _, ok := job["Points"]; ok {
+		t.Error("job embed shows points")
+	}
+
+	news := fields(messages[0].Embeds[1])
+	if len(news) != 4 || news["Author"].Value != "pg" || news["Points"].Value != "312" || news["Comments"].Value != "48" {
+		t.Errorf("news fields = %v, want author, points, comments and tags", news)
+	}
+	if _, ok := news["Salary"]; ok {
+		t.Error("news embed shows a salary")
+	}
+	if !news["Points"].Inline || news["Tags"].Inline {
+		t.Errorf("tags should span the embed, other fields sit inline: %v", news)
+	}
+
+	// Fields are shown in the category's order
+	var order []string
+	for _, f := range messages[0].Embeds[1].Fields {
+		order = append(order, f.Name)
+	}
+	if got := strings.Join(order, ", "); got != "Author, Points, Comments, Tags" {
+		t.Errorf("field order = %s", got)
+	}
+}
@@ -0,0 +1,176 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// File formats for the as_file option
+const (
+	FileJSON     = "json"
+	FileCSV      = "csv"
+	FileMarkdown = "md"
+)
+
+// maxFileBytes is Discord's upload limit for webhooks on unboosted servers
+const maxFileBytes = 8 << 20
+
+var fileContentTypes = map[string]string{
+	FileJSON:     "application/json",
+	FileCSV:      "text/csv",
+	FileMarkdown: "text/markdown",
+}
+
+// attachment is a rendered file ready to upload
+type attachment struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+func validateFileConfig(config map[string]interface{}) error {
+	format, ok := config["file_format"].(string)
+	if !ok {
+		return nil
+	}
+	if _, known := fileContentTypes[format]; !known {
+		return fmt.Errorf("discord 'file_format' must be one of: %s, %s, %s", FileJSON, FileCSV, FileMarkdown)
+	}
+	return nil
+}
+
+// buildAttachment serializes the step input into a file in the configured
+// format (JSON by default)
+func buildAttachment(data interface{}, config map[string]interface{}) (*attachment, error) {
+	format, _ := config["file_format"].(string)
+	if format == "" {
+		format = FileJSON
+	}
+
+	var content []byte
+	var err error
+	switch format {
+	case FileCSV:
+		content, err = csvFile(data)
+	case FileMarkdown:
+		content = markdownFile(data)
+	default:
+		content, err = json.MarshalIndent(data, "", "  ")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxFileBytes {
+		return nil, fmt.Errorf("file is %d bytes, over Discord's %d byte limit", len(content), maxFileBytes)
+	}
+
+	name, _ := config["file_name"].(string)
+	if name == "" {
+		name = "digest-" + time.Now().UTC().Format("2006-01-02")
+	}
+	if !strings.HasSuffix(name, "."+format) {
+		name += "." + format
+	}
+
+	return &attachment{Name: name, ContentType: fileContentTypes[format], Content: content}, nil
+}
+
+// sendFile uploads a file to the webhook as multipart/form-data, with the
+// message's username, avatar and content in the payload_json part
+func (e *Executor) sendFile(ctx context.Context, webhookURL string, message *model.DiscordMessage, file *attachment) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("payload_json", string(payload)); err != nil {
+		return err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, file.Name))
+	header.Set("Content-Type", file.ContentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(file.Content); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return e.postWithRetry(ctx, webhookURL, w.FormDataContentType(), body.Bytes())
+}
+
+// csvFile flattens scraped or RSS items into one row per item
+func csvFile(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := model.WriteItemsCSV(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// markdownFile renders items as a heading per item followed by its fields;
+// text such as AI output is written as is
+func markdownFile(data interface{}) []byte {
+	var sb strings.Builder
+	switch v := data.(type) {
+	case string:
+		sb.WriteString(v)
+	case []model.ScrapedItem:
+		for i, item := range v {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			fmt.Fprintf(&sb, "## %s\n\n", markdownLink(item.Title, item.URL))
+			for _, f := range item.RelevantFields() {
+				fmt.Fprintf(&sb, "- **%s:** %s\n", f.Label, f.Value)
+			}
+			if item.Source != "" || item.PostedAt != "" {
+				fmt.Fprintf(&sb, "- **Source:** %s\n", strings.TrimSpace(item.Source+" "+item.PostedAt))
+			}
+			if item.Description != "" {
+				fmt.Fprintf(&sb, "\n%s\n", item.Description)
+			}
+		}
+	case []model.RSSItem:
+		for i, item := range v {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			fmt.Fprintf(&sb, "## %s\n\n", markdownLink(item.Title, item.Link))
+			if item.Source != "" || item.PubDate != "" {
+				fmt.Fprintf(&sb, "- **Source:** %s\n", strings.TrimSpace(item.Source+" "+item.PubDate))
+			}
+			if item.Description != "" {
+				fmt.Fprintf(&sb, "\n%s\n", item.Description)
+			}
+		}
+	case model.Alert:
+		sb.WriteString(v.Text())
+	default:
+		jsonBytes, _ := json.MarshalIndent(data, "", "  ")
+		sb.WriteString("```json\n" + string(jsonBytes) + "\n```\n")
+	}
+	return []byte(sb.String())
+}
+
+func markdownLink(title, url string) string {
+	if url == "" {
+		return title
+	}
+	return fmt.Sprintf("[%s](%s)", title, url)
+}
@@ -0,0 +1,72 @@
+package discord
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// webhookLimits spaces out sends to the same webhook across every execution
+// in the process, so concurrent tasks sharing a webhook don't trip Discord's
+// per-webhook limit
+var webhookLimits = newWebhookLimiter()
+
+// webhookLimiter hands out send slots per webhook, at most one per interval
+type webhookLimiter struct {
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newWebhookLimiter() *webhookLimiter {
+	return &webhookLimiter{next: make(map[string]time.Time)}
+}
+
+// Wait blocks until the webhook identified by key may be sent to, reserving
+// the following slot for the next caller. Reservations are handed out in call
+// order, so concurrent senders are serialized rather than racing.
+func (l *webhookLimiter) Wait(ctx context.Context, key string, interval time.Duration) error {
+	if interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[key]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[key] = at.Add(interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// webhookKey identifies a webhook by its ID so the same webhook is limited
+// together however its URL is written (discord.com vs discordapp.com, query
+// parameters such as ?wait=true)
+func webhookKey(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return webhookURL
+	}
+	if _, rest, ok := strings.Cut(u.Path, "/api/webhooks/"); ok {
+		if id, _, _ := strings.Cut(rest, "/"); id != "" {
+			return id
+		}
+	}
+	return u.Host + u.Path
+}
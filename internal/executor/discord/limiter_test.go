@@ -0,0 +1,120 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+)
+
+func TestConcurrentSendsToOneWebhookAreThrottled(t *testing.T) {
+	const (
+		senders  = 5
+		interval = 50 * time.Millisecond
+	)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// Record when each request leaves, before connection setup skews it
+	var mu sync.Mutex
+	var sends []time.Time
+	client := server.Client()
+	transport := client.Transport
+	client.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		sends = append(sends, time.Now())
+		mu.Unlock()
+		return transport.RoundTrip(r)
+	})
+
+	guard := netguard.New(config.OutboundConfig{AllowedHosts: []string{"127.0.0.1"}})
+
+	// Each send is a separate executor, as concurrent tasks would have
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		e := NewExecutor(config.DiscordConfig{RateLimitMs: int(interval / time.Millisecond)}, guard)
+		e.client = client
+		// The same webhook, written differently by each task
+		webhookURL := server.URL + "/api/webhooks/1264/token"
+		if i%2 == 1 {
+			webhookURL += "?wait=true"
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			input := &model.ExecutorResult{Data: scrapedItems(1), ItemCount: 1}
+			if _, err := e.Execute(context.Background(), input, map[string]interface{}{"webhook_url": webhookURL}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sends) != senders {
+		t.Fatalf("sent %d messages, want %d", len(sends), senders)
+	}
+	// The nth slot opens n intervals after the first and timers never fire
+	// early, so measure from the start: gaps between neighbours would also
+	// shrink whenever one sender happened to wake late
+	sort.Slice(sends, func(i, j int) bool { return sends[i].Before(sends[j]) })
+	for i, sent := range sends {
+		if offset, want := sent.Sub(start), time.Duration(i)*interval; offset < want {
+			t.Errorf("message %d was sent %v after the start, want at least %v", i+1, offset, want)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestLimiterKeepsWebhooksIndependent(t *testing.T) {
+	l := newWebhookLimiter()
+	ctx := context.Background()
+	const interval = time.Second
+
+	if err := l.Wait(ctx, "a", interval); err != nil {
+		t.Fatal(err)
+	}
+	// Another webhook's slot is free although "a" is now busy for a second
+	start := time.Now()
+	if err := l.Wait(ctx, "b", interval); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited > 100*time.Millisecond {
+		t.Errorf("waited %v for an idle webhook", waited)
+	}
+
+	// A cancelled caller gives up its wait
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.Wait(cancelled, "a", interval); err == nil {
+		t.Error("a cancelled wait returned nil")
+	}
+}
+
+func TestWebhookKey(t *testing.T) {
+	for _, url := range []string{
+		"https://discord.com/api/webhooks/123/abc",
+		"https://discordapp.com/api/webhooks/123/abc",
+		"https://discord.com/api/webhooks/123/abc?wait=true",
+	} {
+		if got := webhookKey(url); got != "123" {
+			t.Errorf("webhookKey(%q) = %q, want 123", url, got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// webhookVerifyTimeout bounds the reachability check so a slow Discord
+// doesn't hold up saving a config
+const webhookVerifyTimeout = 5 * time.Second
+
+// VerifyWebhook checks a Discord webhook exists with a GET through client,
+// which Discord answers with the webhook's metadata without posting anything
+func VerifyWebhook(ctx context.Context, client *http.Client, webhookURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookVerifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, webhookURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook unreachable")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Discord returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// apiBaseURL is the Discord REST API used with bot tokens
+var apiBaseURL = "https://discord.com/api/v10"
+
+// CreateWebhook creates a webhook in a channel through client using a bot
+// token, returning the new webhook's ID and URL. The bot needs the Manage
+// Webhooks permission in that channel.
+func CreateWebhook(ctx context.Context, client *http.Client, botToken, channelID, name string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookVerifyTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return "", "", err
+	}
+	endpoint := fmt.Sprintf("%s/channels/%s/webhooks", apiBaseURL, url.PathEscape(channelID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Authorization", "Bot "+botToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("Discord unreachable")
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return "", "", fmt.Errorf("Discord rejected the bot token")
+	case resp.StatusCode == http.StatusForbidden:
+		return "", "", fmt.Errorf("bot lacks the Manage Webhooks permission in channel %s", channelID)
+	case resp.StatusCode == http.StatusNotFound:
+		return "", "", fmt.Errorf("channel %s not found or not visible to the bot", channelID)
+	case resp.StatusCode >= 400:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", "", fmt.Errorf("Discord returned status %d: %s", resp.StatusCode, msg)
+	}
+
+	var webhook struct {
+		ID    string `json:"id"`
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&webhook); err != nil || webhook.ID == "" || webhook.Token == "" {
+		return "", "", fmt.Errorf("unexpected response from Discord")
+	}
+	return webhook.ID, fmt.Sprintf("https://discord.com/api/webhooks/%s/%s", webhook.ID, webhook.Token), nil
+}
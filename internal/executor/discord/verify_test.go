@@ -0,0 +1,73 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	var methods []string
+	discord := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.URL.Path != "/api/webhooks/1/valid" {
+			http.Error(w, `{"message": "Unknown Webhook", "code": 10015}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id": "1", "type": 1}`))
+	}))
+	defer discord.Close()
+	ctx := context.Background()
+
+	if err := VerifyWebhook(ctx, discord.Client(), discord.URL+"/api/webhooks/1/valid"); err != nil {
+		t.Errorf("valid webhook: %v", err)
+	}
+
+	err := VerifyWebhook(ctx, discord.Client(), discord.URL+"/api/webhooks/1/deleted")
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("deleted webhook: err = %v, want Discord's status", err)
+	}
+
+	// Verification must never post a message
+	for _, m := range methods {
+		if m != http.MethodGet {
+			t.Errorf("verification sent a %s request", m)
+		}
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	if err := VerifyWebhook(ctx, closed.Client(), closed.URL+"/api/webhooks/1/valid"); err == nil || err.Error() != "webhook unreachable" {
+		t.Errorf("unreachable webhook: err = %v", err)
+	}
+}
+
+func TestCreateWebhookUsesGivenClient(t *testing.T) {
+	var auth, path string
+	discord := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, path = r.Header.Get("Authorization"), r.URL.Path
+		w.Write([]byte(`{"id": "42", "token": "secret"}`))
+	}))
+	defer discord.Close()
+
+	defer func(base string) { apiBaseURL = base }(apiBaseURL)
+	apiBaseURL = discord.URL + "/api/v10"
+
+	// Only the test server's client trusts its certificate
+	id, webhookURL, err := CreateWebhook(context.Background(), discord.Client(), "bot-token", "100", "alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "42" || webhookURL != "https://discord.com/api/webhooks/42/secret" {
+		t.Errorf("got %s %s", id, webhookURL)
+	}
+	if auth != "Bot bot-token" || path != "/api/v10/channels/100/webhooks" {
+		t.Errorf("request had auth %q, path %q", auth, path)
+	}
+
+	if _, _, err := CreateWebhook(context.Background(), http.DefaultClient, "bot-token", "100", "alerts"); err == nil {
+		t.Error("request through another client succeeded")
+	}
+}
@@ -0,0 +1,297 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+)
+
+// TLS modes for SMTP_TLS
+const (
+	TLSStartTLS = "starttls" // Plain connection upgraded with STARTTLS (default, port 587)
+	TLSImplicit = "tls"      // TLS from the first byte (port 465)
+	TLSNone     = "none"     // No encryption, e.g. a local relay
+)
+
+// Executor sends step results as an HTML email over SMTP
+type Executor struct {
+	cfg config.EmailConfig
+}
+
+// NewExecutor creates a new email executor
+func NewExecutor(cfg config.EmailConfig) *Executor {
+	if cfg.From == "" {
+		cfg.From = cfg.User
+	}
+	if cfg.TLSMode == "" {
+		cfg.TLSMode = TLSStartTLS
+	}
+	return &Executor{cfg: cfg}
+}
+
+func (e *Executor) Type() string {
+	return "email"
+}
+
+func (e *Executor) Validate(config map[string]interface{}) error {
+	if e.cfg.Host == "" {
+		return fmt.Errorf("email requires SMTP_HOST to be configured")
+	}
+	if _, err := mail.ParseAddress(e.cfg.From); err != nil {
+		return fmt.Errorf("email requires a valid SMTP_FROM or SMTP_USER sender address")
+	}
+	if _, err := recipients(config); err != nil {
+		return err
+	}
+	if tmplStr, _ := config["template"].(string); tmplStr != "" {
+		if _, err := parseTemplate(tmplStr); err != nil {
+			return fmt.Errorf("email 'template' is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	if input == nil {
+		return nil, fmt.Errorf("email executor requires input data")
+	}
+	if e.cfg.Host == "" {
+		return nil, fmt.Errorf("no SMTP server configured: set SMTP_HOST")
+	}
+
+	to, err := recipients(config)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := e.compose(input, config, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.send(ctx, to, msg.raw); err != nil {
+		return nil, fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return &model.ExecutorResult{
+		Data: map[string]interface{}{
+			"status":     "sent",
+			"message_id": msg.MessageID,
+			"to":         to,
+		},
+		ItemCount: input.ItemCount,
+		Metadata: map[string]interface{}{
+			"items_sent": input.ItemCount,
+			"message_id": msg.MessageID,
+		},
+	}, nil
+}
+
+// Preview returns the email Execute would send, without sending it
+func (e *Executor) Preview(input *model.ExecutorResult, config map[string]interface{}) (interface{}, error) {
+	if input == nil {
+		return nil, fmt.Errorf("email executor requires input data")
+	}
+	to, err := recipients(config)
+	if err != nil {
+		return nil, err
+	}
+	return e.compose(input, config, to)
+}
+
+// message is a rendered email
+type message struct {
+	MessageID string   `json:"message_id"`
+	From      string   `json:"from"`
+	To        []string `json:"to"`
+	Subject   string   `json:"subject"`
+	HTML      string   `json:"html"`
+	raw       []byte
+}
+
+func (e *Executor) compose(input *model.ExecutorResult, config map[string]interface{}, to []string) (*message, error) {
+	html, err := renderHTML(input, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	subject, _ := config["subject"].(string)
+	if subject == "" {
+		subject = fmt.Sprintf("Multi-Worker digest: %d items", input.ItemCount)
+	}
+	return e.newMessage(to, subject, html), nil
+}
+
+// newMessage builds the raw email for an already rendered body
+func (e *Executor) newMessage(to []string, subject, html string) *message {
+	// Header values must stay on one line
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	msg := &message{
+		MessageID: newMessageID(e.cfg.From),
+		From:      e.cfg.From,
+		To:        to,
+		Subject:   subject,
+		HTML:      html,
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", msg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: %s\r\n", msg.MessageID)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(html, "\r\n", "\n"), "\n", "\r\n"))
+	msg.raw = buf.Bytes()
+
+	return msg
+}
+
+// Configured reports whether an SMTP server is set up to send through
+func (e *Executor) Configured() bool {
+	return e.cfg.Host != ""
+}
+
+// SendHTML sends an HTML email outside a pipeline, e.g. an account notice
+func (e *Executor) SendHTML(ctx context.Context, to []string, subject, html string) error {
+	if !e.Configured() {
+		return fmt.Errorf("no SMTP server configured: set SMTP_HOST")
+	}
+	msg := e.newMessage(to, subject, html)
+	if err := e.send(ctx, to, msg.raw); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// send delivers raw to the recipients, connecting with the configured TLS
+// mode and giving up when ctx is done
+func (e *Executor) send(ctx context.Context, to []string, raw []byte) error {
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: e.cfg.Host}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if e.cfg.TLSMode == TLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(2 * time.Minute)
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if e.cfg.TLSMode == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("server does not support STARTTLS (set SMTP_TLS=none to send unencrypted)")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if e.cfg.User != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.cfg.User, e.cfg.Password, e.cfg.Host)); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	from, _ := mail.ParseAddress(e.cfg.From)
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		addr, _ := mail.ParseAddress(rcpt)
+		if err := client.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("recipient %s rejected: %w", rcpt, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// recipients reads 'to' as one address, a comma-separated list or an array
+func recipients(config map[string]interface{}) ([]string, error) {
+	var raw []string
+	switch v := config["to"].(type) {
+	case string:
+		raw = strings.Split(v, ",")
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("email 'to' must be an address or an array of addresses")
+			}
+			raw = append(raw, s)
+		}
+	}
+
+	var to []string
+	for _, r := range raw {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		if _, err := mail.ParseAddress(r); err != nil {
+			return nil, fmt.Errorf("email 'to' has an invalid address %q", r)
+		}
+		to = append(to, r)
+	}
+	if len(to) == 0 {
+		return nil, fmt.Errorf("email requires 'to' in config")
+	}
+	return to, nil
+}
+
+// newMessageID returns a unique Message-ID on the sender's domain
+func newMessageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if _, d, ok := strings.Cut(addr.Address, "@"); ok {
+			domain = d
+		}
+	}
+	b := make([]byte, 12)
+	rand.Read(b)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(b), domain)
+}
@@ -0,0 +1,130 @@
+package email
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// defaultTemplate renders items as a table with inline styles, since most
+// mail clients ignore <style> blocks
+const defaultTemplate = `<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f5f6f8;font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;color:#1f2328;">
+{{- if .Text}}
+<pre style="white-space:pre-wrap;font-family:inherit;font-size:14px;line-height:1.5;">{{.Text}}</pre>
+{{- else if .Rows}}
+<table cellpadding="0" cellspacing="0" style="width:100%;max-width:760px;border-collapse:collapse;background:#ffffff;border:1px solid #d0d7de;">
+  <tr style="background:#f0f3f6;">
+    <th align="left" style="padding:10px 12px;font-size:13px;border-bottom:1px solid #d0d7de;">Title</th>
+    <th align="left" style="padding:10px 12px;font-size:13px;border-bottom:1px solid #d0d7de;">Details</th>
+    <th align="left" style="padding:10px 12px;font-size:13px;border-bottom:1px solid #d0d7de;">Source</th>
+  </tr>
+  {{- range .Rows}}
+  <tr>
+    <td style="padding:10px 12px;font-size:14px;border-bottom:1px solid #eaeef2;vertical-align:top;">
+      {{- if .URL}}<a href="{{.URL}}" style="color:#0969da;text-decoration:none;font-weight:600;">{{.Title}}</a>{{else}}<strong>{{.Title}}</strong>{{end}}
+      {{- if .Description}}<div style="margin-top:4px;font-size:13px;color:#57606a;">{{.Description}}</div>{{end}}
+    </td>
+    <td style="padding:10px 12px;font-size:13px;border-bottom:1px solid #eaeef2;vertical-align:top;">
+      {{- range .Fields}}<div><span style="color:#57606a;">{{.Label}}:</span> {{.Value}}</div>{{end}}
+    </td>
+    <td style="padding:10px 12px;font-size:13px;border-bottom:1px solid #eaeef2;vertical-align:top;color:#57606a;white-space:nowrap;">{{.Source}}{{if .Date}}<br>{{.Date}}{{end}}</td>
+  </tr>
+  {{- end}}
+</table>
+{{- else}}
+<p style="font-size:14px;">No items.</p>
+{{- end}}
+</body>
+</html>
+`
+
+// row is one item in the default table
+type row struct {
+	Title       string
+	URL         string
+	Description string
+	Fields      []model.ItemFieldValue
+	Source      string
+	Date        string
+}
+
+// view is the data passed to the default template
+type view struct {
+	Text string
+	Rows []row
+}
+
+var defaultTmpl = template.Must(parseTemplate(defaultTemplate))
+
+func parseTemplate(tmplStr string) (*template.Template, error) {
+	return template.New("email").Parse(tmplStr)
+}
+
+// descriptionLimit keeps long item descriptions from swamping the table
+const descriptionLimit = 300
+
+// renderHTML renders the input with the step's template, which receives the
+// step input as is, or with the default table
+func renderHTML(input *model.ExecutorResult, config map[string]interface{}) (string, error) {
+	if tmplStr, _ := config["template"].(string); tmplStr != "" {
+		tmpl, err := parseTemplate(tmplStr)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, input.Data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	var v view
+	switch data := input.Data.(type) {
+	case string:
+		v.Text = data
+	case model.Alert:
+		v.Text = data.Text()
+	case []model.ScrapedItem:
+		for _, item := range data {
+			v.Rows = append(v.Rows, row{
+				Title:       item.Title,
+				URL:         item.URL,
+				Description: truncate(item.Description, descriptionLimit),
+				Fields:      item.RelevantFields(),
+				Source:      item.Source,
+				Date:        item.PostedAt,
+			})
+		}
+	case []model.RSSItem:
+		for _, item := range data {
+			v.Rows = append(v.Rows, row{
+				Title:       item.Title,
+				URL:         item.Link,
+				Description: truncate(item.Description, descriptionLimit),
+				Source:      item.Source,
+				Date:        item.PubDate,
+			})
+		}
+	default:
+		jsonBytes, _ := json.MarshalIndent(input.Data, "", "  ")
+		v.Text = string(jsonBytes)
+	}
+
+	var buf bytes.Buffer
+	if err := defaultTmpl.Execute(&buf, v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func truncate(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/multi-worker/internal/model"
 	"github.com/multi-worker/internal/storage"
 )
 
+// What deduplicate compares to recognise an item it has seen
+const (
+	DedupeByURL   = "url"   // The item's URL (default)
+	DedupeByTitle = "title" // Its normalised company and title, across sources
+)
+
 // Executor handles filtering and deduplication in pipelines
 type Executor struct {
 	cache *storage.CacheRepository
@@ -24,7 +31,25 @@ func (e *Executor) Type() string {
 }
 
 func (e *Executor) Validate(config map[string]interface{}) error {
-	return nil // All configs are optional
+	// All configs are optional
+	if days, ok := config["dedupe_window_days"].(float64); ok && days < 0 {
+		return fmt.Errorf("filter 'dedupe_window_days' must not be negative")
+	}
+	switch by, _ := config["dedupe_by"].(string); by {
+	case "", DedupeByURL, DedupeByTitle:
+	default:
+		return fmt.Errorf("filter 'dedupe_by' must be '%s' or '%s'", DedupeByURL, DedupeByTitle)
+	}
+	if _, err := compilePatterns(config, "include_regex"); err != nil {
+		return err
+	}
+	if _, err := compilePatterns(config, "exclude_regex"); err != nil {
+		return err
+	}
+	if err := validateFieldRules(config); err != nil {
+		return err
+	}
+	return validateSort(config)
 }
 
 func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
@@ -33,23 +58,36 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 	}
 
 	// Get configuration
-	includeKeywords := getStringSlice(config, "include_keywords")
-	excludeKeywords := getStringSlice(config, "exclude_keywords")
+	rules, err := newMatchRules(config)
+	if err != nil {
+		return nil, err
+	}
 	dedupe, _ := config["deduplicate"].(bool)
+	if bypass, _ := config["bypass_dedup"].(bool); bypass {
+		dedupe = false
+	}
 	taskID, _ := config["task_id"].(string)
+	window := storage.DedupeWindow(config)
+	dedupeBy, _ := config["dedupe_by"].(string)
 	limit := 0
 	if l, ok := config["limit"].(float64); ok {
 		limit = int(l)
 	}
+	sortBy, _ := config["sort_by"].(string)
+	sortDesc, _ := config["sort_desc"].(bool)
 
 	var filtered interface{}
 	var count int
 
 	switch v := input.Data.(type) {
 	case []model.ScrapedItem:
-		items := filterScrapedItems(v, includeKeywords, excludeKeywords)
+		items := filterScrapedItems(v, rules)
+		items = filterScrapedFields(items, newFieldRules(config))
 		if dedupe && e.cache != nil && taskID != "" {
-			items = e.dedupeScrapedItems(ctx, items, taskID)
+			items = e.dedupeScrapedItems(ctx, items, taskID, window, dedupeBy)
+		}
+		if sortBy != "" {
+			items = sortScrapedItems(items, sortBy, sortDesc)
 		}
 		if limit > 0 && len(items) > limit {
 			items = items[:limit]
@@ -58,9 +96,12 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 		count = len(items)
 
 	case []model.RSSItem:
-		items := filterRSSItems(v, includeKeywords, excludeKeywords)
+		items := filterRSSItems(v, rules)
 		if dedupe && e.cache != nil && taskID != "" {
-			items = e.dedupeRSSItems(ctx, items, taskID)
+			items = e.dedupeRSSItems(ctx, items, taskID, window, dedupeBy)
+		}
+		if sortBy != "" {
+			items = sortRSSItems(items, sortBy, sortDesc)
 		}
 		if limit > 0 && len(items) > limit {
 			items = items[:limit]
@@ -79,98 +120,64 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 	}, nil
 }
 
-func filterScrapedItems(items []model.ScrapedItem, include, exclude []string) []model.ScrapedItem {
-	if len(include) == 0 && len(exclude) == 0 {
+func filterScrapedItems(items []model.ScrapedItem, rules matchRules) []model.ScrapedItem {
+	if rules.empty() {
 		return items
 	}
 
 	var filtered []model.ScrapedItem
 	for _, item := range items {
-		text := strings.ToLower(item.Title + " " + item.Description + " " + strings.Join(item.Tags, " "))
-
-		// Check exclude first
-		excluded := false
-		for _, keyword := range exclude {
-			if strings.Contains(text, strings.ToLower(keyword)) {
-				excluded = true
-				break
-			}
+		text := item.Title + " " + item.Description + " " + strings.Join(item.Tags, " ")
+		for _, f := range item.RelevantFields() {
+			text += " " + f.Value
 		}
-		if excluded {
-			continue
+		if rules.keep(text) {
+			filtered = append(filtered, item)
 		}
-
-		// Check include (if specified, at least one must match)
-		if len(include) > 0 {
-			included := false
-			for _, keyword := range include {
-				if strings.Contains(text, strings.ToLower(keyword)) {
-					included = true
-					break
-				}
-			}
-			if !included {
-				continue
-			}
-		}
-
-		filtered = append(filtered, item)
 	}
 
 	return filtered
 }
 
-func filterRSSItems(items []model.RSSItem, include, exclude []string) []model.RSSItem {
-	if len(include) == 0 && len(exclude) == 0 {
+func filterRSSItems(items []model.RSSItem, rules matchRules) []model.RSSItem {
+	if rules.empty() {
 		return items
 	}
 
 	var filtered []model.RSSItem
 	for _, item := range items {
-		text := strings.ToLower(item.Title + " " + item.Description)
-
-		excluded := false
-		for _, keyword := range exclude {
-			if strings.Contains(text, strings.ToLower(keyword)) {
-				excluded = true
-				break
-			}
+		if rules.keep(item.Title + " " + item.Description) {
+			filtered = append(filtered, item)
 		}
-		if excluded {
-			continue
-		}
-
-		if len(include) > 0 {
-			included := false
-			for _, keyword := range include {
-				if strings.Contains(text, strings.ToLower(keyword)) {
-					included = true
-					break
-				}
-			}
-			if !included {
-				continue
-			}
-		}
-
-		filtered = append(filtered, item)
 	}
 
 	return filtered
 }
 
-func (e *Executor) dedupeScrapedItems(ctx context.Context, items []model.ScrapedItem, taskID string) []model.ScrapedItem {
+func (e *Executor) dedupeScrapedItems(ctx context.Context, items []model.ScrapedItem, taskID string, window time.Duration, by string) []model.ScrapedItem {
 	var unique []model.ScrapedItem
 	var hashes []string
+	seen := make(map[string]bool)
 
 	for _, item := range items {
 		content := item.URL
 		if content == "" {
 			content = item.ID + item.Source
 		}
+		if by == DedupeByTitle {
+			if key := dedupeTitleKey(item.Company, item.Title); key != "" {
+				content = key
+			}
+		}
 		hash := e.cache.HashContent(content)
 
-		exists, _ := e.cache.ExistsForTask(ctx, hash, taskID)
+		// Also catches duplicates within this batch, e.g. from several boards
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		exists, _ := e.cache.ExistsForTask(ctx, hash, taskID, window)
 		if exists {
 			continue
 		}
@@ -186,18 +193,29 @@ func (e *Executor) dedupeScrapedItems(ctx context.Context, items []model.Scraped
 	return unique
 }
 
-func (e *Executor) dedupeRSSItems(ctx context.Context, items []model.RSSItem, taskID string) []model.RSSItem {
+func (e *Executor) dedupeRSSItems(ctx context.Context, items []model.RSSItem, taskID string, window time.Duration, by string) []model.RSSItem {
 	var unique []model.RSSItem
 	var hashes []string
+	seen := make(map[string]bool)
 
 	for _, item := range items {
 		content := item.Link
 		if content == "" {
 			content = item.ID
 		}
+		if by == DedupeByTitle {
+			if key := dedupeTitleKey("", item.Title); key != "" {
+				content = key
+			}
+		}
 		hash := e.cache.HashContent(content)
 
-		exists, _ := e.cache.ExistsForTask(ctx, hash, taskID)
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		exists, _ := e.cache.ExistsForTask(ctx, hash, taskID, window)
 		if exists {
 			continue
 		}
@@ -270,3 +288,19 @@ func (e SkipPipelineError) Error() string {
 func NewSkipPipelineError(reason string) SkipPipelineError {
 	return SkipPipelineError{Reason: reason}
 }
+
+// dedupeTitleKey is what dedupe_by title compares: the company and title,
+// lowercased with whitespace collapsed, so a job reposted under another URL
+// still matches. It is empty for items without a title, which fall back to
+// their URL. The prefix keeps these hashes apart from URL hashes.
+func dedupeTitleKey(company, title string) string {
+	title = normalizeText(title)
+	if title == "" {
+		return ""
+	}
+	return "title:" + normalizeText(company) + "\n" + title
+}
+
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// remoteTerms mark a location as open to remote work
+var remoteTerms = []string{"remote", "worldwide", "anywhere"}
+
+// fieldRules filter scraped items on their structured fields
+type fieldRules struct {
+	minSalary        float64
+	locationContains []string
+	remoteOnly       bool
+	dropMissing      bool // Drop items lacking a field a rule needs, instead of keeping them
+}
+
+func newFieldRules(config map[string]interface{}) fieldRules {
+	rules := fieldRules{locationContains: getStringSlice(config, "location_contains")}
+	rules.minSalary, _ = config["min_salary"].(float64)
+	rules.remoteOnly, _ = config["remote_only"].(bool)
+	rules.dropMissing, _ = config["drop_missing"].(bool)
+	return rules
+}
+
+func validateFieldRules(config map[string]interface{}) error {
+	if v, ok := config["min_salary"]; ok {
+		if n, ok := v.(float64); !ok || n < 0 {
+			return fmt.Errorf("filter 'min_salary' must be a non-negative number")
+		}
+	}
+	return nil
+}
+
+func (f fieldRules) empty() bool {
+	return f.minSalary <= 0 && len(f.locationContains) == 0 && !f.remoteOnly
+}
+
+// keep reports whether an item passes the field rules. Salary is compared by
+// the highest amount in the salary text, so a range passes when its top does.
+// With both location_contains and remote_only, a location matching either passes.
+func (f fieldRules) keep(item model.ScrapedItem) bool {
+	if f.minSalary > 0 {
+		salary, ok := parseSalary(item.Salary)
+		if !ok {
+			if f.dropMissing {
+				return false
+			}
+		} else if salary < f.minSalary {
+			return false
+		}
+	}
+
+	if len(f.locationContains) > 0 || f.remoteOnly {
+		location := strings.ToLower(strings.TrimSpace(item.Location))
+		if location == "" {
+			return !f.dropMissing
+		}
+		terms := f.locationContains
+		if f.remoteOnly {
+			terms = append(append([]string{}, terms...), remoteTerms...)
+		}
+		matched := false
+		for _, term := range terms {
+			if strings.Contains(location, strings.ToLower(term)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func filterScrapedFields(items []model.ScrapedItem, rules fieldRules) []model.ScrapedItem {
+	if rules.empty() {
+		return items
+	}
+
+	var filtered []model.ScrapedItem
+	for _, item := range items {
+		if rules.keep(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// matchRules decides which items a filter step keeps. Keywords match
+// case-insensitively as substrings; regexes match the text as is, so use
+// (?i) for case-insensitive patterns.
+type matchRules struct {
+	includeKeywords []string
+	excludeKeywords []string
+	includeRegex    []*regexp.Regexp
+	excludeRegex    []*regexp.Regexp
+}
+
+func newMatchRules(config map[string]interface{}) (matchRules, error) {
+	rules := matchRules{
+		includeKeywords: getStringSlice(config, "include_keywords"),
+		excludeKeywords: getStringSlice(config, "exclude_keywords"),
+	}
+	var err error
+	if rules.includeRegex, err = compilePatterns(config, "include_regex"); err != nil {
+		return rules, err
+	}
+	if rules.excludeRegex, err = compilePatterns(config, "exclude_regex"); err != nil {
+		return rules, err
+	}
+	return rules, nil
+}
+
+// compilePatterns compiles the regexes listed under key
+func compilePatterns(config map[string]interface{}, key string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, p := range getStringSlice(config, key) {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("filter '%s': invalid pattern %q: %w", key, p, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+func (m matchRules) empty() bool {
+	return len(m.includeKeywords) == 0 && len(m.excludeKeywords) == 0 &&
+		len(m.includeRegex) == 0 && len(m.excludeRegex) == 0
+}
+
+// keep reports whether an item's combined text passes the rules. Any
+// exclusion wins; otherwise each kind of include given (keywords, regexes)
+// needs at least one match.
+func (m matchRules) keep(text string) bool {
+	lower := strings.ToLower(text)
+
+	for _, keyword := range m.excludeKeywords {
+		if strings.Contains(lower, strings.ToLower(keyword)) {
+			return false
+		}
+	}
+	for _, re := range m.excludeRegex {
+		if re.MatchString(text) {
+			return false
+		}
+	}
+
+	if len(m.includeKeywords) > 0 {
+		included := false
+		for _, keyword := range m.includeKeywords {
+			if strings.Contains(lower, strings.ToLower(keyword)) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	if len(m.includeRegex) > 0 {
+		included := false
+		for _, re := range m.includeRegex {
+			if re.MatchString(text) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,159 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// Keys accepted by sort_by
+const (
+	SortPostedAt = "posted_at"
+	SortSalary   = "salary"
+	SortTitle    = "title"
+)
+
+// salaryNumber matches amounts such as "120,000", "8.500.000", "80k" or "10 juta"
+var salaryNumber = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)*)\s*(k|jt|juta|m)?\b`)
+
+func validateSort(config map[string]interface{}) error {
+	sortBy, ok := config["sort_by"]
+	if !ok {
+		return nil
+	}
+	switch sortBy {
+	case SortPostedAt, SortSalary, SortTitle:
+		return nil
+	}
+	return fmt.Errorf("filter 'sort_by' must be one of %s, %s, %s", SortPostedAt, SortSalary, SortTitle)
+}
+
+// sortKey is an item's value for the sort field; items without one sink to
+// the bottom whatever the direction
+type sortKey struct {
+	ok    bool
+	time  time.Time
+	num   float64
+	title string
+}
+
+func (a sortKey) less(b sortKey, by string) bool {
+	switch by {
+	case SortPostedAt:
+		return a.time.Before(b.time)
+	case SortSalary:
+		return a.num < b.num
+	default:
+		return a.title < b.title
+	}
+}
+
+// sortOrder returns the indexes of keys in stable sorted order
+func sortOrder(keys []sortKey, by string, desc bool) []int {
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := keys[order[i]], keys[order[j]]
+		if a.ok != b.ok {
+			return a.ok
+		}
+		if !a.ok {
+			return false
+		}
+		if desc {
+			return b.less(a, by)
+		}
+		return a.less(b, by)
+	})
+	return order
+}
+
+func titleKey(title string) sortKey {
+	title = strings.ToLower(strings.TrimSpace(title))
+	return sortKey{ok: title != "", title: title}
+}
+
+func sortScrapedItems(items []model.ScrapedItem, by string, desc bool) []model.ScrapedItem {
+	keys := make([]sortKey, len(items))
+	for i, item := range items {
+		switch by {
+		case SortPostedAt:
+			keys[i].time, keys[i].ok = model.ParseDate(item.PostedAt)
+		case SortSalary:
+			keys[i].num, keys[i].ok = parseSalary(item.Salary)
+		default:
+			keys[i] = titleKey(item.Title)
+		}
+	}
+
+	sorted := make([]model.ScrapedItem, 0, len(items))
+	for _, i := range sortOrder(keys, by, desc) {
+		sorted = append(sorted, items[i])
+	}
+	return sorted
+}
+
+func sortRSSItems(items []model.RSSItem, by string, desc bool) []model.RSSItem {
+	keys := make([]sortKey, len(items))
+	for i, item := range items {
+		switch by {
+		case SortPostedAt:
+			keys[i].time, keys[i].ok = model.ParseDate(item.PubDate)
+		case SortSalary:
+			// Feed entries carry no salary, so their order is kept
+		default:
+			keys[i] = titleKey(item.Title)
+		}
+	}
+
+	sorted := make([]model.RSSItem, 0, len(items))
+	for _, i := range sortOrder(keys, by, desc) {
+		sorted = append(sorted, items[i])
+	}
+	return sorted
+}
+
+// parseSalary returns the highest amount in a free-form salary such as
+// "$80k - $120k" or "Rp 8.000.000 - 12.000.000". Currencies aren't
+// converted, so sorting only makes sense among items from similar sources.
+func parseSalary(s string) (float64, bool) {
+	best, found := 0.0, false
+	for _, m := range salaryNumber.FindAllStringSubmatch(s, -1) {
+		n, ok := parseAmount(m[1])
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(m[2]) {
+		case "k":
+			n *= 1e3
+		case "jt", "juta", "m":
+			n *= 1e6
+		}
+		if !found || n > best {
+			best, found = n, true
+		}
+	}
+	return best, found
+}
+
+// parseAmount reads a number whose "." or "," groups thousands, or marks
+// decimals when followed by fewer than three digits
+func parseAmount(s string) (float64, bool) {
+	var b strings.Builder
+	groups := strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == ',' })
+	for i, g := range groups {
+		if i > 0 && i == len(groups)-1 && len(g) != 3 {
+			b.WriteByte('.')
+		}
+		b.WriteString(g)
+	}
+	n, err := strconv.ParseFloat(b.String(), 64)
+	return n, err == nil
+}
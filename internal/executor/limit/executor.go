@@ -0,0 +1,79 @@
+package limit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// Executor keeps the first N scraped or RSS items and drops the rest,
+// without any of the filter step's matching. Other results pass through.
+type Executor struct{}
+
+// NewExecutor creates a new limit executor
+func NewExecutor() *Executor {
+	return &Executor{}
+}
+
+func (e *Executor) Type() string {
+	return "limit"
+}
+
+func (e *Executor) Validate(config map[string]interface{}) error {
+	_, err := parseCount(config)
+	return err
+}
+
+func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	count, err := parseCount(config)
+	if err != nil {
+		return nil, err
+	}
+	if input == nil || input.Data == nil {
+		return input, nil
+	}
+
+	var limited interface{}
+	var total, kept int
+
+	switch v := input.Data.(type) {
+	case []model.ScrapedItem:
+		total = len(v)
+		if len(v) > count {
+			v = v[:count]
+		}
+		limited, kept = v, len(v)
+
+	case []model.RSSItem:
+		total = len(v)
+		if len(v) > count {
+			v = v[:count]
+		}
+		limited, kept = v, len(v)
+
+	default:
+		return input, nil
+	}
+
+	metadata := make(map[string]interface{}, len(input.Metadata)+1)
+	for k, val := range input.Metadata {
+		metadata[k] = val
+	}
+	metadata["dropped"] = total - kept
+
+	return &model.ExecutorResult{
+		Data:      limited,
+		Metadata:  metadata,
+		ItemCount: kept,
+	}, nil
+}
+
+// parseCount reads the required positive 'count'
+func parseCount(config map[string]interface{}) (int, error) {
+	count, ok := config["count"].(float64)
+	if !ok || count < 1 || count != float64(int(count)) {
+		return 0, fmt.Errorf("limit requires 'count' to be a positive whole number")
+	}
+	return int(count), nil
+}
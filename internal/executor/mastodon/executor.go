@@ -0,0 +1,427 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+)
+
+const (
+	// defaultMaxChars is Mastodon's default status length limit; instances
+	// with a higher limit can raise it with max_chars
+	defaultMaxChars = 500
+	// minMaxChars keeps max_chars large enough for an item with its link
+	minMaxChars = 100
+	// defaultMaxItems is how many of the input's items are posted
+	defaultMaxItems = 10
+	// urlWeight is the length Mastodon counts for any URL in a status
+	urlWeight = 23
+)
+
+// visibilities are the status visibilities Mastodon accepts
+var visibilities = map[string]bool{"public": true, "unlisted": true, "private": true, "direct": true}
+
+// urlPattern finds the URLs Mastodon counts as urlWeight characters
+var urlPattern = regexp.MustCompile(`https?://\S+`)
+
+// Executor posts statuses to a Mastodon instance, threading what doesn't fit
+// in one status into replies
+type Executor struct {
+	defaultInstance string
+	defaultToken    string
+	client          *http.Client
+	guard           *netguard.Guard
+}
+
+// NewExecutor creates a new Mastodon executor
+func NewExecutor(cfg config.MastodonConfig, guard *netguard.Guard) *Executor {
+	return &Executor{
+		defaultInstance: cfg.Instance,
+		defaultToken:    cfg.Token,
+		client:          guard.Client(10 * time.Second),
+		guard:           guard,
+	}
+}
+
+// statusRequest is the body of a POST /api/v1/statuses call
+type statusRequest struct {
+	Status      string `json:"status"`
+	Visibility  string `json:"visibility,omitempty"`
+	InReplyToID string `json:"in_reply_to_id,omitempty"`
+}
+
+// statusResponse holds the fields used from a created status
+type statusResponse struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+func (e *Executor) Type() string {
+	return "mastodon"
+}
+
+func (e *Executor) Validate(config map[string]interface{}) error {
+	instance, _ := config["instance"].(string)
+	if instance == "" && e.defaultInstance == "" {
+		return fmt.Errorf("mastodon requires 'instance' in config or MASTODON_INSTANCE environment variable")
+	}
+	if instance != "" {
+		if err := e.guard.CheckURL(instanceURL(instance)); err != nil {
+			return fmt.Errorf("mastodon 'instance': %w", err)
+		}
+	}
+	if token, _ := config["access_token"].(string); token == "" && e.defaultToken == "" {
+		return fmt.Errorf("mastodon requires 'access_token' in config or MASTODON_TOKEN environment variable")
+	}
+	_, err := parseOptions(config)
+	return err
+}
+
+func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	if input == nil {
+		return nil, fmt.Errorf("mastodon executor requires input data")
+	}
+
+	instance, _ := config["instance"].(string)
+	if instance == "" {
+		instance = e.defaultInstance
+	}
+	token, _ := config["access_token"].(string)
+	if token == "" {
+		token = e.defaultToken
+	}
+	if instance == "" || token == "" {
+		return nil, fmt.Errorf("no Mastodon account configured: set instance and access_token in pipeline config or MASTODON_INSTANCE and MASTODON_TOKEN environment variables")
+	}
+
+	opts, err := parseOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := renderStatuses(input, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format status: %w", err)
+	}
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("nothing to post")
+	}
+
+	endpoint := instanceURL(instance) + "/api/v1/statuses"
+	if err := e.guard.CheckURL(endpoint); err != nil {
+		return nil, err
+	}
+	var first statusResponse
+	replyTo := ""
+	for i, text := range statuses {
+		posted, err := e.post(ctx, endpoint, token, statusRequest{
+			Status:      text,
+			Visibility:  opts.visibility,
+			InReplyToID: replyTo,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to post status %d of %d: %w", i+1, len(statuses), err)
+		}
+		if i == 0 {
+			first = *posted
+		}
+		replyTo = posted.ID
+	}
+
+	return &model.ExecutorResult{
+		Data: map[string]interface{}{
+			"status": "posted",
+			"url":    first.URL,
+		},
+		ItemCount: input.ItemCount,
+		Metadata: map[string]interface{}{
+			"items_sent":      input.ItemCount,
+			"statuses_posted": len(statuses),
+		},
+	}, nil
+}
+
+// Preview returns the statuses Execute would post, without posting them
+func (e *Executor) Preview(input *model.ExecutorResult, config map[string]interface{}) (interface{}, error) {
+	if input == nil {
+		return nil, fmt.Errorf("mastodon executor requires input data")
+	}
+
+	opts, err := parseOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := renderStatuses(input, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format status: %w", err)
+	}
+
+	requests := make([]statusRequest, 0, len(statuses))
+	for _, text := range statuses {
+		requests = append(requests, statusRequest{Status: text, Visibility: opts.visibility})
+	}
+	return requests, nil
+}
+
+// options are the step settings that shape the posted statuses
+type options struct {
+	visibility string
+	maxItems   int
+	maxChars   int
+	header     string
+	template   string
+}
+
+func parseOptions(config map[string]interface{}) (options, error) {
+	opts := options{maxItems: defaultMaxItems, maxChars: defaultMaxChars}
+
+	opts.visibility, _ = config["visibility"].(string)
+	if opts.visibility != "" && !visibilities[opts.visibility] {
+		return opts, fmt.Errorf("mastodon 'visibility' must be public, unlisted, private or direct")
+	}
+	if raw, ok := config["max_items"]; ok {
+		n, ok := raw.(float64)
+		if !ok || n < 1 || n != float64(int(n)) {
+			return opts, fmt.Errorf("mastodon 'max_items' must be a positive whole number")
+		}
+		opts.maxItems = int(n)
+	}
+	if raw, ok := config["max_chars"]; ok {
+		n, ok := raw.(float64)
+		if !ok || n < minMaxChars || n != float64(int(n)) {
+			return opts, fmt.Errorf("mastodon 'max_chars' must be a whole number of at least %d", minMaxChars)
+		}
+		opts.maxChars = int(n)
+	}
+	opts.header, _ = config["header"].(string)
+	opts.template, _ = config["template"].(string)
+	return opts, nil
+}
+
+// renderStatuses turns the input into a thread of statuses within the
+// length limit. Each of the top items becomes a block of its title and
+// link; other input is split at paragraphs, then words.
+func renderStatuses(input *model.ExecutorResult, opts options) ([]string, error) {
+	var blocks []string
+	if opts.header != "" {
+		blocks = append(blocks, opts.header)
+	}
+
+	if opts.template != "" {
+		text, err := executeTemplate(opts.template, input.Data)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, paragraphs(text)...)
+		return pack(blocks, opts.maxChars), nil
+	}
+
+	switch v := input.Data.(type) {
+	case string:
+		blocks = append(blocks, paragraphs(v)...)
+
+	case model.Alert:
+		blocks = append(blocks, paragraphs(v.Text())...)
+
+	case []model.ScrapedItem:
+		for i, item := range v {
+			if i == opts.maxItems {
+				break
+			}
+			blocks = append(blocks, itemBlock(item.Title, item.URL, opts.maxChars))
+		}
+
+	case []model.RSSItem:
+		for i, item := range v {
+			if i == opts.maxItems {
+				break
+			}
+			blocks = append(blocks, itemBlock(item.Title, item.Link, opts.maxChars))
+		}
+
+	default:
+		jsonBytes, _ := json.MarshalIndent(input.Data, "", "  ")
+		blocks = append(blocks, paragraphs(string(jsonBytes))...)
+	}
+
+	return pack(blocks, opts.maxChars), nil
+}
+
+// itemBlock renders an item as its title and link, shortening the title so
+// the block fits in one status
+func itemBlock(title, url string, limit int) string {
+	title = strings.TrimSpace(title)
+	if url == "" {
+		return truncate(title, limit)
+	}
+	room := limit - statusLength("\n"+url)
+	return truncate(title, room) + "\n" + url
+}
+
+// pack joins blocks into as few statuses as fit within limit, starting a new
+// status rather than splitting a block unless the block alone is too long
+func pack(blocks []string, limit int) []string {
+	var statuses []string
+	current := ""
+	for _, block := range blocks {
+		if block == "" {
+			continue
+		}
+		if statusLength(block) > limit {
+			if current != "" {
+				statuses = append(statuses, current)
+				current = ""
+			}
+			parts := splitWords(block, limit)
+			statuses = append(statuses, parts[:len(parts)-1]...)
+			current = parts[len(parts)-1]
+			continue
+		}
+
+		joined := block
+		if current != "" {
+			joined = current + "\n\n" + block
+		}
+		if statusLength(joined) <= limit {
+			current = joined
+			continue
+		}
+		statuses = append(statuses, current)
+		current = block
+	}
+	if current != "" {
+		statuses = append(statuses, current)
+	}
+	return statuses
+}
+
+// splitWords breaks an overlong block into statuses at spaces, cutting words
+// that don't fit in a status on their own
+func splitWords(block string, limit int) []string {
+	var parts []string
+	current := ""
+	for _, word := range strings.Fields(block) {
+		for statusLength(word) > limit {
+			if current != "" {
+				parts = append(parts, current)
+				current = ""
+			}
+			runes := []rune(word)
+			parts = append(parts, string(runes[:limit]))
+			word = string(runes[limit:])
+		}
+
+		joined := word
+		if current != "" {
+			joined = current + " " + word
+		}
+		if statusLength(joined) <= limit {
+			current = joined
+			continue
+		}
+		parts = append(parts, current)
+		current = word
+	}
+	if current != "" || len(parts) == 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// paragraphs splits text at blank lines
+func paragraphs(text string) []string {
+	var blocks []string
+	for _, p := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			blocks = append(blocks, p)
+		}
+	}
+	return blocks
+}
+
+// statusLength counts characters the way Mastodon does, with every URL
+// counting as urlWeight characters whatever its length
+func statusLength(text string) int {
+	n := utf8.RuneCountInString(text)
+	for _, url := range urlPattern.FindAllString(text, -1) {
+		n += urlWeight - utf8.RuneCountInString(url)
+	}
+	return n
+}
+
+// truncate shortens text to at most limit characters, marking the cut
+func truncate(text string, limit int) string {
+	if statusLength(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && statusLength(string(runes)+"…") > limit {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "…"
+}
+
+// instanceURL accepts an instance as a host name or a base URL
+func instanceURL(instance string) string {
+	instance = strings.TrimSuffix(strings.TrimSpace(instance), "/")
+	if !strings.HasPrefix(instance, "http://") && !strings.HasPrefix(instance, "https://") {
+		instance = "https://" + instance
+	}
+	return instance
+}
+
+func (e *Executor) post(ctx context.Context, endpoint, token string, status statusRequest) (*statusResponse, error) {
+	jsonBody, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Mastodon API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var posted statusResponse
+	if err := json.Unmarshal(body, &posted); err != nil {
+		return nil, fmt.Errorf("failed to parse Mastodon response: %w", err)
+	}
+	return &posted, nil
+}
+
+func executeTemplate(tmplStr string, data interface{}) (string, error) {
+	tmpl, err := template.New("mastodon").Parse(tmplStr)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
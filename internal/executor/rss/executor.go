@@ -2,9 +2,11 @@ package rss
 
 import (
 	"context"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -39,6 +41,9 @@ func (e *Executor) Validate(config map[string]interface{}) error {
 			return fmt.Errorf("rss requires 'url' or 'urls' in config")
 		}
 	}
+	if days, ok := config["dedupe_window_days"].(float64); ok && days < 0 {
+		return fmt.Errorf("rss 'dedupe_window_days' must not be negative")
+	}
 	return nil
 }
 
@@ -72,19 +77,26 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 		}
 	}
 
-	// Get task ID for caching
+	// Get task ID for caching; a forced run skips conditional requests and/or dedup
 	taskID, _ := config["task_id"].(string)
+	forceRefresh, _ := config["force_refresh"].(bool)
+	bypassDedup, _ := config["bypass_dedup"].(bool)
 
 	// Fetch all RSS feeds
 	var allItems []model.RSSItem
 	var errors []string
+	var notModified []string
 
 	for _, url := range urls {
-		items, err := e.fetchFeed(ctx, url, limit)
+		items, unchanged, err := e.fetchFeed(ctx, url, limit, taskID, forceRefresh)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", url, err))
 			continue
 		}
+		if unchanged {
+			notModified = append(notModified, url)
+			continue
+		}
 
 		// Filter by keywords if provided
 		if len(keywords) > 0 {
@@ -92,8 +104,8 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 		}
 
 		// Deduplicate using cache
-		if e.cache != nil && taskID != "" {
-			items = e.filterNewItems(ctx, items, taskID)
+		if e.cache != nil && taskID != "" && !bypassDedup {
+			items = e.filterNewItems(ctx, items, taskID, storage.DedupeWindow(config))
 		}
 
 		allItems = append(allItems, items...)
@@ -111,6 +123,9 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 	if len(errors) > 0 {
 		metadata["errors"] = errors
 	}
+	if len(notModified) > 0 {
+		metadata["not_modified"] = notModified
+	}
 
 	return &model.ExecutorResult{
 		Data:      allItems,
@@ -133,13 +148,47 @@ type rssChannel struct {
 }
 
 type rssItem struct {
-	Title       string   `xml:"title"`
-	Link        string   `xml:"link"`
-	Description string   `xml:"description"`
-	PubDate     string   `xml:"pubDate"`
-	GUID        string   `xml:"guid"`
-	Author      string   `xml:"author"`
-	Categories  []string `xml:"category"`
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Description string         `xml:"description"`
+	PubDate     string         `xml:"pubDate"`
+	GUID        string         `xml:"guid"`
+	Author      string         `xml:"author"`
+	Categories  []string       `xml:"category"`
+	Enclosures  []rssEnclosure `xml:"enclosure"`
+	ItunesImage itunesImage    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	mediaElements
+}
+
+type rssEnclosure struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// mediaElements are the Media RSS (http://search.yahoo.com/mrss/) elements
+// of an RSS item or Atom entry. YouTube and others wrap them in a media:group.
+type mediaElements struct {
+	MediaContent    []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaGroup      struct {
+		Content    []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+		Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	} `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+type mediaContent struct {
+	URL        string           `xml:"url,attr"`
+	Type       string           `xml:"type,attr"`
+	Medium     string           `xml:"medium,attr"`
+	Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type mediaThumbnail struct {
+	URL string `xml:"url,attr"`
 }
 
 type atomFeed struct {
@@ -150,45 +199,157 @@ type atomFeed struct {
 
 type atomEntry struct {
 	Title   string     `xml:"title"`
-	Link    atomLink   `xml:"link"`
+	Links   []atomLink `xml:"link"`
 	Summary string     `xml:"summary"`
-	Content string     `xml:"content"`
+	Content string     `xml:"http://www.w3.org/2005/Atom content"`
 	Updated string     `xml:"updated"`
 	ID      string     `xml:"id"`
 	Author  atomAuthor `xml:"author"`
+	mediaElements
 }
 
 type atomLink struct {
 	Href string `xml:"href,attr"`
 	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// link returns the entry's page: its alternate link, or failing that the
+// first link that isn't an enclosure
+func (entry atomEntry) link() string {
+	fallback := ""
+	for _, l := range entry.Links {
+		switch l.Rel {
+		case "alternate":
+			return l.Href
+		case "", "related", "via":
+			if fallback == "" {
+				fallback = l.Href
+			}
+		}
+	}
+	return fallback
+}
+
+// enclosure returns the entry's first enclosure link, if any
+func (entry atomEntry) enclosure() (atomLink, bool) {
+	for _, l := range entry.Links {
+		if l.Rel == "enclosure" && l.Href != "" {
+			return l, true
+		}
+	}
+	return atomLink{}, false
+}
+
+// media picks an image for the item and its attached media from the Media
+// RSS elements, after any enclosure the feed itself declares
+func (m mediaElements) media(enclosureURL, enclosureType string) (imageURL, mediaURL, mediaType string) {
+	contents := append(append([]mediaContent{}, m.MediaContent...), m.MediaGroup.Content...)
+
+	mediaURL, mediaType = enclosureURL, enclosureType
+	for _, c := range contents {
+		if mediaURL == "" && c.URL != "" {
+			mediaURL, mediaType = c.URL, c.Type
+		}
+	}
+
+	thumbnails := append(append([]mediaThumbnail{}, m.MediaThumbnails...), m.MediaGroup.Thumbnails...)
+	for _, c := range contents {
+		thumbnails = append(thumbnails, c.Thumbnails...)
+	}
+	for _, t := range thumbnails {
+		if t.URL != "" {
+			return t.URL, mediaURL, mediaType
+		}
+	}
+
+	// Without a thumbnail, an image attachment is the item's picture
+	for _, c := range contents {
+		if c.URL != "" && (c.Medium == "image" || strings.HasPrefix(c.Type, "image/")) {
+			return c.URL, mediaURL, mediaType
+		}
+	}
+	if strings.HasPrefix(enclosureType, "image/") {
+		return enclosureURL, mediaURL, mediaType
+	}
+	return "", mediaURL, mediaType
 }
 
 type atomAuthor struct {
 	Name string `xml:"name"`
 }
 
-func (e *Executor) fetchFeed(ctx context.Context, url string, limit int) ([]model.RSSItem, error) {
+// fetchFeed downloads and parses a feed. When the task has fetched the feed
+// before, the request is conditional and an unchanged feed reports notModified;
+// forceRefresh always fetches the full feed but still refreshes the validators.
+func (e *Executor) fetchFeed(ctx context.Context, url string, limit int, taskID string, forceRefresh bool) (items []model.RSSItem, notModified bool, err error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; MultiWorker/1.0)")
-	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml, text/xml")
+
+	conditional := e.cache != nil && taskID != ""
+	if conditional && !forceRefresh {
+		etag, lastModified, err := e.cache.GetFeedValidators(ctx, taskID, url)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
 
 	resp, err := e.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, true, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+		return nil, false, fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+
+	items, err = parseFeed(body, resp.Header.Get("Content-Type"), limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Only remember validators for feeds that parsed, so a broken response is refetched
+	if conditional {
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			if err := e.cache.SaveFeedValidators(ctx, taskID, url, etag, lastModified); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+	}
+
+	return items, false, nil
+}
+
+func parseFeed(body []byte, contentType string, limit int) ([]model.RSSItem, error) {
+
+	// JSON Feed is recognised up front; everything else goes through the XML parsers
+	if isJSONFeed(contentType, body) {
+		var feed jsonFeed
+		if err := json.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("could not parse JSON Feed: %w", err)
+		}
+		return convertJSONFeedItems(feed.Items, feed.Title, limit), nil
 	}
 
 	// Try RSS first
@@ -203,7 +364,7 @@ func (e *Executor) fetchFeed(ctx context.Context, url string, limit int) ([]mode
 		return convertAtomItems(atom.Entries, atom.Title, limit), nil
 	}
 
-	return nil, fmt.Errorf("could not parse feed as RSS or Atom")
+	return nil, fmt.Errorf("could not parse feed as JSON Feed, RSS or Atom")
 }
 
 func convertRSSItems(items []rssItem, source string, limit int) []model.RSSItem {
@@ -219,6 +380,15 @@ func convertRSSItems(items []rssItem, source string, limit int) []model.RSSItem
 			id = item.Link
 		}
 
+		var enclosure rssEnclosure
+		if len(item.Enclosures) > 0 {
+			enclosure = item.Enclosures[0]
+		}
+		imageURL, mediaURL, mediaType := item.media(enclosure.URL, enclosure.Type)
+		if imageURL == "" {
+			imageURL = item.ItunesImage.Href
+		}
+
 		result = append(result, model.RSSItem{
 			ID:          id,
 			Title:       item.Title,
@@ -228,6 +398,9 @@ func convertRSSItems(items []rssItem, source string, limit int) []model.RSSItem
 			PubDate:     item.PubDate,
 			Categories:  item.Categories,
 			Author:      item.Author,
+			ImageURL:    imageURL,
+			MediaURL:    mediaURL,
+			MediaType:   mediaType,
 		})
 	}
 
@@ -247,14 +420,20 @@ func convertAtomItems(entries []atomEntry, source string, limit int) []model.RSS
 			description = entry.Content
 		}
 
+		enclosure, _ := entry.enclosure()
+		imageURL, mediaURL, mediaType := entry.media(enclosure.Href, enclosure.Type)
+
 		result = append(result, model.RSSItem{
 			ID:          entry.ID,
 			Title:       entry.Title,
 			Description: stripHTMLTags(description),
-			Link:        entry.Link.Href,
+			Link:        entry.link(),
 			Source:      source,
 			PubDate:     entry.Updated,
 			Author:      entry.Author.Name,
+			ImageURL:    imageURL,
+			MediaURL:    mediaURL,
+			MediaType:   mediaType,
 		})
 	}
 
@@ -277,7 +456,7 @@ func filterByKeywords(items []model.RSSItem, keywords []string) []model.RSSItem
 	return filtered
 }
 
-func (e *Executor) filterNewItems(ctx context.Context, items []model.RSSItem, taskID string) []model.RSSItem {
+func (e *Executor) filterNewItems(ctx context.Context, items []model.RSSItem, taskID string, window time.Duration) []model.RSSItem {
 	var newItems []model.RSSItem
 	var newHashes []string
 
@@ -288,7 +467,7 @@ func (e *Executor) filterNewItems(ctx context.Context, items []model.RSSItem, ta
 		}
 		hash := e.cache.HashContent(content)
 
-		exists, err := e.cache.ExistsForTask(ctx, hash, taskID)
+		exists, err := e.cache.ExistsForTask(ctx, hash, taskID, window)
 		if err != nil || exists {
 			continue
 		}
@@ -0,0 +1,141 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"strings"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// JSON Feed (https://jsonfeed.org) structures
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            json.RawMessage      `json:"id"` // A string per the spec, but some feeds publish numbers
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Tags          []string             `json:"tags"`
+	Authors       []jsonFeedAuthor     `json:"authors"`
+	Author        *jsonFeedAuthor      `json:"author"` // JSON Feed 1.0
+	Image         string               `json:"image"`
+	BannerImage   string               `json:"banner_image"`
+	Attachments   []jsonFeedAttachment `json:"attachments"`
+}
+
+type jsonFeedAttachment struct {
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// isJSONFeed reports whether a response is a JSON Feed, by content type or
+// by a body that declares a jsonfeed.org version
+func isJSONFeed(contentType string, body []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == "application/feed+json" {
+		return true
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+	var probe struct {
+		Version string `json:"version"`
+	}
+	return json.Unmarshal(trimmed, &probe) == nil && strings.Contains(probe.Version, "jsonfeed.org")
+}
+
+func convertJSONFeedItems(items []jsonFeedItem, source string, limit int) []model.RSSItem {
+	var result []model.RSSItem
+
+	for i, item := range items {
+		if limit > 0 && i >= limit {
+			break
+		}
+
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+
+		id := jsonFeedID(item.ID)
+		if id == "" {
+			id = link
+		}
+
+		description := item.ContentText
+		if description == "" {
+			description = stripHTMLTags(item.ContentHTML)
+		}
+		if description == "" {
+			description = item.Summary
+		}
+
+		pubDate := item.DatePublished
+		if pubDate == "" {
+			pubDate = item.DateModified
+		}
+
+		var author string
+		if len(item.Authors) > 0 {
+			author = item.Authors[0].Name
+		} else if item.Author != nil {
+			author = item.Author.Name
+		}
+
+		imageURL := item.Image
+		if imageURL == "" {
+			imageURL = item.BannerImage
+		}
+		var mediaURL, mediaType string
+		if len(item.Attachments) > 0 {
+			mediaURL, mediaType = item.Attachments[0].URL, item.Attachments[0].MimeType
+			if imageURL == "" && strings.HasPrefix(mediaType, "image/") {
+				imageURL = mediaURL
+			}
+		}
+
+		result = append(result, model.RSSItem{
+			ID:          id,
+			Title:       item.Title,
+			Description: strings.TrimSpace(description),
+			Link:        link,
+			Source:      source,
+			PubDate:     pubDate,
+			Categories:  item.Tags,
+			Author:      author,
+			ImageURL:    imageURL,
+			MediaURL:    mediaURL,
+			MediaType:   mediaType,
+		})
+	}
+
+	return result
+}
+
+func jsonFeedID(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	// Numeric IDs are kept as their literal text
+	return strings.Trim(string(raw), `"`)
+}
@@ -1,11 +1,11 @@
 package scraper
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/multi-worker/internal/config"
@@ -13,10 +13,11 @@ import (
 
 // HTTPClient is a configured HTTP client for scraping
 type HTTPClient struct {
-	client    *http.Client
-	userAgent string
-	rateLimit time.Duration
-	lastReq   time.Time
+	client     *http.Client
+	userAgent  string
+	limits     *hostLimiter
+	maxRetries int        // Extra attempts after a network error or 5xx response
+	proxies    *proxyPool // nil when scraping without a proxy
 }
 
 // NewHTTPClient creates a new HTTP client for scraping
@@ -27,11 +28,9 @@ func NewHTTPClient(cfg config.ScraperConfig) *HTTPClient {
 		DisableCompression:  false,
 	}
 
-	if cfg.ProxyURL != "" {
-		proxyURL, err := url.Parse(cfg.ProxyURL)
-		if err == nil {
-			transport.Proxy = http.ProxyURL(proxyURL)
-		}
+	proxies := newProxyPool(append([]string{cfg.ProxyURL}, cfg.ProxyURLs...))
+	if proxies != nil {
+		transport.Proxy = proxyFromContext
 	}
 
 	return &HTTPClient{
@@ -39,99 +38,127 @@ func NewHTTPClient(cfg config.ScraperConfig) *HTTPClient {
 			Timeout:   cfg.RequestTimeout,
 			Transport: transport,
 		},
-		userAgent: cfg.UserAgent,
-		rateLimit: time.Duration(cfg.RateLimitMs) * time.Millisecond,
+		userAgent:  cfg.UserAgent,
+		limits:     newHostLimiter(time.Duration(cfg.RateLimitMs) * time.Millisecond),
+		maxRetries: cfg.MaxRetries,
+		proxies:    proxies,
 	}
 }
 
-// Get performs an HTTP GET request with rate limiting
-func (c *HTTPClient) Get(ctx context.Context, url string) ([]byte, error) {
-	// Apply rate limiting
-	if elapsed := time.Since(c.lastReq); elapsed < c.rateLimit {
-		time.Sleep(c.rateLimit - elapsed)
-	}
-	c.lastReq = time.Now()
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// Backoff between retries of a failed request: retryBaseDelay, doubling
+// each time up to retryMaxDelay
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
 
-	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+// Get performs an HTTP GET request with per-host rate limiting
+func (c *HTTPClient) Get(ctx context.Context, url string) ([]byte, error) {
+	return c.do(ctx, "GET", url, nil, map[string]string{
+		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"Accept-Language": "en-US,en;q=0.5",
+	})
+}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
+// GetJSON performs an HTTP GET request expecting JSON response
+func (c *HTTPClient) GetJSON(ctx context.Context, url string) ([]byte, error) {
+	return c.do(ctx, "GET", url, nil, map[string]string{
+		"Accept": "application/json",
+	})
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// GetJSONWithHeaders performs a GET request expecting JSON, with extra
+// headers such as an API token
+func (c *HTTPClient) GetJSONWithHeaders(ctx context.Context, url string, headers map[string]string) ([]byte, error) {
+	merged := map[string]string{"Accept": "application/json"}
+	for k, v := range headers {
+		merged[k] = v
 	}
+	return c.do(ctx, "GET", url, nil, merged)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// PostJSON performs an HTTP POST request with JSON body
+func (c *HTTPClient) PostJSON(ctx context.Context, url string, body io.Reader) ([]byte, error) {
+	// Buffer the body so a retry can send it again
+	payload, err := io.ReadAll(body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read request body: %w", err)
 	}
-
-	return body, nil
+	return c.do(ctx, "POST", url, payload, map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	})
 }
 
-// GetJSON performs an HTTP GET request expecting JSON response
-func (c *HTTPClient) GetJSON(ctx context.Context, url string) ([]byte, error) {
-	if elapsed := time.Since(c.lastReq); elapsed < c.rateLimit {
-		time.Sleep(c.rateLimit - elapsed)
-	}
-	c.lastReq = time.Now()
+// do sends a request, retrying network errors and 5xx responses up to
+// maxRetries times with exponential backoff. 4xx responses fail at once, and
+// no retry is started that the context's deadline wouldn't let finish waiting.
+func (c *HTTPClient) do(ctx context.Context, method, url string, body []byte, headers map[string]string) ([]byte, error) {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		respBody, retryable, err := c.attempt(ctx, method, url, body, headers)
+		if err == nil || !retryable || attempt >= c.maxRetries || ctx.Err() != nil {
+			return respBody, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return nil, err
+		}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+		delay = min(delay*2, retryMaxDelay)
 	}
+}
 
-	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set("Accept", "application/json")
+// attempt makes a single rate-limited request, reporting whether a failure
+// is worth retrying
+func (c *HTTPClient) attempt(ctx context.Context, method, url string, body []byte, headers map[string]string) ([]byte, bool, error) {
+	ctx, proxy := c.proxies.withProxy(ctx)
 
-	resp, err := c.client.Do(req)
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	// Apply rate limiting per host
+	if err := c.limits.Wait(ctx, req.URL.Host); err != nil {
+		return nil, false, err
 	}
 
-	return io.ReadAll(resp.Body)
-}
-
-// PostJSON performs an HTTP POST request with JSON body
-func (c *HTTPClient) PostJSON(ctx context.Context, url string, body io.Reader) ([]byte, error) {
-	if elapsed := time.Since(c.lastReq); elapsed < c.rateLimit {
-		time.Sleep(c.rateLimit - elapsed)
+	req.Header.Set("User-Agent", c.userAgent)
+	for k, v := range headers {
+		req.Header.Set(k, v)
 	}
-	c.lastReq = time.Now()
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	for k, v := range headersFromContext(ctx) {
+		req.Header.Set(k, v)
 	}
 
-	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		if proxy != nil && isProxyError(err) {
+			c.proxies.MarkDown(proxy)
+		}
+		return nil, true, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, resp.StatusCode >= 500, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return io.ReadAll(resp.Body)
+	return respBody, false, nil
 }
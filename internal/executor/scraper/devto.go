@@ -40,46 +40,70 @@ type devToArticle struct {
 	CommentsCount          int `json:"comments_count"`
 }
 
-func (s *DevToScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
-	url := "https://dev.to/api/articles?per_page=50"
-	if query != "" {
-		url = fmt.Sprintf("https://dev.to/api/articles?tag=%s&per_page=50", strings.ReplaceAll(query, " ", ""))
-	}
+// devToPageSize is how many articles are requested per page
+const devToPageSize = 50
 
-	data, err := s.client.GetJSON(ctx, url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch Dev.to: %w", err)
-	}
-
-	var articles []devToArticle
-	if err := json.Unmarshal(data, &articles); err != nil {
-		return nil, fmt.Errorf("failed to parse Dev.to response: %w", err)
-	}
+func (s *DevToScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	return collect(ctx, s, query, limit)
+}
 
-	if limit > 0 && len(articles) > limit {
-		articles = articles[:limit]
+// ScrapeStream pages through the Dev.to articles API until limit articles
+// were delivered or a page comes back short
+func (s *DevToScraper) ScrapeStream(ctx context.Context, query string, limit int, emit func(page []model.ScrapedItem) bool) error {
+	if limit <= 0 {
+		limit = devToPageSize
 	}
 
-	var items []model.ScrapedItem
-	for _, article := range articles {
-		items = append(items, model.ScrapedItem{
-			ID:          fmt.Sprintf("%d", article.ID),
-			Title:       article.Title,
-			Description: article.Description,
-			URL:         article.URL,
-			Source:      "Dev.to",
-			Category:    "news",
-			Tags:        article.TagList,
-			PostedAt:    article.PublishedAt,
-			Extra: map[string]interface{}{
-				"author":    article.User.Name,
-				"reactions": article.PositiveReactionsCount,
-				"comments":  article.CommentsCount,
-			},
-		})
+	delivered := 0
+	for page := 1; delivered < limit; page++ {
+		url := fmt.Sprintf("https://dev.to/api/articles?per_page=%d&page=%d", devToPageSize, page)
+		if query != "" {
+			url += "&tag=" + strings.ReplaceAll(query, " ", "")
+		}
+
+		data, err := s.client.GetJSON(ctx, url)
+		if err != nil {
+			return fmt.Errorf("failed to fetch Dev.to: %w", err)
+		}
+
+		var articles []devToArticle
+		if err := json.Unmarshal(data, &articles); err != nil {
+			return fmt.Errorf("failed to parse Dev.to response: %w", err)
+		}
+		full := len(articles) == devToPageSize
+
+		if len(articles) > limit-delivered {
+			articles = articles[:limit-delivered]
+		}
+
+		items := make([]model.ScrapedItem, 0, len(articles))
+		for _, article := range articles {
+			items = append(items, model.ScrapedItem{
+				ID:          fmt.Sprintf("%d", article.ID),
+				Title:       article.Title,
+				Description: article.Description,
+				URL:         article.URL,
+				Source:      "Dev.to",
+				Category:    "news",
+				Tags:        article.TagList,
+				PostedAt:    article.PublishedAt,
+				Extra: map[string]interface{}{
+					"author":    article.User.Name,
+					"reactions": article.PositiveReactionsCount,
+					"comments":  article.CommentsCount,
+				},
+			})
+		}
+		delivered += len(items)
+
+		if len(items) > 0 && !emit(items) {
+			return nil
+		}
+		if !full {
+			return nil
+		}
 	}
-
-	return items, nil
+	return nil
 }
 
 // ProductHuntScraper scrapes from Product Hunt
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/multi-worker/internal/model"
 	"github.com/multi-worker/internal/storage"
@@ -28,13 +29,53 @@ func (e *Executor) Type() string {
 }
 
 func (e *Executor) Validate(config map[string]interface{}) error {
-	if _, ok := config["source"]; !ok {
-		// If no specific source, check for sources array
-		if _, ok := config["sources"]; !ok {
-			return fmt.Errorf("scraper requires 'source' or 'sources' in config")
+	_, hasSource := config["source"]
+	_, hasSources := config["sources"]
+	category, hasCategory := config["category"].(string)
+	if !hasSource && !hasSources && !hasCategory {
+		return fmt.Errorf("scraper requires 'source', 'sources' or 'category' in config")
+	}
+
+	// Catch typos at creation rather than on every run
+	var names []string
+	if hasSource {
+		name, ok := config["source"].(string)
+		if !ok {
+			return fmt.Errorf("scraper 'source' must be a source name")
+		}
+		names = append(names, name)
+	}
+	if hasSources {
+		list, ok := config["sources"].([]interface{})
+		if !ok {
+			return fmt.Errorf("scraper 'sources' must be an array of source names")
+		}
+		for _, v := range list {
+			name, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("scraper 'sources' must be an array of source names")
+			}
+			names = append(names, name)
+		}
+	}
+	var unknown []string
+	for _, name := range names {
+		if _, err := e.registry.Get(name); err != nil {
+			unknown = append(unknown, name)
 		}
 	}
-	return nil
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown scraper sources: %s (see GET /api/v1/scrapers)", strings.Join(unknown, ", "))
+	}
+	if hasCategory && len(e.registry.GetByCategory(category)) == 0 {
+		return fmt.Errorf("unknown scraper category %q (see GET /api/v1/scrapers)", category)
+	}
+
+	if days, ok := config["dedupe_window_days"].(float64); ok && days < 0 {
+		return fmt.Errorf("scraper 'dedupe_window_days' must not be negative")
+	}
+	_, err := parseStepHeaders(config)
+	return err
 }
 
 func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
@@ -59,28 +100,14 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 		limit = int(l)
 	}
 
-	// Get task ID for caching
+	// Get task ID for caching; a forced run can skip dedup
 	taskID, _ := config["task_id"].(string)
+	bypassDedup, _ := config["bypass_dedup"].(bool)
 
-	// Determine sources to scrape
-	var sources []string
-	if source, ok := config["source"].(string); ok {
-		sources = append(sources, source)
-	}
-	if sourcesArr, ok := config["sources"].([]interface{}); ok {
-		for _, s := range sourcesArr {
-			if str, ok := s.(string); ok {
-				sources = append(sources, str)
-			}
-		}
-	}
-
-	// If category specified, get all sources in that category
-	if category, ok := config["category"].(string); ok {
-		categorySources := e.registry.GetByCategory(category)
-		for _, s := range categorySources {
-			sources = append(sources, s.Name())
-		}
+	sources := e.ResolveSources(config)
+	stepHeaders, err := parseStepHeaders(config)
+	if err != nil {
+		return nil, err
 	}
 
 	// Scrape from all sources
@@ -94,15 +121,35 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 			continue
 		}
 
-		items, err := source.Scrape(ctx, query, limit)
+		dedupe := e.cache != nil && taskID != "" && !bypassDedup
+		sourceCtx := withHeaders(ctx, e.registry.Headers(sourceName, stepHeaders))
+
+		// Streaming sources are deduplicated and cached a page at a time,
+		// so only their new items are ever held
+		if streaming, ok := source.(StreamingSource); ok {
+			err := streaming.ScrapeStream(sourceCtx, query, limit, func(page []model.ScrapedItem) bool {
+				if dedupe {
+					page = e.filterNewItems(ctx, page, taskID, storage.DedupeWindow(config))
+				}
+				allItems = append(allItems, page...)
+				return true
+			})
+			if err != nil {
+				// Pages already handed on were marked as seen, so keep them
+				errors = append(errors, fmt.Sprintf("%s: %v", sourceName, err))
+			}
+			continue
+		}
+
+		items, err := source.Scrape(sourceCtx, query, limit)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", sourceName, err))
 			continue
 		}
 
 		// Deduplicate using cache
-		if e.cache != nil && taskID != "" {
-			items = e.filterNewItems(ctx, items, taskID)
+		if dedupe {
+			items = e.filterNewItems(ctx, items, taskID, storage.DedupeWindow(config))
 		}
 
 		allItems = append(allItems, items...)
@@ -110,9 +157,9 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 
 	// Build metadata
 	metadata := map[string]interface{}{
-		"sources":      sources,
-		"query":        query,
-		"total_items":  len(allItems),
+		"sources":     sources,
+		"query":       query,
+		"total_items": len(allItems),
 	}
 	if len(errors) > 0 {
 		metadata["errors"] = errors
@@ -125,8 +172,33 @@ func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, con
 	}, nil
 }
 
+// ResolveSources returns the source names a step scrapes: its source, its
+// sources and every source in its category
+func (e *Executor) ResolveSources(config map[string]interface{}) []string {
+	var sources []string
+	if source, ok := config["source"].(string); ok {
+		sources = append(sources, source)
+	}
+	if sourcesArr, ok := config["sources"].([]interface{}); ok {
+		for _, s := range sourcesArr {
+			if str, ok := s.(string); ok {
+				sources = append(sources, str)
+			}
+		}
+	}
+
+	// If category specified, get all sources in that category
+	if category, ok := config["category"].(string); ok {
+		categorySources := e.registry.GetByCategory(category)
+		for _, s := range categorySources {
+			sources = append(sources, s.Name())
+		}
+	}
+	return sources
+}
+
 // filterNewItems removes items that have been seen before
-func (e *Executor) filterNewItems(ctx context.Context, items []model.ScrapedItem, taskID string) []model.ScrapedItem {
+func (e *Executor) filterNewItems(ctx context.Context, items []model.ScrapedItem, taskID string, window time.Duration) []model.ScrapedItem {
 	var newItems []model.ScrapedItem
 	var newHashes []string
 
@@ -138,7 +210,7 @@ func (e *Executor) filterNewItems(ctx context.Context, items []model.ScrapedItem
 		}
 		hash := e.cache.HashContent(content)
 
-		exists, err := e.cache.ExistsForTask(ctx, hash, taskID)
+		exists, err := e.cache.ExistsForTask(ctx, hash, taskID, window)
 		if err != nil || exists {
 			continue
 		}
@@ -0,0 +1,126 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/storage"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+// pagedSource is a large paginated source. It builds one page at a time and
+// records what the executor had cached when each page was requested.
+type pagedSource struct {
+	total, pageSize int
+	prefix          string
+	pages           int
+	largestPage     int
+	beforePage      func(page int)
+}
+
+func (s *pagedSource) Name() string     { return "paged" }
+func (s *pagedSource) Category() string { return "jobs" }
+
+func (s *pagedSource) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	return nil, fmt.Errorf("paginated source was scraped in one go")
+}
+
+func (s *pagedSource) ScrapeStream(ctx context.Context, query string, limit int, emit func(page []model.ScrapedItem) bool) error {
+	for start := 0; start < min(limit, s.total); start += s.pageSize {
+		if s.beforePage != nil {
+			s.beforePage(s.pages)
+		}
+		page := make([]model.ScrapedItem, 0, s.pageSize)
+		for i := start; i < min(start+s.pageSize, limit, s.total); i++ {
+			page = append(page, model.ScrapedItem{Title: fmt.Sprintf("Job %d", i), URL: s.url(i), Source: "paged"})
+		}
+		s.pages++
+		s.largestPage = max(s.largestPage, len(page))
+		if !emit(page) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *pagedSource) url(i int) string {
+	return fmt.Sprintf("https://example.com/%s/jobs/%d", s.prefix, i)
+}
+
+func newPagedExecutor(source *pagedSource, cache *storage.CacheRepository) *Executor {
+	registry := &Registry{sources: make(map[string]Source)}
+	registry.register(source)
+	return NewExecutor(registry, cache)
+}
+
+func TestLargeSourceIsStreamedInPages(t *testing.T) {
+	source := &pagedSource{total: 5000, pageSize: 100}
+	e := newPagedExecutor(source, nil)
+
+	result, err := e.Execute(context.Background(), nil, map[string]interface{}{"source": "paged", "limit": float64(2500)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ItemCount != 2500 || len(result.Data.([]model.ScrapedItem)) != 2500 {
+		t.Errorf("got %d items, want the limit of 2500", result.ItemCount)
+	}
+	if source.pages != 25 || source.largestPage != 100 {
+		t.Errorf("source built %d pages of up to %d items, want 25 of 100", source.pages, source.largestPage)
+	}
+}
+
+func TestStreamedPagesAreDedupedAndCachedAsTheyArrive(t *testing.T) {
+	db := storagetest.Open(t)
+	cache := storage.NewCacheRepository(db)
+	ctx := context.Background()
+	taskID := storagetest.CreateTask(t, db, storagetest.CreateUser(t, db, model.UserRoleUser).ID).ID
+
+	source := &pagedSource{total: 1000, pageSize: 100, prefix: fmt.Sprint(time.Now().UnixNano())}
+	e := newPagedExecutor(source, cache)
+	config := map[string]interface{}{"source": "paged", "limit": float64(1000), "task_id": taskID}
+
+	// Each page is cached before the next one is fetched, rather than all
+	// items in one batch at the end
+	source.beforePage = func(page int) {
+		if page == 0 {
+			return
+		}
+		for _, i := range []int{page*100 - 1, page * 100} {
+			seen, err := cache.ExistsForTask(ctx, cache.HashContent(source.url(i)), taskID, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := i < page*100; seen != want {
+				t.Errorf("before page %d: item %d cached = %v, want %v", page+1, i, seen, want)
+			}
+		}
+	}
+
+	result, err := e.Execute(ctx, nil, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ItemCount != 1000 {
+		t.Fatalf("first run: %d new items, want 1000", result.ItemCount)
+	}
+
+	// Only new items are accumulated: a rerun over the same pages holds none,
+	// and a grown source yields just its new tail
+	source.beforePage = nil
+	source.total = 1050
+	config["limit"] = float64(1050)
+	result, err = e.Execute(ctx, nil, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := result.Data.([]model.ScrapedItem)
+	if len(items) != 50 || items[0].Title != "Job 1000" {
+		t.Errorf("second run: %d new items, want the 50 added since", len(items))
+	}
+	if source.largestPage != 100 {
+		t.Errorf("source built a page of %d items", source.largestPage)
+	}
+}
@@ -0,0 +1,169 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// GitHubScraper fetches releases of a repository when the query is
+// "owner/repo", and otherwise the week's most starred new repositories,
+// filtered by language when the query names one
+type GitHubScraper struct {
+	client *HTTPClient
+	token  string
+}
+
+func NewGitHubScraper(client *HTTPClient, token string) *GitHubScraper {
+	return &GitHubScraper{client: client, token: token}
+}
+
+func (s *GitHubScraper) Name() string {
+	return "github"
+}
+
+func (s *GitHubScraper) Category() string {
+	return "news"
+}
+
+type gitHubRelease struct {
+	ID          int64  `json:"id"`
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	PublishedAt string `json:"published_at"`
+	Prerelease  bool   `json:"prerelease"`
+	Author      struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+type gitHubRepo struct {
+	ID              int64    `json:"id"`
+	FullName        string   `json:"full_name"`
+	Description     string   `json:"description"`
+	HTMLURL         string   `json:"html_url"`
+	Language        string   `json:"language"`
+	StargazersCount int      `json:"stargazers_count"`
+	CreatedAt       string   `json:"created_at"`
+	Topics          []string `json:"topics"`
+	Owner           struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+// gitHubMaxPerPage is the most items the GitHub API returns per page
+const gitHubMaxPerPage = 100
+
+// gitHubBodyLimit is how much of a release's notes become its description
+const gitHubBodyLimit = 500
+
+func (s *GitHubScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	if limit <= 0 || limit > gitHubMaxPerPage {
+		limit = gitHubMaxPerPage
+	}
+	query = strings.TrimSpace(query)
+	if strings.Count(query, "/") == 1 && !strings.ContainsAny(query, " ") {
+		return s.releases(ctx, query, limit)
+	}
+	return s.trending(ctx, query, limit)
+}
+
+// headers authenticates with GITHUB_TOKEN when set, which raises the API's
+// rate limit from 60 to 5,000 requests an hour
+func (s *GitHubScraper) headers() map[string]string {
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if s.token != "" {
+		headers["Authorization"] = "Bearer " + s.token
+	}
+	return headers
+}
+
+func (s *GitHubScraper) releases(ctx context.Context, repo string, limit int) ([]model.ScrapedItem, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/releases?per_page=%d", repo, limit)
+	data, err := s.client.GetJSONWithHeaders(ctx, apiURL, s.headers())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GitHub releases of %s: %w", repo, err)
+	}
+
+	var releases []gitHubRelease
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub releases: %w", err)
+	}
+
+	items := make([]model.ScrapedItem, 0, len(releases))
+	for _, release := range releases {
+		title := release.TagName
+		if release.Name != "" && release.Name != release.TagName {
+			title += " - " + release.Name
+		}
+		tags := []string{repo}
+		if release.Prerelease {
+			tags = append(tags, "prerelease")
+		}
+		items = append(items, model.ScrapedItem{
+			ID:          fmt.Sprintf("github-release-%d", release.ID),
+			Title:       title,
+			Description: truncateText(strings.TrimSpace(release.Body), gitHubBodyLimit),
+			URL:         release.HTMLURL,
+			Source:      "GitHub",
+			Category:    "news",
+			Tags:        tags,
+			PostedAt:    release.PublishedAt,
+			Extra: map[string]interface{}{
+				"author": release.Author.Login,
+			},
+		})
+	}
+	return items, nil
+}
+
+// trending approximates GitHub's trending page, which has no API, with the
+// most starred repositories created in the past week
+func (s *GitHubScraper) trending(ctx context.Context, language string, limit int) ([]model.ScrapedItem, error) {
+	q := "created:>" + time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+	if language != "" {
+		q += " language:" + strings.ReplaceAll(language, " ", "-")
+	}
+	apiURL := fmt.Sprintf("https://api.github.com/search/repositories?q=%s&sort=stars&order=desc&per_page=%d", url.QueryEscape(q), limit)
+	data, err := s.client.GetJSONWithHeaders(ctx, apiURL, s.headers())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GitHub trending repositories: %w", err)
+	}
+
+	var result struct {
+		Items []gitHubRepo `json:"items"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub search response: %w", err)
+	}
+
+	items := make([]model.ScrapedItem, 0, len(result.Items))
+	for _, repo := range result.Items {
+		tags := repo.Topics
+		if repo.Language != "" {
+			tags = append([]string{repo.Language}, tags...)
+		}
+		items = append(items, model.ScrapedItem{
+			ID:          fmt.Sprintf("github-repo-%d", repo.ID),
+			Title:       repo.FullName,
+			Description: repo.Description,
+			URL:         repo.HTMLURL,
+			Source:      "GitHub",
+			Category:    "news",
+			Tags:        tags,
+			PostedAt:    repo.CreatedAt,
+			Extra: map[string]interface{}{
+				"author": repo.Owner.Login,
+				"stars":  repo.StargazersCount,
+			},
+		})
+	}
+	return items, nil
+}
@@ -109,15 +109,23 @@ func (s *HackerNewsScraper) Category() string {
 	return "news"
 }
 
+// hnChunkSize is how many stories are fetched before they're handed on
+const hnChunkSize = 10
+
 func (s *HackerNewsScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	return collect(ctx, s, query, limit)
+}
+
+// ScrapeStream fetches top stories one by one, handing them on in chunks
+func (s *HackerNewsScraper) ScrapeStream(ctx context.Context, query string, limit int, emit func(page []model.ScrapedItem) bool) error {
 	data, err := s.client.GetJSON(ctx, "https://hacker-news.firebaseio.com/v0/topstories.json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch HN top stories: %w", err)
+		return fmt.Errorf("failed to fetch HN top stories: %w", err)
 	}
 
 	var storyIDs []int
 	if err := json.Unmarshal(data, &storyIDs); err != nil {
-		return nil, fmt.Errorf("failed to parse story IDs: %w", err)
+		return fmt.Errorf("failed to parse story IDs: %w", err)
 	}
 
 	if limit <= 0 {
@@ -127,9 +135,10 @@ func (s *HackerNewsScraper) Scrape(ctx context.Context, query string, limit int)
 		storyIDs = storyIDs[:limit*2]
 	}
 
-	var items []model.ScrapedItem
+	delivered := 0
+	var chunk []model.ScrapedItem
 	for _, id := range storyIDs {
-		if len(items) >= limit {
+		if delivered+len(chunk) >= limit {
 			break
 		}
 
@@ -152,7 +161,7 @@ func (s *HackerNewsScraper) Scrape(ctx context.Context, query string, limit int)
 			url = fmt.Sprintf("https://news.ycombinator.com/item?id=%d", item.ID)
 		}
 
-		items = append(items, model.ScrapedItem{
+		chunk = append(chunk, model.ScrapedItem{
 			ID:          fmt.Sprintf("%d", item.ID),
 			Title:       item.Title,
 			Description: fmt.Sprintf("%d points, %d comments", item.Descendants, len(item.Kids)),
@@ -164,9 +173,20 @@ func (s *HackerNewsScraper) Scrape(ctx context.Context, query string, limit int)
 				"comments": len(item.Kids),
 			},
 		})
+
+		if len(chunk) == hnChunkSize {
+			delivered += len(chunk)
+			if !emit(chunk) {
+				return nil
+			}
+			chunk = nil
+		}
 	}
 
-	return items, nil
+	if len(chunk) > 0 {
+		emit(chunk)
+	}
+	return nil
 }
 
 func extractCompanyFromTitle(title string) string {
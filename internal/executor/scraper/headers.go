@@ -0,0 +1,80 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// headersKey carries a source's header overrides to the client
+type headersKey struct{}
+
+// withHeaders attaches header overrides to ctx. The client sets them on every
+// request made with it, over its own defaults and User-Agent.
+func withHeaders(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, headersKey{}, headers)
+}
+
+// headersFromContext returns the overrides attached by withHeaders
+func headersFromContext(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(headersKey{}).(map[string]string)
+	return headers
+}
+
+// sourceSettings is a source's entry in the sources file
+type sourceSettings struct {
+	Headers map[string]string `json:"headers"`
+}
+
+// loadSourceHeaders reads the header overrides of each source from a JSON
+// file such as {"remoteok": {"headers": {"User-Agent": "..."}}}
+func loadSourceHeaders(path string) (map[string]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var settings map[string]sourceSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("invalid sources file: %w", err)
+	}
+
+	headers := make(map[string]map[string]string, len(settings))
+	for name, s := range settings {
+		if len(s.Headers) == 0 {
+			continue
+		}
+		headers[name] = make(map[string]string, len(s.Headers))
+		for k, v := range s.Headers {
+			headers[name][http.CanonicalHeaderKey(k)] = v
+		}
+	}
+	return headers, nil
+}
+
+// parseStepHeaders reads a scraper step's 'headers' object, which overrides
+// the sources file for every source the step scrapes
+func parseStepHeaders(config map[string]interface{}) (map[string]string, error) {
+	raw, ok := config["headers"]
+	if !ok {
+		return nil, nil
+	}
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("scraper 'headers' must be an object of header names to values")
+	}
+
+	headers := make(map[string]string, len(fields))
+	for name, v := range fields {
+		value, ok := v.(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("scraper 'headers' must be an object of header names to values")
+		}
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	return headers, nil
+}
@@ -49,12 +49,34 @@ type glintsResponse struct {
 	} `json:"data"`
 }
 
+// glintsPageSize is how many jobs are requested per page of the Glints API
+const glintsPageSize = 20
+
 func (s *GlintsIndonesiaScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	return collect(ctx, s, query, limit)
+}
+
+// ScrapeStream pages through the Glints jobs API by offset, falling back to
+// a search link when the first page fails
+func (s *GlintsIndonesiaScraper) ScrapeStream(ctx context.Context, query string, limit int, emit func(page []model.ScrapedItem) bool) error {
+	delivered, err := paginate(limit, glintsPageSize, func(offset, size int) ([]model.ScrapedItem, error) {
+		return s.scrapePage(ctx, query, offset, size)
+	}, emit)
+	if err != nil && delivered == 0 {
+		items, _ := s.scrapeSearchPage(ctx, query, limit)
+		emit(items)
+		return nil
+	}
+	return err
+}
+
+func (s *GlintsIndonesiaScraper) scrapePage(ctx context.Context, query string, offset, size int) ([]model.ScrapedItem, error) {
 	// Glints API endpoint for Indonesia jobs
 	baseURL := "https://glints.com/api/v2/jobs"
 	params := url.Values{}
 	params.Set("country", "ID")
-	params.Set("limit", fmt.Sprintf("%d", limit))
+	params.Set("limit", fmt.Sprintf("%d", size))
+	params.Set("offset", fmt.Sprintf("%d", offset))
 	if query != "" {
 		params.Set("keyword", query)
 	}
@@ -62,13 +84,12 @@ func (s *GlintsIndonesiaScraper) Scrape(ctx context.Context, query string, limit
 	apiURL := baseURL + "?" + params.Encode()
 	data, err := s.client.GetJSON(ctx, apiURL)
 	if err != nil {
-		// Fallback to search page if API fails
-		return s.scrapeSearchPage(ctx, query, limit)
+		return nil, fmt.Errorf("glints API error: %w", err)
 	}
 
 	var response glintsResponse
 	if err := json.Unmarshal(data, &response); err != nil {
-		return s.scrapeSearchPage(ctx, query, limit)
+		return nil, fmt.Errorf("failed to parse Glints response: %w", err)
 	}
 
 	var items []model.ScrapedItem
@@ -129,10 +150,11 @@ func (s *GlintsIndonesiaScraper) scrapeSearchPage(ctx context.Context, query str
 // JobstreetIndonesiaScraper scrapes jobs from Jobstreet.co.id
 type JobstreetIndonesiaScraper struct {
 	client *HTTPClient
+	real   Source // Scrapes actual listings; the search link is a fallback
 }
 
 func NewJobstreetIndonesiaScraper(client *HTTPClient) *JobstreetIndonesiaScraper {
-	return &JobstreetIndonesiaScraper{client: client}
+	return &JobstreetIndonesiaScraper{client: client, real: NewJobstreetRealScraper(client)}
 }
 
 func (s *JobstreetIndonesiaScraper) Name() string {
@@ -144,6 +166,10 @@ func (s *JobstreetIndonesiaScraper) Category() string {
 }
 
 func (s *JobstreetIndonesiaScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	if items, err := s.real.Scrape(ctx, query, limit); err == nil && len(items) > 0 {
+		return items, nil
+	}
+
 	searchQuery := query
 	if searchQuery == "" {
 		searchQuery = "developer"
@@ -172,10 +198,11 @@ func (s *JobstreetIndonesiaScraper) Scrape(ctx context.Context, query string, li
 // KalibrrIndonesiaScraper scrapes tech jobs from Kalibrr
 type KalibrrIndonesiaScraper struct {
 	client *HTTPClient
+	real   Source // Scrapes actual listings; the search link is a fallback
 }
 
 func NewKalibrrIndonesiaScraper(client *HTTPClient) *KalibrrIndonesiaScraper {
-	return &KalibrrIndonesiaScraper{client: client}
+	return &KalibrrIndonesiaScraper{client: client, real: NewKalibrrRealScraper(client)}
 }
 
 func (s *KalibrrIndonesiaScraper) Name() string {
@@ -187,6 +214,10 @@ func (s *KalibrrIndonesiaScraper) Category() string {
 }
 
 func (s *KalibrrIndonesiaScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	if items, err := s.real.Scrape(ctx, query, limit); err == nil && len(items) > 0 {
+		return items, nil
+	}
+
 	searchQuery := query
 	if searchQuery == "" {
 		searchQuery = "software"
@@ -257,10 +288,11 @@ func (s *LinkedInIndonesiaScraper) Scrape(ctx context.Context, query string, lim
 // IndeedIndonesiaScraper provides Indeed job search for Indonesia
 type IndeedIndonesiaScraper struct {
 	client *HTTPClient
+	real   Source // Scrapes actual listings; the search link is a fallback
 }
 
 func NewIndeedIndonesiaScraper(client *HTTPClient) *IndeedIndonesiaScraper {
-	return &IndeedIndonesiaScraper{client: client}
+	return &IndeedIndonesiaScraper{client: client, real: NewIndeedRealScraper(client)}
 }
 
 func (s *IndeedIndonesiaScraper) Name() string {
@@ -272,6 +304,10 @@ func (s *IndeedIndonesiaScraper) Category() string {
 }
 
 func (s *IndeedIndonesiaScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	if items, err := s.real.Scrape(ctx, query, limit); err == nil && len(items) > 0 {
+		return items, nil
+	}
+
 	searchQuery := query
 	if searchQuery == "" {
 		searchQuery = "developer"
@@ -62,12 +62,40 @@ type glintsGraphQLResponse struct {
 	} `json:"data"`
 }
 
+// glintsGraphQLPageSize is how many jobs are requested per page of the
+// Glints GraphQL API
+const glintsGraphQLPageSize = 20
+
 func (s *GlintsRealJobScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	return collect(ctx, s, query, limit)
+}
+
+// ScrapeStream pages through the Glints GraphQL API by offset, falling back
+// to the simple search API when the first page fails
+func (s *GlintsRealJobScraper) ScrapeStream(ctx context.Context, query string, limit int, emit func(page []model.ScrapedItem) bool) error {
 	searchQuery := query
 	if searchQuery == "" {
 		searchQuery = "admin"
 	}
 
+	delivered, err := paginate(limit, glintsGraphQLPageSize, func(offset, size int) ([]model.ScrapedItem, error) {
+		return s.scrapePage(ctx, searchQuery, offset, size)
+	}, emit)
+	if err != nil && delivered == 0 {
+		// Fallback to simple API
+		items, err := s.scrapeSimpleAPI(ctx, searchQuery, limit)
+		if err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			emit(items)
+		}
+		return nil
+	}
+	return err
+}
+
+func (s *GlintsRealJobScraper) scrapePage(ctx context.Context, searchQuery string, offset, size int) ([]model.ScrapedItem, error) {
 	// Glints GraphQL endpoint
 	graphqlURL := "https://glints.com/api/graphql"
 
@@ -80,23 +108,22 @@ func (s *GlintsRealJobScraper) Scrape(ctx context.Context, query string, limit i
 				"CountryCode": "ID",
 				"CityName": ["Jakarta", "Bekasi"],
 				"limit": %d,
-				"offset": 0
+				"offset": %d
 			}
 		},
 		"query": "query searchJobs($data: JobSearchConditionInput!) { jobs(data: $data) { data { id title createdAt cityName isRemote salaryEstimate { minAmount maxAmount currency } company { name logo } skills { name } minYearsOfExperience maxYearsOfExperience educationLevel jobDescription } } }"
 	}`
 
-	queryBody := fmt.Sprintf(graphqlQuery, searchQuery, limit)
+	queryBody := fmt.Sprintf(graphqlQuery, searchQuery, size, offset)
 
 	data, err := s.client.PostJSON(ctx, graphqlURL, bytes.NewBufferString(queryBody))
 	if err != nil {
-		// Fallback to simple API
-		return s.scrapeSimpleAPI(ctx, searchQuery, limit)
+		return nil, fmt.Errorf("glints API error: %w", err)
 	}
 
 	var response glintsGraphQLResponse
 	if err := json.Unmarshal(data, &response); err != nil {
-		return s.scrapeSimpleAPI(ctx, searchQuery, limit)
+		return nil, fmt.Errorf("failed to parse Glints response: %w", err)
 	}
 
 	var items []model.ScrapedItem
@@ -239,12 +266,41 @@ type jobstreetAPIResponse struct {
 	} `json:"data"`
 }
 
+// jobstreetPageSize is how many jobs are requested per page of the
+// Jobstreet API
+const jobstreetPageSize = 20
+
 func (s *JobstreetRealScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	return collect(ctx, s, query, limit)
+}
+
+// ScrapeStream pages through the Jobstreet GraphQL API, falling back to the
+// search page's HTML when the first page fails
+func (s *JobstreetRealScraper) ScrapeStream(ctx context.Context, query string, limit int, emit func(page []model.ScrapedItem) bool) error {
 	searchQuery := query
 	if searchQuery == "" {
 		searchQuery = "admin"
 	}
 
+	delivered, err := paginate(limit, jobstreetPageSize, func(offset, size int) ([]model.ScrapedItem, error) {
+		return s.scrapePage(ctx, searchQuery, offset/size+1, size)
+	}, emit)
+	if err != nil && delivered == 0 {
+		// Fallback to HTML scraping
+		items, err := s.scrapeHTML(ctx, searchQuery, limit)
+		if err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			emit(items)
+		}
+		return nil
+	}
+	return err
+}
+
+// scrapePage fetches a page of the API, numbered from 1
+func (s *JobstreetRealScraper) scrapePage(ctx context.Context, searchQuery string, page, size int) ([]model.ScrapedItem, error) {
 	// Jobstreet GraphQL API
 	graphqlURL := "https://xapi.supercharge-srp.co/job-search/graphql"
 
@@ -256,22 +312,21 @@ func (s *JobstreetRealScraper) Scrape(ctx context.Context, query string, limit i
 			"keyword": "%s",
 			"locationId": ["jakarta", "bekasi"],
 			"pageSize": %d,
-			"page": 1
+			"page": %d
 		},
 		"query": "query GetJobList($country: String!, $locale: String!, $keyword: String, $locationId: [String], $pageSize: Int, $page: Int) { jobs(country: $country, locale: $locale, keyword: $keyword, locationId: $locationId, pageSize: $pageSize, page: $page) { jobs { id title jobUrl company { name } location { label } salary { label } listingDate workTypes teaser } } }"
 	}`
 
-	queryBody := fmt.Sprintf(graphqlQuery, searchQuery, limit)
+	queryBody := fmt.Sprintf(graphqlQuery, searchQuery, size, page)
 
 	data, err := s.client.PostJSON(ctx, graphqlURL, bytes.NewBufferString(queryBody))
 	if err != nil {
-		// Fallback to HTML scraping
-		return s.scrapeHTML(ctx, searchQuery, limit)
+		return nil, fmt.Errorf("jobstreet API error: %w", err)
 	}
 
 	var response jobstreetAPIResponse
 	if err := json.Unmarshal(data, &response); err != nil {
-		return s.scrapeHTML(ctx, searchQuery, limit)
+		return nil, fmt.Errorf("failed to parse Jobstreet response: %w", err)
 	}
 
 	var items []model.ScrapedItem
@@ -384,15 +439,30 @@ type kalibrrResponse struct {
 	} `json:"jobs"`
 }
 
+// kalibrrPageSize is how many jobs are requested per page of the Kalibrr API
+const kalibrrPageSize = 20
+
 func (s *KalibrrRealScraper) Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error) {
+	return collect(ctx, s, query, limit)
+}
+
+// ScrapeStream pages through the Kalibrr job board API by offset
+func (s *KalibrrRealScraper) ScrapeStream(ctx context.Context, query string, limit int, emit func(page []model.ScrapedItem) bool) error {
 	searchQuery := query
 	if searchQuery == "" {
 		searchQuery = "admin"
 	}
 
+	_, err := paginate(limit, kalibrrPageSize, func(offset, size int) ([]model.ScrapedItem, error) {
+		return s.scrapePage(ctx, searchQuery, offset, size)
+	}, emit)
+	return err
+}
+
+func (s *KalibrrRealScraper) scrapePage(ctx context.Context, searchQuery string, offset, size int) ([]model.ScrapedItem, error) {
 	// Kalibrr API endpoint
-	apiURL := fmt.Sprintf("https://www.kalibrr.com/kjs/job_board/search?keywords=%s&country=Indonesia&city=Jakarta,Bekasi&sort=Freshness&limit=%d",
-		url.QueryEscape(searchQuery), limit)
+	apiURL := fmt.Sprintf("https://www.kalibrr.com/kjs/job_board/search?keywords=%s&country=Indonesia&city=Jakarta,Bekasi&sort=Freshness&limit=%d&offset=%d",
+		url.QueryEscape(searchQuery), size, offset)
 
 	data, err := s.client.GetJSON(ctx, apiURL)
 	if err != nil {
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// hostLimiter hands out request slots per host, at most one per interval, so
+// concurrent scrapes of different sites don't throttle each other
+type hostLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     map[string]time.Time
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{interval: interval, next: make(map[string]time.Time)}
+}
+
+// Wait blocks until host may be requested, reserving the following slot for
+// the next caller. Reservations are handed out in call order, so concurrent
+// requests to one host are spaced out rather than racing.
+func (l *hostLimiter) Wait(ctx context.Context, host string) error {
+	if l.interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[host] = at.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
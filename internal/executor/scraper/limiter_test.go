@@ -0,0 +1,105 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+)
+
+// arrivals is a test server recording when each request reached it
+type arrivals struct {
+	mu    sync.Mutex
+	times []time.Time
+}
+
+func newArrivalServer(t *testing.T) (*httptest.Server, *arrivals) {
+	t.Helper()
+	a := &arrivals{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		a.times = append(a.times, time.Now())
+		a.mu.Unlock()
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server, a
+}
+
+// spacedFrom checks that the nth arrival came at least n intervals after
+// start. Slots open one interval apart and timers never fire early, so this
+// holds however late any one request happened to wake.
+func (a *arrivals) spacedFrom(t *testing.T, name string, start time.Time, interval time.Duration) {
+	t.Helper()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sort.Slice(a.times, func(i, j int) bool { return a.times[i].Before(a.times[j]) })
+	for i, at := range a.times {
+		if offset, want := at.Sub(start), time.Duration(i)*interval; offset < want {
+			t.Errorf("host %s request %d arrived %v after the start, want at least %v", name, i+1, offset, want)
+		}
+	}
+}
+
+func TestHTTPClientSpacesConcurrentRequestsPerHost(t *testing.T) {
+	const (
+		interval = 50 * time.Millisecond
+		perHost  = 5
+	)
+	serverA, arrivalsA := newArrivalServer(t)
+	serverB, arrivalsB := newArrivalServer(t)
+	client := NewHTTPClient(config.ScraperConfig{
+		RateLimitMs:    int(interval / time.Millisecond),
+		RequestTimeout: 5 * time.Second,
+	})
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < perHost; i++ {
+		for _, url := range []string{serverA.URL, serverB.URL} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.Get(context.Background(), url); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	for name, a := range map[string]*arrivals{"A": arrivalsA, "B": arrivalsB} {
+		if len(a.times) != perHost {
+			t.Fatalf("host %s got %d requests, want %d", name, len(a.times), perHost)
+		}
+		a.spacedFrom(t, name, start, interval)
+	}
+
+	// The hosts are limited independently, so both finish in about the time
+	// one needs; serialized they would take twice as long
+	if serialized := (2*perHost - 1) * interval; elapsed >= serialized {
+		t.Errorf("took %v, hosts appear to throttle each other (serialized %v)", elapsed, serialized)
+	}
+}
+
+func TestHostLimiterWaitHonoursCancellation(t *testing.T) {
+	limiter := newHostLimiter(time.Hour)
+	if err := limiter.Wait(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "example.com"); err != context.DeadlineExceeded {
+		t.Errorf("err = %v, want the context's deadline", err)
+	}
+	if err := limiter.Wait(context.Background(), "other.example.com"); err != nil {
+		t.Errorf("other host waited on the first: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"github.com/multi-worker/internal/model"
+)
+
+// maxPages bounds how many pages one scrape requests from a paginated API
+const maxPages = 20
+
+// pageFunc fetches the page of up to size items starting at offset
+type pageFunc func(offset, size int) ([]model.ScrapedItem, error)
+
+// paginate requests pages of pageSize items through fetch and hands them to
+// emit until limit items were delivered (one page when limit isn't set), a
+// page comes back short or brings nothing new, or emit returns false. Every
+// request goes through the client, so the per-host rate limit spaces the
+// pages out. It returns how many items were delivered and the error of the
+// page that failed, if any; callers fall back when the first page does.
+func paginate(limit, pageSize int, fetch pageFunc, emit func(page []model.ScrapedItem) bool) (int, error) {
+	if limit <= 0 {
+		limit = pageSize
+	}
+
+	delivered := 0
+	seen := make(map[string]bool)
+	for page := 0; page < maxPages && delivered < limit; page++ {
+		items, err := fetch(page*pageSize, pageSize)
+		if err != nil {
+			return delivered, err
+		}
+		full := len(items) >= pageSize
+
+		// An API that ignores the offset serves the same page again
+		fresh := make([]model.ScrapedItem, 0, len(items))
+		for _, item := range items {
+			if item.ID != "" {
+				if seen[item.ID] {
+					continue
+				}
+				seen[item.ID] = true
+			}
+			fresh = append(fresh, item)
+		}
+		if len(fresh) == 0 {
+			return delivered, nil
+		}
+
+		if len(fresh) > limit-delivered {
+			fresh = fresh[:limit-delivered]
+		}
+		delivered += len(fresh)
+		if !emit(fresh) || !full {
+			return delivered, nil
+		}
+	}
+	return delivered, nil
+}
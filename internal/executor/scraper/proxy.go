@@ -0,0 +1,110 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// proxyCooldown is how long a proxy that failed to connect is skipped
+const proxyCooldown = 5 * time.Minute
+
+// proxyKey carries the proxy chosen for a request to the transport
+type proxyKey struct{}
+
+// proxyPool rotates requests across proxies round-robin, skipping any that
+// recently failed to connect
+type proxyPool struct {
+	mu        sync.Mutex
+	proxies   []*url.URL
+	downUntil []time.Time
+	next      int
+}
+
+// newProxyPool parses the proxy URLs, logging and dropping invalid ones. It
+// returns nil when no proxy is usable.
+func newProxyPool(rawURLs []string) *proxyPool {
+	pool := &proxyPool{}
+	seen := make(map[string]bool)
+	for _, raw := range rawURLs {
+		if raw == "" || seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			log.Printf("Ignoring invalid scraper proxy %q", raw)
+			continue
+		}
+		pool.proxies = append(pool.proxies, u)
+	}
+	if len(pool.proxies) == 0 {
+		return nil
+	}
+	pool.downUntil = make([]time.Time, len(pool.proxies))
+	return pool
+}
+
+// Pick returns the next healthy proxy. When every proxy is cooling down it
+// carries on rotating through them rather than scraping without one.
+func (p *proxyPool) Pick() *url.URL {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for range p.proxies {
+		i := p.next
+		p.next = (p.next + 1) % len(p.proxies)
+		if p.downUntil[i].Before(now) {
+			return p.proxies[i]
+		}
+	}
+	proxy := p.proxies[p.next]
+	p.next = (p.next + 1) % len(p.proxies)
+	return proxy
+}
+
+// MarkDown skips proxy for proxyCooldown
+func (p *proxyPool) MarkDown(proxy *url.URL) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, u := range p.proxies {
+		if u == proxy {
+			p.downUntil[i] = time.Now().Add(proxyCooldown)
+			log.Printf("Scraper proxy %s failed to connect, skipping it for %s", u.Redacted(), proxyCooldown)
+			return
+		}
+	}
+}
+
+// proxyFromContext is the transport's Proxy func, using the proxy picked for
+// the request
+func proxyFromContext(req *http.Request) (*url.URL, error) {
+	proxy, _ := req.Context().Value(proxyKey{}).(*url.URL)
+	return proxy, nil
+}
+
+// withProxy attaches the pool's next proxy to ctx; a nil pool leaves ctx as is
+func (p *proxyPool) withProxy(ctx context.Context) (context.Context, *url.URL) {
+	if p == nil {
+		return ctx, nil
+	}
+	proxy := p.Pick()
+	return context.WithValue(ctx, proxyKey{}, proxy), proxy
+}
+
+// isProxyError reports whether a request failed connecting to its proxy.
+// With a proxy set every dial goes to the proxy, so dial errors count too.
+func isProxyError(err error) bool {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	return opErr.Op == "proxyconnect" || opErr.Op == "dial"
+}
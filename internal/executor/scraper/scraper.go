@@ -3,6 +3,7 @@ package scraper
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/multi-worker/internal/config"
 	"github.com/multi-worker/internal/model"
@@ -15,10 +16,33 @@ type Source interface {
 	Scrape(ctx context.Context, query string, limit int) ([]model.ScrapedItem, error)
 }
 
+// StreamingSource is a Source that can deliver its items a page at a time,
+// so large limits don't have to be held in memory at once
+type StreamingSource interface {
+	Source
+	// ScrapeStream calls emit with each page of up to limit items in total,
+	// stopping early when emit returns false
+	ScrapeStream(ctx context.Context, query string, limit int, emit func(page []model.ScrapedItem) bool) error
+}
+
+// collect gathers a streaming source's pages into one slice, for Scrape
+func collect(ctx context.Context, source StreamingSource, query string, limit int) ([]model.ScrapedItem, error) {
+	var items []model.ScrapedItem
+	err := source.ScrapeStream(ctx, query, limit, func(page []model.ScrapedItem) bool {
+		items = append(items, page...)
+		return true
+	})
+	if err != nil && len(items) == 0 {
+		return nil, err
+	}
+	return items, nil
+}
+
 // Registry manages all scraper sources
 type Registry struct {
 	sources map[string]Source
 	client  *HTTPClient
+	headers map[string]map[string]string // Header overrides by source name
 }
 
 // NewRegistry creates a new scraper registry
@@ -30,46 +54,69 @@ func NewRegistry(cfg config.ScraperConfig) *Registry {
 	}
 
 	// Register job board scrapers
-	registry.sources["remoteok"] = NewRemoteOKScraper(client)
-	registry.sources["hackernews_jobs"] = NewHackerNewsJobsScraper(client)
-	registry.sources["github_jobs"] = NewGitHubJobsScraper(client)
-	registry.sources["stackoverflow_jobs"] = NewStackOverflowJobsScraper(client)
-	registry.sources["weworkremotely"] = NewWeWorkRemotelyScraper(client)
+	registry.register(NewRemoteOKScraper(client))
+	registry.register(NewHackerNewsJobsScraper(client))
+	registry.register(NewGitHubJobsScraper(client))
+	registry.register(NewStackOverflowJobsScraper(client))
+	registry.register(NewWeWorkRemotelyScraper(client))
 
 	// Register freelance scrapers
-	registry.sources["freelancer"] = NewFreelancerScraper(client)
-	registry.sources["upwork"] = NewUpworkScraper(client)
+	registry.register(NewFreelancerScraper(client))
+	registry.register(NewUpworkScraper(client))
 
 	// Register tech news scrapers
-	registry.sources["hackernews"] = NewHackerNewsScraper(client)
-	registry.sources["devto"] = NewDevToScraper(client)
-	registry.sources["producthunt"] = NewProductHuntScraper(client)
+	registry.register(NewHackerNewsScraper(client))
+	registry.register(NewDevToScraper(client))
+	registry.register(NewProductHuntScraper(client))
+	registry.register(NewGitHubScraper(client, cfg.GitHubToken))
 
 	// Register Indonesian job scrapers
-	registry.sources["glints_indonesia"] = NewGlintsIndonesiaScraper(client)
-	registry.sources["jobstreet_indonesia"] = NewJobstreetIndonesiaScraper(client)
-	registry.sources["kalibrr_indonesia"] = NewKalibrrIndonesiaScraper(client)
-	registry.sources["linkedin_indonesia"] = NewLinkedInIndonesiaScraper(client)
-	registry.sources["indeed_indonesia"] = NewIndeedIndonesiaScraper(client)
-	registry.sources["techinasia_jobs"] = NewTechInAsiaJobsScraper(client)
-	registry.sources["remoteok_indonesia"] = NewRemoteOKIndonesiaScraper(client)
+	registry.register(NewGlintsIndonesiaScraper(client))
+	registry.register(NewJobstreetIndonesiaScraper(client))
+	registry.register(NewKalibrrIndonesiaScraper(client))
+	registry.register(NewLinkedInIndonesiaScraper(client))
+	registry.register(NewIndeedIndonesiaScraper(client))
+	registry.register(NewTechInAsiaJobsScraper(client))
+	registry.register(NewRemoteOKIndonesiaScraper(client))
 
 	// Register Jakarta/Bekasi specific scrapers (entry-level friendly)
 	// These scrape REAL job listings with actual details
-	registry.sources["jakarta_bekasi_jobs"] = NewJakartaBekasiRealScraper(client)
-	registry.sources["entry_level_jobs"] = NewEntryLevelRealScraper(client)
-	registry.sources["remote_jakarta"] = NewRemoteJakartaRealScraper(client)
-	registry.sources["loker_jakarta"] = NewLokerJakartaRealScraper(client)
+	registry.register(NewJakartaBekasiRealScraper(client))
+	registry.register(NewEntryLevelRealScraper(client))
+	registry.register(NewRemoteJakartaRealScraper(client))
+	registry.register(NewLokerJakartaRealScraper(client))
 
 	// Individual real job scrapers
-	registry.sources["glints_jobs"] = NewGlintsRealJobScraper(client)
-	registry.sources["jobstreet_jobs"] = NewJobstreetRealScraper(client)
-	registry.sources["kalibrr_jobs"] = NewKalibrrRealScraper(client)
-	registry.sources["indeed_jobs"] = NewIndeedRealScraper(client)
+	registry.register(NewGlintsRealJobScraper(client))
+	registry.register(NewJobstreetRealScraper(client))
+	registry.register(NewKalibrrRealScraper(client))
+	registry.register(NewIndeedRealScraper(client))
+
+	if cfg.SourcesFile != "" {
+		headers, err := loadSourceHeaders(cfg.SourcesFile)
+		if err != nil {
+			log.Printf("Ignoring scraper sources file %s: %v", cfg.SourcesFile, err)
+		}
+		for name := range headers {
+			if _, ok := registry.sources[name]; !ok {
+				log.Printf("Scraper sources file names unknown source %q", name)
+			}
+		}
+		registry.headers = headers
+	}
 
 	return registry
 }
 
+// register adds a source under its own name, so every source is reachable
+// through Get(source.Name())
+func (r *Registry) register(source Source) {
+	if _, exists := r.sources[source.Name()]; exists {
+		panic(fmt.Sprintf("scraper source '%s' registered twice", source.Name()))
+	}
+	r.sources[source.Name()] = source
+}
+
 // Get returns a source by name
 func (r *Registry) Get(name string) (Source, error) {
 	source, ok := r.sources[name]
@@ -79,10 +126,38 @@ func (r *Registry) Get(name string) (Source, error) {
 	return source, nil
 }
 
+// Headers returns the header overrides for a source: its entry in the
+// sources file, with a step's own headers on top
+func (r *Registry) Headers(name string, step map[string]string) map[string]string {
+	if len(step) == 0 {
+		return r.headers[name]
+	}
+	merged := make(map[string]string, len(r.headers[name])+len(step))
+	for k, v := range r.headers[name] {
+		merged[k] = v
+	}
+	for k, v := range step {
+		merged[k] = v
+	}
+	return merged
+}
+
+// supersededSources are older source names that now scrape through the real
+// scraper they map to. They stay selectable by name, but a category scrape
+// skips them so the same board isn't fetched twice.
+var supersededSources = map[string]string{
+	"jobstreet_indonesia": "jobstreet_jobs",
+	"kalibrr_indonesia":   "kalibrr_jobs",
+	"indeed_indonesia":    "indeed_jobs",
+}
+
 // GetByCategory returns all sources in a category
 func (r *Registry) GetByCategory(category string) []Source {
 	var sources []Source
-	for _, source := range r.sources {
+	for name, source := range r.sources {
+		if _, ok := supersededSources[name]; ok {
+			continue
+		}
 		if source.Category() == category {
 			sources = append(sources, source)
 		}
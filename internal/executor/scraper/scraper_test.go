@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/multi-worker/internal/config"
+)
+
+func TestRegistryHasEverySource(t *testing.T) {
+	registry := NewRegistry(config.ScraperConfig{})
+	client := registry.client
+
+	sources := []Source{
+		NewRemoteOKScraper(client),
+		NewHackerNewsJobsScraper(client),
+		NewGitHubJobsScraper(client),
+		NewStackOverflowJobsScraper(client),
+		NewWeWorkRemotelyScraper(client),
+		NewFreelancerScraper(client),
+		NewUpworkScraper(client),
+		NewHackerNewsScraper(client),
+		NewDevToScraper(client),
+		NewProductHuntScraper(client),
+		NewGitHubScraper(client, ""),
+		NewGlintsIndonesiaScraper(client),
+		NewJobstreetIndonesiaScraper(client),
+		NewKalibrrIndonesiaScraper(client),
+		NewLinkedInIndonesiaScraper(client),
+		NewIndeedIndonesiaScraper(client),
+		NewTechInAsiaJobsScraper(client),
+		NewRemoteOKIndonesiaScraper(client),
+		NewJakartaBekasiRealScraper(client),
+		NewEntryLevelRealScraper(client),
+		NewRemoteJakartaRealScraper(client),
+		NewLokerJakartaRealScraper(client),
+		NewGlintsRealJobScraper(client),
+		NewJobstreetRealScraper(client),
+		NewKalibrrRealScraper(client),
+		NewIndeedRealScraper(client),
+	}
+
+	for _, source := range sources {
+		if _, err := registry.Get(source.Name()); err != nil {
+			t.Errorf("%T: %v", source, err)
+		}
+	}
+	if got := len(registry.Available()); got != len(sources) {
+		t.Errorf("registry has %d sources, want %d", got, len(sources))
+	}
+
+	// A constructor added to the package but not to the list above would
+	// slip past the checks, so count them in the source files too
+	if constructors := sourceConstructors(t); constructors != len(sources) {
+		t.Errorf("package declares %d source constructors, test lists %d", constructors, len(sources))
+	}
+}
+
+// sourceConstructors counts the New...Scraper functions in the package
+func sourceConstructors(t *testing.T) int {
+	t.Helper()
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if ok && fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "New") && strings.HasSuffix(fn.Name.Name, "Scraper") {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
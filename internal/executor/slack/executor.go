@@ -0,0 +1,357 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+)
+
+const (
+	maxBlocksPerMessage = 50   // Slack limit per message
+	maxSectionText      = 3000 // Slack limit per section text object
+	maxFieldText        = 2000 // Slack limit per section field
+)
+
+// Executor handles Slack notifications in pipelines
+type Executor struct {
+	defaultWebhook string
+	rateLimit      time.Duration
+	lastSend       time.Time
+	mu             sync.Mutex
+	client         *http.Client
+	guard          *netguard.Guard
+}
+
+// NewExecutor creates a new Slack executor
+func NewExecutor(cfg config.SlackConfig, guard *netguard.Guard) *Executor {
+	return &Executor{
+		defaultWebhook: cfg.DefaultWebhook,
+		rateLimit:      time.Duration(cfg.RateLimitMs) * time.Millisecond,
+		client:         guard.Client(10 * time.Second),
+		guard:          guard,
+	}
+}
+
+func (e *Executor) Type() string {
+	return "slack"
+}
+
+func (e *Executor) Validate(config map[string]interface{}) error {
+	// Webhook can come from the step config or SLACK_DEFAULT_WEBHOOK,
+	// so only reject a value that is obviously not a URL
+	if webhookURL, ok := config["webhook_url"].(string); ok && webhookURL != "" {
+		if !strings.HasPrefix(webhookURL, "https://") {
+			return fmt.Errorf("slack 'webhook_url' must be an https URL")
+		}
+		if err := e.guard.CheckURL(webhookURL); err != nil {
+			return fmt.Errorf("slack 'webhook_url': %w", err)
+		}
+	}
+	return nil
+}
+
+// SetWebhook allows runtime injection of webhook URL
+func (e *Executor) SetWebhook(url string) {
+	if url != "" {
+		e.defaultWebhook = url
+	}
+}
+
+func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	// Validate input
+	if input == nil {
+		return nil, fmt.Errorf("slack executor requires input data")
+	}
+
+	// Get webhook URL
+	webhookURL, _ := config["webhook_url"].(string)
+	if webhookURL == "" {
+		webhookURL = e.defaultWebhook
+	}
+
+	if webhookURL == "" {
+		return nil, fmt.Errorf("no Slack webhook URL configured: set webhook_url in pipeline config or SLACK_DEFAULT_WEBHOOK environment variable")
+	}
+	if err := e.guard.CheckURL(webhookURL); err != nil {
+		return nil, err
+	}
+
+	tmplStr, _ := config["template"].(string)
+	username, _ := config["username"].(string)
+	iconURL, _ := config["icon_url"].(string)
+
+	// Format the messages, one per chunk of blocks
+	messages, err := e.formatMessages(input, tmplStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format message: %w", err)
+	}
+
+	for _, message := range messages {
+		message.Username = username
+		message.IconURL = iconURL
+
+		e.waitForRateLimit()
+
+		if err := e.send(ctx, webhookURL, message); err != nil {
+			return nil, fmt.Errorf("failed to send Slack message: %w", err)
+		}
+
+		e.mu.Lock()
+		e.lastSend = time.Now()
+		e.mu.Unlock()
+	}
+
+	return &model.ExecutorResult{
+		Data: map[string]interface{}{
+			"status":  "sent",
+			"webhook": maskWebhook(webhookURL),
+		},
+		ItemCount: input.ItemCount,
+		Metadata: map[string]interface{}{
+			"items_sent":    input.ItemCount,
+			"messages_sent": len(messages),
+		},
+	}, nil
+}
+
+// Preview returns the messages Execute would send, without sending them
+func (e *Executor) Preview(input *model.ExecutorResult, config map[string]interface{}) (interface{}, error) {
+	if input == nil {
+		return nil, fmt.Errorf("slack executor requires input data")
+	}
+
+	tmplStr, _ := config["template"].(string)
+	messages, err := e.formatMessages(input, tmplStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format message: %w", err)
+	}
+	for _, message := range messages {
+		message.Username, _ = config["username"].(string)
+		message.IconURL, _ = config["icon_url"].(string)
+	}
+	return messages, nil
+}
+
+func (e *Executor) waitForRateLimit() {
+	e.mu.Lock()
+	elapsed := time.Since(e.lastSend)
+	e.mu.Unlock()
+
+	if elapsed < e.rateLimit {
+		time.Sleep(e.rateLimit - elapsed)
+	}
+}
+
+func (e *Executor) formatMessages(input *model.ExecutorResult, tmplStr string) ([]*model.SlackMessage, error) {
+	var text string
+
+	switch {
+	case tmplStr != "":
+		content, err := executeTemplate(tmplStr, input.Data)
+		if err != nil {
+			return nil, err
+		}
+		text = content
+
+	default:
+		// If input is a string (from AI processor), use it directly
+		if str, ok := input.Data.(string); ok {
+			text = str
+			break
+		}
+		if alert, ok := input.Data.(model.Alert); ok {
+			text = alert.Text()
+			break
+		}
+
+		blocks, err := createBlocks(input.Data)
+		if err != nil {
+			// Fallback to JSON representation
+			jsonBytes, _ := json.MarshalIndent(input.Data, "", "  ")
+			text = "```" + string(jsonBytes) + "```"
+			break
+		}
+		return chunkBlocks(blocks, fmt.Sprintf("%d new items", input.ItemCount)), nil
+	}
+
+	return chunkBlocks(textBlocks(text), truncate(text, 150)), nil
+}
+
+// textBlocks splits plain text into section blocks within Slack's text limit
+func textBlocks(text string) []model.SlackBlock {
+	var blocks []model.SlackBlock
+	for len(text) > 0 {
+		end := min(len(text), maxSectionText)
+		blocks = append(blocks, sectionBlock(text[:end]))
+		text = text[end:]
+	}
+	return blocks
+}
+
+func createBlocks(data interface{}) ([]model.SlackBlock, error) {
+	var blocks []model.SlackBlock
+
+	switch v := data.(type) {
+	case []model.ScrapedItem:
+		for _, item := range v {
+			blocks = append(blocks, sectionBlock(itemText(item.Title, item.URL, item.Description)))
+
+			var fields []model.SlackText
+			for _, f := range item.RelevantFields() {
+				fields = append(fields, fieldText(f.Label, f.Value))
+			}
+			if len(fields) > 0 {
+				blocks = append(blocks, model.SlackBlock{Type: "section", Fields: fields})
+			}
+
+			if item.Source != "" {
+				blocks = append(blocks, contextBlock(item.Source))
+			}
+			blocks = append(blocks, model.SlackBlock{Type: "divider"})
+		}
+
+	case []model.RSSItem:
+		for _, item := range v {
+			blocks = append(blocks, sectionBlock(itemText(item.Title, item.Link, item.Description)))
+
+			footer := item.Source
+			if item.PubDate != "" {
+				if footer != "" {
+					footer += " · "
+				}
+				footer += item.PubDate
+			}
+			if footer != "" {
+				blocks = append(blocks, contextBlock(footer))
+			}
+			blocks = append(blocks, model.SlackBlock{Type: "divider"})
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported data type for blocks")
+	}
+
+	return blocks, nil
+}
+
+// chunkBlocks splits blocks into messages that stay within Slack's block limit
+func chunkBlocks(blocks []model.SlackBlock, fallbackText string) []*model.SlackMessage {
+	var messages []*model.SlackMessage
+	for start := 0; start < len(blocks); start += maxBlocksPerMessage {
+		end := min(start+maxBlocksPerMessage, len(blocks))
+		messages = append(messages, &model.SlackMessage{
+			Text:   fallbackText,
+			Blocks: blocks[start:end],
+		})
+	}
+	return messages
+}
+
+func itemText(title, link, description string) string {
+	text := "*" + escape(title) + "*"
+	if link != "" {
+		text = "*<" + link + "|" + escape(title) + ">*"
+	}
+	if description != "" {
+		text += "\n" + escape(description)
+	}
+	return truncate(text, maxSectionText)
+}
+
+func sectionBlock(text string) model.SlackBlock {
+	return model.SlackBlock{
+		Type: "section",
+		Text: &model.SlackText{Type: "mrkdwn", Text: text},
+	}
+}
+
+func contextBlock(text string) model.SlackBlock {
+	return model.SlackBlock{
+		Type:     "context",
+		Elements: []model.SlackText{{Type: "mrkdwn", Text: escape(text)}},
+	}
+}
+
+func fieldText(name, value string) model.SlackText {
+	return model.SlackText{
+		Type: "mrkdwn",
+		Text: truncate("*"+name+"*\n"+escape(value), maxFieldText),
+	}
+}
+
+func (e *Executor) send(ctx context.Context, webhookURL string, message *model.SlackMessage) error {
+	jsonBody, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Slack API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// SendSimple sends a simple text message
+func (e *Executor) SendSimple(ctx context.Context, webhookURL, content string) error {
+	message := &model.SlackMessage{Text: content}
+	return e.send(ctx, webhookURL, message)
+}
+
+func executeTemplate(tmplStr string, data interface{}) (string, error) {
+	tmpl, err := template.New("slack").Parse(tmplStr)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// escape encodes the characters Slack treats as control sequences in mrkdwn
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
+
+func maskWebhook(url string) string {
+	if len(url) < 40 {
+		return "***"
+	}
+	return url[:33] + "***"
+}
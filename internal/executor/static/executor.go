@@ -0,0 +1,100 @@
+package static
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// defaultSource is set on items that don't name their own source
+const defaultSource = "manual"
+
+// itemFields are the keys mapped onto model.ScrapedItem; any other key of an
+// item is kept in its Extra
+var itemFields = map[string]bool{
+	"id": true, "title": true, "description": true, "url": true, "source": true,
+	"category": true, "tags": true, "salary": true, "company": true,
+	"location": true, "posted_at": true, "extra": true,
+}
+
+// Executor emits a fixed list of items from its config instead of fetching
+// any, for hand-curated digests and for testing the steps after it
+type Executor struct{}
+
+// NewExecutor creates a new static executor
+func NewExecutor() *Executor {
+	return &Executor{}
+}
+
+func (e *Executor) Type() string {
+	return "static"
+}
+
+func (e *Executor) Validate(config map[string]interface{}) error {
+	_, err := parseItems(config)
+	return err
+}
+
+func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	items, err := parseItems(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ExecutorResult{
+		Data:      items,
+		ItemCount: len(items),
+	}, nil
+}
+
+// parseItems maps the config's "items" objects onto scraped items
+func parseItems(config map[string]interface{}) ([]model.ScrapedItem, error) {
+	raw, ok := config["items"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("static requires a non-empty 'items' array")
+	}
+
+	items := make([]model.ScrapedItem, 0, len(raw))
+	for i, r := range raw {
+		fields, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("static item %d must be an object", i+1)
+		}
+
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("static item %d: %w", i+1, err)
+		}
+		var item model.ScrapedItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, fmt.Errorf("static item %d: %w", i+1, err)
+		}
+		if item.Title == "" && item.URL == "" {
+			return nil, fmt.Errorf("static item %d needs a 'title' or 'url'", i+1)
+		}
+
+		for key, value := range fields {
+			if itemFields[key] {
+				continue
+			}
+			if item.Extra == nil {
+				item.Extra = make(map[string]interface{})
+			}
+			item.Extra[key] = value
+		}
+		if item.Source == "" {
+			item.Source = defaultSource
+		}
+		if item.ID == "" {
+			// Stable across runs so deduplication recognises the item
+			hash := sha256.Sum256([]byte(item.Title + "\n" + item.URL))
+			item.ID = "static-" + hex.EncodeToString(hash[:8])
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
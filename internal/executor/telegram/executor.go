@@ -0,0 +1,306 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+)
+
+// maxMessageLength is Telegram's limit for a single sendMessage text
+const maxMessageLength = 4096
+
+// markdownEscaper escapes the characters MarkdownV2 treats as formatting
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// Executor handles Telegram bot notifications in pipelines
+type Executor struct {
+	defaultToken string
+	apiURL       string
+	rateLimit    time.Duration
+	lastSend     time.Time
+	mu           sync.Mutex
+	client       *http.Client
+}
+
+// NewExecutor creates a new Telegram executor
+func NewExecutor(cfg config.TelegramConfig) *Executor {
+	return &Executor{
+		defaultToken: cfg.BotToken,
+		apiURL:       strings.TrimSuffix(cfg.APIURL, "/"),
+		rateLimit:    time.Duration(cfg.RateLimitMs) * time.Millisecond,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// sendMessageRequest is the body of a Bot API sendMessage call
+type sendMessageRequest struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview,omitempty"`
+}
+
+func (e *Executor) Type() string {
+	return "telegram"
+}
+
+func (e *Executor) Validate(config map[string]interface{}) error {
+	if chatID(config) == "" {
+		return fmt.Errorf("telegram requires 'chat_id' in config")
+	}
+	if token, _ := config["bot_token"].(string); token == "" && e.defaultToken == "" {
+		return fmt.Errorf("telegram requires 'bot_token' in config or TELEGRAM_BOT_TOKEN environment variable")
+	}
+	return nil
+}
+
+func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	if input == nil {
+		return nil, fmt.Errorf("telegram executor requires input data")
+	}
+
+	token, _ := config["bot_token"].(string)
+	if token == "" {
+		token = e.defaultToken
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no Telegram bot token configured: set bot_token in pipeline config or TELEGRAM_BOT_TOKEN environment variable")
+	}
+
+	chat := chatID(config)
+	if chat == "" {
+		return nil, fmt.Errorf("telegram requires 'chat_id' in config")
+	}
+
+	tmplStr, _ := config["template"].(string)
+	disablePreview, _ := config["disable_preview"].(bool)
+
+	text, parseMode, err := formatText(input, tmplStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format message: %w", err)
+	}
+
+	messages := splitMessage(text, maxMessageLength)
+	for _, msg := range messages {
+		e.waitForRateLimit()
+
+		req := sendMessageRequest{
+			ChatID:                chat,
+			Text:                  msg,
+			ParseMode:             parseMode,
+			DisableWebPagePreview: disablePreview,
+		}
+		if err := e.send(ctx, token, req); err != nil {
+			return nil, fmt.Errorf("failed to send Telegram message: %w", err)
+		}
+
+		e.mu.Lock()
+		e.lastSend = time.Now()
+		e.mu.Unlock()
+	}
+
+	return &model.ExecutorResult{
+		Data: map[string]interface{}{
+			"status":  "sent",
+			"chat_id": chat,
+		},
+		ItemCount: input.ItemCount,
+		Metadata: map[string]interface{}{
+			"items_sent":    input.ItemCount,
+			"messages_sent": len(messages),
+		},
+	}, nil
+}
+
+// Preview returns the messages Execute would send, without sending them
+func (e *Executor) Preview(input *model.ExecutorResult, config map[string]interface{}) (interface{}, error) {
+	if input == nil {
+		return nil, fmt.Errorf("telegram executor requires input data")
+	}
+
+	tmplStr, _ := config["template"].(string)
+	disablePreview, _ := config["disable_preview"].(bool)
+
+	text, parseMode, err := formatText(input, tmplStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format message: %w", err)
+	}
+
+	var messages []sendMessageRequest
+	for _, msg := range splitMessage(text, maxMessageLength) {
+		messages = append(messages, sendMessageRequest{
+			ChatID:                chatID(config),
+			Text:                  msg,
+			ParseMode:             parseMode,
+			DisableWebPagePreview: disablePreview,
+		})
+	}
+	return messages, nil
+}
+
+func (e *Executor) waitForRateLimit() {
+	e.mu.Lock()
+	elapsed := time.Since(e.lastSend)
+	e.mu.Unlock()
+
+	if elapsed < e.rateLimit {
+		time.Sleep(e.rateLimit - elapsed)
+	}
+}
+
+// formatText renders the input as MarkdownV2, or as plain text when it comes
+// from a template or the AI processor and can't be escaped safely
+func formatText(input *model.ExecutorResult, tmplStr string) (string, string, error) {
+	if tmplStr != "" {
+		content, err := executeTemplate(tmplStr, input.Data)
+		return content, "", err
+	}
+
+	switch v := input.Data.(type) {
+	case string:
+		return v, "", nil
+
+	case model.Alert:
+		return v.Text(), "", nil
+
+	case []model.ScrapedItem:
+		var blocks []string
+		for _, item := range v {
+			var b strings.Builder
+			b.WriteString(link(item.Title, item.URL))
+			var details []string
+			for _, f := range item.RelevantFields() {
+				if f.Key != "tags" {
+					details = append(details, escape(f.Value))
+				}
+			}
+			if len(details) > 0 {
+				b.WriteString("\n" + strings.Join(details, " · "))
+			}
+			if item.Source != "" {
+				b.WriteString("\n_" + escape(item.Source) + "_")
+			}
+			blocks = append(blocks, b.String())
+		}
+		return strings.Join(blocks, "\n\n"), "MarkdownV2", nil
+
+	case []model.RSSItem:
+		var blocks []string
+		for _, item := range v {
+			block := link(item.Title, item.Link)
+			if item.Source != "" {
+				block += "\n_" + escape(item.Source) + "_"
+			}
+			blocks = append(blocks, block)
+		}
+		return strings.Join(blocks, "\n\n"), "MarkdownV2", nil
+
+	default:
+		jsonBytes, _ := json.MarshalIndent(input.Data, "", "  ")
+		return string(jsonBytes), "", nil
+	}
+}
+
+// splitMessage breaks text into chunks within Telegram's limit, preferring
+// to cut at blank lines so items aren't split across messages
+func splitMessage(text string, limit int) []string {
+	var messages []string
+	for len(text) > limit {
+		cut := strings.LastIndex(text[:limit], "\n\n")
+		if cut <= 0 {
+			cut = strings.LastIndex(text[:limit], "\n")
+		}
+		if cut <= 0 {
+			cut = limit
+		}
+		messages = append(messages, text[:cut])
+		text = strings.TrimLeft(text[cut:], "\n")
+	}
+	if text != "" {
+		messages = append(messages, text)
+	}
+	return messages
+}
+
+func (e *Executor) send(ctx context.Context, token string, message sendMessageRequest) error {
+	jsonBody, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	endpoint := e.apiURL + "/bot" + token + "/sendMessage"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		// The request URL embeds the bot token; don't let it leak into logs
+		return fmt.Errorf("request failed: %s", strings.ReplaceAll(err.Error(), token, "***"))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Telegram API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func executeTemplate(tmplStr string, data interface{}) (string, error) {
+	tmpl, err := template.New("telegram").Parse(tmplStr)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// chatID accepts the chat ID as a string ("@channel" or "-100...") or a number
+func chatID(config map[string]interface{}) string {
+	switch v := config["chat_id"].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	}
+	return ""
+}
+
+func link(title, url string) string {
+	if url == "" {
+		return "*" + escape(title) + "*"
+	}
+	// Inside the URL part only ')' and '\' need escaping
+	url = strings.NewReplacer(`\`, `\\`, ")", `\)`).Replace(url)
+	return "*[" + escape(title) + "](" + url + ")*"
+}
+
+func escape(s string) string {
+	return markdownEscaper.Replace(s)
+}
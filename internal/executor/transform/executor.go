@@ -0,0 +1,191 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// Executor rewrites items deterministically: each target field is set from a
+// text/template rendered against the item ("fields") or copied from another
+// field ("mapping"). Feed entries are converted to scraped items first, so
+// the output is always []model.ScrapedItem.
+type Executor struct{}
+
+// NewExecutor creates a new transform executor
+func NewExecutor() *Executor {
+	return &Executor{}
+}
+
+func (e *Executor) Type() string {
+	return "transform"
+}
+
+func (e *Executor) Validate(config map[string]interface{}) error {
+	_, _, err := parseConfig(config)
+	return err
+}
+
+func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	if input == nil || input.Data == nil {
+		return input, nil
+	}
+
+	templates, mapping, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []model.ScrapedItem
+	switch v := input.Data.(type) {
+	case []model.ScrapedItem:
+		items = v
+	case []model.RSSItem:
+		items = make([]model.ScrapedItem, 0, len(v))
+		for _, item := range v {
+			items = append(items, item.ToScrapedItem())
+		}
+	default:
+		return nil, fmt.Errorf("transform expects scraped or RSS items, got %T", input.Data)
+	}
+
+	transformed := make([]model.ScrapedItem, 0, len(items))
+	for i, item := range items {
+		// Every value is computed from the original item, so targets don't
+		// see each other's new values
+		values := make(map[string]string, len(mapping)+len(templates))
+		for target, source := range mapping {
+			values[target] = fieldValue(item, source)
+		}
+		for target, tmpl := range templates {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, item); err != nil {
+				return nil, fmt.Errorf("item %d: field %q: %w", i+1, target, err)
+			}
+			values[target] = buf.String()
+		}
+
+		out := item
+		out.Extra = copyExtra(item.Extra)
+		for target, value := range values {
+			setField(&out, target, value)
+		}
+		transformed = append(transformed, out)
+	}
+
+	return &model.ExecutorResult{
+		Data:      transformed,
+		ItemCount: len(transformed),
+		Metadata:  input.Metadata,
+	}, nil
+}
+
+// parseConfig compiles the "fields" templates and reads the "mapping" copies
+func parseConfig(config map[string]interface{}) (map[string]*template.Template, map[string]string, error) {
+	rawFields, _ := config["fields"].(map[string]interface{})
+	rawMapping, _ := config["mapping"].(map[string]interface{})
+	if len(rawFields) == 0 && len(rawMapping) == 0 {
+		return nil, nil, fmt.Errorf("transform requires a 'fields' or 'mapping' object")
+	}
+
+	templates := make(map[string]*template.Template, len(rawFields))
+	for target, raw := range rawFields {
+		text, ok := raw.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("transform field %q must be a template string", target)
+		}
+		tmpl, err := template.New(target).Funcs(funcs).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, nil, fmt.Errorf("transform field %q: invalid template: %w", target, err)
+		}
+		templates[target] = tmpl
+	}
+
+	mapping := make(map[string]string, len(rawMapping))
+	for target, raw := range rawMapping {
+		source, ok := raw.(string)
+		if !ok || source == "" {
+			return nil, nil, fmt.Errorf("transform mapping %q must name a source field", target)
+		}
+		if _, ok := templates[target]; ok {
+			return nil, nil, fmt.Errorf("transform field %q is set by both 'fields' and 'mapping'", target)
+		}
+		mapping[target] = source
+	}
+
+	return templates, mapping, nil
+}
+
+// fieldValue reads any field of an item by its JSON name, falling back to Extra
+func fieldValue(item model.ScrapedItem, key string) string {
+	switch key {
+	case "id":
+		return item.ID
+	case "title":
+		return item.Title
+	case "description":
+		return item.Description
+	case "url":
+		return item.URL
+	case "source":
+		return item.Source
+	case "category":
+		return item.Category
+	case "posted_at":
+		return item.PostedAt
+	}
+	return item.Field(key)
+}
+
+// setField writes a field by its JSON name; unknown names go to Extra
+func setField(item *model.ScrapedItem, key, value string) {
+	switch key {
+	case "id":
+		item.ID = value
+	case "title":
+		item.Title = value
+	case "description":
+		item.Description = value
+	case "url":
+		item.URL = value
+	case "source":
+		item.Source = value
+	case "category":
+		item.Category = value
+	case "salary":
+		item.Salary = value
+	case "company":
+		item.Company = value
+	case "location":
+		item.Location = value
+	case "posted_at":
+		item.PostedAt = value
+	case "tags":
+		item.Tags = nil
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				item.Tags = append(item.Tags, tag)
+			}
+		}
+	default:
+		if item.Extra == nil {
+			item.Extra = make(map[string]interface{})
+		}
+		item.Extra[key] = value
+	}
+}
+
+func copyExtra(extra map[string]interface{}) map[string]interface{} {
+	if extra == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(extra))
+	for k, v := range extra {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,85 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// funcs are the helpers available to transform templates. Names and argument
+// order follow sprig, so the value being transformed comes last and can be
+// piped in: {{ .Title | trunc 60 | upper }}.
+var funcs = map[string]interface{}{
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"title":     titleCase,
+	"trim":      strings.TrimSpace,
+	"trunc":     trunc,
+	"replace":   func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"contains":  func(substr, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix": func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix": func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"split":     func(sep, s string) []string { return strings.Split(s, sep) },
+	"join":      func(sep string, list []string) string { return strings.Join(list, sep) },
+	"default":   defaultValue,
+	"coalesce":  coalesce,
+	"date":      formatDate,
+}
+
+// titleCase capitalizes the first letter of each word
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = strings.ToUpper(string(r)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+// trunc shortens s to at most n characters, adding an ellipsis when cut
+func trunc(n int, s string) string {
+	runes := []rune(s)
+	if n <= 0 || len(runes) <= n {
+		return s
+	}
+	if n <= 3 {
+		return string(runes[:n])
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+// defaultValue returns def when value is empty
+func defaultValue(def string, value interface{}) string {
+	s := ""
+	if value != nil {
+		s = fmt.Sprint(value)
+	}
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
+
+// coalesce returns the first non-empty value
+func coalesce(values ...interface{}) string {
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if s := fmt.Sprint(v); strings.TrimSpace(s) != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// formatDate renders a date in a Go layout; unparseable input is returned unchanged
+func formatDate(layout, s string) string {
+	t, ok := model.ParseDate(s)
+	if !ok {
+		return s
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+)
+
+// maxErrorBody caps how much of a failed response is included in the step error
+const maxErrorBody = 1024
+
+// Executor delivers pipeline data to an arbitrary HTTP endpoint, other than
+// the server's own network
+type Executor struct {
+	client *http.Client
+	guard  *netguard.Guard
+}
+
+// NewExecutor creates a new webhook executor
+func NewExecutor(guard *netguard.Guard) *Executor {
+	return &Executor{
+		client: guard.Client(30 * time.Second),
+		guard:  guard,
+	}
+}
+
+func (e *Executor) Type() string {
+	return "webhook"
+}
+
+func (e *Executor) Validate(config map[string]interface{}) error {
+	rawURL, _ := config["url"].(string)
+	if rawURL == "" {
+		return fmt.Errorf("webhook requires 'url' in config")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook 'url' must be an absolute http(s) URL")
+	}
+	if err := e.guard.CheckURL(rawURL); err != nil {
+		return fmt.Errorf("webhook 'url': %w", err)
+	}
+
+	switch method(config) {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return fmt.Errorf("webhook 'method' must be POST, PUT or PATCH")
+	}
+
+	if headers, ok := config["headers"]; ok {
+		if _, ok := headers.(map[string]interface{}); !ok {
+			return fmt.Errorf("webhook 'headers' must be an object")
+		}
+	}
+
+	if tmplStr, ok := config["template"].(string); ok && tmplStr != "" {
+		if _, err := template.New("webhook").Parse(tmplStr); err != nil {
+			return fmt.Errorf("invalid webhook template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (e *Executor) Execute(ctx context.Context, input *model.ExecutorResult, config map[string]interface{}) (*model.ExecutorResult, error) {
+	if input == nil {
+		return nil, fmt.Errorf("webhook executor requires input data")
+	}
+
+	targetURL, _ := config["url"].(string)
+	if targetURL == "" {
+		return nil, fmt.Errorf("webhook requires 'url' in config")
+	}
+	if err := e.guard.CheckURL(targetURL); err != nil {
+		return nil, err
+	}
+
+	body, err := buildBody(input, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webhook body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method(config), targetURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if headers, ok := config["headers"].(map[string]interface{}); ok {
+		for k, v := range headers {
+			req.Header.Set(k, fmt.Sprint(v))
+		}
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return &model.ExecutorResult{
+		Data: map[string]interface{}{
+			"status":      "sent",
+			"status_code": resp.StatusCode,
+		},
+		ItemCount: input.ItemCount,
+		Metadata: map[string]interface{}{
+			"items_sent":  input.ItemCount,
+			"method":      req.Method,
+			"status_code": resp.StatusCode,
+		},
+	}, nil
+}
+
+// Preview returns the request Execute would make, without making it
+func (e *Executor) Preview(input *model.ExecutorResult, config map[string]interface{}) (interface{}, error) {
+	if input == nil {
+		return nil, fmt.Errorf("webhook executor requires input data")
+	}
+
+	body, err := buildBody(input, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webhook body: %w", err)
+	}
+
+	preview := map[string]interface{}{
+		"method": method(config),
+		"body":   string(body),
+	}
+	if json.Valid(body) {
+		preview["body"] = json.RawMessage(body)
+	}
+	if targetURL, _ := config["url"].(string); targetURL != "" {
+		preview["url"] = targetURL
+	}
+	return preview, nil
+}
+
+// buildBody renders the optional template against the step input, falling
+// back to the input data encoded as JSON
+func buildBody(input *model.ExecutorResult, config map[string]interface{}) ([]byte, error) {
+	tmplStr, _ := config["template"].(string)
+	if tmplStr == "" {
+		return json.Marshal(input.Data)
+	}
+
+	tmpl, err := template.New("webhook").Parse(tmplStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, input.Data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func method(config map[string]interface{}) string {
+	m, _ := config["method"].(string)
+	if m == "" {
+		return http.MethodPost
+	}
+	return strings.ToUpper(m)
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -16,19 +17,33 @@ type contextKey string
 
 const UserContextKey contextKey = "user"
 
+// apiKeyContextKey holds the ID of the validated API key a request
+// authenticated with, for RateLimitAPIKeys
+const apiKeyContextKey contextKey = "api_key"
+
+// ErrInvalidRefreshToken is returned when a refresh token is unknown,
+// expired or already used
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
 // AuthMiddleware handles JWT and API key authentication
 type AuthMiddleware struct {
-	jwtSecret []byte
-	userRepo  *storage.UserRepository
-	expHours  int
+	jwtSecret   []byte
+	userRepo    *storage.UserRepository
+	refreshRepo *storage.RefreshTokenRepository
+	apiKeyRepo  *storage.APIKeyRepository
+	expHours    int
+	refreshDays int
 }
 
 // NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(cfg config.JWTConfig, userRepo *storage.UserRepository) *AuthMiddleware {
+func NewAuthMiddleware(cfg config.JWTConfig, userRepo *storage.UserRepository, refreshRepo *storage.RefreshTokenRepository, apiKeyRepo *storage.APIKeyRepository) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtSecret: []byte(cfg.Secret),
-		userRepo:  userRepo,
-		expHours:  cfg.ExpirationHours,
+		jwtSecret:   []byte(cfg.Secret),
+		userRepo:    userRepo,
+		refreshRepo: refreshRepo,
+		apiKeyRepo:  apiKeyRepo,
+		expHours:    cfg.ExpirationHours,
+		refreshDays: cfg.RefreshExpirationDays,
 	}
 }
 
@@ -57,7 +72,17 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 					Email:  user.Email,
 					Role:   user.Role,
 				}
+				// A user has one personal key, so their ID identifies it
+				ctx := context.WithValue(r.Context(), UserContextKey, claims)
+				ctx = context.WithValue(ctx, apiKeyContextKey, "user:"+user.ID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			// Then a scoped key
+			if claims, keyID := m.scopedKeyClaims(r.Context(), apiKey); claims != nil {
 				ctx := context.WithValue(r.Context(), UserContextKey, claims)
+				ctx = context.WithValue(ctx, apiKeyContextKey, "scoped:"+keyID)
 				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
@@ -67,6 +92,80 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	})
 }
 
+// scopedKeyClaims returns the claims and ID of a scoped API key whose owner
+// is still active, or nil
+func (m *AuthMiddleware) scopedKeyClaims(ctx context.Context, key string) (*model.TokenClaims, string) {
+	if m.apiKeyRepo == nil {
+		return nil, ""
+	}
+	apiKey, err := m.apiKeyRepo.FindByKey(ctx, key)
+	if err != nil || apiKey == nil {
+		return nil, ""
+	}
+	user, err := m.userRepo.FindByID(ctx, apiKey.UserID)
+	if err != nil || user == nil || !user.IsActive {
+		return nil, ""
+	}
+
+	claims := &model.TokenClaims{
+		UserID: user.ID,
+		Email:  user.Email,
+		Role:   user.Role,
+		Scopes: append([]string{}, apiKey.Scopes...),
+	}
+	if apiKey.TaskID != nil {
+		claims.TaskID = *apiKey.TaskID
+	}
+	return claims, apiKey.ID
+}
+
+// RequireScope returns middleware that lets scoped API keys through only
+// with the given scope. A key restricted to a task is further limited to
+// routes about that task, identified by their {id} or {taskId} path value.
+// JWTs and personal API keys have full access.
+func (m *AuthMiddleware) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetUserFromContext(r.Context())
+			if claims == nil {
+				http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+			if !claims.HasScope(scope) {
+				http.Error(w, `{"error": "API key lacks the `+scope+` scope"}`, http.StatusForbidden)
+				return
+			}
+			if claims.TaskID != "" {
+				taskID := r.PathValue("id")
+				if taskID == "" {
+					taskID = r.PathValue("taskId")
+				}
+				if taskID != claims.TaskID {
+					http.Error(w, `{"error": "API key is restricted to another task"}`, http.StatusForbidden)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireFullAccess rejects scoped API keys, e.g. from managing API keys
+func (m *AuthMiddleware) RequireFullAccess(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := GetUserFromContext(r.Context())
+		if claims == nil {
+			http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		if claims.Scoped() {
+			http.Error(w, `{"error": "scoped API keys can't use this endpoint"}`, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // RequireAdmin middleware checks for admin role
 func (m *AuthMiddleware) RequireAdmin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -100,6 +199,69 @@ func (m *AuthMiddleware) GenerateToken(user *model.User) (string, int64, error)
 	return tokenStr, expiresAt.Unix(), nil
 }
 
+// IssueTokens creates a JWT and a stored refresh token for a user
+func (m *AuthMiddleware) IssueTokens(ctx context.Context, user *model.User) (*model.LoginResponse, error) {
+	token, expiresAt, err := m.GenerateToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshExpiresAt := time.Now().AddDate(0, 0, m.refreshDays)
+	refreshToken, err := m.refreshRepo.Create(ctx, user.ID, refreshExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt.Unix(),
+		User:             user,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new JWT and a new refresh token;
+// the old refresh token can't be used again
+func (m *AuthMiddleware) Refresh(ctx context.Context, refreshToken string) (*model.LoginResponse, error) {
+	userID, err := m.refreshRepo.Consume(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := m.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if !user.IsActive {
+		// Deactivated outside SetUserActive; end the remaining sessions too
+		if err := m.refreshRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return m.IssueTokens(ctx, user)
+}
+
+// RevokeRefreshToken ends the session a refresh token belongs to. JWTs
+// already issued stay valid until they expire.
+func (m *AuthMiddleware) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	return m.refreshRepo.Revoke(ctx, refreshToken)
+}
+
+// RevokeUserSessions revokes all of a user's refresh tokens. JWTs already
+// issued stay valid until they expire.
+func (m *AuthMiddleware) RevokeUserSessions(ctx context.Context, userID string) error {
+	return m.refreshRepo.RevokeAllForUser(ctx, userID)
+}
+
 // ValidateToken validates a JWT token and returns claims
 func (m *AuthMiddleware) ValidateToken(tokenStr string) (*model.TokenClaims, error) {
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/storage"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+func newTestAuth(db *storage.Database) *AuthMiddleware {
+	return NewAuthMiddleware(config.JWTConfig{
+		Secret:                "test-secret-test-secret-test-secret",
+		ExpirationHours:       1,
+		RefreshExpirationDays: 1,
+	}, storage.NewUserRepository(db), storage.NewRefreshTokenRepository(db), storage.NewAPIKeyRepository(db))
+}
+
+func TestRefreshIsSingleUse(t *testing.T) {
+	db := storagetest.Open(t)
+	auth := newTestAuth(db)
+	ctx := context.Background()
+	user := storagetest.CreateUser(t, db, model.UserRoleUser)
+
+	issued, err := auth.IssueTokens(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshed, err := auth.Refresh(ctx, issued.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if refreshed.RefreshToken == issued.RefreshToken {
+		t.Error("refresh returned the same refresh token")
+	}
+	if _, err := auth.Refresh(ctx, issued.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("reusing a refresh token: got %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshRejectsInactiveUsers(t *testing.T) {
+	db := storagetest.Open(t)
+	auth := newTestAuth(db)
+	users := storage.NewUserRepository(db)
+	ctx := context.Background()
+
+	t.Run("disabled through SetActive", func(t *testing.T) {
+		user := storagetest.CreateUser(t, db, model.UserRoleUser)
+		first, err := auth.IssueTokens(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := auth.IssueTokens(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := users.SetActive(ctx, user.ID, false); err != nil {
+			t.Fatal(err)
+		}
+		// Re-enabling doesn't bring revoked sessions back
+		if _, err := users.SetActive(ctx, user.ID, true); err != nil {
+			t.Fatal(err)
+		}
+		for _, token := range []string{first.RefreshToken, second.RefreshToken} {
+			if _, err := auth.Refresh(ctx, token); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("refresh after disabling: got %v, want ErrInvalidRefreshToken", err)
+			}
+		}
+	})
+
+	t.Run("disabled in the database", func(t *testing.T) {
+		user := storagetest.CreateUser(t, db, model.UserRoleUser)
+		first, err := auth.IssueTokens(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := auth.IssueTokens(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := db.Exec(`UPDATE users SET is_active = false WHERE id = $1`, user.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := auth.Refresh(ctx, first.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Fatalf("refresh of a disabled user: got %v, want ErrInvalidRefreshToken", err)
+		}
+
+		// The refused refresh revoked the user's other sessions too
+		if _, err := db.Exec(`UPDATE users SET is_active = true WHERE id = $1`, user.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := auth.Refresh(ctx, second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("other session after a refused refresh: got %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle buckets are dropped
+const sweepInterval = time.Minute
+
+// rateLimiter keeps a token bucket per client
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // tokens added per second
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from key's bucket, or reports how long until one is
+// available
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets that have refilled completely, since a new bucket
+// starts full anyway
+func (l *rateLimiter) sweep(now time.Time) {
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// RateLimit returns middleware allowing each client IP rps requests per
+// second with bursts of up to burst, answering 429 with Retry-After beyond
+// that. It runs before authentication, so nothing the client sends, such as
+// an API key, picks its bucket. An rps of 0 or less disables the limit.
+func RateLimit(rps float64, burst int) func(http.Handler) http.Handler {
+	return limit(rps, burst, func(r *http.Request) string {
+		return "ip:" + clientIP(r)
+	})
+}
+
+// RateLimitAPIKeys returns middleware giving each API key its own bucket,
+// shared by every IP using it. It goes after Authenticate, which records the
+// key only once it's validated; other requests pass through.
+func RateLimitAPIKeys(rps float64, burst int) func(http.Handler) http.Handler {
+	return limit(rps, burst, func(r *http.Request) string {
+		if id, ok := r.Context().Value(apiKeyContextKey).(string); ok {
+			return "key:" + id
+		}
+		return ""
+	})
+}
+
+// limit builds rate limiting middleware over the bucket key picks for a
+// request; an empty key isn't limited
+func limit(rps float64, burst int, key func(r *http.Request) string) func(http.Handler) http.Handler {
+	if rps <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := &rateLimiter{
+		rate:    rps,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k := key(r)
+			if k == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ok, wait := limiter.allow(k, time.Now())
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				http.Error(w, `{"error": "rate limit exceeded"}`, http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the address the request came from
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestRateLimitIgnoresAPIKeyHeader(t *testing.T) {
+	handler := RateLimit(1, 2)(okHandler())
+
+	codes := make([]int, 0, 4)
+	for i := 0; i < 4; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		// A different made-up key each time must not buy a fresh bucket
+		req.Header.Set("X-API-Key", fmt.Sprintf("random-%d", i))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+
+	want := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Fatalf("request %d: got %d, want %d (all: %v)", i, codes[i], want[i], codes)
+		}
+	}
+}
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	handler := RateLimit(0.5, 1)(okHandler())
+
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "203.0.113.8:4000"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("request %d: got %d, want %d", i, rec.Code, want)
+		}
+		if want == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "2" {
+			t.Errorf("Retry-After = %q, want 2", rec.Header().Get("Retry-After"))
+		}
+	}
+}
+
+func TestRateLimitAPIKeysOnlyValidatedKeys(t *testing.T) {
+	handler := RateLimitAPIKeys(1, 1)(okHandler())
+
+	serve := func(keyID, ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+		req.RemoteAddr = ip + ":4000"
+		if keyID != "" {
+			req = req.WithContext(context.WithValue(req.Context(), apiKeyContextKey, keyID))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Unauthenticated or JWT requests aren't limited here
+	for i := 0; i < 3; i++ {
+		if code := serve("", "203.0.113.9"); code != http.StatusOK {
+			t.Fatalf("request without a validated key got %d", code)
+		}
+	}
+
+	// One key's bucket is shared across IPs
+	if code := serve("scoped:k1", "203.0.113.10"); code != http.StatusOK {
+		t.Fatalf("first request with key got %d", code)
+	}
+	if code := serve("scoped:k1", "203.0.113.11"); code != http.StatusTooManyRequests {
+		t.Fatalf("second request with the same key from another IP got %d, want 429", code)
+	}
+	if code := serve("scoped:k2", "203.0.113.11"); code != http.StatusOK {
+		t.Fatalf("another key got %d", code)
+	}
+}
+
+func TestRateLimiterSweepsIdleBuckets(t *testing.T) {
+	l := &rateLimiter{rate: 1, burst: 2, buckets: make(map[string]*bucket)}
+	start := time.Now()
+	l.lastSweep = start
+
+	for i := 0; i < 100; i++ {
+		l.allow(fmt.Sprintf("ip:10.0.0.%d", i), start)
+	}
+	if len(l.buckets) != 100 {
+		t.Fatalf("got %d buckets, want 100", len(l.buckets))
+	}
+
+	// After a sweep interval every bucket has refilled and is dropped; only
+	// the caller's new one remains
+	l.allow("ip:10.0.1.1", start.Add(sweepInterval))
+	if len(l.buckets) != 1 {
+		t.Fatalf("got %d buckets after sweep, want 1", len(l.buckets))
+	}
+}
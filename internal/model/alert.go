@@ -0,0 +1,21 @@
+package model
+
+import "strings"
+
+// Alert is the data an assert step passes on when its conditions fail;
+// delivery steps render it as a notification instead of a list of items
+type Alert struct {
+	Title     string   `json:"title"`
+	Failures  []string `json:"failures"`   // One line per failed condition
+	ItemCount int      `json:"item_count"` // Items the assertion was evaluated on
+}
+
+// Text renders the alert as plain text
+func (a Alert) Text() string {
+	var b strings.Builder
+	b.WriteString(a.Title)
+	for _, f := range a.Failures {
+		b.WriteString("\n- " + f)
+	}
+	return b.String()
+}
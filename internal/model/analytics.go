@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// ItemCount is the number of items delivered for one source/category pair
+type ItemCount struct {
+	Source   string
+	Category string
+	Count    int
+}
+
+// ItemStat is one day's delivered item count for a group
+type ItemStat struct {
+	Day   time.Time `json:"day" db:"day"`
+	Group string    `json:"group" db:"grp"`
+	Count int       `json:"count" db:"count"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// API key scopes. A scoped key can only call routes requiring one of its scopes.
+const (
+	ScopeTasksRead    = "tasks:read"    // List and inspect tasks, executions and analytics
+	ScopeTasksWrite   = "tasks:write"   // Create, update, delete, pause and resume tasks
+	ScopeTasksTrigger = "tasks:trigger" // Run tasks and resume executions
+	ScopeDiscordRead  = "discord:read"  // List Discord bots, channels and task configs
+	ScopeDiscordWrite = "discord:write" // Change Discord bots, channels and task configs
+)
+
+// ValidScope reports whether s is a known scope
+func ValidScope(s string) bool {
+	switch s {
+	case ScopeTasksRead, ScopeTasksWrite, ScopeTasksTrigger, ScopeDiscordRead, ScopeDiscordWrite:
+		return true
+	}
+	return false
+}
+
+// ScopeList is a set of scopes stored as a JSON array
+type ScopeList []string
+
+func (s ScopeList) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *ScopeList) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// APIKey is a named API key limited to some scopes and, optionally, one task
+type APIKey struct {
+	ID         string     `json:"id" db:"id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"` // First characters of the key, to tell keys apart
+	Scopes     ScopeList  `json:"scopes" db:"scopes"`
+	TaskID     *string    `json:"task_id,omitempty" db:"task_id"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	TaskID *string  `json:"task_id,omitempty"` // Restrict the key to this task
+}
+
+// CreateAPIKeyResponse carries the key itself, which is only shown once
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ItemField names a ScrapedItem detail worth showing for a category. Key is
+// one of company, salary, location or tags, or otherwise a key in Extra.
+type ItemField struct {
+	Key   string
+	Label string
+}
+
+// ItemFieldValue is an ItemField rendered for a specific item
+type ItemFieldValue struct {
+	Key   string
+	Label string
+	Value string
+}
+
+// categoryFields lists, per scraper category, the fields relevant to its
+// items in display order
+var categoryFields = map[string][]ItemField{
+	"jobs": {
+		{Key: "company", Label: "Company"},
+		{Key: "salary", Label: "Salary"},
+		{Key: "location", Label: "Location"},
+		{Key: "tags", Label: "Tags"},
+	},
+	"freelance": {
+		{Key: "salary", Label: "Budget"},
+		{Key: "location", Label: "Location"},
+		{Key: "tags", Label: "Skills"},
+	},
+	"news": {
+		{Key: "author", Label: "Author"},
+		{Key: "points", Label: "Points"},
+		{Key: "stars", Label: "Stars"},
+		{Key: "reactions", Label: "Reactions"},
+		{Key: "comments", Label: "Comments"},
+		{Key: "tags", Label: "Tags"},
+	},
+}
+
+// CategoryFields returns the fields relevant to a category. Unknown and
+// empty categories get the job fields, which is how items were always shown.
+func CategoryFields(category string) []ItemField {
+	if fields, ok := categoryFields[strings.ToLower(category)]; ok {
+		return fields
+	}
+	return categoryFields["jobs"]
+}
+
+// Field returns the value of one of the item's fields as text, or "" if unset
+func (item ScrapedItem) Field(key string) string {
+	switch key {
+	case "company":
+		return item.Company
+	case "salary":
+		return item.Salary
+	case "location":
+		return item.Location
+	case "tags":
+		return strings.Join(item.Tags, ", ")
+	}
+
+	v, ok := item.Extra[key]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// RelevantFields returns the item's non-empty fields for its category
+func (item ScrapedItem) RelevantFields() []ItemFieldValue {
+	var values []ItemFieldValue
+	for _, f := range CategoryFields(item.Category) {
+		if v := item.Field(f.Key); v != "" {
+			values = append(values, ItemFieldValue{Key: f.Key, Label: f.Label, Value: v})
+		}
+	}
+	return values
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelevantFieldsFollowCategory(t *testing.T) {
+	item := ScrapedItem{
+		Company:  "Acme",
+		Salary:   "$120k",
+		Location: "Remote",
+		Tags:     []string{"go", "k8s"},
+		Extra:    map[string]interface{}{"author": "pg", "points": float64(312), "comments": 48},
+	}
+
+	tests := []struct {
+		category string
+		want     []ItemFieldValue
+	}{
+		{"jobs", []ItemFieldValue{
+			{"company", "Company", "Acme"},
+			{"salary", "Salary", "$120k"},
+			{"location", "Location", "Remote"},
+			{"tags", "Tags", "go, k8s"},
+		}},
+		{"freelance", []ItemFieldValue{
+			{"salary", "Budget", "$120k"},
+			{"location", "Location", "Remote"},
+			{"tags", "Skills", "go, k8s"},
+		}},
+		{"News", []ItemFieldValue{
+			{"author", "Author", "pg"},
+			{"points", "Points", "312"},
+			{"comments", "Comments", "48"},
+			{"tags", "Tags", "go, k8s"},
+		}},
+		// Items were always shown with the job fields
+		{"", []ItemFieldValue{
+			{"company", "Company", "Acme"},
+			{"salary", "Salary", "$120k"},
+			{"location", "Location", "Remote"},
+			{"tags", "Tags", "go, k8s"},
+		}},
+	}
+	for _, tt := range tests {
+		item.Category = tt.category
+		if got := item.RelevantFields(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("category %q: fields = %v, want %v", tt.category, got, tt.want)
+		}
+	}
+}
+
+func TestRelevantFieldsSkipEmptyValues(t *testing.T) {
+	item := ScrapedItem{Category: "news", Extra: map[string]interface{}{"points": float64(5), "author": nil}}
+	want := []ItemFieldValue{{"points", "Points", "5"}}
+	if got := item.RelevantFields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+}
@@ -54,6 +54,13 @@ type TaskDiscordConfig struct {
 	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
 }
 
+// Levels of the hierarchy that resolves the Discord webhook a task posts to
+const (
+	WebhookSourceTask    = "task_webhook" // The task config's own webhook_url
+	WebhookSourceChannel = "task_channel" // The channel the task config names
+	WebhookSourceDefault = "default_bot"  // The default bot's first channel
+)
+
 // EmbedConfig for Discord embeds
 type EmbedConfig struct {
 	Color       int    `json:"color,omitempty"`
@@ -122,6 +129,7 @@ type SetTaskDiscordConfigRequest struct {
 	EmbedConfig     EmbedConfig `json:"embed_config,omitempty"`
 	Username        string      `json:"username,omitempty"`
 	AvatarURL       string      `json:"avatar_url,omitempty"`
+	Verify          bool        `json:"verify,omitempty"` // Check the resolved webhook is reachable before saving
 }
 
 // DiscordBotWithChannels includes bot with its configured channels
@@ -138,3 +146,15 @@ type DiscordConfigSummary struct {
 	ChannelName string `json:"channel_name,omitempty"`
 	HasWebhook  bool   `json:"has_webhook"`
 }
+
+// TaskSecret describes a stored task secret. The value itself is never
+// returned by the API; steps reference it as {{secret "name"}}.
+type TaskSecret struct {
+	Name      string    `json:"name" db:"name"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SetTaskSecretsRequest upserts secrets by name; an empty value deletes the secret
+type SetTaskSecretsRequest struct {
+	Secrets map[string]string `json:"secrets" validate:"required"`
+}
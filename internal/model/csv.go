@@ -0,0 +1,71 @@
+package model
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// scrapedColumns are the fixed CSV columns of scraped items; keys of Extra
+// follow in alphabetical order
+var scrapedColumns = []string{"id", "title", "description", "url", "source", "category", "tags", "salary", "company", "location", "posted_at"}
+
+var rssColumns = []string{"id", "title", "description", "link", "source", "pub_date", "categories", "author"}
+
+// WriteItemsCSV flattens scraped or RSS items into one row per item, after
+// a header row
+func WriteItemsCSV(w io.Writer, data interface{}) error {
+	cw := csv.NewWriter(w)
+	switch v := data.(type) {
+	case []ScrapedItem:
+		extraSet := make(map[string]bool)
+		for _, item := range v {
+			for k := range item.Extra {
+				extraSet[k] = true
+			}
+		}
+		extras := make([]string, 0, len(extraSet))
+		for k := range extraSet {
+			extras = append(extras, k)
+		}
+		sort.Strings(extras)
+
+		if err := cw.Write(append(append([]string{}, scrapedColumns...), extras...)); err != nil {
+			return err
+		}
+		for _, item := range v {
+			row := []string{
+				item.ID, item.Title, item.Description, item.URL, item.Source, item.Category,
+				strings.Join(item.Tags, ", "), item.Salary, item.Company, item.Location, item.PostedAt,
+			}
+			for _, k := range extras {
+				row = append(row, item.Field(k))
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+
+	case []RSSItem:
+		if err := cw.Write(rssColumns); err != nil {
+			return err
+		}
+		for _, item := range v {
+			row := []string{
+				item.ID, item.Title, item.Description, item.Link, item.Source, item.PubDate,
+				strings.Join(item.Categories, ", "), item.Author,
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("csv needs scraped or RSS items, got %T", data)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the date formats seen in feeds and scraped items
+var dateLayouts = []string{
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC3339,
+	"Mon, 02 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC822,
+	time.RFC822Z,
+}
+
+// ParseDate parses the PostedAt and PubDate strings sources emit: RFC 1123
+// and RFC 3339 variants, plain dates, and Unix timestamps in seconds or
+// milliseconds
+func ParseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 0 {
+		if n > 1e12 {
+			return time.UnixMilli(n).UTC(), true
+		}
+		return time.Unix(n, 0).UTC(), true
+	}
+	return time.Time{}, false
+}
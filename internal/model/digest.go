@@ -0,0 +1,21 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Kinds of item a digest step holds
+const (
+	DigestKindScraped = "scraped"
+	DigestKindRSS     = "rss"
+)
+
+// DigestItem is an item a digest step holds across runs until it flushes
+type DigestItem struct {
+	ID        int64           `db:"id"`
+	Key       string          `db:"item_key"` // Identifies the item, so holding it twice keeps one copy
+	Kind      string          `db:"kind"`
+	Item      json.RawMessage `db:"item"` // The ScrapedItem or RSSItem as JSON
+	CreatedAt time.Time       `db:"created_at"`
+}
@@ -2,8 +2,11 @@ package model
 
 import (
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -16,6 +19,35 @@ const (
 	ExecutionStatusFailed    ExecutionStatus = "failed"
 )
 
+// Valid reports whether s is a known execution status
+func (s ExecutionStatus) Valid() bool {
+	switch s {
+	case ExecutionStatusPending, ExecutionStatusRunning, ExecutionStatusCompleted, ExecutionStatusFailed:
+		return true
+	}
+	return false
+}
+
+// TriggerType is the normalized source of an execution, alongside the
+// free-form TriggeredBy
+type TriggerType string
+
+const (
+	TriggerTypeSchedule TriggerType = "schedule"
+	TriggerTypeManual   TriggerType = "manual"
+	TriggerTypeReplay   TriggerType = "replay"
+	TriggerTypeWebhook  TriggerType = "webhook"
+)
+
+// Valid reports whether t is one of the known trigger types
+func (t TriggerType) Valid() bool {
+	switch t {
+	case TriggerTypeSchedule, TriggerTypeManual, TriggerTypeReplay, TriggerTypeWebhook:
+		return true
+	}
+	return false
+}
+
 type Execution struct {
 	ID          string          `json:"id" db:"id"`
 	TaskID      string          `json:"task_id" db:"task_id"`
@@ -27,6 +59,45 @@ type Execution struct {
 	StepResults StepResults     `json:"step_results" db:"step_results"`
 	Error       *string         `json:"error,omitempty" db:"error"`
 	TriggeredBy string          `json:"triggered_by" db:"triggered_by"` // "schedule" or "manual" or user_id
+	TriggerType TriggerType     `json:"trigger_type" db:"trigger_type"`
+	Forced      bool            `json:"forced" db:"forced"` // run bypassed cached responses or dedup
+}
+
+// ExecutionCursor marks a position in a task's execution history, newest
+// first; a page fetched with it starts right after that execution
+type ExecutionCursor struct {
+	StartedAt time.Time
+	ID        string
+}
+
+// CursorAfter returns the cursor continuing after an execution
+func CursorAfter(e Execution) ExecutionCursor {
+	return ExecutionCursor{StartedAt: e.StartedAt, ID: e.ID}
+}
+
+// String encodes the cursor as an opaque token
+func (c ExecutionCursor) String() string {
+	raw := c.StartedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ParseExecutionCursor decodes a token produced by ExecutionCursor.String
+func ParseExecutionCursor(token string) (*ExecutionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	startedAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || !uuidPattern.MatchString(id) {
+		return nil, errors.New("invalid cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, startedAt)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &ExecutionCursor{StartedAt: t, ID: id}, nil
 }
 
 type StepResult struct {
@@ -57,9 +128,15 @@ func (s *StepResults) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, s)
 }
 
+// ExecutionFilter narrows execution listings; zero fields match everything
 type ExecutionFilter struct {
-	TaskID string
-	Status ExecutionStatus
-	Limit  int
-	Offset int
+	TaskID      string
+	OwnerID     string // Only executions of tasks this user created
+	Status      ExecutionStatus
+	TriggerType TriggerType
+	Since       *time.Time       // Started at or after
+	Until       *time.Time       // Started before
+	Before      *ExecutionCursor // Keyset page; takes precedence over Offset
+	Limit       int
+	Offset      int
 }
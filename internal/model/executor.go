@@ -47,6 +47,41 @@ type RSSItem struct {
 	PubDate     string   `json:"pub_date"`
 	Categories  []string `json:"categories,omitempty"`
 	Author      string   `json:"author,omitempty"`
+	ImageURL    string   `json:"image_url,omitempty"`  // Thumbnail or artwork for the item
+	MediaURL    string   `json:"media_url,omitempty"`  // Attached media, e.g. a podcast episode's audio
+	MediaType   string   `json:"media_type,omitempty"` // MIME type of MediaURL, when the feed gives one
+	AIReason    string   `json:"ai_reason,omitempty"`  // Why an ai_filter step kept the item
+}
+
+// ToScrapedItem converts a feed entry so it can travel with scraped items
+func (item RSSItem) ToScrapedItem() ScrapedItem {
+	scraped := ScrapedItem{
+		ID:          item.ID,
+		Title:       item.Title,
+		Description: item.Description,
+		URL:         item.Link,
+		Source:      item.Source,
+		Category:    "news",
+		Tags:        item.Categories,
+		PostedAt:    item.PubDate,
+	}
+	extra := map[string]interface{}{}
+	if item.Author != "" {
+		extra["author"] = item.Author
+	}
+	if item.ImageURL != "" {
+		extra["image_url"] = item.ImageURL
+	}
+	if item.MediaURL != "" {
+		extra["media_url"] = item.MediaURL
+	}
+	if item.AIReason != "" {
+		extra["ai_reason"] = item.AIReason
+	}
+	if len(extra) > 0 {
+		scraped.Extra = extra
+	}
+	return scraped
 }
 
 // DiscordMessage represents a message to send to Discord
@@ -65,6 +100,12 @@ type DiscordEmbed struct {
 	Fields      []DiscordEmbedField `json:"fields,omitempty"`
 	Footer      *DiscordEmbedFooter `json:"footer,omitempty"`
 	Timestamp   string              `json:"timestamp,omitempty"`
+	Thumbnail   *DiscordEmbedImage  `json:"thumbnail,omitempty"`
+	Image       *DiscordEmbedImage  `json:"image,omitempty"`
+}
+
+type DiscordEmbedImage struct {
+	URL string `json:"url"`
 }
 
 type DiscordEmbedField struct {
@@ -77,3 +118,37 @@ type DiscordEmbedFooter struct {
 	Text    string `json:"text"`
 	IconURL string `json:"icon_url,omitempty"`
 }
+
+// SlackMessage represents a message to send to a Slack incoming webhook
+type SlackMessage struct {
+	Text     string       `json:"text,omitempty"`
+	Blocks   []SlackBlock `json:"blocks,omitempty"`
+	Username string       `json:"username,omitempty"`
+	IconURL  string       `json:"icon_url,omitempty"`
+}
+
+// SlackBlock is a Block Kit layout block
+type SlackBlock struct {
+	Type     string      `json:"type"`
+	Text     *SlackText  `json:"text,omitempty"`
+	Fields   []SlackText `json:"fields,omitempty"`
+	Elements []SlackText `json:"elements,omitempty"`
+}
+
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ScraperSource describes a source a scraper step can name
+type ScraperSource struct {
+	Name      string `json:"name"`
+	Category  string `json:"category"`
+	Streaming bool   `json:"streaming"` // Fetched page by page; large limits are cheap
+}
+
+// ScraperList is the catalogue of scraper sources, for building step configs
+type ScraperList struct {
+	Sources    []ScraperSource     `json:"sources"`
+	Categories map[string][]string `json:"categories"`
+}
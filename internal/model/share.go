@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// TaskShare is a read-only public link to a task's delivered items
+type TaskShare struct {
+	Token     string     `json:"token" db:"token"`
+	TaskID    string     `json:"task_id" db:"task_id"`
+	CreatedBy string     `json:"created_by" db:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateTaskShareRequest struct {
+	ExpiresInHours int `json:"expires_in_hours,omitempty"` // 0 means the link never expires
+}
+
+// DeliveredItem is an item that reached a delivery step
+type DeliveredItem struct {
+	Title       string    `json:"title" db:"title"`
+	URL         string    `json:"url,omitempty" db:"url"`
+	Source      string    `json:"source,omitempty" db:"source"`
+	Category    string    `json:"category,omitempty" db:"category"`
+	DeliveredAt time.Time `json:"delivered_at" db:"delivered_at"`
+}
+
+// SharedTask is the public view of a task behind a share link. It never
+// includes the pipeline, config or secrets.
+type SharedTask struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	LastRunAt   *time.Time      `json:"last_run_at,omitempty"`
+	Items       []DeliveredItem `json:"items"`
+}
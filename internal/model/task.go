@@ -13,30 +13,77 @@ const (
 	TaskStatusEnabled  TaskStatus = "enabled"
 	TaskStatusDisabled TaskStatus = "disabled"
 	TaskStatusRunning  TaskStatus = "running"
+	TaskStatusArchived TaskStatus = "archived" // Deleted without losing its executions; restorable
 )
 
 type Task struct {
-	ID          string         `json:"id" db:"id"`
-	Name        string         `json:"name" db:"name"`
-	Description string         `json:"description" db:"description"`
-	Schedule    string         `json:"schedule" db:"schedule"` // Cron expression
-	Status      TaskStatus     `json:"status" db:"status"`
-	Pipeline    PipelineSteps  `json:"pipeline" db:"pipeline"`
-	LastRunAt   *time.Time     `json:"last_run_at,omitempty" db:"last_run_at"`
-	NextRunAt   *time.Time     `json:"next_run_at,omitempty" db:"next_run_at"`
-	CreatedBy   string         `json:"created_by" db:"created_by"`
-	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+	ID             string        `json:"id" db:"id"`
+	Name           string        `json:"name" db:"name"`
+	Description    string        `json:"description" db:"description"`
+	Schedule       string        `json:"schedule" db:"schedule"`             // Cron expression
+	Schedules      ScheduleList  `json:"schedules,omitempty" db:"schedules"` // Extra cron expressions, each firing the task
+	Timezone       string        `json:"timezone,omitempty" db:"timezone"`   // IANA name; empty means server local time
+	Status         TaskStatus    `json:"status" db:"status"`
+	Pipeline       PipelineSteps `json:"pipeline" db:"pipeline"`
+	TimeoutSeconds int           `json:"timeout_seconds,omitempty" db:"timeout_seconds"` // 0 uses the scheduler default
+	AllowOverlap   bool          `json:"allow_overlap,omitempty" db:"allow_overlap"`     // Start runs even while the previous one is going
+	CatchUp        bool          `json:"catch_up,omitempty" db:"catch_up"`               // Run once on startup if a fire time was missed while down
+	LastRunAt      *time.Time    `json:"last_run_at,omitempty" db:"last_run_at"`
+	NextRunAt      *time.Time    `json:"next_run_at,omitempty" db:"next_run_at"`
+	WebhookError   string        `json:"webhook_error,omitempty" db:"webhook_error"`     // Set when the task's Discord webhook failed re-verification
+	TriggerWebhook bool          `json:"trigger_webhook,omitempty" db:"trigger_webhook"` // An inbound webhook secret can run the task
+	CreatedBy      string        `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
 }
 
 type PipelineStep struct {
-	Type   string                 `json:"type"`
-	Name   string                 `json:"name,omitempty"`
-	Config map[string]interface{} `json:"config"`
+	Type          string                 `json:"type"`
+	Name          string                 `json:"name,omitempty"`
+	Config        map[string]interface{} `json:"config"`
+	Debug         bool                   `json:"debug,omitempty"`          // Store a redacted snapshot of the step's output data
+	CaptureOutput bool                   `json:"capture_output,omitempty"` // Keep the full output so later steps can be resumed from it
 }
 
 type PipelineSteps []PipelineStep
 
+// ScheduleList is a set of cron expressions stored as a JSON array
+type ScheduleList []string
+
+func (s ScheduleList) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *ScheduleList) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// AllSchedules returns every cron expression the task runs on: schedule
+// followed by schedules, without blanks or repeats
+func (t Task) AllSchedules() []string {
+	var all []string
+	seen := make(map[string]bool)
+	for _, expr := range append([]string{t.Schedule}, t.Schedules...) {
+		if expr == "" || seen[expr] {
+			continue
+		}
+		seen[expr] = true
+		all = append(all, expr)
+	}
+	return all
+}
+
 func (p PipelineSteps) Value() (driver.Value, error) {
 	return json.Marshal(p)
 }
@@ -54,16 +101,65 @@ func (p *PipelineSteps) Scan(value interface{}) error {
 }
 
 type CreateTaskRequest struct {
-	Name        string         `json:"name" validate:"required,min=3,max=100"`
-	Description string         `json:"description" validate:"max=500"`
-	Schedule    string         `json:"schedule" validate:"required"`
-	Pipeline    []PipelineStep `json:"pipeline" validate:"required,min=1"`
+	Name           string         `json:"name" validate:"required,min=3,max=100"`
+	Description    string         `json:"description" validate:"max=500"`
+	Schedule       string         `json:"schedule"`            // Required unless schedules is set
+	Schedules      []string       `json:"schedules,omitempty"` // Extra cron expressions, e.g. ["0 9 * * *", "0 17 * * *"]
+	Timezone       string         `json:"timezone,omitempty"`
+	Pipeline       []PipelineStep `json:"pipeline" validate:"required,min=1"`
+	TimeoutSeconds int            `json:"timeout_seconds,omitempty" validate:"omitempty,min=0"`
+	AllowOverlap   bool           `json:"allow_overlap,omitempty"`
+	CatchUp        bool           `json:"catch_up,omitempty"`
 }
 
 type UpdateTaskRequest struct {
-	Name        *string        `json:"name,omitempty" validate:"omitempty,min=3,max=100"`
-	Description *string        `json:"description,omitempty" validate:"omitempty,max=500"`
-	Schedule    *string        `json:"schedule,omitempty"`
-	Status      *TaskStatus    `json:"status,omitempty"`
-	Pipeline    []PipelineStep `json:"pipeline,omitempty"`
+	Name           *string        `json:"name,omitempty" validate:"omitempty,min=3,max=100"`
+	Description    *string        `json:"description,omitempty" validate:"omitempty,max=500"`
+	Schedule       *string        `json:"schedule,omitempty"`
+	Schedules      []string       `json:"schedules,omitempty"` // Replaces the extra schedules; [] clears them
+	Timezone       *string        `json:"timezone,omitempty"`
+	Status         *TaskStatus    `json:"status,omitempty"`
+	Pipeline       []PipelineStep `json:"pipeline,omitempty"`
+	TimeoutSeconds *int           `json:"timeout_seconds,omitempty" validate:"omitempty,min=0"`
+	AllowOverlap   *bool          `json:"allow_overlap,omitempty"`
+	CatchUp        *bool          `json:"catch_up,omitempty"`
+}
+
+// Bulk task actions
+const (
+	BulkActionEnable  = "enable"
+	BulkActionDisable = "disable"
+	BulkActionDelete  = "delete"
+	BulkActionTrigger = "trigger"
+)
+
+// BulkTaskRequest applies one action to several tasks
+type BulkTaskRequest struct {
+	Action  string   `json:"action"` // enable, disable, delete or trigger
+	TaskIDs []string `json:"task_ids"`
+	Hard    bool     `json:"hard,omitempty"` // With delete: remove the tasks and their executions instead of archiving them
+}
+
+// BulkTaskResult reports the outcome of a bulk action for one task
+type BulkTaskResult struct {
+	TaskID      string `json:"task_id"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+	ExecutionID string `json:"execution_id,omitempty"` // Set for trigger
+}
+
+// BulkTaskResponse lists per-task results so partial failures are visible
+type BulkTaskResponse struct {
+	Action    string           `json:"action"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkTaskResult `json:"results"`
+}
+
+// NextRuns previews when a task's schedules will fire
+type NextRuns struct {
+	TaskID    string      `json:"task_id"`
+	Timezone  string      `json:"timezone"`  // Zone the schedules are evaluated in
+	Schedules []string    `json:"schedules"` // Normalized six-field expressions, seconds first
+	Runs      []time.Time `json:"runs"`
 }
@@ -33,13 +33,71 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token     string `json:"token"`
-	ExpiresAt int64  `json:"expires_at"`
-	User      *User  `json:"user"`
+	Token            string `json:"token"`
+	ExpiresAt        int64  `json:"expires_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt int64  `json:"refresh_expires_at"`
+	User             *User  `json:"user"`
+}
+
+// RefreshRequest carries a refresh token to exchange or revoke
+// SetUserActiveRequest enables or disables a user account
+type SetUserActiveRequest struct {
+	IsActive *bool `json:"is_active"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ForgotPasswordRequest asks for a password reset token to be mailed
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest sets a new password with a mailed reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ChangePasswordRequest replaces the caller's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+	// RevokeSessions logs out every other session by revoking all refresh
+	// tokens; the response then carries a fresh token pair
+	RevokeSessions bool `json:"revoke_sessions"`
+}
+
+// ChangePasswordResponse confirms a password change
+type ChangePasswordResponse struct {
+	Message string         `json:"message"`
+	Session *LoginResponse `json:"session,omitempty"` // New tokens when sessions were revoked
 }
 
 type TokenClaims struct {
 	UserID string   `json:"user_id"`
 	Email  string   `json:"email"`
 	Role   UserRole `json:"role"`
+	Scopes []string `json:"scopes,omitempty"`  // Set for scoped API keys; nil means full access
+	TaskID string   `json:"task_id,omitempty"` // Set when a scoped API key is restricted to one task
+}
+
+// Scoped reports whether the claims come from a scoped API key
+func (c *TokenClaims) Scoped() bool {
+	return c.Scopes != nil
+}
+
+// HasScope reports whether the claims grant a scope; full access grants all
+func (c *TokenClaims) HasScope(scope string) bool {
+	if !c.Scoped() {
+		return true
+	}
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,171 @@
+// Package netguard keeps requests to user-configured URLs, such as webhooks,
+// from reaching the server's own network
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+)
+
+// ErrInternalAddress is returned for URLs and connections that would reach a
+// private, loopback, link-local, unspecified or reserved address
+var ErrInternalAddress = errors.New("private or loopback addresses are not allowed")
+
+// discordHosts serve Discord webhooks; their subdomains (ptb., canary.) too
+var discordHosts = []string{"discord.com", "discordapp.com"}
+
+// Guard checks outbound URLs against the configured allowlist
+type Guard struct {
+	allowed []string // Lowercased host names from OUTBOUND_ALLOWED_HOSTS
+}
+
+// New creates a guard allowing cfg's hosts
+func New(cfg config.OutboundConfig) *Guard {
+	g := &Guard{}
+	for _, host := range cfg.AllowedHosts {
+		g.allowed = append(g.allowed, strings.ToLower(strings.TrimSuffix(host, ".")))
+	}
+	return g
+}
+
+// CheckDiscordWebhook requires an https URL on a Discord host or an
+// allowlisted one
+func (g *Guard) CheckDiscordWebhook(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return errors.New("webhook URL must be an absolute https URL")
+	}
+	host := strings.ToLower(u.Hostname())
+	if !matchHost(host, discordHosts) && !g.Allowed(host) {
+		return fmt.Errorf("webhook URL must be on discord.com or discordapp.com, not %s", host)
+	}
+	return nil
+}
+
+// CheckURL requires an absolute http(s) URL that doesn't name an internal
+// address, unless its host is allowlisted. Host names are resolved when the
+// request is made, where Client refuses internal addresses.
+func (g *Guard) CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("URL must be an absolute http(s) URL")
+	}
+	host := strings.ToLower(u.Hostname())
+	if g.Allowed(host) {
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrInternalAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && internal(ip) {
+		return ErrInternalAddress
+	}
+	return nil
+}
+
+// Allowed reports whether host, or a domain it belongs to, is allowlisted
+func (g *Guard) Allowed(host string) bool {
+	return matchHost(strings.ToLower(host), g.allowed)
+}
+
+// Client returns an HTTP client that refuses to connect to internal
+// addresses, checked after DNS resolution and on every redirect, except
+// for allowlisted hosts
+func (g *Guard) Client(timeout time.Duration) *http.Client {
+	open := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	guarded := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: refuseInternal}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(addr); err == nil && g.Allowed(host) {
+			return open.DialContext(ctx, network, addr)
+		}
+		return guarded.DialContext(ctx, network, addr)
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// refuseInternal is a dialer Control func, seeing the resolved address
+func refuseInternal(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip != nil && internal(ip) {
+		return fmt.Errorf("%w: %s", ErrInternalAddress, host)
+	}
+	return nil
+}
+
+// blockedNets are shared, reserved and translation ranges the net.IP
+// predicates don't cover. NAT64 and the other IPv6 forms that carry an
+// IPv4 address are blocked whole rather than trusting what they embed.
+var blockedNets = parseCIDRs(
+	"0.0.0.0/8",       // "This" network
+	"100.64.0.0/10",   // Carrier-grade NAT
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // TEST-NET-1
+	"192.88.99.0/24",  // 6to4 relay anycast
+	"198.18.0.0/15",   // Benchmarking
+	"198.51.100.0/24", // TEST-NET-2
+	"203.0.113.0/24",  // TEST-NET-3
+	"224.0.0.0/4",     // Multicast
+	"240.0.0.0/4",     // Reserved, including broadcast
+	"::/96",           // IPv4-compatible
+	"64:ff9b::/96",    // NAT64
+	"64:ff9b:1::/48",  // Local-use NAT64
+	"100::/64",        // Discard-only
+	"2001::/32",       // Teredo
+	"2001:db8::/32",   // Documentation
+	"2002::/16",       // 6to4
+	"ff00::/8",        // Multicast
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
+
+// internal reports whether ip reaches anything but the public internet.
+// IPv4-mapped addresses (::ffff:a.b.c.d) are checked as the IPv4 address.
+func internal(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return true
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHost reports whether host is one of hosts or a subdomain of one
+func matchHost(host string, hosts []string) bool {
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package netguard
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+)
+
+func TestInternal(t *testing.T) {
+	for _, tc := range []struct {
+		ip       string
+		internal bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"169.254.169.254", true},
+		{"0.1.2.3", true},
+		{"100.64.0.1", true},
+		{"100.127.255.254", true},
+		{"192.0.0.8", true},
+		{"192.0.2.10", true},
+		{"198.18.0.1", true},
+		{"198.19.255.255", true},
+		{"198.51.100.7", true},
+		{"203.0.113.9", true},
+		{"224.0.0.251", true},
+		{"240.0.0.1", true},
+		{"255.255.255.255", true},
+		{"::1", true},
+		{"::", true},
+		{"fd00::1", true},
+		{"fe80::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:100.64.0.1", true},
+		{"::127.0.0.1", true},
+		{"64:ff9b::7f00:1", true},
+		{"64:ff9b::a9fe:a9fe", true},
+		{"64:ff9b:1::1", true},
+		{"2002:7f00:1::", true},
+		{"2001:db8::1", true},
+		{"2001:0:4136:e378::1", true},
+		{"ff02::1", true},
+
+		{"8.8.8.8", false},
+		{"100.63.255.255", false},
+		{"100.128.0.1", false},
+		{"198.20.0.1", false},
+		{"::ffff:1.1.1.1", false},
+		{"2606:4700:4700::1111", false},
+	} {
+		if got := internal(net.ParseIP(tc.ip)); got != tc.internal {
+			t.Errorf("internal(%s) = %v, want %v", tc.ip, got, tc.internal)
+		}
+	}
+}
+
+func TestCheckURLRefusesReservedAddresses(t *testing.T) {
+	g := New(config.OutboundConfig{AllowedHosts: []string{"100.64.0.5"}})
+	for _, raw := range []string{
+		"http://100.64.0.1/hook",
+		"https://[64:ff9b::a00:1]/hook",
+		"http://[::ffff:10.0.0.1]/hook",
+		"http://198.18.0.1:8080/",
+	} {
+		if err := g.CheckURL(raw); !errors.Is(err, ErrInternalAddress) {
+			t.Errorf("CheckURL(%s) = %v, want ErrInternalAddress", raw, err)
+		}
+	}
+	if err := g.CheckURL("http://100.64.0.5/hook"); err != nil {
+		t.Errorf("allowlisted address refused: %v", err)
+	}
+}
+
+func TestClientRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, err := New(config.OutboundConfig{}).Client(time.Second).Get(server.URL)
+	if !errors.Is(err, ErrInternalAddress) {
+		t.Errorf("request to %s = %v, want ErrInternalAddress", server.URL, err)
+	}
+
+	resp, err := New(config.OutboundConfig{AllowedHosts: []string{"127.0.0.1"}}).Client(time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("allowlisted request failed: %v", err)
+	}
+	resp.Body.Close()
+}
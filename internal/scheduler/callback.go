@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+)
+
+// callbackTimeout bounds each callback so a slow dashboard can't pile up
+// goroutines
+const callbackTimeout = 5 * time.Second
+
+// ExecutionCallback posts a compact summary of every finished execution,
+// successful or not, to a global URL such as an external dashboard
+type ExecutionCallback struct {
+	url    string
+	client *http.Client
+
+	sending sync.WaitGroup // Summaries still being posted, for Flush
+}
+
+// executionSummary is posted as JSON
+type executionSummary struct {
+	ExecutionID string                `json:"execution_id"`
+	TaskID      string                `json:"task_id"`
+	TaskName    string                `json:"task_name"`
+	Status      model.ExecutionStatus `json:"status"`
+	TriggerType model.TriggerType     `json:"trigger_type"`
+	StartedAt   time.Time             `json:"started_at"`
+	FinishedAt  *time.Time            `json:"finished_at,omitempty"`
+	DurationMs  *int64                `json:"duration_ms,omitempty"`
+	Error       *string               `json:"error,omitempty"`
+	Steps       []stepSummary         `json:"steps"`
+}
+
+type stepSummary struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	ItemCount *int   `json:"item_count,omitempty"` // Items the step produced, when it completed
+}
+
+// NewExecutionCallback creates an execution callback, or returns nil when no URL is configured
+func NewExecutionCallback(cfg config.NotificationConfig) *ExecutionCallback {
+	if cfg.ExecutionCallbackURL == "" {
+		return nil
+	}
+	return &ExecutionCallback{
+		url:    cfg.ExecutionCallbackURL,
+		client: &http.Client{Timeout: callbackTimeout},
+	}
+}
+
+// Send posts the execution's summary in the background; failures are only logged
+func (c *ExecutionCallback) Send(execution *model.Execution) {
+	if c == nil || execution == nil {
+		return
+	}
+
+	summary := executionSummary{
+		ExecutionID: execution.ID,
+		TaskID:      execution.TaskID,
+		TaskName:    execution.TaskName,
+		Status:      execution.Status,
+		TriggerType: execution.TriggerType,
+		StartedAt:   execution.StartedAt,
+		FinishedAt:  execution.FinishedAt,
+		DurationMs:  execution.Duration,
+		Error:       execution.Error,
+		Steps:       make([]stepSummary, 0, len(execution.StepResults)),
+	}
+	for _, step := range execution.StepResults {
+		summary.Steps = append(summary.Steps, stepSummary{
+			Name:      step.StepName,
+			Type:      step.StepType,
+			Status:    step.Status,
+			ItemCount: stepItemCount(step),
+		})
+	}
+
+	c.sending.Add(1)
+	go func() {
+		defer c.sending.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), callbackTimeout)
+		defer cancel()
+		if err := c.send(ctx, summary); err != nil {
+			log.Printf("Warning: execution callback for %s failed: %v", summary.ExecutionID, err)
+		}
+	}()
+}
+
+// Flush waits for summaries still being posted, giving up when ctx ends
+func (c *ExecutionCallback) Flush(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	if err := waitGroupContext(ctx, &c.sending); err != nil {
+		return fmt.Errorf("execution callbacks still pending: %w", err)
+	}
+	return nil
+}
+
+func (c *ExecutionCallback) send(ctx context.Context, summary executionSummary) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// stepItemCount reads the item count recorded in a completed step's output.
+// Outputs read back from the database hold it as a float64.
+func stepItemCount(step model.StepResult) *int {
+	output, ok := step.Output.(map[string]interface{})
+	if !ok {
+		if step.Status == "completed" {
+			// Completed without output data, e.g. "No new items found"
+			zero := 0
+			return &zero
+		}
+		return nil
+	}
+
+	var count int
+	switch v := output["item_count"].(type) {
+	case int:
+		count = v
+	case float64:
+		count = int(v)
+	default:
+		return nil
+	}
+	return &count
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+)
+
+func TestFlushWaitsForQueuedDeliveries(t *testing.T) {
+	var callbacks, notifications atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Slow enough that the posts are still queued when Flush starts
+		time.Sleep(100 * time.Millisecond)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["steps"]; ok {
+			callbacks.Add(1)
+		} else {
+			notifications.Add(1)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.NotificationConfig{ErrorWebhook: server.URL, ExecutionCallbackURL: server.URL, ErrorThrottle: time.Minute}
+	runner := &PipelineRunner{notifier: NewErrorNotifier(cfg), callback: NewExecutionCallback(cfg)}
+
+	task := model.Task{ID: "task-1", Name: "Jobs"}
+	runner.notifier.Notify(task, "exec-1", nil, errInterrupted)
+	runner.callback.Send(&model.Execution{ID: "exec-1", TaskID: task.ID, Status: model.ExecutionStatusFailed})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := runner.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if callbacks.Load() != 1 || notifications.Load() != 1 {
+		t.Errorf("delivered %d callbacks and %d notifications by the end of Flush, want 1 each", callbacks.Load(), notifications.Load())
+	}
+}
+
+func TestFlushGivesUpAtDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	callback := NewExecutionCallback(config.NotificationConfig{ExecutionCallbackURL: server.URL})
+	callback.Send(&model.Execution{ID: "exec-1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err := callback.Flush(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Flush = %v, want the deadline error", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Flush took %v past a 50ms deadline", elapsed)
+	}
+}
+
+func TestFlushWithoutConfiguredURLs(t *testing.T) {
+	runner := &PipelineRunner{}
+	if err := runner.Flush(context.Background()); err != nil {
+		t.Errorf("Flush with nothing configured = %v", err)
+	}
+}
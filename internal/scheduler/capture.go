@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// Kinds of captured data; executors switch on the concrete Go type of their
+// input, so it has to be restored exactly when an execution is resumed
+const (
+	captureKindScraped = "scraped_items"
+	captureKindRSS     = "rss_items"
+	captureKindText    = "text"
+	captureKindRaw     = "raw"
+)
+
+// capturedOutput is the stored form of a step's result
+type capturedOutput struct {
+	Kind      string                 `json:"kind"`
+	Data      json.RawMessage        `json:"data"`
+	ItemCount int                    `json:"item_count"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func encodeCapturedOutput(result *model.ExecutorResult) ([]byte, error) {
+	kind := captureKindRaw
+	switch result.Data.(type) {
+	case []model.ScrapedItem:
+		kind = captureKindScraped
+	case []model.RSSItem:
+		kind = captureKindRSS
+	case string:
+		kind = captureKindText
+	}
+
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(capturedOutput{
+		Kind:      kind,
+		Data:      data,
+		ItemCount: result.ItemCount,
+		Metadata:  result.Metadata,
+	})
+}
+
+func decodeCapturedOutput(raw []byte) (*model.ExecutorResult, error) {
+	var captured capturedOutput
+	if err := json.Unmarshal(raw, &captured); err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	var err error
+	switch captured.Kind {
+	case captureKindScraped:
+		var items []model.ScrapedItem
+		err = json.Unmarshal(captured.Data, &items)
+		data = items
+	case captureKindRSS:
+		var items []model.RSSItem
+		err = json.Unmarshal(captured.Data, &items)
+		data = items
+	case captureKindText:
+		var text string
+		err = json.Unmarshal(captured.Data, &text)
+		data = text
+	default:
+		err = json.Unmarshal(captured.Data, &data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ExecutorResult{
+		Data:      data,
+		ItemCount: captured.ItemCount,
+		Metadata:  captured.Metadata,
+	}, nil
+}
+
+// captureOutput stores a step's full result so the execution can later be
+// resumed from the step after it. Failures are logged, not fatal to the run.
+func (r *PipelineRunner) captureOutput(ctx context.Context, execID string, stepIndex int, result *model.ExecutorResult) {
+	encoded, err := encodeCapturedOutput(result)
+	if err != nil {
+		log.Printf("Warning: failed to encode output of step %d: %v", stepIndex+1, err)
+		return
+	}
+	if err := r.execRepo.SaveCapturedOutput(ctx, execID, stepIndex, encoded); err != nil {
+		log.Printf("Warning: failed to capture output of step %d: %v", stepIndex+1, err)
+	}
+}
+
+// CapturedOutput returns the output captured for a step of an execution, or
+// nil if that step's output was not captured
+func (r *PipelineRunner) CapturedOutput(ctx context.Context, execID string, stepIndex int) (*model.ExecutorResult, error) {
+	raw, err := r.execRepo.GetCapturedOutput(ctx, execID, stepIndex)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	result, err := decodeCapturedOutput(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode captured output: %w", err)
+	}
+	return result, nil
+}
+
+// LatestOutput returns the last output captured by the task's most recent
+// completed execution that captured any, with that execution's ID, or nil
+// if none did
+func (r *PipelineRunner) LatestOutput(ctx context.Context, taskID string) (*model.ExecutorResult, string, error) {
+	execID, raw, err := r.execRepo.LatestCapturedOutput(ctx, taskID)
+	if err != nil {
+		return nil, "", err
+	}
+	if execID == "" {
+		return nil, "", nil
+	}
+
+	result, err := decodeCapturedOutput(raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode captured output: %w", err)
+	}
+	return result, execID, nil
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+const (
+	// maxDebugItems is how many items of a list result are kept in a snapshot
+	maxDebugItems = 5
+	// maxDebugBytes caps the encoded snapshot stored in executions.step_results
+	maxDebugBytes = 8 * 1024
+)
+
+var (
+	sensitiveKeyPattern   = regexp.MustCompile(`(?i)webhook|token|secret|password|api_?key|authorization`)
+	sensitiveValuePattern = regexp.MustCompile(`(?i)https?://\S*(?:/api/webhooks/|hooks\.slack\.com/)\S*|\bBearer\s+\S+`)
+)
+
+// debugSnapshot returns a size-capped, redacted copy of a step's output data
+// for storing alongside the step result
+func debugSnapshot(data interface{}) interface{} {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return map[string]interface{}{"error": "output is not JSON-encodable"}
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return map[string]interface{}{"error": "output is not JSON-encodable"}
+	}
+	generic = redact(generic)
+
+	items, isList := generic.([]interface{})
+	if !isList {
+		return capSnapshot(generic)
+	}
+
+	total := len(items)
+	if len(items) > maxDebugItems {
+		items = items[:maxDebugItems]
+	}
+
+	// Drop trailing items until the snapshot fits
+	for ; len(items) > 0; items = items[:len(items)-1] {
+		snapshot := map[string]interface{}{"items": items, "total_items": total}
+		if encoded, _ := json.Marshal(snapshot); len(encoded) <= maxDebugBytes {
+			return snapshot
+		}
+	}
+
+	return capSnapshot(map[string]interface{}{"items": generic, "total_items": total})
+}
+
+// capSnapshot falls back to a truncated JSON string when a value is too large
+func capSnapshot(value interface{}) interface{} {
+	encoded, _ := json.Marshal(value)
+	if len(encoded) <= maxDebugBytes {
+		return value
+	}
+	return map[string]interface{}{
+		"truncated": true,
+		"json":      string(encoded[:maxDebugBytes]),
+	}
+}
+
+// redact blanks out fields whose name or value looks like a credential
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if sensitiveKeyPattern.MatchString(k) {
+				v[k] = "[redacted]"
+				continue
+			}
+			v[k] = redact(item)
+		}
+		return v
+
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redact(item)
+		}
+		return v
+
+	case string:
+		return sensitiveValuePattern.ReplaceAllString(v, "[redacted]")
+
+	default:
+		return value
+	}
+}
@@ -0,0 +1,233 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/multi-worker/internal/executor/filter"
+	"github.com/multi-worker/internal/model"
+	"github.com/robfig/cron/v3"
+)
+
+// digestOptions say when a digest step flushes the items it holds
+type digestOptions struct {
+	flushCount    int           // Flush once this many items are held; 0 means no count
+	flushSchedule cron.Schedule // Flush once a fire time has passed since the oldest item was held
+}
+
+// digestConfig reads flush_count and flush_schedule, at least one of which
+// is required. The schedule is a cron expression in the task's timezone.
+func digestConfig(config map[string]interface{}) (digestOptions, error) {
+	var opts digestOptions
+	if raw, ok := config["flush_count"]; ok {
+		n, ok := raw.(float64)
+		if !ok || n < 1 || n != float64(int(n)) {
+			return opts, errors.New("digest 'flush_count' must be a positive whole number")
+		}
+		opts.flushCount = int(n)
+	}
+	if raw, ok := config["flush_schedule"]; ok {
+		expr, ok := raw.(string)
+		if !ok || expr == "" {
+			return opts, errors.New("digest 'flush_schedule' must be a cron expression")
+		}
+		timezone, _ := config["task_timezone"].(string)
+		spec, err := cronSpec(expr, timezone)
+		if err != nil {
+			return opts, fmt.Errorf("invalid digest 'flush_schedule': %w", err)
+		}
+		opts.flushSchedule, err = cronParser.Parse(spec)
+		if err != nil {
+			return opts, fmt.Errorf("invalid digest 'flush_schedule': %w", err)
+		}
+	}
+	if opts.flushCount == 0 && opts.flushSchedule == nil {
+		return opts, errors.New("digest step requires 'flush_count', 'flush_schedule' or both")
+	}
+	return opts, nil
+}
+
+// digestFlush is what a digest step passed on, to be marked sent once
+// the pipeline has delivered it
+type digestFlush struct {
+	stepName  string
+	throughID int64 // The newest item flushed
+}
+
+// markDigestsSent marks the items flushed by a task's digest steps as sent.
+// Delivery already happened, so a failure only means they are sent again.
+func (r *PipelineRunner) markDigestsSent(ctx context.Context, taskID string, flushes []digestFlush) {
+	for _, flush := range flushes {
+		if _, err := r.cacheRepo.MarkDigestSent(ctx, taskID, flush.stepName, flush.throughID); err != nil {
+			log.Printf("Warning: failed to mark digest items sent for task %s: %v", taskID, err)
+		}
+	}
+}
+
+// executeDigest holds the input's items for the task and passes on
+// everything held once a flush is due. The items stay held until the
+// runner marks them sent after delivery, so a failed delivery flushes them
+// again on the next run. Until a flush is due the pipeline ends quietly at
+// this step. Without a task, as in a dry run, the input passes through
+// unchanged and the flush is nil.
+func (r *PipelineRunner) executeDigest(ctx context.Context, step model.PipelineStep, input *model.ExecutorResult) (*model.ExecutorResult, *digestFlush, error) {
+	opts, err := digestConfig(step.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	taskID, _ := step.Config["task_id"].(string)
+	if taskID == "" || r.cacheRepo == nil {
+		return input, nil, nil
+	}
+
+	items, err := digestItems(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	added, err := r.cacheRepo.HoldDigestItems(ctx, taskID, step.Name, items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	held, oldest, err := r.cacheRepo.PendingDigest(ctx, taskID, step.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if held == 0 {
+		return nil, nil, filter.NewSkipPipelineError("digest holds no items")
+	}
+	countDue := opts.flushCount > 0 && held >= opts.flushCount
+	scheduleDue := opts.flushSchedule != nil && !opts.flushSchedule.Next(*oldest).After(time.Now())
+	if !countDue && !scheduleDue {
+		return nil, nil, filter.NewSkipPipelineError(fmt.Sprintf("digest holds %d items (%d new), not due to flush", held, added))
+	}
+
+	flushed, err := r.cacheRepo.HeldDigest(ctx, taskID, step.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(flushed) == 0 {
+		return nil, nil, filter.NewSkipPipelineError("digest holds no items")
+	}
+	data, count, err := digestData(flushed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadata := map[string]interface{}{
+		"digest_items": count,
+		"new_items":    added,
+	}
+	metadata["held_since"] = flushed[0].CreatedAt
+	return &model.ExecutorResult{
+		Data:      data,
+		ItemCount: count,
+		Metadata:  metadata,
+	}, &digestFlush{stepName: step.Name, throughID: flushed[len(flushed)-1].ID}, nil
+}
+
+// digestItems encodes the input's scraped or RSS items for storage
+func digestItems(input *model.ExecutorResult) ([]model.DigestItem, error) {
+	if filter.SkipEmpty(input) {
+		return nil, nil
+	}
+
+	var items []model.DigestItem
+	add := func(kind, key string, item interface{}) error {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if key == "" {
+			key = string(data)
+		}
+		hash := sha256.Sum256([]byte(kind + "\n" + key))
+		items = append(items, model.DigestItem{Key: hex.EncodeToString(hash[:]), Kind: kind, Item: data})
+		return nil
+	}
+
+	switch v := input.Data.(type) {
+	case []model.ScrapedItem:
+		for _, item := range v {
+			key := item.URL
+			if key == "" && item.ID != "" {
+				key = item.Source + ":" + item.ID
+			}
+			if err := add(model.DigestKindScraped, key, item); err != nil {
+				return nil, err
+			}
+		}
+	case []model.RSSItem:
+		for _, item := range v {
+			key := item.Link
+			if key == "" {
+				key = item.ID
+			}
+			if err := add(model.DigestKindRSS, key, item); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("digest step needs scraped or RSS items, got %T", input.Data)
+	}
+	return items, nil
+}
+
+// digestData decodes flushed items: RSS items when all of them are, else
+// scraped items, converting any RSS items held before the pipeline changed
+func digestData(flushed []model.DigestItem) (interface{}, int, error) {
+	allRSS := true
+	for _, held := range flushed {
+		if held.Kind != model.DigestKindRSS {
+			allRSS = false
+			break
+		}
+	}
+
+	if allRSS {
+		items := make([]model.RSSItem, 0, len(flushed))
+		for _, held := range flushed {
+			var item model.RSSItem
+			if err := json.Unmarshal(held.Item, &item); err != nil {
+				return nil, 0, fmt.Errorf("invalid digest item: %w", err)
+			}
+			items = append(items, item)
+		}
+		return items, len(items), nil
+	}
+
+	items := make([]model.ScrapedItem, 0, len(flushed))
+	for _, held := range flushed {
+		if held.Kind == model.DigestKindRSS {
+			var item model.RSSItem
+			if err := json.Unmarshal(held.Item, &item); err != nil {
+				return nil, 0, fmt.Errorf("invalid digest item: %w", err)
+			}
+			items = append(items, item.ToScrapedItem())
+			continue
+		}
+		var item model.ScrapedItem
+		if err := json.Unmarshal(held.Item, &item); err != nil {
+			return nil, 0, fmt.Errorf("invalid digest item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, len(items), nil
+}
+
+// digestAfter reports whether a digest step follows step i. Empty results
+// still run on to it, so a scheduled flush isn't missed.
+func digestAfter(pipeline []model.PipelineStep, i int) bool {
+	for _, step := range pipeline[i+1:] {
+		if step.Type == "digest" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+func TestDigestKeepsItemsHeldUntilDelivered(t *testing.T) {
+	db := storagetest.Open(t)
+	runner := newTestRunner(t, db)
+	ctx := context.Background()
+
+	var down atomic.Bool
+	var delivered atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		delivered.Add(1)
+	}))
+	defer server.Close()
+
+	user := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, user.ID,
+		staticStep("first", "second"),
+		model.PipelineStep{Type: "digest", Config: map[string]interface{}{"flush_count": float64(2)}},
+		model.PipelineStep{Type: "webhook", Config: map[string]interface{}{"url": server.URL}},
+	)
+
+	held := func() int {
+		t.Helper()
+		n, _, err := runner.cacheRepo.PendingDigest(ctx, task.ID, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	down.Store(true)
+	if _, err := runner.Run(ctx, *task, "test", RunOptions{}); err == nil {
+		t.Fatal("run succeeded, want the delivery to fail")
+	}
+	if n := held(); n != 2 {
+		t.Fatalf("digest holds %d items after a failed delivery, want 2", n)
+	}
+
+	down.Store(false)
+	if _, err := runner.Run(ctx, *task, "test", RunOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if delivered.Load() != 1 {
+		t.Errorf("delivered %d times, want once", delivered.Load())
+	}
+	if n := held(); n != 0 {
+		t.Errorf("digest holds %d items after delivery, want none", n)
+	}
+}
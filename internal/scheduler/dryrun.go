@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/multi-worker/internal/executor/filter"
+	"github.com/multi-worker/internal/model"
+)
+
+// DryRunStep is the outcome of one step of a dry run
+type DryRunStep struct {
+	StepName   string      `json:"step_name"`
+	StepType   string      `json:"step_type"`
+	Status     string      `json:"status"`
+	ItemCount  int         `json:"item_count"`
+	DurationMs int64       `json:"duration_ms"`
+	Error      string      `json:"error,omitempty"`
+	Output     interface{} `json:"output,omitempty"`
+	WouldSend  interface{} `json:"would_send,omitempty"` // delivery steps only
+}
+
+// DryRunResult is the outcome of a dry run
+type DryRunResult struct {
+	Status string       `json:"status"`
+	Steps  []DryRunStep `json:"steps"`
+	Error  string       `json:"error,omitempty"`
+	Data   interface{}  `json:"data,omitempty"` // preview of the data the pipeline ended with
+}
+
+// DryRun executes a pipeline that doesn't belong to a task. No execution is
+// recorded, items are neither deduplicated nor marked as seen, and delivery
+// steps only report what they would send, passing their input on unchanged.
+// AI steps may reuse cached completions but don't cache new ones. Run
+// settings such as task_id in a step's config are ignored.
+func (r *PipelineRunner) DryRun(ctx context.Context, pipeline []model.PipelineStep) *DryRunResult {
+	ctx, cancel := context.WithTimeout(ctx, defaultExecutionTimeout)
+	defer cancel()
+
+	result := &DryRunResult{Status: "completed"}
+	var current *model.ExecutorResult
+	outputs := make(map[string]*model.ExecutorResult)
+
+	for i, step := range pipeline {
+		stepName := step.Name
+		if stepName == "" {
+			stepName = fmt.Sprintf("Step %d: %s", i+1, step.Type)
+		}
+		stepResult := DryRunStep{StepName: stepName, StepType: step.Type}
+		started := time.Now()
+
+		// resolveSecrets copies the config, so the caller's pipeline is never
+		// modified; there is no task, so secret references can't be resolved
+		output := current
+		config, err := resolveSecrets(step.Config, nil)
+		if err == nil {
+			// Run settings only the runner may set: a task_id from the request
+			// would dedupe against, and write into, a task the caller may not own
+			for _, key := range inheritedConfigKeys {
+				delete(config, key)
+			}
+			config["dry_run"] = true
+			step.Config = config
+			if isDeliveryStep(step.Type) {
+				stepResult.WouldSend, err = r.previewStep(step, current)
+			} else if step.Type == "merge" {
+				output, err = r.executeMerge(step, outputs)
+			} else {
+				output, err = r.executeStep(ctx, step, current)
+			}
+		}
+		stepResult.DurationMs = time.Since(started).Milliseconds()
+
+		if err != nil {
+			stepResult.Error = err.Error()
+			if errors.As(err, new(filter.SkipPipelineError)) {
+				stepResult.Status = "skipped"
+				result.Status = "skipped"
+			} else {
+				stepResult.Status = "failed"
+				result.Status = "failed"
+				result.Error = fmt.Sprintf("step %d (%s) failed: %v", i+1, step.Type, err)
+			}
+			result.Steps = append(result.Steps, stepResult)
+			break
+		}
+
+		stepResult.Status = "completed"
+		if output != nil {
+			stepResult.ItemCount = output.ItemCount
+			if output != current {
+				stepResult.Output = output.Metadata
+			}
+		}
+		current = output
+		if step.Name != "" {
+			outputs[step.Name] = output
+		}
+
+		if filter.SkipEmpty(output) {
+			stepResult.Output = "No new items found"
+			result.Steps = append(result.Steps, stepResult)
+			break
+		}
+		result.Steps = append(result.Steps, stepResult)
+	}
+
+	if current != nil {
+		result.Data = debugSnapshot(current.Data)
+	}
+	return result
+}
+
+// previewStep stands in for a delivery step, returning what it would send
+func (r *PipelineRunner) previewStep(step model.PipelineStep, input *model.ExecutorResult) (interface{}, error) {
+	if err := checkMinItems(step, input); err != nil {
+		return nil, err
+	}
+
+	switch step.Type {
+	case "discord":
+		return r.discordExec.Preview(input, step.Config)
+	case "slack":
+		return r.slackExec.Preview(input, step.Config)
+	case "telegram":
+		return r.telegramExec.Preview(input, step.Config)
+	case "mastodon":
+		return r.mastodonExec.Preview(input, step.Config)
+	case "email":
+		return r.emailExec.Preview(input, step.Config)
+	case "webhook":
+		return r.webhookExec.Preview(input, step.Config)
+	default:
+		return nil, fmt.Errorf("unknown delivery step type: %s", step.Type)
+	}
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+func TestDryRunIgnoresTaskIDFromRequest(t *testing.T) {
+	db := storagetest.Open(t)
+	runner := newTestRunner(t, db)
+	ctx := context.Background()
+
+	// Someone else's task, named in every step of the previewed pipeline
+	owner := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, owner.ID, staticStep("first"))
+	runSettings := func(config map[string]interface{}) map[string]interface{} {
+		config["task_id"] = task.ID
+		config["bypass_dedup"] = false
+		return config
+	}
+
+	source := staticStep("first", "second")
+	runSettings(source.Config)
+	pipeline := []model.PipelineStep{
+		source,
+		{Type: "filter", Config: runSettings(map[string]interface{}{"deduplicate": true})},
+		{Type: "parallel", Config: map[string]interface{}{"steps": []interface{}{
+			map[string]interface{}{"type": "filter", "config": runSettings(map[string]interface{}{"deduplicate": true})},
+		}}},
+		{Type: "digest", Config: runSettings(map[string]interface{}{"flush_count": float64(10)})},
+	}
+
+	result := runner.DryRun(ctx, pipeline)
+	if result.Status != "completed" {
+		t.Fatalf("dry run %s: %s", result.Status, result.Error)
+	}
+	if last := result.Steps[len(result.Steps)-1]; last.StepType != "digest" || last.ItemCount != 2 {
+		t.Errorf("digest step = %+v, want both items passed through", last)
+	}
+
+	var cached int
+	if err := db.GetContext(ctx, &cached, `SELECT COUNT(*) FROM content_cache WHERE task_id = $1`, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if cached != 0 {
+		t.Errorf("dry run cached %d items for the task", cached)
+	}
+	if held, _, err := runner.cacheRepo.PendingDigest(ctx, task.ID, ""); err != nil || held != 0 {
+		t.Errorf("dry run held %d digest items for the task (%v)", held, err)
+	}
+	if pipeline[1].Config["task_id"] != task.ID {
+		t.Error("dry run changed the caller's pipeline")
+	}
+}
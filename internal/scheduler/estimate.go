@@ -0,0 +1,260 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// Estimation heuristics. They are deliberately rough: the point is to spot a
+// pipeline that fans out to dozens of requests or feeds an AI step thousands
+// of items, not to predict a bill to the cent.
+const (
+	estTokensPerItem       = 150  // A scraped item reduced for a prompt
+	estPromptTokens        = 300  // Instructions wrapped around the items
+	estSummaryTokens       = 700  // Typical ai_processor response
+	estFilterTokensPerItem = 20   // ai_filter's per-item verdict
+	estFetchMs             = 1500 // One upstream page or feed
+	estAIBaseMs            = 2000 // Completion latency before output
+	estAIMsPerOutputToken  = 15
+	estDeliveryMs          = 500 // One outgoing message
+	estScraperDefaultLimit = 10  // Same defaults as the executors
+	estRSSDefaultLimit     = 20
+	estDiscordTextChars    = 2000
+	estCharsPerToken       = 4
+)
+
+// StepEstimate is the predicted load of one pipeline step
+type StepEstimate struct {
+	StepName     string   `json:"step_name"`
+	StepType     string   `json:"step_type"`
+	Requests     int      `json:"requests"`  // Upstream or outgoing HTTP requests
+	ItemsOut     int      `json:"items_out"` // Upper bound on items passed on
+	InputTokens  int      `json:"input_tokens,omitempty"`
+	OutputTokens int      `json:"output_tokens,omitempty"`
+	CostUSD      *float64 `json:"cost_usd,omitempty"` // Unset when the provider has no configured price
+	RuntimeMs    int64    `json:"runtime_ms"`
+	Note         string   `json:"note,omitempty"`
+}
+
+// PipelineEstimate is the predicted load of a whole pipeline
+type PipelineEstimate struct {
+	Steps        []StepEstimate `json:"steps"`
+	Requests     int            `json:"requests"`
+	InputTokens  int            `json:"input_tokens"`
+	OutputTokens int            `json:"output_tokens"`
+	CostUSD      *float64       `json:"cost_usd"` // Unset when any AI step is unpriced
+	RuntimeMs    int64          `json:"runtime_ms"`
+}
+
+// Estimate predicts, without running anything, how many requests, AI tokens,
+// dollars and milliseconds a pipeline costs when every source returns a full
+// page of items. Filters are assumed to keep everything up to their limit.
+func (r *PipelineRunner) Estimate(pipeline []model.PipelineStep) *PipelineEstimate {
+	result := &PipelineEstimate{Steps: []StepEstimate{}}
+	totalCost, priced := 0.0, true
+	items := 0
+	text := false // Whether the data flowing between steps is AI text
+	// Items out of each named step, for merge steps
+	named := make(map[string]int)
+
+	for i, step := range pipeline {
+		stepName := step.Name
+		if stepName == "" {
+			stepName = fmt.Sprintf("Step %d: %s", i+1, step.Type)
+		}
+		est := StepEstimate{StepName: stepName, StepType: step.Type, ItemsOut: items}
+
+		switch step.Type {
+		case "scraper":
+			sources := len(r.scraperExec.ResolveSources(step.Config))
+			pages := configInt(step.Config, "pages", 1)
+			est.Requests = sources * pages
+			est.ItemsOut = sources * configInt(step.Config, "limit", estScraperDefaultLimit)
+			// Sources and their pages are fetched one after another
+			est.RuntimeMs = int64(est.Requests) * estFetchMs
+			text = false
+
+		case "rss":
+			est.Requests = len(feedURLs(step.Config))
+			est.ItemsOut = configInt(step.Config, "limit", estRSSDefaultLimit)
+			est.RuntimeMs = int64(est.Requests) * estFetchMs
+			text = false
+
+		case "static":
+			fixed, _ := step.Config["items"].([]interface{})
+			est.ItemsOut = len(fixed)
+			text = false
+
+		case "filter":
+			if limit := configInt(step.Config, "limit", 0); limit > 0 && limit < items {
+				est.ItemsOut = limit
+			}
+
+		case "limit":
+			if count := configInt(step.Config, "count", 0); count > 0 && count < items {
+				est.ItemsOut = count
+			}
+
+		case "assert":
+			// Passes its input on, or a single alert in its place
+
+		case "transform":
+			// Rewrites items in place
+
+		case "ai_processor", "ai":
+			est.Requests = r.aiExecutor.Requests(step.Config)
+			est.InputTokens = estPromptTokens + items*estTokensPerItem
+			est.OutputTokens = estSummaryTokens
+			est.RuntimeMs = estAIBaseMs + estSummaryTokens*estAIMsPerOutputToken
+			est.ItemsOut = 1
+			text = true
+			est.CostUSD = priceOf(r.aiExecutor.Cost(step.Config, est.InputTokens, est.OutputTokens))
+			if est.Requests > 1 {
+				// Merged providers each read the prompt and answer, concurrently
+				est.InputTokens *= est.Requests
+				est.OutputTokens *= est.Requests
+			}
+
+		case "ai_filter":
+			batch := r.aiFilterExec.BatchSize(step.Config)
+			est.Requests = (items + batch - 1) / batch
+			est.InputTokens = est.Requests*estPromptTokens + items*estTokensPerItem
+			est.OutputTokens = items * estFilterTokensPerItem
+			// Batches run one after another
+			est.RuntimeMs = int64(est.Requests)*estAIBaseMs + int64(est.OutputTokens)*estAIMsPerOutputToken
+			est.CostUSD = priceOf(r.aiFilterExec.Cost(step.Config, est.InputTokens, est.OutputTokens))
+
+		case "parallel":
+			// Branches run concurrently, so the slowest one sets the runtime
+			branches, _ := parallelBranches(step.Config)
+			est.ItemsOut = 0
+			branchesPriced := true
+			branchCost := 0.0
+			for _, branch := range branches {
+				sub := r.Estimate([]model.PipelineStep{branch})
+				est.Requests += sub.Requests
+				est.ItemsOut += sub.Steps[0].ItemsOut
+				est.InputTokens += sub.InputTokens
+				est.OutputTokens += sub.OutputTokens
+				est.RuntimeMs = max(est.RuntimeMs, sub.RuntimeMs)
+				if sub.CostUSD == nil {
+					branchesPriced = false
+				} else {
+					branchCost += *sub.CostUSD
+				}
+			}
+			if !branchesPriced {
+				priced = false
+				est.Note = "no price configured for a branch's provider (see AI_PRICING)"
+			} else if est.InputTokens > 0 {
+				est.CostUSD = &branchCost
+			}
+			text = false
+
+		case "merge":
+			est.ItemsOut = 0
+			sources, _ := mergeSources(step.Config)
+			for _, name := range sources {
+				est.ItemsOut += named[name]
+			}
+			text = false
+
+		case "digest":
+			if count := configInt(step.Config, "flush_count", 0); count > items {
+				est.ItemsOut = count
+			}
+			est.Note = "holds items across runs; estimated as a flushing run"
+
+		case "discord":
+			est.Requests = discordMessages(step.Config, items, text)
+			est.RuntimeMs = int64(est.Requests) * estDeliveryMs
+
+		case "slack", "telegram", "mastodon", "email", "webhook":
+			if items > 0 || text {
+				est.Requests = 1
+			}
+			est.RuntimeMs = int64(est.Requests) * estDeliveryMs
+
+		default:
+			est.Note = "unknown step type; not estimated"
+		}
+
+		if (step.Type == "ai_processor" || step.Type == "ai" || step.Type == "ai_filter") && est.CostUSD == nil {
+			priced = false
+			est.Note = "no price configured for this provider (see AI_PRICING)"
+		}
+		if est.CostUSD != nil {
+			totalCost += *est.CostUSD
+		}
+
+		items = est.ItemsOut
+		if step.Name != "" {
+			named[step.Name] = est.ItemsOut
+		}
+		result.Requests += est.Requests
+		result.InputTokens += est.InputTokens
+		result.OutputTokens += est.OutputTokens
+		result.RuntimeMs += est.RuntimeMs
+		result.Steps = append(result.Steps, est)
+	}
+
+	if priced {
+		result.CostUSD = &totalCost
+	}
+	return result
+}
+
+// discordMessages predicts how many webhook posts a discord step makes
+func discordMessages(config map[string]interface{}, items int, text bool) int {
+	if asFile, _ := config["as_file"].(bool); asFile {
+		// Everything goes in one upload
+		if items > 0 || text {
+			return 1
+		}
+		return 0
+	}
+	if text {
+		// AI text is split into messages of at most 2000 characters
+		return (estSummaryTokens*estCharsPerToken + estDiscordTextChars - 1) / estDiscordTextChars
+	}
+	if items == 0 {
+		return 0
+	}
+	if mode, _ := config["display_mode"].(string); mode == "compact" {
+		return 1
+	}
+	// One embed per item, ten embeds per message
+	return (items + 9) / 10
+}
+
+// feedURLs returns the feeds an rss step fetches
+func feedURLs(config map[string]interface{}) []string {
+	var urls []string
+	if url, ok := config["url"].(string); ok {
+		urls = append(urls, url)
+	}
+	if urlsArr, ok := config["urls"].([]interface{}); ok {
+		for _, u := range urlsArr {
+			if str, ok := u.(string); ok {
+				urls = append(urls, str)
+			}
+		}
+	}
+	return urls
+}
+
+// configInt reads a positive whole number from a step config
+func configInt(config map[string]interface{}, key string, defaultValue int) int {
+	if v, ok := config[key].(float64); ok && v >= 1 {
+		return int(v)
+	}
+	return defaultValue
+}
+
+func priceOf(cost float64, ok bool) *float64 {
+	if !ok {
+		return nil
+	}
+	return &cost
+}
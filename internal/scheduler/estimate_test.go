@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/executor/ai"
+	"github.com/multi-worker/internal/executor/scraper"
+	"github.com/multi-worker/internal/model"
+)
+
+// newEstimateRunner builds a runner that can estimate pipelines; only the
+// ollama provider has a price
+func newEstimateRunner() *PipelineRunner {
+	registry := ai.NewProviderRegistry(&config.AIConfig{
+		DefaultProvider: "ollama",
+		Ollama:          config.OllamaConfig{Model: "llama3", BaseURL: "http://localhost:11434"},
+		Pricing:         map[string]config.TokenPrice{"ollama": {Input: 1, Output: 2}},
+	})
+	return &PipelineRunner{
+		scraperExec:  scraper.NewExecutor(nil, nil),
+		aiExecutor:   ai.NewExecutor(registry, nil),
+		aiFilterExec: ai.NewFilterExecutor(registry),
+	}
+}
+
+func scraperStep(limit int, sources ...string) model.PipelineStep {
+	list := make([]interface{}, len(sources))
+	for i, s := range sources {
+		list[i] = s
+	}
+	return model.PipelineStep{Type: "scraper", Config: map[string]interface{}{"sources": list, "limit": float64(limit)}}
+}
+
+func TestEstimateScalesWithLimit(t *testing.T) {
+	r := newEstimateRunner()
+	estimate := func(limit int) *PipelineEstimate {
+		return r.Estimate([]model.PipelineStep{
+			scraperStep(limit, "remoteok"),
+			{Type: "ai_filter", Config: map[string]interface{}{"criteria": "Go jobs", "batch_size": float64(10)}},
+			{Type: "ai", Config: map[string]interface{}{"prompt": "Summarize"}},
+			{Type: "discord", Config: map[string]interface{}{}},
+		})
+	}
+	small, large := estimate(10), estimate(40)
+
+	if small.Steps[0].ItemsOut != 10 || large.Steps[0].ItemsOut != 40 {
+		t.Errorf("scraper items = %d and %d, want the limit", small.Steps[0].ItemsOut, large.Steps[0].ItemsOut)
+	}
+	// The filter classifies in batches, so requests grow with the items
+	if small.Steps[1].Requests != 1 || large.Steps[1].Requests != 4 {
+		t.Errorf("ai_filter requests = %d and %d, want 1 and 4", small.Steps[1].Requests, large.Steps[1].Requests)
+	}
+	if large.Steps[2].InputTokens <= small.Steps[2].InputTokens {
+		t.Errorf("summary input tokens = %d for 40 items, %d for 10", large.Steps[2].InputTokens, small.Steps[2].InputTokens)
+	}
+	if large.InputTokens <= small.InputTokens || large.RuntimeMs <= small.RuntimeMs {
+		t.Errorf("totals didn't grow with the limit: %+v vs %+v", large, small)
+	}
+	if small.CostUSD == nil || large.CostUSD == nil || *large.CostUSD <= *small.CostUSD {
+		t.Errorf("cost = %v and %v, want it to grow with the limit", small.CostUSD, large.CostUSD)
+	}
+
+	// Without an AI step, Discord sends an embed per item, ten per message
+	direct := r.Estimate([]model.PipelineStep{scraperStep(40, "remoteok"), {Type: "discord", Config: map[string]interface{}{}}})
+	if direct.Steps[1].Requests != 4 {
+		t.Errorf("discord requests for 40 items = %d, want 4", direct.Steps[1].Requests)
+	}
+}
+
+func TestEstimateScalesWithSteps(t *testing.T) {
+	r := newEstimateRunner()
+	one := r.Estimate([]model.PipelineStep{
+		scraperStep(10, "remoteok"),
+		{Type: "ai", Config: map[string]interface{}{"prompt": "Summarize"}},
+	})
+	more := r.Estimate([]model.PipelineStep{
+		scraperStep(10, "remoteok", "weworkremotely", "hackernews"),
+		{Type: "ai", Config: map[string]interface{}{"prompt": "Summarize"}},
+		{Type: "ai", Config: map[string]interface{}{"prompt": "Translate"}},
+		{Type: "webhook", Config: map[string]interface{}{"url": "https://example.com/hook"}},
+	})
+
+	if len(more.Steps) != 4 || more.Steps[0].Requests != 3 || more.Steps[0].ItemsOut != 30 {
+		t.Fatalf("steps = %+v, want three sources of 10 items", more.Steps)
+	}
+	if more.Requests != 3+1+1+1 || one.Requests != 1+1 {
+		t.Errorf("requests = %d and %d, want 6 and 2", more.Requests, one.Requests)
+	}
+
+	// Totals are the sums of the steps
+	var requests, input int
+	var runtime int64
+	for _, s := range more.Steps {
+		requests += s.Requests
+		input += s.InputTokens
+		runtime += s.RuntimeMs
+	}
+	if requests != more.Requests || input != more.InputTokens || runtime != more.RuntimeMs {
+		t.Errorf("totals %d/%d/%d don't add up to the steps' %d/%d/%d",
+			more.Requests, more.InputTokens, more.RuntimeMs, requests, input, runtime)
+	}
+	if *more.CostUSD <= *one.CostUSD {
+		t.Errorf("cost = %v for more steps, %v for fewer", *more.CostUSD, *one.CostUSD)
+	}
+}
+
+func TestEstimateLeavesUnpricedCostUnset(t *testing.T) {
+	est := newEstimateRunner().Estimate([]model.PipelineStep{
+		scraperStep(10, "remoteok"),
+		{Type: "ai", Config: map[string]interface{}{"prompt": "Summarize", "provider": "openai"}},
+	})
+	if est.CostUSD != nil || est.Steps[1].CostUSD != nil || est.Steps[1].Note == "" {
+		t.Errorf("estimate = %+v, want no cost and a note for the unpriced provider", est)
+	}
+	if est.InputTokens == 0 {
+		t.Error("tokens are estimated even without a price")
+	}
+}
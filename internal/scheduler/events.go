@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// Step event types, as sent on an execution's stream
+const (
+	StepEventStarted   = "started"
+	StepEventCompleted = "completed"
+	StepEventFailed    = "failed"
+	StepEventSkipped   = "skipped"
+)
+
+// eventBuffer is how many events a slow subscriber may fall behind by
+// before further events are dropped for it
+const eventBuffer = 64
+
+// StepEvent reports progress of one step of an execution
+type StepEvent struct {
+	Event     string    `json:"event"`
+	Step      int       `json:"step"` // 1-based position in the execution's step results
+	StepName  string    `json:"step_name"`
+	StepType  string    `json:"step_type"`
+	ItemCount *int      `json:"item_count,omitempty"` // Completed steps only
+	Error     string    `json:"error,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// ExecutionEvents fans out the step events of executions running in this
+// process to their subscribers
+type ExecutionEvents struct {
+	mu   sync.Mutex
+	subs map[string]map[chan StepEvent]struct{}
+}
+
+func newExecutionEvents() *ExecutionEvents {
+	return &ExecutionEvents{subs: make(map[string]map[chan StepEvent]struct{})}
+}
+
+// Subscribe returns a channel of an execution's step events, closed when
+// the execution finishes, and a func that stops the subscription. Executions
+// that aren't running here simply never send.
+func (e *ExecutionEvents) Subscribe(execID string) (<-chan StepEvent, func()) {
+	ch := make(chan StepEvent, eventBuffer)
+	e.mu.Lock()
+	if e.subs[execID] == nil {
+		e.subs[execID] = make(map[chan StepEvent]struct{})
+	}
+	e.subs[execID][ch] = struct{}{}
+	e.mu.Unlock()
+
+	return ch, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if _, ok := e.subs[execID][ch]; ok {
+			delete(e.subs[execID], ch)
+			if len(e.subs[execID]) == 0 {
+				delete(e.subs, execID)
+			}
+			close(ch)
+		}
+	}
+}
+
+// publish sends an event to the execution's subscribers without waiting on
+// any of them
+func (e *ExecutionEvents) publish(execID string, event StepEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for ch := range e.subs[execID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// finish closes the channels of an execution's subscribers
+func (e *ExecutionEvents) finish(execID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for ch := range e.subs[execID] {
+		close(ch)
+	}
+	delete(e.subs, execID)
+}
+
+// StoredStepEvents turns recorded step results into the events they would
+// have been streamed as, for executions that finished or run elsewhere
+func StoredStepEvents(results model.StepResults) []StepEvent {
+	events := make([]StepEvent, 0, len(results))
+	for i, result := range results {
+		events = append(events, stepEvent(i+1, result))
+	}
+	return events
+}
+
+// stepEvent describes the nth step result in its current state
+func stepEvent(n int, result model.StepResult) StepEvent {
+	event := StepEvent{
+		Step:     n,
+		StepName: result.StepName,
+		StepType: result.StepType,
+		Time:     result.StartedAt,
+	}
+	if result.FinishedAt != nil {
+		event.Time = *result.FinishedAt
+	}
+	switch result.Status {
+	case StepEventCompleted, StepEventFailed, StepEventSkipped:
+		event.Event = result.Status
+	default:
+		event.Event = StepEventStarted
+	}
+	if result.Error != nil {
+		event.Error = *result.Error
+	}
+
+	// Live results hold an int, ones read back from the database a float64
+	if output, ok := result.Output.(map[string]interface{}); ok {
+		switch count := output["item_count"].(type) {
+		case int:
+			event.ItemCount = &count
+		case float64:
+			n := int(count)
+			event.ItemCount = &n
+		}
+	}
+	return event
+}
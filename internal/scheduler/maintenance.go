@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/storage"
+)
+
+// Maintenance periodically prunes old rows so tables don't grow unbounded
+type Maintenance struct {
+	execRepo           *storage.ExecutionRepository
+	cacheRepo          *storage.CacheRepository
+	executionRetention time.Duration
+	cacheRetention     time.Duration
+	interval           time.Duration
+	cancel             context.CancelFunc
+	done               chan struct{}
+}
+
+// NewMaintenance creates the background maintenance job
+func NewMaintenance(execRepo *storage.ExecutionRepository, cacheRepo *storage.CacheRepository, cfg config.MaintenanceConfig) *Maintenance {
+	interval := time.Duration(cfg.CleanupIntervalHours) * time.Hour
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	return &Maintenance{
+		execRepo:           execRepo,
+		cacheRepo:          cacheRepo,
+		executionRetention: time.Duration(cfg.ExecutionRetentionDays) * 24 * time.Hour,
+		cacheRetention:     time.Duration(cfg.CacheRetentionDays) * 24 * time.Hour,
+		interval:           interval,
+	}
+}
+
+// Start runs the maintenance loop in the background until Stop is called.
+// Expired AI completions are removed on every run; the retention settings
+// only decide whether old executions and cache entries are pruned too.
+func (m *Maintenance) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+
+	go func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			m.run(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	log.Printf("Maintenance started: every %s (execution retention %s, cache retention %s; 0s keeps forever)",
+		m.interval, m.executionRetention, m.cacheRetention)
+}
+
+// Stop ends the maintenance loop, waiting for a run in progress to finish
+func (m *Maintenance) Stop(ctx context.Context) error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+
+	select {
+	case <-m.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Maintenance) run(ctx context.Context) {
+	// Expired completions are never served, so they go whatever the retention
+	deleted, err := m.cacheRepo.CleanExpiredCompletions(ctx)
+	if err != nil {
+		log.Printf("Warning: AI completion cache cleanup failed: %v", err)
+	} else if deleted > 0 {
+		log.Printf("AI completion cache cleanup removed %d expired entries", deleted)
+	}
+
+	if m.executionRetention > 0 {
+		deleted, err := m.execRepo.DeleteOld(ctx, time.Now().Add(-m.executionRetention))
+		if err != nil {
+			log.Printf("Warning: execution cleanup failed: %v", err)
+		} else {
+			log.Printf("Execution cleanup removed %d executions", deleted)
+		}
+	}
+
+	if m.cacheRetention > 0 {
+		// Global on purpose: a step's dedupe_window_days only narrows what
+		// counts as seen, so a shorter retention here overrides it
+		deleted, err := m.cacheRepo.CleanOld(ctx, time.Now().Add(-m.cacheRetention))
+		if err != nil {
+			log.Printf("Warning: content cache cleanup failed: %v", err)
+		} else {
+			log.Printf("Content cache cleanup removed %d entries", deleted)
+		}
+
+		deleted, err = m.cacheRepo.CleanSentDigestItems(ctx, time.Now().Add(-m.cacheRetention))
+		if err != nil {
+			log.Printf("Warning: digest cleanup failed: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Digest cleanup removed %d sent items", deleted)
+		}
+	}
+}
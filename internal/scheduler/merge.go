@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// mergeSources reads the names of the earlier steps a merge step combines
+// from its "from" config
+func mergeSources(config map[string]interface{}) ([]string, error) {
+	raw, ok := config["from"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, errors.New("merge step requires a non-empty 'from' array of step names")
+	}
+
+	names := make([]string, 0, len(raw))
+	for _, v := range raw {
+		name, ok := v.(string)
+		if !ok || name == "" {
+			return nil, errors.New("merge 'from' must be an array of step names")
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// executeMerge concatenates the outputs of the named earlier steps, in the
+// order listed, with the same rules as a parallel step's branches
+func (r *PipelineRunner) executeMerge(step model.PipelineStep, outputs map[string]*model.ExecutorResult) (*model.ExecutorResult, error) {
+	names, err := mergeSources(step.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	batches := make([]interface{}, 0, len(names))
+	stepCounts := make(map[string]int, len(names))
+	for _, name := range names {
+		output, ok := outputs[name]
+		if !ok {
+			return nil, fmt.Errorf("step %q has no output in this run to merge", name)
+		}
+		if _, ok := mergeable(output.Data); !ok {
+			return nil, fmt.Errorf("output of step %q (%T) can't be merged", name, output.Data)
+		}
+		batches = append(batches, output.Data)
+		stepCounts[name] = output.ItemCount
+	}
+
+	data, count := mergeItems(batches)
+	return &model.ExecutorResult{
+		Data:      data,
+		ItemCount: count,
+		Metadata: map[string]interface{}{
+			"merged_steps": names,
+			"step_counts":  stepCounts,
+		},
+	}, nil
+}
+
+// validateMergeSources checks that a merge step only names steps before it
+func validateMergeSources(config map[string]interface{}, earlier map[string]bool) error {
+	names, err := mergeSources(config)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if !earlier[name] {
+			return fmt.Errorf("merge 'from' names %q, which isn't an earlier step", name)
+		}
+	}
+	return nil
+}
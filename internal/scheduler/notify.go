@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+)
+
+// ErrorNotifier posts failed executions to a global webhook, at most once per
+// task per throttle window so a broken task on a tight schedule can't flood it
+type ErrorNotifier struct {
+	webhookURL string
+	throttle   time.Duration
+	client     *http.Client
+
+	mu         sync.Mutex
+	lastSent   map[string]time.Time
+	suppressed map[string]int
+
+	sending sync.WaitGroup // Notifications still being posted, for Flush
+}
+
+// errorNotification is posted as JSON. content and text carry a readable
+// summary so the URL can be a Discord or Slack incoming webhook.
+type errorNotification struct {
+	Content     string `json:"content"`
+	Text        string `json:"text"`
+	TaskID      string `json:"task_id"`
+	TaskName    string `json:"task_name"`
+	ExecutionID string `json:"execution_id"`
+	Step        string `json:"step,omitempty"`
+	Error       string `json:"error"`
+	Suppressed  int    `json:"suppressed,omitempty"`
+}
+
+// NewErrorNotifier creates an error notifier, or returns nil when no webhook is configured
+func NewErrorNotifier(cfg config.NotificationConfig) *ErrorNotifier {
+	if cfg.ErrorWebhook == "" {
+		return nil
+	}
+	return &ErrorNotifier{
+		webhookURL: cfg.ErrorWebhook,
+		throttle:   cfg.ErrorThrottle,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		lastSent:   make(map[string]time.Time),
+		suppressed: make(map[string]int),
+	}
+}
+
+// Notify reports a failed execution in the background. Failures within the
+// throttle window are counted and mentioned in the next notification.
+func (n *ErrorNotifier) Notify(task model.Task, execID string, stepResults model.StepResults, runErr error) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	if last, ok := n.lastSent[task.ID]; ok && time.Since(last) < n.throttle {
+		n.suppressed[task.ID]++
+		n.mu.Unlock()
+		return
+	}
+	suppressed := n.suppressed[task.ID]
+	n.lastSent[task.ID] = time.Now()
+	delete(n.suppressed, task.ID)
+	n.mu.Unlock()
+
+	notification := errorNotification{
+		TaskID:      task.ID,
+		TaskName:    task.Name,
+		ExecutionID: execID,
+		Step:        failedStep(stepResults),
+		Error:       runErr.Error(),
+		Suppressed:  suppressed,
+	}
+	notification.Content = notification.summary()
+	notification.Text = notification.Content
+
+	n.sending.Add(1)
+	go func() {
+		defer n.sending.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := n.send(ctx, notification); err != nil {
+			log.Printf("Warning: failed to send error notification for task %s: %v", task.ID, err)
+		}
+	}()
+}
+
+// Flush waits for notifications still being posted, giving up when ctx ends
+func (n *ErrorNotifier) Flush(ctx context.Context) error {
+	if n == nil {
+		return nil
+	}
+	if err := waitGroupContext(ctx, &n.sending); err != nil {
+		return fmt.Errorf("error notifications still pending: %w", err)
+	}
+	return nil
+}
+
+func (n *ErrorNotifier) send(ctx context.Context, notification errorNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (e errorNotification) summary() string {
+	msg := fmt.Sprintf("Task %q failed (execution %s)", e.TaskName, e.ExecutionID)
+	if e.Step != "" {
+		msg += fmt.Sprintf(" at %s", e.Step)
+	}
+	msg += ": " + e.Error
+	if e.Suppressed > 0 {
+		msg += fmt.Sprintf("\n%d earlier failure(s) of this task were not reported", e.Suppressed)
+	}
+	return truncateString(msg, 1900)
+}
+
+// failedStep returns the name of the step that failed, if any
+func failedStep(stepResults model.StepResults) string {
+	for i := len(stepResults) - 1; i >= 0; i-- {
+		if stepResults[i].Status == "failed" {
+			return stepResults[i].StepName
+		}
+	}
+	return ""
+}
+
+// waitGroupContext waits for wg, returning ctx's error if it ends first
+func waitGroupContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
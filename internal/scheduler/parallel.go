@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/multi-worker/internal/executor/filter"
+	"github.com/multi-worker/internal/model"
+)
+
+// inheritedConfigKeys are the run settings the runner injects into a step's
+// config, which a parallel step hands down to each of its branches
+var inheritedConfigKeys = []string{"task_id", "task_timezone", "force_refresh", "bypass_dedup"}
+
+// parallelBranches reads the sub-steps of a parallel step from its "steps" config
+func parallelBranches(config map[string]interface{}) ([]model.PipelineStep, error) {
+	raw, ok := config["steps"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, errors.New("parallel step requires a non-empty 'steps' array")
+	}
+
+	// Round-trip through JSON so branches are decoded exactly like pipeline steps
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parallel steps: %w", err)
+	}
+	var branches []model.PipelineStep
+	if err := json.Unmarshal(data, &branches); err != nil {
+		return nil, fmt.Errorf("invalid parallel steps: %w", err)
+	}
+	return branches, nil
+}
+
+// validateParallel validates each branch of a parallel step
+func (r *PipelineRunner) validateParallel(config map[string]interface{}) error {
+	branches, err := parallelBranches(config)
+	if err != nil {
+		return err
+	}
+	for i, branch := range branches {
+		if isDeliveryStep(branch.Type) || branch.Type == "merge" || branch.Type == "digest" {
+			return fmt.Errorf("branch %d: %s steps can't run in parallel", i+1, branch.Type)
+		}
+		if err := r.validateStep(branch); err != nil {
+			return fmt.Errorf("branch %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// executeParallel runs the branches of a parallel step concurrently on the
+// same input and concatenates their items. A failing branch is reported in
+// the metadata; the step only fails when every branch does.
+func (r *PipelineRunner) executeParallel(ctx context.Context, step model.PipelineStep, input *model.ExecutorResult) (*model.ExecutorResult, error) {
+	branches, err := parallelBranches(step.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.ExecutorResult, len(branches))
+	errs := make([]error, len(branches))
+
+	var wg sync.WaitGroup
+	for i, branch := range branches {
+		if branch.Config == nil {
+			branch.Config = make(map[string]interface{})
+		}
+		// A branch's own values never count, so a dry run, which strips
+		// these from the parallel step, can't be given them through a branch
+		for _, key := range inheritedConfigKeys {
+			if v, ok := step.Config[key]; ok {
+				branch.Config[key] = v
+			} else {
+				delete(branch.Config, key)
+			}
+		}
+
+		wg.Add(1)
+		go func(i int, branch model.PipelineStep) {
+			defer wg.Done()
+			results[i], errs[i] = r.executeStep(ctx, branch, input)
+		}(i, branch)
+	}
+	wg.Wait()
+
+	var batches []interface{}
+	var branchErrors []string
+	branchCounts := make([]int, len(branches))
+	failed := 0
+	for i, branch := range branches {
+		err := errs[i]
+		if errors.As(err, new(filter.SkipPipelineError)) {
+			// A branch with nothing to offer doesn't stop the others
+			continue
+		}
+		if err == nil && results[i] != nil {
+			if _, ok := mergeable(results[i].Data); !ok {
+				err = fmt.Errorf("output of type %T can't be merged", results[i].Data)
+			}
+		}
+		if err != nil {
+			failed++
+			branchErrors = append(branchErrors, fmt.Sprintf("branch %d (%s): %v", i+1, branch.Type, err))
+			continue
+		}
+		if results[i] != nil && results[i].Data != nil {
+			batches = append(batches, results[i].Data)
+			branchCounts[i] = results[i].ItemCount
+		}
+	}
+
+	if failed == len(branches) {
+		return nil, fmt.Errorf("all %d branches failed: %s", failed, branchErrors[0])
+	}
+
+	data, count := mergeItems(batches)
+	metadata := map[string]interface{}{
+		"branches":      len(branches),
+		"failed":        failed,
+		"branch_counts": branchCounts,
+	}
+	if len(branchErrors) > 0 {
+		metadata["branch_errors"] = branchErrors
+	}
+
+	return &model.ExecutorResult{
+		Data:      data,
+		ItemCount: count,
+		Metadata:  metadata,
+	}, nil
+}
+
+// mergeable reports whether a branch's data can be concatenated, and its
+// length when it can
+func mergeable(data interface{}) (int, bool) {
+	switch v := data.(type) {
+	case nil:
+		return 0, true
+	case []model.ScrapedItem:
+		return len(v), true
+	case []model.RSSItem:
+		return len(v), true
+	}
+	return 0, false
+}
+
+// mergeItems concatenates branch outputs. Batches of one kind keep their
+// type; a mix of scraped and RSS items is converted to scraped items, which
+// every later step accepts.
+func mergeItems(batches []interface{}) (interface{}, int) {
+	var scraped []model.ScrapedItem
+	var rssItems []model.RSSItem
+	for _, batch := range batches {
+		switch v := batch.(type) {
+		case []model.ScrapedItem:
+			scraped = append(scraped, v...)
+		case []model.RSSItem:
+			rssItems = append(rssItems, v...)
+		}
+	}
+
+	switch {
+	case len(rssItems) == 0:
+		if scraped == nil {
+			scraped = []model.ScrapedItem{}
+		}
+		return scraped, len(scraped)
+	case len(scraped) == 0:
+		return rssItems, len(rssItems)
+	}
+
+	for _, item := range rssItems {
+		scraped = append(scraped, item.ToScrapedItem())
+	}
+	return scraped, len(scraped)
+}
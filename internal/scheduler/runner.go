@@ -2,30 +2,55 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/multi-worker/internal/executor/ai"
+	"github.com/multi-worker/internal/executor/assert"
 	"github.com/multi-worker/internal/executor/discord"
+	"github.com/multi-worker/internal/executor/email"
 	"github.com/multi-worker/internal/executor/filter"
+	"github.com/multi-worker/internal/executor/limit"
+	"github.com/multi-worker/internal/executor/mastodon"
 	"github.com/multi-worker/internal/executor/rss"
 	"github.com/multi-worker/internal/executor/scraper"
+	"github.com/multi-worker/internal/executor/slack"
+	"github.com/multi-worker/internal/executor/static"
+	"github.com/multi-worker/internal/executor/telegram"
+	"github.com/multi-worker/internal/executor/transform"
+	"github.com/multi-worker/internal/executor/webhook"
 	"github.com/multi-worker/internal/model"
 	"github.com/multi-worker/internal/storage"
 )
 
 // PipelineRunner executes task pipelines
 type PipelineRunner struct {
-	taskRepo    *storage.TaskRepository
-	execRepo    *storage.ExecutionRepository
-	cacheRepo   *storage.CacheRepository
-	discordRepo *storage.DiscordRepository
-	aiExecutor  *ai.Executor
-	scraperExec *scraper.Executor
-	rssExec     *rss.Executor
-	discordExec *discord.Executor
-	filterExec  *filter.Executor
+	taskRepo      *storage.TaskRepository
+	execRepo      *storage.ExecutionRepository
+	cacheRepo     *storage.CacheRepository
+	discordRepo   *storage.DiscordRepository
+	secretRepo    *storage.SecretRepository
+	statsRepo     *storage.AnalyticsRepository
+	aiExecutor    *ai.Executor
+	aiFilterExec  *ai.FilterExecutor
+	scraperExec   *scraper.Executor
+	rssExec       *rss.Executor
+	discordExec   *discord.Executor
+	slackExec     *slack.Executor
+	telegramExec  *telegram.Executor
+	mastodonExec  *mastodon.Executor
+	emailExec     *email.Executor
+	webhookExec   *webhook.Executor
+	filterExec    *filter.Executor
+	assertExec    *assert.Executor
+	transformExec *transform.Executor
+	staticExec    *static.Executor
+	limitExec     *limit.Executor
+	notifier      *ErrorNotifier
+	callback      *ExecutionCallback
+	events        *ExecutionEvents
 }
 
 // NewPipelineRunner creates a new pipeline runner
@@ -34,55 +59,152 @@ func NewPipelineRunner(
 	execRepo *storage.ExecutionRepository,
 	cacheRepo *storage.CacheRepository,
 	discordRepo *storage.DiscordRepository,
+	secretRepo *storage.SecretRepository,
+	statsRepo *storage.AnalyticsRepository,
 	aiExec *ai.Executor,
+	aiFilterExec *ai.FilterExecutor,
 	scraperExec *scraper.Executor,
 	rssExec *rss.Executor,
 	discordExec *discord.Executor,
+	slackExec *slack.Executor,
+	telegramExec *telegram.Executor,
+	mastodonExec *mastodon.Executor,
+	emailExec *email.Executor,
+	webhookExec *webhook.Executor,
 	filterExec *filter.Executor,
+	assertExec *assert.Executor,
+	transformExec *transform.Executor,
+	staticExec *static.Executor,
+	limitExec *limit.Executor,
+	notifier *ErrorNotifier,
+	callback *ExecutionCallback,
 ) *PipelineRunner {
 	return &PipelineRunner{
-		taskRepo:    taskRepo,
-		execRepo:    execRepo,
-		cacheRepo:   cacheRepo,
-		discordRepo: discordRepo,
-		aiExecutor:  aiExec,
-		scraperExec: scraperExec,
-		rssExec:     rssExec,
-		discordExec: discordExec,
-		filterExec:  filterExec,
+		taskRepo:      taskRepo,
+		execRepo:      execRepo,
+		cacheRepo:     cacheRepo,
+		discordRepo:   discordRepo,
+		secretRepo:    secretRepo,
+		statsRepo:     statsRepo,
+		aiExecutor:    aiExec,
+		aiFilterExec:  aiFilterExec,
+		scraperExec:   scraperExec,
+		rssExec:       rssExec,
+		discordExec:   discordExec,
+		slackExec:     slackExec,
+		telegramExec:  telegramExec,
+		mastodonExec:  mastodonExec,
+		emailExec:     emailExec,
+		webhookExec:   webhookExec,
+		filterExec:    filterExec,
+		assertExec:    assertExec,
+		transformExec: transformExec,
+		staticExec:    staticExec,
+		limitExec:     limitExec,
+		notifier:      notifier,
+		callback:      callback,
+		events:        newExecutionEvents(),
 	}
 }
 
+// Events returns the step events of executions this runner is running
+func (r *PipelineRunner) Events() *ExecutionEvents {
+	return r.events
+}
+
+// Flush waits, until ctx ends, for the error notifications and execution
+// callbacks finished runs are still posting in the background. Items held
+// by digest steps are stored, so they wait for the next flush after a
+// restart instead.
+func (r *PipelineRunner) Flush(ctx context.Context) error {
+	return errors.Join(r.notifier.Flush(ctx), r.callback.Flush(ctx))
+}
+
+// RunOptions adjust how a single run treats cached state
+type RunOptions struct {
+	// ForceRefresh fetches sources unconditionally instead of trusting
+	// cached responses (e.g. RSS ETag/Last-Modified validators)
+	ForceRefresh bool
+	// BypassDedup delivers items even if the task has seen them before,
+	// without recording them as seen
+	BypassDedup bool
+}
+
+// Forced reports whether the run bypasses any cache
+func (o RunOptions) Forced() bool {
+	return o.ForceRefresh || o.BypassDedup
+}
+
 // Run executes a task's pipeline
-func (r *PipelineRunner) Run(ctx context.Context, task model.Task, triggeredBy string) (*model.Execution, error) {
+func (r *PipelineRunner) Run(ctx context.Context, task model.Task, triggeredBy string, opts RunOptions) (*model.Execution, error) {
+	return r.run(ctx, task, triggeredBy, triggerTypeFor(triggeredBy), opts, 0, nil)
+}
+
+// RunWithInput executes a task's whole pipeline with input as the first
+// step's input, for runs started by an inbound webhook
+func (r *PipelineRunner) RunWithInput(ctx context.Context, task model.Task, input *model.ExecutorResult) (*model.Execution, error) {
+	return r.run(ctx, task, string(model.TriggerTypeWebhook), model.TriggerTypeWebhook, RunOptions{}, 0, input)
+}
+
+// Resume executes a task's pipeline starting at fromStep (zero-based), using
+// input as the output of the step before it. The run is recorded as a new execution.
+func (r *PipelineRunner) Resume(ctx context.Context, task model.Task, triggeredBy string, fromStep int, input *model.ExecutorResult) (*model.Execution, error) {
+	if fromStep < 1 || fromStep >= len(task.Pipeline) {
+		return nil, fmt.Errorf("cannot resume from step %d of a %d-step pipeline", fromStep+1, len(task.Pipeline))
+	}
+	return r.run(ctx, task, triggeredBy, model.TriggerTypeReplay, RunOptions{}, fromStep, input)
+}
+
+// triggerTypeFor maps the free-form triggered_by value to its trigger type;
+// anything that isn't a known system trigger is a user running the task
+func triggerTypeFor(triggeredBy string) model.TriggerType {
+	switch model.TriggerType(triggeredBy) {
+	case model.TriggerTypeSchedule, model.TriggerTypeReplay, model.TriggerTypeWebhook:
+		return model.TriggerType(triggeredBy)
+	}
+	return model.TriggerTypeManual
+}
+
+func (r *PipelineRunner) run(ctx context.Context, task model.Task, triggeredBy string, triggerType model.TriggerType, opts RunOptions, fromStep int, input *model.ExecutorResult) (*model.Execution, error) {
 	// Create execution record
-	execution, err := r.execRepo.Create(ctx, task.ID, task.Name, triggeredBy)
+	execution, err := r.execRepo.Create(ctx, task.ID, task.Name, triggeredBy, triggerType, opts.Forced())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create execution record: %w", err)
 	}
 
 	// Update task status to running
-	if err := r.taskRepo.UpdateStatus(ctx, task.ID, model.TaskStatusRunning); err != nil {
+	if err := r.taskRepo.SwapStatus(ctx, task.ID, model.TaskStatusEnabled, model.TaskStatusRunning); err != nil {
 		log.Printf("Warning: failed to update task status to running: %v", err)
 	}
 
-	// Execute pipeline
-	stepResults, finalErr := r.executePipeline(ctx, task, execution.ID)
+	// Execute pipeline within the task's timeout; bookkeeping below uses the
+	// parent context without its cancellation, so a run that timed out or
+	// was cancelled by shutdown can still be recorded as failed
+	runCtx, cancel := context.WithTimeout(ctx, executionTimeout(task))
+	stepResults, finalErr := r.executePipeline(runCtx, task, execution.ID, opts, fromStep, input)
+	cancel()
+	interrupted := errors.Is(context.Cause(ctx), errInterrupted)
+	ctx = context.WithoutCancel(ctx)
 
 	// Update execution with results
 	if finalErr != nil {
 		errMsg := finalErr.Error()
+		if interrupted {
+			errMsg = errInterrupted.Error()
+		}
 		if err := r.execRepo.Fail(ctx, execution.ID, stepResults, errMsg); err != nil {
 			log.Printf("Warning: failed to mark execution as failed: %v", err)
 		}
+		r.notifier.Notify(task, execution.ID, stepResults, finalErr)
 	} else {
 		if err := r.execRepo.Complete(ctx, execution.ID, stepResults); err != nil {
 			log.Printf("Warning: failed to mark execution as complete: %v", err)
 		}
 	}
+	r.events.finish(execution.ID)
 
 	// Update task status back to enabled
-	if err := r.taskRepo.UpdateStatus(ctx, task.ID, model.TaskStatusEnabled); err != nil {
+	if err := r.taskRepo.SwapStatus(ctx, task.ID, model.TaskStatusRunning, model.TaskStatusEnabled); err != nil {
 		log.Printf("Warning: failed to update task status to enabled: %v", err)
 	}
 
@@ -93,15 +215,30 @@ func (r *PipelineRunner) Run(ctx context.Context, task model.Task, triggeredBy s
 
 	// Fetch updated execution
 	execution, _ = r.execRepo.FindByID(ctx, execution.ID)
+	r.callback.Send(execution)
 
 	return execution, finalErr
 }
 
-func (r *PipelineRunner) executePipeline(ctx context.Context, task model.Task, execID string) (model.StepResults, error) {
+func (r *PipelineRunner) executePipeline(ctx context.Context, task model.Task, execID string, opts RunOptions, fromStep int, input *model.ExecutorResult) (model.StepResults, error) {
 	var stepResults model.StepResults
-	var currentResult *model.ExecutorResult
+	currentResult := input
+	outputs := make(map[string]*model.ExecutorResult) // Named steps' results, for merge steps
+
+	var delivered bool
+	var flushes []digestFlush // Passed on by digest steps, not yet delivered
+
+	secrets, err := r.loadSecrets(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	redactor := newSecretRedactor(secrets)
 
 	for i, step := range task.Pipeline {
+		if i < fromStep {
+			continue
+		}
+
 		stepName := step.Name
 		if stepName == "" {
 			stepName = fmt.Sprintf("Step %d: %s", i+1, step.Type)
@@ -113,25 +250,52 @@ func (r *PipelineRunner) executePipeline(ctx context.Context, task model.Task, e
 			Status:    "running",
 			StartedAt: time.Now(),
 		}
+		r.events.publish(execID, stepEvent(len(stepResults)+1, stepResult))
 
 		// Add task_id to config for caching
 		if step.Config == nil {
 			step.Config = make(map[string]interface{})
 		}
 		step.Config["task_id"] = task.ID
+		if task.Timezone != "" {
+			step.Config["task_timezone"] = task.Timezone
+		}
+		if opts.ForceRefresh {
+			step.Config["force_refresh"] = true
+		}
+		if opts.BypassDedup {
+			step.Config["bypass_dedup"] = true
+		}
 
-		// Execute the step
-		result, err := r.executeStep(ctx, step, currentResult)
+		// Substitute secret references on a copy so decrypted values never
+		// reach the stored pipeline or step results
+		var result *model.ExecutorResult
+		step.Config, err = resolveSecrets(step.Config, secrets)
+		if err == nil && step.Type == "merge" {
+			result, err = r.executeMerge(step, outputs)
+		} else if err == nil && step.Type == "digest" {
+			var flush *digestFlush
+			result, flush, err = r.executeDigest(ctx, step, currentResult)
+			if flush != nil {
+				flushes = append(flushes, *flush)
+			}
+		} else if err == nil {
+			// Execute the step
+			result, err = r.executeStep(ctx, step, currentResult)
+		}
 
 		now := time.Now()
 		stepResult.FinishedAt = &now
 
 		if err != nil {
+			err = redactor.Error(err)
+
 			// Check if it's a skip error
-			if _, ok := err.(filter.SkipPipelineError); ok {
+			if errors.As(err, new(filter.SkipPipelineError)) {
 				stepResult.Status = "skipped"
 				stepResult.Error = stringPtr(err.Error())
 				stepResults = append(stepResults, stepResult)
+				r.events.publish(execID, stepEvent(len(stepResults), stepResult))
 				log.Printf("Task %s: pipeline skipped at step %d: %v", task.ID, i+1, err)
 				return stepResults, nil
 			}
@@ -139,6 +303,7 @@ func (r *PipelineRunner) executePipeline(ctx context.Context, task model.Task, e
 			stepResult.Status = "failed"
 			stepResult.Error = stringPtr(err.Error())
 			stepResults = append(stepResults, stepResult)
+			r.events.publish(execID, stepEvent(len(stepResults), stepResult))
 
 			// Update execution with partial results
 			if updateErr := r.execRepo.UpdateStepResults(ctx, execID, stepResults); updateErr != nil {
@@ -148,23 +313,48 @@ func (r *PipelineRunner) executePipeline(ctx context.Context, task model.Task, e
 			return stepResults, fmt.Errorf("step %d (%s) failed: %w", i+1, step.Type, err)
 		}
 
+		if step.CaptureOutput {
+			r.captureOutput(ctx, execID, i, result)
+		}
+
 		// Check if we should skip remaining steps (empty results)
-		if filter.SkipEmpty(result) {
+		if filter.SkipEmpty(result) && !digestAfter(task.Pipeline, i) {
 			stepResult.Status = "completed"
 			stepResult.Output = "No new items found"
 			stepResults = append(stepResults, stepResult)
+			r.events.publish(execID, stepEvent(len(stepResults), stepResult))
 			log.Printf("Task %s: no new items at step %d, skipping notification", task.ID, i+1)
+			// Nothing a digest passed on survived to be delivered
+			r.markDigestsSent(ctx, task.ID, flushes)
 			return stepResults, nil
 		}
 
 		stepResult.Status = "completed"
-		stepResult.Output = map[string]interface{}{
+		output := map[string]interface{}{
 			"item_count": result.ItemCount,
-			"metadata":   result.Metadata,
+			"metadata":   redactor.Value(result.Metadata),
 		}
+		if step.Debug {
+			output["data"] = redactor.Value(debugSnapshot(result.Data))
+		}
+		stepResult.Output = output
 		stepResults = append(stepResults, stepResult)
+		r.events.publish(execID, stepEvent(len(stepResults), stepResult))
+
+		// Count what reached users once, at the first delivery step
+		if isDeliveryStep(step.Type) && !delivered {
+			delivered = true
+			r.recordDelivered(ctx, task.ID, currentResult)
+		}
+		if isDeliveryStep(step.Type) {
+			r.markDigestsSent(ctx, task.ID, flushes)
+			flushes = nil
+		}
 
 		currentResult = result
+		if step.Name != "" {
+			outputs[step.Name] = result
+		}
 
 		// Update execution with progress
 		if updateErr := r.execRepo.UpdateStepResults(ctx, execID, stepResults); updateErr != nil {
@@ -172,10 +362,18 @@ func (r *PipelineRunner) executePipeline(ctx context.Context, task model.Task, e
 		}
 	}
 
+	// No delivery step followed a digest, so completing the run is enough
+	r.markDigestsSent(ctx, task.ID, flushes)
 	return stepResults, nil
 }
 
 func (r *PipelineRunner) executeStep(ctx context.Context, step model.PipelineStep, input *model.ExecutorResult) (*model.ExecutorResult, error) {
+	if isDeliveryStep(step.Type) {
+		if err := checkMinItems(step, input); err != nil {
+			return nil, err
+		}
+	}
+
 	switch step.Type {
 	case "scraper":
 		return r.scraperExec.Execute(ctx, input, step.Config)
@@ -183,9 +381,15 @@ func (r *PipelineRunner) executeStep(ctx context.Context, step model.PipelineSte
 	case "rss":
 		return r.rssExec.Execute(ctx, input, step.Config)
 
+	case "static":
+		return r.staticExec.Execute(ctx, input, step.Config)
+
 	case "ai_processor", "ai":
 		return r.aiExecutor.Execute(ctx, input, step.Config)
 
+	case "ai_filter":
+		return r.aiFilterExec.Execute(ctx, input, step.Config)
+
 	case "discord":
 		// Resolve webhook URL from database if not in config
 		if _, hasWebhook := step.Config["webhook_url"]; !hasWebhook {
@@ -199,43 +403,173 @@ func (r *PipelineRunner) executeStep(ctx context.Context, step model.PipelineSte
 		}
 		return r.discordExec.Execute(ctx, input, step.Config)
 
+	case "slack":
+		return r.slackExec.Execute(ctx, input, step.Config)
+
+	case "telegram":
+		return r.telegramExec.Execute(ctx, input, step.Config)
+
+	case "mastodon":
+		return r.mastodonExec.Execute(ctx, input, step.Config)
+
+	case "email":
+		return r.emailExec.Execute(ctx, input, step.Config)
+
+	case "webhook":
+		return r.webhookExec.Execute(ctx, input, step.Config)
+
 	case "filter":
 		return r.filterExec.Execute(ctx, input, step.Config)
 
+	case "limit":
+		return r.limitExec.Execute(ctx, input, step.Config)
+
+	case "assert":
+		return r.assertExec.Execute(ctx, input, step.Config)
+
+	case "transform":
+		return r.transformExec.Execute(ctx, input, step.Config)
+
+	case "parallel":
+		return r.executeParallel(ctx, step, input)
+
+	case "merge":
+		// Needs the outputs of earlier steps, which only the pipeline has
+		return nil, fmt.Errorf("merge steps only run as part of a pipeline")
+
+	case "digest":
+		// Pipelines run digests themselves to mark what they deliver as
+		// sent; elsewhere, as in a dry run, there's no task to hold items for
+		result, _, err := r.executeDigest(ctx, step, input)
+		return result, err
+
 	default:
 		return nil, fmt.Errorf("unknown step type: %s", step.Type)
 	}
 }
 
+// isDeliveryStep reports whether a step type sends items to an external destination
+func isDeliveryStep(stepType string) bool {
+	switch stepType {
+	case "discord", "slack", "telegram", "mastodon", "email", "webhook":
+		return true
+	}
+	return false
+}
+
+// recordDelivered adds the items handed to a delivery step to the analytics
+// aggregates and the recent delivered items shown on share links
+func (r *PipelineRunner) recordDelivered(ctx context.Context, taskID string, input *model.ExecutorResult) {
+	if r.statsRepo == nil || input == nil {
+		return
+	}
+
+	counts := make(map[model.ItemCount]int)
+	var delivered []model.DeliveredItem
+	switch v := input.Data.(type) {
+	case []model.ScrapedItem:
+		for _, item := range v {
+			counts[model.ItemCount{Source: item.Source, Category: item.Category}]++
+			delivered = append(delivered, model.DeliveredItem{Title: item.Title, URL: item.URL, Source: item.Source, Category: item.Category})
+		}
+	case []model.RSSItem:
+		for _, item := range v {
+			category := ""
+			if len(item.Categories) > 0 {
+				category = item.Categories[0]
+			}
+			counts[model.ItemCount{Source: item.Source, Category: category}]++
+			delivered = append(delivered, model.DeliveredItem{Title: item.Title, URL: item.Link, Source: item.Source, Category: category})
+		}
+	default:
+		return
+	}
+
+	var rows []model.ItemCount
+	for key, n := range counts {
+		key.Count = n
+		rows = append(rows, key)
+	}
+	if err := r.statsRepo.RecordItems(ctx, taskID, rows); err != nil {
+		log.Printf("Warning: failed to record item stats for task %s: %v", taskID, err)
+	}
+	if err := r.statsRepo.RecordDeliveredItems(ctx, taskID, delivered); err != nil {
+		log.Printf("Warning: failed to record delivered items for task %s: %v", taskID, err)
+	}
+}
+
 // ValidatePipeline validates a pipeline configuration
 func (r *PipelineRunner) ValidatePipeline(pipeline []model.PipelineStep) []error {
 	var errors []error
+	named := make(map[string]bool)
 
 	for i, step := range pipeline {
-		var err error
-		switch step.Type {
-		case "scraper":
-			err = r.scraperExec.Validate(step.Config)
-		case "rss":
-			err = r.rssExec.Validate(step.Config)
-		case "ai_processor", "ai":
-			err = r.aiExecutor.Validate(step.Config)
-		case "discord":
-			err = r.discordExec.Validate(step.Config)
-		case "filter":
-			err = r.filterExec.Validate(step.Config)
-		default:
-			err = fmt.Errorf("unknown step type: %s", step.Type)
+		err := r.validateStep(step)
+		if err == nil && step.Type == "merge" {
+			err = validateMergeSources(step.Config, named)
 		}
-
 		if err != nil {
 			errors = append(errors, fmt.Errorf("step %d: %w", i+1, err))
 		}
+		if step.Name != "" {
+			named[step.Name] = true
+		}
 	}
 
 	return errors
 }
 
+func (r *PipelineRunner) validateStep(step model.PipelineStep) error {
+	if isDeliveryStep(step.Type) {
+		if _, err := minItems(step.Config); err != nil {
+			return fmt.Errorf("%s %w", step.Type, err)
+		}
+	}
+
+	switch step.Type {
+	case "scraper":
+		return r.scraperExec.Validate(step.Config)
+	case "rss":
+		return r.rssExec.Validate(step.Config)
+	case "static":
+		return r.staticExec.Validate(step.Config)
+	case "ai_processor", "ai":
+		return r.aiExecutor.Validate(step.Config)
+	case "ai_filter":
+		return r.aiFilterExec.Validate(step.Config)
+	case "discord":
+		return r.discordExec.Validate(step.Config)
+	case "slack":
+		return r.slackExec.Validate(step.Config)
+	case "telegram":
+		return r.telegramExec.Validate(step.Config)
+	case "mastodon":
+		return r.mastodonExec.Validate(step.Config)
+	case "email":
+		return r.emailExec.Validate(step.Config)
+	case "webhook":
+		return r.webhookExec.Validate(step.Config)
+	case "filter":
+		return r.filterExec.Validate(step.Config)
+	case "limit":
+		return r.limitExec.Validate(step.Config)
+	case "assert":
+		return r.assertExec.Validate(step.Config)
+	case "transform":
+		return r.transformExec.Validate(step.Config)
+	case "parallel":
+		return r.validateParallel(step.Config)
+	case "merge":
+		_, err := mergeSources(step.Config)
+		return err
+	case "digest":
+		_, err := digestConfig(step.Config)
+		return err
+	default:
+		return fmt.Errorf("unknown step type: %s", step.Type)
+	}
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,332 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/crypto"
+	"github.com/multi-worker/internal/executor/ai"
+	"github.com/multi-worker/internal/executor/filter"
+	"github.com/multi-worker/internal/executor/static"
+	"github.com/multi-worker/internal/executor/webhook"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+	"github.com/multi-worker/internal/storage"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+const testEncryptionKey = "0123456789abcdef0123456789abcdef"
+
+// newTestRunner builds a runner on db with the steps tests use. Webhook
+// steps may post to 127.0.0.1, where tests run their fake endpoints.
+func newTestRunner(t *testing.T, db *storage.Database) *PipelineRunner {
+	t.Helper()
+	cipher, err := crypto.New(config.EncryptionConfig{Key: testEncryptionKey, KeyVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard := netguard.New(config.OutboundConfig{AllowedHosts: []string{"127.0.0.1"}})
+	cacheRepo := storage.NewCacheRepository(db)
+
+	return &PipelineRunner{
+		taskRepo:    storage.NewTaskRepository(db),
+		execRepo:    storage.NewExecutionRepository(db),
+		cacheRepo:   cacheRepo,
+		discordRepo: storage.NewDiscordRepository(db, cipher),
+		secretRepo:  storage.NewSecretRepository(db, cipher),
+		statsRepo:   storage.NewAnalyticsRepository(db),
+		webhookExec: webhook.NewExecutor(guard),
+		filterExec:  filter.NewExecutor(cacheRepo),
+		staticExec:  static.NewExecutor(),
+		events:      newExecutionEvents(),
+	}
+}
+
+// staticStep emits an item per title
+func staticStep(titles ...string) model.PipelineStep {
+	items := make([]interface{}, len(titles))
+	for i, title := range titles {
+		items[i] = map[string]interface{}{"title": title, "url": "https://example.com/" + title}
+	}
+	return model.PipelineStep{Type: "static", Config: map[string]interface{}{"items": items}}
+}
+
+func TestRunMasksSecretsInRecordedResults(t *testing.T) {
+	db := storagetest.Open(t)
+	runner := newTestRunner(t, db)
+	ctx := context.Background()
+
+	// The endpoint echoes the credential back in its error, as some APIs do
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid credential "+r.Header.Get("Authorization"), http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	const secret = "sk-live/4f9a"
+	source := staticStep("alert")
+	source.Config["items"] = []interface{}{map[string]interface{}{"title": `key {{secret "api_key"}}`}}
+	source.Debug = true
+	user := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, user.ID, source, model.PipelineStep{
+		Type: "webhook",
+		Config: map[string]interface{}{
+			"url":     server.URL + "/hook",
+			"headers": map[string]interface{}{"Authorization": `Bearer {{secret "api_key"}}`},
+		},
+	})
+	if err := runner.secretRepo.Set(ctx, task.ID, map[string]string{"api_key": secret}); err != nil {
+		t.Fatal(err)
+	}
+
+	execution, err := runner.Run(ctx, *task, "test", RunOptions{})
+	if err == nil {
+		t.Fatal("run succeeded, want the webhook step to fail")
+	}
+	if strings.Contains(err.Error(), secret) {
+		t.Errorf("run error leaks the secret: %v", err)
+	}
+
+	recorded, err := json.Marshal(execution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(recorded), secret) {
+		t.Errorf("recorded execution leaks the secret: %s", recorded)
+	}
+	if !strings.Contains(string(recorded), "invalid credential Bearer "+secretMask) {
+		t.Errorf("recorded execution lost the masked error: %s", recorded)
+	}
+
+	// The stored pipeline still holds the reference, not the value
+	stored, err := runner.taskRepo.FindByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := json.Marshal(stored.Pipeline); strings.Contains(string(raw), secret) {
+		t.Errorf("stored pipeline holds the secret: %s", raw)
+	}
+}
+
+// okServer accepts every request, counting them
+func okServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestDeliveredItemCountsAggregateAcrossRuns(t *testing.T) {
+	db := storagetest.Open(t)
+	runner := newTestRunner(t, db)
+	ctx := context.Background()
+	server, _ := okServer(t)
+
+	source := model.PipelineStep{Type: "static", Config: map[string]interface{}{"items": []interface{}{
+		map[string]interface{}{"title": "Go dev", "category": "jobs", "source": "remoteok"},
+		map[string]interface{}{"title": "Rust dev", "category": "jobs", "source": "hackernews"},
+		map[string]interface{}{"title": "Release notes", "category": "news", "source": "hackernews"},
+	}}}
+	deliver := model.PipelineStep{Type: "webhook", Config: map[string]interface{}{"url": server.URL}}
+
+	owner := storagetest.CreateUser(t, db, model.UserRoleUser)
+	other := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, owner.ID, source, deliver)
+	for i := 0; i < 3; i++ {
+		if _, err := runner.Run(ctx, *task, "test", RunOptions{}); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+	}
+
+	since := time.Now().AddDate(0, 0, -1)
+	totals := func(groupBy, ownerID string) map[string]int {
+		stats, err := runner.statsRepo.ItemCounts(ctx, groupBy, since, ownerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := make(map[string]int)
+		for _, s := range stats {
+			out[s.Group] += s.Count
+		}
+		return out
+	}
+
+	if got := totals("category", owner.ID); got["jobs"] != 6 || got["news"] != 3 || len(got) != 2 {
+		t.Errorf("by category = %v, want jobs 6 and news 3", got)
+	}
+	if got := totals("source", ""); got["hackernews"] != 6 || got["remoteok"] != 3 {
+		t.Errorf("by source = %v, want hackernews 6 and remoteok 3", got)
+	}
+	if got := totals("category", other.ID); len(got) != 0 {
+		t.Errorf("another user sees %v, want nothing", got)
+	}
+
+	recent, err := runner.statsRepo.RecentDeliveredItems(ctx, task.ID, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 9 {
+		t.Errorf("recorded %d delivered items, want 9", len(recent))
+	}
+}
+
+func TestResumeDeliversCapturedAIOutput(t *testing.T) {
+	db := storagetest.Open(t)
+	runner := newTestRunner(t, db)
+	ctx := context.Background()
+
+	var completions atomic.Int32
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		completions.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": map[string]string{"role": "assistant", "content": "Two Go jobs posted today"},
+			"done":    true,
+		})
+	}))
+	defer ollama.Close()
+	registry := ai.NewProviderRegistry(&config.AIConfig{
+		DefaultProvider: "ollama",
+		Ollama:          config.OllamaConfig{Model: "llama3", BaseURL: ollama.URL},
+	})
+	runner.aiExecutor = ai.NewExecutor(registry, nil)
+
+	// Delivery fails until the endpoint is fixed
+	var healthy atomic.Bool
+	var mu sync.Mutex
+	var delivered []string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		delivered = append(delivered, string(body))
+		mu.Unlock()
+	}))
+	defer hook.Close()
+
+	summarize := model.PipelineStep{Type: "ai_processor", CaptureOutput: true, Config: map[string]interface{}{"prompt": "Summarize"}}
+	deliver := model.PipelineStep{Type: "webhook", Config: map[string]interface{}{"url": hook.URL}}
+	user := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, user.ID, staticStep("go-dev", "go-sre"), summarize, deliver)
+
+	failed, err := runner.Run(ctx, *task, "test", RunOptions{})
+	if err == nil {
+		t.Fatal("run succeeded, want the delivery step to fail")
+	}
+	if completions.Load() != 1 {
+		t.Fatalf("made %d completions, want 1", completions.Load())
+	}
+
+	input, err := runner.CapturedOutput(ctx, failed.ID, 1)
+	if err != nil || input == nil {
+		t.Fatalf("captured AI output = %v, %v", input, err)
+	}
+	if _, ok := input.Data.(string); !ok {
+		t.Fatalf("captured output restored as %T, want the summary text", input.Data)
+	}
+
+	healthy.Store(true)
+	resumed, err := runner.Resume(ctx, *task, "test", 2, input)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if resumed.ID == failed.ID || resumed.Status != model.ExecutionStatusCompleted || resumed.TriggerType != model.TriggerTypeReplay {
+		t.Errorf("resumed execution %s is %s (%s), want a new completed replay", resumed.ID, resumed.Status, resumed.TriggerType)
+	}
+	if completions.Load() != 1 {
+		t.Errorf("resume made %d more completions, want none", completions.Load()-1)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delivered) != 1 || !strings.Contains(delivered[0], "Two Go jobs posted today") {
+		t.Errorf("delivered %q, want the captured summary", delivered)
+	}
+
+	// Only the delivery step ran
+	if len(resumed.StepResults) != 1 || resumed.StepResults[0].StepType != "webhook" {
+		t.Errorf("resumed results = %+v, want only the webhook step", resumed.StepResults)
+	}
+}
+
+func TestTriggerTypeFor(t *testing.T) {
+	tests := map[string]model.TriggerType{
+		"schedule":                             model.TriggerTypeSchedule,
+		"replay":                               model.TriggerTypeReplay,
+		"webhook":                              model.TriggerTypeWebhook,
+		"api":                                  model.TriggerTypeManual,
+		"7f1c0e1a-56f4-4d8e-9a3b-0c2f3e4d5a6b": model.TriggerTypeManual,
+	}
+	for triggeredBy, want := range tests {
+		if got := triggerTypeFor(triggeredBy); got != want {
+			t.Errorf("triggerTypeFor(%q) = %q, want %q", triggeredBy, got, want)
+		}
+	}
+}
+
+func TestExecutionsRecordAndFilterByTriggerType(t *testing.T) {
+	db := storagetest.Open(t)
+	runner := newTestRunner(t, db)
+	ctx := context.Background()
+
+	user := storagetest.CreateUser(t, db, model.UserRoleUser)
+	task := storagetest.CreateTask(t, db, user.ID, staticStep("a"))
+
+	scheduled, err := runner.Run(ctx, *task, "schedule", RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manual, err := runner.Run(ctx, *task, user.ID, RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhook, err := runner.RunWithInput(ctx, *task, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		execution   *model.Execution
+		triggeredBy string
+		triggerType model.TriggerType
+	}{
+		{scheduled, "schedule", model.TriggerTypeSchedule},
+		{manual, user.ID, model.TriggerTypeManual},
+		{webhook, "webhook", model.TriggerTypeWebhook},
+	} {
+		// The free-form value is kept alongside the normalized type
+		if tt.execution.TriggeredBy != tt.triggeredBy || tt.execution.TriggerType != tt.triggerType {
+			t.Errorf("execution recorded as %q/%q, want %q/%q",
+				tt.execution.TriggeredBy, tt.execution.TriggerType, tt.triggeredBy, tt.triggerType)
+		}
+
+		found, err := runner.execRepo.FindByTaskIDFiltered(ctx, task.ID, model.ExecutionFilter{TriggerType: tt.triggerType, Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != 1 || found[0].ID != tt.execution.ID {
+			t.Errorf("filtering by %s found %d executions, want only %s", tt.triggerType, len(found), tt.execution.ID)
+		}
+		count, err := runner.execRepo.CountFiltered(ctx, model.ExecutionFilter{TaskID: task.ID, TriggerType: tt.triggerType})
+		if err != nil || count != 1 {
+			t.Errorf("counting %s executions = %d, %v, want 1", tt.triggerType, count, err)
+		}
+	}
+
+	replays, err := runner.execRepo.FindRecentFiltered(ctx, model.ExecutionFilter{OwnerID: user.ID, TriggerType: model.TriggerTypeReplay, Limit: 10})
+	if err != nil || len(replays) != 0 {
+		t.Errorf("replay filter found %d executions, %v, want none", len(replays), err)
+	}
+}
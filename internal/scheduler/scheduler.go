@@ -2,27 +2,76 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/robfig/cron/v3"
+	"github.com/multi-worker/internal/config"
 	"github.com/multi-worker/internal/model"
 	"github.com/multi-worker/internal/storage"
+	"github.com/robfig/cron/v3"
 )
 
+// ErrTooManyExecutions is returned when a manual run can't get an execution
+// slot within the configured wait
+var ErrTooManyExecutions = errors.New("too many executions in progress, try again later")
+
+// ErrTaskRunning is returned when a manual run is requested for a task that is
+// already running and doesn't allow overlapping runs
+var ErrTaskRunning = errors.New("task is already running")
+
+// ErrTaskArchived is returned when a run is requested for an archived task
+var ErrTaskArchived = errors.New("task is archived; restore it first")
+
+// ErrTaskDisabled is returned when an inbound webhook fires for a paused task
+var ErrTaskDisabled = errors.New("task is disabled")
+
+// errInterrupted is the cause of runs cancelled by shutdown, and the error
+// recorded on executions a previous process left unfinished
+var errInterrupted = errors.New("interrupted by shutdown")
+
+// idlePollInterval is how often Stop checks whether in-flight runs are done
+const idlePollInterval = 100 * time.Millisecond
+
+// defaultExecutionTimeout bounds a pipeline run when the task doesn't set its own timeout
+const defaultExecutionTimeout = 30 * time.Minute
+
 // Scheduler manages task scheduling and execution
 type Scheduler struct {
-	cron       *cron.Cron
-	taskRepo   *storage.TaskRepository
-	execRepo   *storage.ExecutionRepository
-	runner     *PipelineRunner
-	entryMap   map[string]cron.EntryID
-	mu         sync.RWMutex
-	running    bool
-	ctx        context.Context
-	cancel     context.CancelFunc
+	cron     *cron.Cron
+	taskRepo *storage.TaskRepository
+	execRepo *storage.ExecutionRepository
+	runner   *PipelineRunner
+	entryMap map[string][]cron.EntryID // A task has one entry per schedule
+	mu       sync.RWMutex
+	running  bool
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+
+	// Cancelled when shutdown begins so runs still queued for a slot give up,
+	// while those already executing get the shutdown grace period
+	queueCtx      context.Context
+	stopQueue     context.CancelFunc
+	shutdownGrace time.Duration
+
+	// Worker pool bounding concurrent scheduled runs; nil means unlimited
+	slots        chan struct{}
+	skipWhenFull bool
+	activeRuns   atomic.Int64
+
+	// Global limit on in-flight executions, scheduled and manual; nil means unlimited
+	execSlots   chan struct{}
+	triggerWait time.Duration
+	inFlight    atomic.Int64
+
+	// Tasks with a run in progress, so a task without allow_overlap never
+	// runs twice at once however its runs are started
+	activeMu    sync.Mutex
+	activeTasks map[string]bool
 }
 
 // NewScheduler creates a new scheduler
@@ -30,14 +79,26 @@ func NewScheduler(
 	taskRepo *storage.TaskRepository,
 	execRepo *storage.ExecutionRepository,
 	runner *PipelineRunner,
+	cfg config.SchedulerConfig,
 ) *Scheduler {
-	return &Scheduler{
-		cron:     cron.New(cron.WithSeconds()),
-		taskRepo: taskRepo,
-		execRepo: execRepo,
-		runner:   runner,
-		entryMap: make(map[string]cron.EntryID),
+	s := &Scheduler{
+		cron:          cron.New(cron.WithSeconds()),
+		taskRepo:      taskRepo,
+		execRepo:      execRepo,
+		runner:        runner,
+		entryMap:      make(map[string][]cron.EntryID),
+		activeTasks:   make(map[string]bool),
+		skipWhenFull:  cfg.SkipWhenFull,
+		triggerWait:   cfg.TriggerWait,
+		shutdownGrace: cfg.ShutdownGrace,
+	}
+	if cfg.MaxConcurrent > 0 {
+		s.slots = make(chan struct{}, cfg.MaxConcurrent)
 	}
+	if cfg.MaxExecutions > 0 {
+		s.execSlots = make(chan struct{}, cfg.MaxExecutions)
+	}
+	return s
 }
 
 // Start starts the scheduler
@@ -49,7 +110,23 @@ func (s *Scheduler) Start(ctx context.Context) error {
 		return nil
 	}
 
-	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.ctx, s.cancel = context.WithCancelCause(ctx)
+	s.queueCtx, s.stopQueue = context.WithCancel(s.ctx)
+
+	// Nothing runs yet, so executions still pending or running were cut
+	// short by a crash or a shutdown that outlasted its grace period
+	if n, err := s.execRepo.FailUnfinished(ctx, errInterrupted.Error()); err != nil {
+		log.Printf("Warning: failed to mark unfinished executions as failed: %v", err)
+	} else if n > 0 {
+		log.Printf("Marked %d unfinished executions as failed: %v", n, errInterrupted)
+	}
+
+	// Tasks those runs left marked running would otherwise never fire again
+	if n, err := s.taskRepo.ResetOrphanedRunning(ctx); err != nil {
+		log.Printf("Warning: failed to reset orphaned running tasks: %v", err)
+	} else if n > 0 {
+		log.Printf("Reset %d tasks left running by an interrupted run to enabled", n)
+	}
 
 	// Load all enabled tasks
 	tasks, err := s.taskRepo.FindEnabled(ctx)
@@ -57,9 +134,18 @@ func (s *Scheduler) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to load tasks: %w", err)
 	}
 
+	now := time.Now()
 	for _, task := range tasks {
 		if err := s.scheduleTask(task); err != nil {
 			log.Printf("Failed to schedule task %s: %v", task.ID, err)
+			continue
+		}
+		if !task.CatchUp {
+			continue
+		}
+		if missed, ok := missedRun(task, now); ok {
+			log.Printf("Task %s missed its run at %s while the server was down, catching up", task.ID, missed.Format(time.RFC3339))
+			go s.runScheduled(task.ID)
 		}
 	}
 
@@ -70,21 +156,62 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop stops the scheduler gracefully
-func (s *Scheduler) Stop() {
+// Stop stops the scheduler gracefully: no new runs start, runs in flight
+// get the shutdown grace period to finish, and any still going after it are
+// cancelled and recorded as interrupted
+func (s *Scheduler) Stop(ctx context.Context) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if !s.running {
-		return
+		s.mu.Unlock()
+		return nil
 	}
+	s.running = false
+	s.mu.Unlock()
 
-	s.cancel()
-	ctx := s.cron.Stop()
-	<-ctx.Done()
+	// The lock is released while waiting, as finishing jobs take it to
+	// update their next run time
+	s.stopQueue()
+	jobsDone := s.cron.Stop().Done()
+
+	grace := time.NewTimer(s.shutdownGrace)
+	defer grace.Stop()
+	if !s.waitIdle(ctx, jobsDone, grace.C) {
+		log.Printf("Cancelling %d executions still in flight at shutdown", s.InFlightCount())
+	}
+	s.cancel(errInterrupted)
+
+	// Give cancelled runs the chance to record their failure, but never wait
+	// past the caller's deadline
+	if !s.waitIdle(ctx, jobsDone, nil) {
+		return fmt.Errorf("timed out waiting for running jobs: %w", ctx.Err())
+	}
 
-	s.running = false
 	log.Println("Scheduler stopped")
+	return nil
+}
+
+// waitIdle waits until the cron jobs are done and no execution is in
+// flight, reporting false if ctx or the deadline ends the wait first
+func (s *Scheduler) waitIdle(ctx context.Context, jobsDone <-chan struct{}, deadline <-chan time.Time) bool {
+	ticker := time.NewTicker(idlePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-jobsDone:
+			if s.inFlight.Load() == 0 {
+				return true
+			}
+		default:
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
 }
 
 // AddTask adds a new task to the scheduler
@@ -100,13 +227,10 @@ func (s *Scheduler) UpdateTask(task model.Task) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Remove old entry if exists
-	if entryID, ok := s.entryMap[task.ID]; ok {
-		s.cron.Remove(entryID)
-		delete(s.entryMap, task.ID)
-	}
+	// Remove old entries if they exist
+	s.removeEntries(task.ID)
 
-	// Add new entry if enabled
+	// Add new entries if enabled
 	if task.Status == model.TaskStatusEnabled {
 		return s.scheduleTask(task)
 	}
@@ -119,14 +243,19 @@ func (s *Scheduler) RemoveTask(taskID string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if entryID, ok := s.entryMap[taskID]; ok {
+	s.removeEntries(taskID)
+}
+
+// removeEntries drops every cron entry of a task; the caller holds s.mu
+func (s *Scheduler) removeEntries(taskID string) {
+	for _, entryID := range s.entryMap[taskID] {
 		s.cron.Remove(entryID)
-		delete(s.entryMap, taskID)
 	}
+	delete(s.entryMap, taskID)
 }
 
 // TriggerTask triggers a task manually
-func (s *Scheduler) TriggerTask(ctx context.Context, taskID, triggeredBy string) (*model.Execution, error) {
+func (s *Scheduler) TriggerTask(ctx context.Context, taskID, triggeredBy string, opts RunOptions) (*model.Execution, error) {
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil {
 		return nil, err
@@ -134,22 +263,102 @@ func (s *Scheduler) TriggerTask(ctx context.Context, taskID, triggeredBy string)
 	if task == nil {
 		return nil, fmt.Errorf("task not found")
 	}
+	if task.Status == model.TaskStatusArchived {
+		return nil, ErrTaskArchived
+	}
+
+	if !s.lockTask(*task) {
+		return nil, ErrTaskRunning
+	}
+	defer s.unlockTask(*task)
+
+	if err := s.acquireManualExecution(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseExecution()
 
-	return s.runner.Run(ctx, *task, triggeredBy)
+	ctx, stop := s.interruptible(ctx)
+	defer stop()
+	return s.runner.Run(ctx, *task, triggeredBy, opts)
 }
 
-// GetNextRun returns the next run time for a task
+// ResumeTask re-runs a task's pipeline from fromStep (zero-based) with the
+// given input, subject to the same execution limit as manual triggers
+func (s *Scheduler) ResumeTask(ctx context.Context, task model.Task, triggeredBy string, fromStep int, input *model.ExecutorResult) (*model.Execution, error) {
+	if task.Status == model.TaskStatusArchived {
+		return nil, ErrTaskArchived
+	}
+	if !s.lockTask(task) {
+		return nil, ErrTaskRunning
+	}
+	defer s.unlockTask(task)
+
+	if err := s.acquireManualExecution(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseExecution()
+
+	ctx, stop := s.interruptible(ctx)
+	defer stop()
+	return s.runner.Resume(ctx, task, triggeredBy, fromStep, input)
+}
+
+// TriggerWebhook runs a task for an inbound webhook, with input as the first
+// step's input. Unlike manual triggers it skips disabled tasks, so pausing a
+// task stops its webhook too.
+func (s *Scheduler) TriggerWebhook(ctx context.Context, task model.Task, input *model.ExecutorResult) (*model.Execution, error) {
+	switch task.Status {
+	case model.TaskStatusArchived:
+		return nil, ErrTaskArchived
+	case model.TaskStatusDisabled:
+		return nil, ErrTaskDisabled
+	}
+	if !s.lockTask(task) {
+		return nil, ErrTaskRunning
+	}
+	defer s.unlockTask(task)
+
+	if err := s.acquireManualExecution(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseExecution()
+
+	ctx, stop := s.interruptible(ctx)
+	defer stop()
+	return s.runner.RunWithInput(ctx, task, input)
+}
+
+// DryRun executes a pipeline without recording or delivering anything,
+// subject to the same execution limit as manual triggers
+func (s *Scheduler) DryRun(ctx context.Context, pipeline []model.PipelineStep) (*DryRunResult, error) {
+	if err := s.acquireManualExecution(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseExecution()
+
+	ctx, stop := s.interruptible(ctx)
+	defer stop()
+	return s.runner.DryRun(ctx, pipeline), nil
+}
+
+// GetNextRun returns the next run time for a task, the earliest across its schedules
 func (s *Scheduler) GetNextRun(taskID string) *time.Time {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if entryID, ok := s.entryMap[taskID]; ok {
+	return s.nextRun(taskID)
+}
+
+// nextRun returns the earliest next run of a task's entries; the caller holds s.mu
+func (s *Scheduler) nextRun(taskID string) *time.Time {
+	var next *time.Time
+	for _, entryID := range s.entryMap[taskID] {
 		entry := s.cron.Entry(entryID)
-		if !entry.Next.IsZero() {
-			return &entry.Next
+		if !entry.Next.IsZero() && (next == nil || entry.Next.Before(*next)) {
+			next = &entry.Next
 		}
 	}
-	return nil
+	return next
 }
 
 // GetScheduledTasks returns all scheduled task IDs
@@ -164,6 +373,106 @@ func (s *Scheduler) GetScheduledTasks() []string {
 	return ids
 }
 
+// RunningCount returns the number of scheduled runs currently holding a pool slot
+func (s *Scheduler) RunningCount() int {
+	return int(s.activeRuns.Load())
+}
+
+// MaxConcurrent returns the worker pool size, or 0 when unlimited
+func (s *Scheduler) MaxConcurrent() int {
+	return cap(s.slots)
+}
+
+// InFlightCount returns the number of executions currently running, scheduled or manual
+func (s *Scheduler) InFlightCount() int {
+	return int(s.inFlight.Load())
+}
+
+// MaxExecutions returns the global execution limit, or 0 when unlimited
+func (s *Scheduler) MaxExecutions() int {
+	return cap(s.execSlots)
+}
+
+// acquireSlot reserves a worker pool slot, waiting for one to free up unless
+// the scheduler is configured to skip runs when the pool is full
+func (s *Scheduler) acquireSlot(ctx context.Context) bool {
+	if s.slots != nil {
+		if s.skipWhenFull {
+			select {
+			case s.slots <- struct{}{}:
+			default:
+				return false
+			}
+		} else {
+			select {
+			case s.slots <- struct{}{}:
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+	s.activeRuns.Add(1)
+	return true
+}
+
+func (s *Scheduler) releaseSlot() {
+	s.activeRuns.Add(-1)
+	if s.slots != nil {
+		<-s.slots
+	}
+}
+
+// acquireExecution takes a global execution slot, waiting until ctx is done
+// for one to free up
+func (s *Scheduler) acquireExecution(ctx context.Context) error {
+	if s.execSlots != nil {
+		select {
+		case s.execSlots <- struct{}{}:
+		default:
+			select {
+			case s.execSlots <- struct{}{}:
+			case <-ctx.Done():
+				return ErrTooManyExecutions
+			}
+		}
+	}
+	s.inFlight.Add(1)
+	return nil
+}
+
+// acquireManualExecution takes a global execution slot for a manual run,
+// giving up after the configured trigger wait
+func (s *Scheduler) acquireManualExecution(ctx context.Context) error {
+	waitCtx, cancel := context.WithTimeout(ctx, s.triggerWait)
+	defer cancel()
+	return s.acquireExecution(waitCtx)
+}
+
+// interruptible ties a manual run to the scheduler, so shutdown cancels it
+// after the grace period just like a scheduled run
+func (s *Scheduler) interruptible(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	s.mu.RLock()
+	base := s.ctx
+	s.mu.RUnlock()
+	if base == nil {
+		return ctx, func() { cancel(nil) }
+	}
+
+	unlink := context.AfterFunc(base, func() { cancel(context.Cause(base)) })
+	return ctx, func() {
+		unlink()
+		cancel(nil)
+	}
+}
+
+func (s *Scheduler) releaseExecution() {
+	s.inFlight.Add(-1)
+	if s.execSlots != nil {
+		<-s.execSlots
+	}
+}
+
 // IsRunning returns whether the scheduler is running
 func (s *Scheduler) IsRunning() bool {
 	s.mu.RLock()
@@ -176,83 +485,297 @@ func (s *Scheduler) scheduleTask(task model.Task) error {
 		return nil
 	}
 
-	// Parse cron expression
-	schedule := task.Schedule
+	// Evaluate the schedule in the task's own timezone
+	if err := ValidateTimezone(task.Timezone); err != nil {
+		return err
+	}
+
+	// Arm one entry per schedule; on a bad expression, drop the ones already armed
+	for _, expr := range task.AllSchedules() {
+		spec, err := cronSpec(expr, task.Timezone)
+		if err == nil {
+			var entryID cron.EntryID
+			entryID, err = s.cron.AddFunc(spec, func() { s.runScheduled(task.ID) })
+			if err == nil {
+				s.entryMap[task.ID] = append(s.entryMap[task.ID], entryID)
+				continue
+			}
+		}
+		s.removeEntries(task.ID)
+		return fmt.Errorf("invalid cron expression '%s': %w", expr, err)
+	}
+
+	// Set initial next run time (without updating last_run_at)
+	if next := s.nextRun(task.ID); next != nil {
+		if err := s.taskRepo.UpdateNextRun(s.ctx, task.ID, *next); err != nil {
+			log.Printf("Warning: failed to set initial next run time for task %s: %v", task.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// runScheduled is the cron job shared by all of a task's schedules
+func (s *Scheduler) runScheduled(taskID string) {
+	// The execution timeout is applied per task by the runner. Waiting for a
+	// slot stops at shutdown; a run that got one may finish within the grace.
+	ctx := s.ctx
+	queueCtx := s.queueCtx
+
+	// Refresh task from database
+	currentTask, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || currentTask == nil {
+		log.Printf("Task %s not found, removing from scheduler", taskID)
+		s.RemoveTask(taskID)
+		return
+	}
+
+	// Skip if task is not enabled or already running; two schedules firing
+	// at the same moment therefore start a single run. The status check
+	// alone races when two ticks read it before either run marks the task
+	// running, so the in-memory lock decides.
+	if currentTask.Status == model.TaskStatusRunning && !currentTask.AllowOverlap {
+		log.Printf("Task %s is already running, skipping scheduled execution", taskID)
+		return
+	}
+	if currentTask.Status != model.TaskStatusEnabled && currentTask.Status != model.TaskStatusRunning {
+		return
+	}
+	if !s.lockTask(*currentTask) {
+		log.Printf("Task %s is already running, skipping scheduled execution", taskID)
+		return
+	}
+	defer s.unlockTask(*currentTask)
+
+	if !s.acquireSlot(queueCtx) {
+		log.Printf("Task %s skipped: max concurrency reached", taskID)
+		return
+	}
+	if s.execSlots != nil && len(s.execSlots) == cap(s.execSlots) {
+		log.Printf("Task %s deferred: %d executions in flight, waiting for a free slot", taskID, s.InFlightCount())
+	}
+	if err := s.acquireExecution(queueCtx); err != nil {
+		s.releaseSlot()
+		log.Printf("Task %s skipped: scheduler stopped while waiting for an execution slot", taskID)
+		return
+	}
+	if queueCtx.Err() != nil {
+		s.releaseExecution()
+		s.releaseSlot()
+		log.Printf("Task %s skipped: scheduler is shutting down", taskID)
+		return
+	}
+	_, err = s.runner.Run(ctx, *currentTask, "schedule", RunOptions{})
+	s.releaseExecution()
+	s.releaseSlot()
+	if err != nil {
+		log.Printf("Task %s execution failed: %v", taskID, err)
+	}
+
+	// Update next run time after execution
+	s.mu.RLock()
+	if next := s.nextRun(taskID); next != nil {
+		if err := s.taskRepo.UpdateNextRun(ctx, taskID, *next); err != nil {
+			log.Printf("Warning: failed to update next run time for task %s: %v", taskID, err)
+		}
+	}
+	s.mu.RUnlock()
+}
+
+// missedRun returns the first fire time of the task's schedules between its
+// last run (or creation, if it never ran) and now. However many fire times
+// were missed, a catch-up is a single run.
+func missedRun(task model.Task, now time.Time) (time.Time, bool) {
+	since := task.CreatedAt
+	if task.LastRunAt != nil {
+		since = *task.LastRunAt
+	}
+
+	var missed time.Time
+	for _, expr := range task.AllSchedules() {
+		spec, err := cronSpec(expr, task.Timezone)
+		if err != nil {
+			continue
+		}
+		schedule, err := cronParser.Parse(spec)
+		if err != nil {
+			continue
+		}
+		next := schedule.Next(since)
+		if !next.IsZero() && next.Before(now) && (missed.IsZero() || next.Before(missed)) {
+			missed = next
+		}
+	}
+	return missed, !missed.IsZero()
+}
+
+// lockTask marks a task as running, failing if it already is. Tasks that
+// allow overlap always succeed.
+func (s *Scheduler) lockTask(task model.Task) bool {
+	if task.AllowOverlap {
+		return true
+	}
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	if s.activeTasks[task.ID] {
+		return false
+	}
+	s.activeTasks[task.ID] = true
+	return true
+}
+
+// unlockTask releases the lock taken by lockTask
+func (s *Scheduler) unlockTask(task model.Task) {
+	if task.AllowOverlap {
+		return
+	}
+	s.activeMu.Lock()
+	delete(s.activeTasks, task.ID)
+	s.activeMu.Unlock()
+}
+
+// cronParser accepts the same six-field specs as the scheduler's cron
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// NormalizeSchedule returns the six-field expression the scheduler runs a
+// schedule as: shortcuts are expanded and five-field expressions get a
+// seconds field of 0. Task creation and scheduling both go through it, so an
+// expression accepted on save is one the scheduler can arm.
+func NormalizeSchedule(schedule string) (string, error) {
+	schedule = strings.TrimSpace(schedule)
 
 	// Support shortcuts
 	switch schedule {
 	case "@hourly":
 		schedule = "0 0 * * * *"
-	case "@daily":
+	case "@daily", "@midnight":
 		schedule = "0 0 0 * * *"
 	case "@weekly":
 		schedule = "0 0 0 * * 0"
 	case "@monthly":
 		schedule = "0 0 0 1 * *"
+	case "@yearly", "@annually":
+		schedule = "0 0 0 1 1 *"
 	}
 
-	// If schedule doesn't have 6 parts, assume it's a 5-part cron and add seconds
-	parts := len(splitCronParts(schedule))
-	if parts == 5 {
-		schedule = "0 " + schedule
+	if !strings.HasPrefix(schedule, "@") {
+		// If schedule doesn't have 6 parts, assume it's a 5-part cron and add seconds
+		switch parts := splitCronParts(schedule); len(parts) {
+		case 5:
+			schedule = "0 " + strings.Join(parts, " ")
+		case 6:
+			schedule = strings.Join(parts, " ")
+		default:
+			return "", fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week) or 6 with seconds first, got %d", len(parts))
+		}
 	}
 
-	entryID, err := s.cron.AddFunc(schedule, func() {
-		ctx, cancel := context.WithTimeout(s.ctx, 30*time.Minute)
-		defer cancel()
+	if _, err := cronParser.Parse(schedule); err != nil {
+		return "", err
+	}
+	return schedule, nil
+}
 
-		// Refresh task from database
-		currentTask, err := s.taskRepo.FindByID(ctx, task.ID)
-		if err != nil || currentTask == nil {
-			log.Printf("Task %s not found, removing from scheduler", task.ID)
-			s.RemoveTask(task.ID)
-			return
-		}
+// cronSpec turns a schedule into the spec given to cron: the normalized
+// expression with the timezone prefixed
+func cronSpec(schedule, timezone string) (string, error) {
+	schedule, err := NormalizeSchedule(schedule)
+	if err != nil {
+		return "", err
+	}
 
-		// Skip if task is not enabled or already running
-		if currentTask.Status == model.TaskStatusRunning {
-			log.Printf("Task %s is already running, skipping scheduled execution", task.ID)
-			return
-		}
-		if currentTask.Status != model.TaskStatusEnabled {
-			return
-		}
+	if timezone != "" {
+		schedule = "CRON_TZ=" + timezone + " " + schedule
+	}
+	return schedule, nil
+}
 
-		_, err = s.runner.Run(ctx, *currentTask, "schedule")
+// NextRuns returns the first n times after from that any of the task's
+// schedules fire, evaluated the way scheduleTask arms them
+func NextRuns(task model.Task, n int, from time.Time) (*model.NextRuns, error) {
+	if err := ValidateTimezone(task.Timezone); err != nil {
+		return nil, err
+	}
+	loc := time.Local
+	if task.Timezone != "" {
+		loc, _ = time.LoadLocation(task.Timezone)
+	}
+
+	result := &model.NextRuns{TaskID: task.ID, Timezone: loc.String()}
+	if task.Timezone == "" {
+		// Tasks without a timezone run in the server's; name it rather than "Local"
+		result.Timezone, _ = from.In(loc).Zone()
+	}
+	var schedules []cron.Schedule
+	for _, expr := range task.AllSchedules() {
+		normalized, err := NormalizeSchedule(expr)
 		if err != nil {
-			log.Printf("Task %s execution failed: %v", task.ID, err)
+			return nil, fmt.Errorf("invalid cron expression '%s': %w", expr, err)
 		}
+		schedule, err := cronParser.Parse(normalized)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression '%s': %w", expr, err)
+		}
+		result.Schedules = append(result.Schedules, normalized)
+		schedules = append(schedules, schedule)
+	}
 
-		// Update next run time after execution
-		s.mu.RLock()
-		if entryID, ok := s.entryMap[task.ID]; ok {
-			entry := s.cron.Entry(entryID)
-			if !entry.Next.IsZero() {
-				if err := s.taskRepo.UpdateNextRun(ctx, task.ID, entry.Next); err != nil {
-					log.Printf("Warning: failed to update next run time for task %s: %v", task.ID, err)
-				}
+	// Step every schedule forward together, taking the earliest each time;
+	// schedules firing at the same moment start a single run
+	next := make([]time.Time, len(schedules))
+	for i, schedule := range schedules {
+		next[i] = schedule.Next(from.In(loc))
+	}
+	for len(result.Runs) < n {
+		earliest := time.Time{}
+		for _, t := range next {
+			if !t.IsZero() && (earliest.IsZero() || t.Before(earliest)) {
+				earliest = t
+			}
+		}
+		if earliest.IsZero() {
+			break
+		}
+		result.Runs = append(result.Runs, earliest)
+		for i, t := range next {
+			if t.Equal(earliest) {
+				next[i] = schedules[i].Next(t)
 			}
 		}
-		s.mu.RUnlock()
-	})
-
-	if err != nil {
-		return fmt.Errorf("invalid cron expression '%s': %w", task.Schedule, err)
 	}
+	return result, nil
+}
 
-	s.entryMap[task.ID] = entryID
-
-	// Set initial next run time (without updating last_run_at)
-	entry := s.cron.Entry(entryID)
-	if !entry.Next.IsZero() {
-		if err := s.taskRepo.UpdateNextRun(s.ctx, task.ID, entry.Next); err != nil {
-			log.Printf("Warning: failed to set initial next run time for task %s: %v", task.ID, err)
+// ValidateSchedule checks that each expression is a cron expression the
+// scheduler accepts
+func ValidateSchedule(exprs ...string) error {
+	for _, expr := range exprs {
+		if _, err := NormalizeSchedule(expr); err != nil {
+			return fmt.Errorf("invalid cron expression '%s': %w", expr, err)
 		}
 	}
+	return nil
+}
 
+// ValidateTimezone checks that tz is a timezone name known to the system
+func ValidateTimezone(tz string) error {
+	if tz == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("invalid timezone '%s'", tz)
+	}
 	return nil
 }
 
+// executionTimeout returns the task's configured timeout or the scheduler default
+func executionTimeout(task model.Task) time.Duration {
+	if task.TimeoutSeconds > 0 {
+		return time.Duration(task.TimeoutSeconds) * time.Second
+	}
+	return defaultExecutionTimeout
+}
+
 func splitCronParts(schedule string) []string {
 	var parts []string
 	current := ""
@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/model"
+)
+
+func TestExecutionLimitBoundsConcurrency(t *testing.T) {
+	const limit = 3
+	s := NewScheduler(nil, nil, nil, config.SchedulerConfig{MaxExecutions: limit, TriggerWait: 50 * time.Millisecond})
+
+	var running, peak, done atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.acquireExecution(context.Background()); err != nil {
+				t.Error(err)
+				return
+			}
+			defer s.releaseExecution()
+
+			n := running.Add(1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			if got := s.InFlightCount(); got > limit {
+				t.Errorf("in-flight count %d exceeds the limit", got)
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			done.Add(1)
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > limit {
+		t.Errorf("%d executions ran at once, limit is %d", peak.Load(), limit)
+	}
+	if done.Load() != 20 {
+		t.Errorf("%d of 20 executions ran; queued ones must wait, not be dropped", done.Load())
+	}
+	if s.InFlightCount() != 0 {
+		t.Errorf("in-flight count is %d after all runs finished", s.InFlightCount())
+	}
+}
+
+func TestManualTriggerGivesUpWhenFull(t *testing.T) {
+	s := NewScheduler(nil, nil, nil, config.SchedulerConfig{MaxExecutions: 1, TriggerWait: 20 * time.Millisecond})
+	ctx := context.Background()
+
+	if err := s.acquireManualExecution(ctx); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := s.acquireManualExecution(ctx); !errors.Is(err, ErrTooManyExecutions) {
+		t.Fatalf("err = %v, want ErrTooManyExecutions", err)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("gave up after %v, want the trigger wait", waited)
+	}
+	if s.InFlightCount() != 1 {
+		t.Errorf("in-flight count = %d, want 1", s.InFlightCount())
+	}
+
+	// A slot freed during the wait is taken
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		s.releaseExecution()
+	}()
+	s.triggerWait = time.Second
+	if err := s.acquireManualExecution(ctx); err != nil {
+		t.Errorf("err = %v after a slot was freed", err)
+	}
+}
+
+func TestTaskWithTwoSchedulesFiresAtBoth(t *testing.T) {
+	s := NewScheduler(nil, nil, nil, config.SchedulerConfig{})
+	task := model.Task{
+		ID:        "task-1",
+		Status:    model.TaskStatusEnabled,
+		Timezone:  "UTC",
+		Schedule:  "0 0 9 * * *",
+		Schedules: []string{"0 0 17 * * *", "0 0 9 * * *"}, // The repeat arms nothing extra
+	}
+	if err := s.AddTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(s.entryMap[task.ID]); got != 2 || len(s.cron.Entries()) != 2 {
+		t.Fatalf("armed %d entries (%d in cron), want one per schedule", got, len(s.cron.Entries()))
+	}
+
+	// Each entry fires at its own time, and both run the same task
+	from := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	var fires []string
+	for _, id := range s.entryMap[task.ID] {
+		fires = append(fires, s.cron.Entry(id).Schedule.Next(from).Format("15:04"))
+	}
+	sort.Strings(fires)
+	if got := strings.Join(fires, ", "); got != "09:00, 17:00" {
+		t.Errorf("entries fire at %s, want 09:00 and 17:00", got)
+	}
+
+	runs, err := NextRuns(task, 4, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, run := range runs.Runs {
+		got = append(got, run.Format("02 15:04"))
+	}
+	if want := "15 09:00, 15 17:00, 16 09:00, 16 17:00"; strings.Join(got, ", ") != want {
+		t.Errorf("next runs = %v, want %s", got, want)
+	}
+}
+
+func TestRemovingATaskDropsAllItsEntries(t *testing.T) {
+	s := NewScheduler(nil, nil, nil, config.SchedulerConfig{})
+	task := model.Task{ID: "task-1", Status: model.TaskStatusEnabled, Schedules: []string{"0 0 9 * * *", "0 0 17 * * *", "@hourly"}}
+	other := model.Task{ID: "task-2", Status: model.TaskStatusEnabled, Schedule: "0 30 * * * *"}
+	for _, tk := range []model.Task{task, other} {
+		if err := s.AddTask(tk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.cron.Entries()) != 4 {
+		t.Fatalf("%d cron entries, want 4", len(s.cron.Entries()))
+	}
+
+	// Updating replaces every entry rather than adding to them
+	task.Schedules = []string{"0 0 12 * * *"}
+	if err := s.UpdateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.entryMap[task.ID]) != 1 || len(s.cron.Entries()) != 2 {
+		t.Errorf("after update: %d entries for the task, %d in cron; want 1 and 2", len(s.entryMap[task.ID]), len(s.cron.Entries()))
+	}
+
+	// A bad expression leaves none of the task's schedules armed
+	task.Schedules = []string{"0 0 9 * * *", "not a schedule"}
+	if err := s.UpdateTask(task); err == nil {
+		t.Error("invalid schedule was accepted")
+	}
+	if len(s.entryMap[task.ID]) != 0 || len(s.cron.Entries()) != 1 {
+		t.Errorf("after a bad update: %d entries for the task, %d in cron; want 0 and 1", len(s.entryMap[task.ID]), len(s.cron.Entries()))
+	}
+
+	if err := s.UpdateTask(model.Task{ID: task.ID, Status: model.TaskStatusEnabled, Schedules: []string{"@daily", "@hourly"}}); err != nil {
+		t.Fatal(err)
+	}
+	s.RemoveTask(task.ID)
+	if _, ok := s.entryMap[task.ID]; ok || len(s.cron.Entries()) != 1 || s.GetNextRun(task.ID) != nil {
+		t.Errorf("after removal: %d cron entries, want only the other task's", len(s.cron.Entries()))
+	}
+	if ids := s.GetScheduledTasks(); len(ids) != 1 || ids[0] != other.ID {
+		t.Errorf("scheduled tasks = %v, want only %s", ids, other.ID)
+	}
+}
+
+func TestShutdownInterruptsManualRuns(t *testing.T) {
+	s := NewScheduler(nil, nil, nil, config.SchedulerConfig{})
+
+	// Before Start there is nothing to tie a run to
+	ctx, stop := s.interruptible(context.Background())
+	if ctx.Err() != nil {
+		t.Fatalf("run context done before shutdown: %v", ctx.Err())
+	}
+	stop()
+
+	s.ctx, s.cancel = context.WithCancelCause(context.Background())
+	ctx, stop = s.interruptible(context.Background())
+	defer stop()
+	s.cancel(errInterrupted)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("manual run not cancelled by shutdown")
+	}
+	if cause := context.Cause(ctx); !errors.Is(cause, errInterrupted) {
+		t.Errorf("cause = %v, want %v", cause, errInterrupted)
+	}
+}
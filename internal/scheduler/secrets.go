@@ -0,0 +1,186 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// secretRefPattern matches {{secret "name"}} references in step config strings
+var secretRefPattern = regexp.MustCompile(`\{\{\s*secret\s+"([^"]+)"\s*\}\}`)
+
+// loadSecrets decrypts a task's secrets, but only when its pipeline actually
+// references one
+func (r *PipelineRunner) loadSecrets(ctx context.Context, task model.Task) (map[string]string, error) {
+	if r.secretRepo == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(task.Pipeline)
+	if err != nil || !secretRefPattern.Match(raw) {
+		return nil, nil
+	}
+
+	return r.secretRepo.GetDecrypted(ctx, task.ID)
+}
+
+// resolveSecrets returns a copy of config with secret references substituted,
+// leaving the task's stored pipeline untouched
+func resolveSecrets(config map[string]interface{}, secrets map[string]string) (map[string]interface{}, error) {
+	resolved, err := resolveSecretValue(config, secrets)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]interface{}), nil
+}
+
+func resolveSecretValue(value interface{}, secrets map[string]string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		var missing string
+		out := secretRefPattern.ReplaceAllStringFunc(v, func(ref string) string {
+			name := secretRefPattern.FindStringSubmatch(ref)[1]
+			secret, ok := secrets[name]
+			if !ok && missing == "" {
+				missing = name
+			}
+			return secret
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("secret %q is not set for this task", missing)
+		}
+		return out, nil
+
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			resolved, err := resolveSecretValue(item, secrets)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := resolveSecretValue(item, secrets)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+
+	default:
+		return value, nil
+	}
+}
+
+// secretMask stands in for secret values in what a run records
+const secretMask = "[secret]"
+
+// secretRedactor masks a task's resolved secret values in step errors,
+// metadata and debug snapshots, which are stored, logged and sent to error
+// notifications. Errors such as *url.Error quote the full URL, so the URL
+// escaped forms of each value are masked too. A nil redactor masks nothing.
+type secretRedactor struct {
+	replacer *strings.Replacer
+}
+
+// newSecretRedactor returns a redactor for secrets, or nil when there are none
+func newSecretRedactor(secrets map[string]string) *secretRedactor {
+	seen := make(map[string]bool)
+	var values []string
+	for _, secret := range secrets {
+		for _, form := range []string{secret, url.QueryEscape(secret), url.PathEscape(secret)} {
+			if form != "" && !seen[form] {
+				seen[form] = true
+				values = append(values, form)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	// Longest first, so a secret containing another is masked whole
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	pairs := make([]string, 0, len(values)*2)
+	for _, v := range values {
+		pairs = append(pairs, v, secretMask)
+	}
+	return &secretRedactor{replacer: strings.NewReplacer(pairs...)}
+}
+
+// String masks secret values in s
+func (r *secretRedactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
+
+// Value returns a copy of v, a decoded JSON-like value, with secret values
+// masked in every string
+func (r *secretRedactor) Value(v interface{}) interface{} {
+	if r == nil {
+		return v
+	}
+	switch val := v.(type) {
+	case string:
+		return r.String(val)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = r.Value(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = r.Value(item)
+		}
+		return out
+	case []string:
+		// Executors build metadata in Go, so it isn't always []interface{},
+		// e.g. the branch_errors of a parallel step
+		out := make([]string, len(val))
+		for i, item := range val {
+			out[i] = r.String(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// Error masks secret values in err's message, keeping err in the chain for
+// errors.Is and errors.As
+func (r *secretRedactor) Error(err error) error {
+	if r == nil || err == nil {
+		return err
+	}
+	msg := r.String(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+// redactedError is an error whose message has secrets masked
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
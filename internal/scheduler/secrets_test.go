@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/multi-worker/internal/executor/filter"
+)
+
+func TestResolveSecretsCopiesConfig(t *testing.T) {
+	config := map[string]interface{}{
+		"url": `https://api.example.com/hook?key={{ secret "api_key" }}`,
+		"headers": map[string]interface{}{
+			"Authorization": `Bearer {{secret "token"}}`,
+		},
+		"recipients": []interface{}{`{{secret "token"}}@example.com`, float64(3)},
+	}
+	secrets := map[string]string{"api_key": "k-123", "token": "t-456"}
+
+	resolved, err := resolveSecrets(config, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"url": "https://api.example.com/hook?key=k-123",
+		"headers": map[string]interface{}{
+			"Authorization": "Bearer t-456",
+		},
+		"recipients": []interface{}{"t-456@example.com", float64(3)},
+	}
+	if !reflect.DeepEqual(resolved, want) {
+		t.Errorf("resolved = %v, want %v", resolved, want)
+	}
+
+	// The stored pipeline keeps its references
+	if config["headers"].(map[string]interface{})["Authorization"] != `Bearer {{secret "token"}}` {
+		t.Errorf("config was modified: %v", config)
+	}
+}
+
+func TestResolveSecretsMissing(t *testing.T) {
+	_, err := resolveSecrets(map[string]interface{}{"url": `{{secret "nope"}}`}, map[string]string{"other": "x"})
+	if err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Errorf("err = %v, want one naming the missing secret", err)
+	}
+}
+
+func TestSecretRedactorMasksEveryForm(t *testing.T) {
+	secret := "p@ss word/1"
+	r := newSecretRedactor(map[string]string{"password": secret, "short": "ss"})
+
+	for _, s := range []string{
+		"login failed for " + secret,
+		`Post "https://example.com/?p=` + url.QueryEscape(secret) + `": connection refused`,
+		"https://example.com/" + url.PathEscape(secret),
+	} {
+		got := r.String(s)
+		if strings.Contains(got, "ss") || !strings.Contains(got, secretMask) {
+			t.Errorf("String(%q) = %q, want every secret masked", s, got)
+		}
+	}
+
+	// A secret containing another is masked whole, not around the shorter one
+	if got := r.String("x " + secret + " y"); got != "x "+secretMask+" y" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestSecretRedactorValue(t *testing.T) {
+	r := newSecretRedactor(map[string]string{"token": "t-456"})
+
+	metadata := map[string]interface{}{
+		"url":           "https://example.com/?token=t-456",
+		"status_code":   500,
+		"branch_errors": []string{"branch 1 (webhook): bad token t-456"},
+		"debug": map[string]interface{}{
+			"items": []interface{}{map[string]interface{}{"title": "t-456"}},
+		},
+	}
+	got := r.Value(metadata).(map[string]interface{})
+
+	if got["url"] != "https://example.com/?token=[secret]" || got["status_code"] != 500 {
+		t.Errorf("masked metadata = %v", got)
+	}
+	if got["branch_errors"].([]string)[0] != "branch 1 (webhook): bad token [secret]" {
+		t.Errorf("branch_errors = %v", got["branch_errors"])
+	}
+	item := got["debug"].(map[string]interface{})["items"].([]interface{})[0].(map[string]interface{})
+	if item["title"] != secretMask {
+		t.Errorf("debug item = %v", item)
+	}
+
+	// What the executor returned is left alone
+	if metadata["url"] != "https://example.com/?token=t-456" {
+		t.Errorf("input was modified: %v", metadata)
+	}
+}
+
+func TestSecretRedactorErrorKeepsChain(t *testing.T) {
+	r := newSecretRedactor(map[string]string{"token": "t-456"})
+
+	err := r.Error(fmt.Errorf("wrapped: %w", filter.NewSkipPipelineError("held t-456")))
+	if strings.Contains(err.Error(), "t-456") {
+		t.Errorf("error = %q, want the secret masked", err)
+	}
+	if !errors.As(err, new(filter.SkipPipelineError)) {
+		t.Error("masked error no longer matches SkipPipelineError")
+	}
+
+	plain := errors.New("nothing secret")
+	if r.Error(plain) != plain {
+		t.Error("an error without secrets was rewrapped")
+	}
+}
+
+func TestNilSecretRedactor(t *testing.T) {
+	r := newSecretRedactor(map[string]string{"empty": ""})
+	if r != nil {
+		t.Fatalf("redactor for empty secrets = %v, want nil", r)
+	}
+	if r.String("a") != "a" || r.Value("a") != "a" || r.Error(nil) != nil {
+		t.Error("a nil redactor changed its input")
+	}
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/multi-worker/internal/executor/filter"
+	"github.com/multi-worker/internal/model"
+)
+
+// minItems reads a delivery step's optional 'min_items', 0 when unset
+func minItems(config map[string]interface{}) (int, error) {
+	raw, ok := config["min_items"]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := raw.(float64)
+	if !ok || n < 1 || n != float64(int(n)) {
+		return 0, errors.New("'min_items' must be a positive whole number")
+	}
+	return int(n), nil
+}
+
+// checkMinItems ends the pipeline quietly when a delivery step would send
+// fewer items than its min_items. Counts are the input's item count, which
+// AI steps carry over from the items they summarised.
+func checkMinItems(step model.PipelineStep, input *model.ExecutorResult) error {
+	threshold, err := minItems(step.Config)
+	if err != nil || threshold == 0 {
+		return err
+	}
+	count := 0
+	if input != nil {
+		count = input.ItemCount
+	}
+	if count < threshold {
+		return filter.NewSkipPipelineError(fmt.Sprintf("%d items, fewer than the %d min_items of the %s step", count, threshold, step.Type))
+	}
+	return nil
+}
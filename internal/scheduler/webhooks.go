@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/multi-worker/internal/executor/discord"
+	"github.com/multi-worker/internal/netguard"
+	"github.com/multi-worker/internal/storage"
+)
+
+// webhookCheckTimeout bounds a whole background re-verification
+const webhookCheckTimeout = 2 * time.Minute
+
+// WebhookChecker re-verifies the Discord webhook tasks resolve to after
+// something they depend on changes, flagging tasks whose webhook no longer
+// works so the task list can show them before a run fails
+type WebhookChecker struct {
+	discordRepo *storage.DiscordRepository
+	taskRepo    *storage.TaskRepository
+	verify      func(ctx context.Context, webhookURL string) error
+}
+
+// NewWebhookChecker creates a webhook checker. Stored URLs may predate
+// validation, so each is checked against guard before it is requested, and
+// requested through guard's client.
+func NewWebhookChecker(discordRepo *storage.DiscordRepository, taskRepo *storage.TaskRepository, guard *netguard.Guard) *WebhookChecker {
+	client := guard.Client(10 * time.Second)
+	return &WebhookChecker{
+		discordRepo: discordRepo,
+		taskRepo:    taskRepo,
+		verify: func(ctx context.Context, webhookURL string) error {
+			if err := guard.CheckDiscordWebhook(webhookURL); err != nil {
+				return err
+			}
+			return discord.VerifyWebhook(ctx, client, webhookURL)
+		},
+	}
+}
+
+// CheckChannel re-verifies, in the background, every task using a channel
+func (c *WebhookChecker) CheckChannel(channelID string) {
+	c.background("channel "+channelID, func(ctx context.Context) ([]string, error) {
+		return c.discordRepo.ListTaskIDsByChannel(ctx, channelID)
+	})
+}
+
+// CheckAll re-verifies, in the background, every task with a Discord
+// config, e.g. after the encryption key was rotated
+func (c *WebhookChecker) CheckAll() {
+	c.background("all tasks", c.discordRepo.ListConfiguredTaskIDs)
+}
+
+// CheckTasks re-verifies the given tasks in the background
+func (c *WebhookChecker) CheckTasks(taskIDs ...string) {
+	c.background("tasks", func(ctx context.Context) ([]string, error) {
+		return taskIDs, nil
+	})
+}
+
+func (c *WebhookChecker) background(what string, list func(ctx context.Context) ([]string, error)) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookCheckTimeout)
+		defer cancel()
+
+		taskIDs, err := list(ctx)
+		if err != nil {
+			log.Printf("Warning: webhook re-verification of %s failed: %v", what, err)
+			return
+		}
+
+		flagged := 0
+		for _, taskID := range taskIDs {
+			if c.checkTask(ctx, taskID) != "" {
+				flagged++
+			}
+		}
+		if len(taskIDs) > 0 {
+			log.Printf("Webhook re-verification of %s: %d task(s) checked, %d flagged", what, len(taskIDs), flagged)
+		}
+	}()
+}
+
+// checkTask verifies the webhook a task resolves to and records the result,
+// returning the problem found, if any
+func (c *WebhookChecker) checkTask(ctx context.Context, taskID string) string {
+	var problem string
+	webhookURL, err := c.discordRepo.GetWebhookForTask(ctx, taskID)
+	switch {
+	case err != nil:
+		problem = "webhook could not be resolved: " + err.Error()
+	case webhookURL == "":
+		problem = "no Discord webhook resolves for this task"
+	default:
+		if err := c.verify(ctx, webhookURL); err != nil {
+			problem = "webhook verification failed: " + err.Error()
+		}
+	}
+
+	if err := c.taskRepo.SetWebhookError(ctx, taskID, problem); err != nil {
+		log.Printf("Warning: failed to record webhook status for task %s: %v", taskID, err)
+	}
+	return problem
+}
@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multi-worker/internal/config"
+	"github.com/multi-worker/internal/crypto"
+	"github.com/multi-worker/internal/model"
+	"github.com/multi-worker/internal/netguard"
+	"github.com/multi-worker/internal/storage"
+	"github.com/multi-worker/internal/storage/storagetest"
+)
+
+func TestCheckChannelFlagsAndClearsDependentTasks(t *testing.T) {
+	db := storagetest.Open(t)
+	ctx := context.Background()
+	cipher, err := crypto.New(config.EncryptionConfig{Key: testEncryptionKey, KeyVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	discordRepo := storage.NewDiscordRepository(db, cipher)
+	taskRepo := storage.NewTaskRepository(db)
+
+	checker := NewWebhookChecker(discordRepo, taskRepo, netguard.New(config.OutboundConfig{}))
+	checker.verify = func(ctx context.Context, webhookURL string) error {
+		if strings.Contains(webhookURL, "/broken") {
+			return errors.New("Discord returned status 404")
+		}
+		return nil
+	}
+
+	owner := storagetest.CreateUser(t, db, model.UserRoleUser)
+	bot, err := discordRepo.CreateBot(ctx, &model.CreateDiscordBotRequest{
+		Name: "Bot", ApplicationID: "app-1", Token: "token", ClientID: "client",
+	}, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel, err := discordRepo.CreateChannel(ctx, &model.CreateDiscordChannelRequest{
+		BotID: bot.ID, ChannelID: "100", Name: "alerts", WebhookURL: "https://discord.com/api/webhooks/1/working",
+	}, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two tasks post to the channel; a third has a webhook of its own
+	var users []*model.Task
+	for i := 0; i < 2; i++ {
+		task := storagetest.CreateTask(t, db, owner.ID)
+		if _, err := discordRepo.SetTaskConfig(ctx, task.ID, &model.SetTaskDiscordConfigRequest{ChannelID: &channel.ID}); err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, task)
+	}
+	bystander := storagetest.CreateTask(t, db, owner.ID)
+	if _, err := discordRepo.SetTaskConfig(ctx, bystander.ID, &model.SetTaskDiscordConfigRequest{
+		WebhookURL: "https://discord.com/api/webhooks/3/broken",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// webhookErrors reads the flags the way the task list shows them
+	webhookErrors := func() map[string]string {
+		t.Helper()
+		tasks, err := taskRepo.FindAllForUser(ctx, owner.ID, nil, 100, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		flags := make(map[string]string)
+		for _, task := range tasks {
+			flags[task.ID] = task.WebhookError
+		}
+		return flags
+	}
+	// CheckChannel works in the background
+	waitFor := func(what string, done func(flags map[string]string) bool) map[string]string {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			flags := webhookErrors()
+			if done(flags) {
+				return flags
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s: %v", what, flags)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	broken := "https://discord.com/api/webhooks/2/broken"
+	if _, err := discordRepo.UpdateChannel(ctx, channel.ID, &model.UpdateDiscordChannelRequest{WebhookURL: &broken}); err != nil {
+		t.Fatal(err)
+	}
+	checker.CheckChannel(channel.ID)
+	flags := waitFor("tasks to be flagged", func(flags map[string]string) bool {
+		return flags[users[0].ID] != "" && flags[users[1].ID] != ""
+	})
+	if !strings.Contains(flags[users[0].ID], "404") {
+		t.Errorf("flag = %q, want the verification error", flags[users[0].ID])
+	}
+	if flags[bystander.ID] != "" {
+		t.Errorf("task not using the channel was checked: %q", flags[bystander.ID])
+	}
+
+	working := "https://discord.com/api/webhooks/2/working"
+	if _, err := discordRepo.UpdateChannel(ctx, channel.ID, &model.UpdateDiscordChannelRequest{WebhookURL: &working}); err != nil {
+		t.Fatal(err)
+	}
+	checker.CheckChannel(channel.ID)
+	waitFor("flags to clear", func(flags map[string]string) bool {
+		return flags[users[0].ID] == "" && flags[users[1].ID] == ""
+	})
+}
+
+func TestWebhookCheckerGoesThroughGuard(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	// Stored URLs from before validation: internal, and not on a Discord host
+	checker := NewWebhookChecker(nil, nil, netguard.New(config.OutboundConfig{}))
+	for _, webhookURL := range []string{
+		server.URL + "/api/webhooks/1/token",
+		strings.Replace(server.URL, "https://", "http://", 1) + "/api/webhooks/1/token",
+		"https://169.254.169.254/api/webhooks/1/token",
+	} {
+		if err := checker.verify(context.Background(), webhookURL); err == nil {
+			t.Errorf("%s passed verification", webhookURL)
+		}
+	}
+	if hits.Load() != 0 {
+		t.Errorf("server got %d requests, want none", hits.Load())
+	}
+}
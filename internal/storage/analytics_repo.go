@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// maxDeliveredItems is how many delivered items are kept per task
+const maxDeliveredItems = 100
+
+// AnalyticsRepository keeps daily aggregates of delivered items
+type AnalyticsRepository struct {
+	db *Database
+}
+
+func NewAnalyticsRepository(db *Database) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// RecordItems adds a run's delivered item counts to today's aggregates
+func (r *AnalyticsRepository) RecordItems(ctx context.Context, taskID string, counts []model.ItemCount) error {
+	query := `
+		INSERT INTO item_stats (day, task_id, source, category, item_count)
+		VALUES (CURRENT_DATE, $1, $2, $3, $4)
+		ON CONFLICT (day, task_id, source, category) DO UPDATE SET
+			item_count = item_stats.item_count + EXCLUDED.item_count
+	`
+	for _, c := range counts {
+		if _, err := r.db.ExecContext(ctx, query, taskID, c.Source, c.Category, c.Count); err != nil {
+			return fmt.Errorf("failed to record item stats: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordDeliveredItems stores the items a run delivered, keeping only the
+// most recent maxDeliveredItems per task
+func (r *AnalyticsRepository) RecordDeliveredItems(ctx context.Context, taskID string, items []model.DeliveredItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO delivered_items (task_id, title, url, source, category) VALUES ($1, $2, $3, $4, $5)`
+	for _, item := range items {
+		if _, err := tx.ExecContext(ctx, query, taskID, item.Title, item.URL, item.Source, item.Category); err != nil {
+			return fmt.Errorf("failed to record delivered item: %w", err)
+		}
+	}
+
+	prune := `
+		DELETE FROM delivered_items
+		WHERE task_id = $1 AND id NOT IN (
+			SELECT id FROM delivered_items WHERE task_id = $1
+			ORDER BY delivered_at DESC LIMIT $2
+		)
+	`
+	if _, err := tx.ExecContext(ctx, prune, taskID, maxDeliveredItems); err != nil {
+		return fmt.Errorf("failed to prune delivered items: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit delivered items: %w", err)
+	}
+	return nil
+}
+
+// RecentDeliveredItems returns a task's most recently delivered items, newest first
+func (r *AnalyticsRepository) RecentDeliveredItems(ctx context.Context, taskID string, limit int) ([]model.DeliveredItem, error) {
+	items := []model.DeliveredItem{}
+	query := `
+		SELECT title, url, source, category, delivered_at
+		FROM delivered_items WHERE task_id = $1
+		ORDER BY delivered_at DESC LIMIT $2
+	`
+	if err := r.db.SelectContext(ctx, &items, query, taskID, limit); err != nil {
+		return nil, fmt.Errorf("failed to query delivered items: %w", err)
+	}
+	return items, nil
+}
+
+// ItemCounts returns per-day totals grouped by "category", "source" or "task"
+// since the given time. A non-empty ownerID limits results to that user's tasks.
+func (r *AnalyticsRepository) ItemCounts(ctx context.Context, groupBy string, since time.Time, ownerID string) ([]model.ItemStat, error) {
+	var column string
+	switch groupBy {
+	case "category":
+		column = "s.category"
+	case "source":
+		column = "s.source"
+	case "task":
+		column = "t.name"
+	default:
+		return nil, fmt.Errorf("unsupported group_by: %s", groupBy)
+	}
+
+	query := `
+		SELECT s.day, ` + column + ` AS grp, SUM(s.item_count) AS count
+		FROM item_stats s
+		JOIN tasks t ON t.id = s.task_id
+		WHERE s.day >= $1::date AND ($2 = '' OR t.created_by::text = $2)
+		GROUP BY s.day, grp
+		ORDER BY s.day, grp
+	`
+	var stats []model.ItemStat
+	if err := r.db.SelectContext(ctx, &stats, query, since, ownerID); err != nil {
+		return nil, fmt.Errorf("failed to query item stats: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// apiKeyPrefixLen is how much of a key is kept in clear to tell keys apart
+const apiKeyPrefixLen = 8
+
+// apiKeyColumns lists the columns scanned into model.APIKey
+const apiKeyColumns = `id, user_id, name, prefix, scopes, task_id, last_used_at, created_at`
+
+// APIKeyRepository handles named, scoped API keys. Like refresh tokens, only
+// a hash of each key is stored.
+type APIKeyRepository struct {
+	db *Database
+}
+
+func NewAPIKeyRepository(db *Database) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create generates a new key, returning it with its stored record
+func (r *APIKeyRepository) Create(ctx context.Context, userID string, req *model.CreateAPIKeyRequest) (string, *model.APIKey, error) {
+	key, err := generateAPIKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	var apiKey model.APIKey
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, task_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiKeyColumns
+	err = r.db.QueryRowxContext(ctx, query, userID, req.Name, key[:apiKeyPrefixLen], hashToken(key), model.ScopeList(req.Scopes), req.TaskID).
+		StructScan(&apiKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+	return key, &apiKey, nil
+}
+
+// FindByKey returns the record of a key and marks it used, or nil if it doesn't exist
+func (r *APIKeyRepository) FindByKey(ctx context.Context, key string) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	query := `
+		UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP
+		WHERE key_hash = $1
+		RETURNING ` + apiKeyColumns
+	if err := r.db.GetContext(ctx, &apiKey, query, hashToken(key)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+	return &apiKey, nil
+}
+
+// ListByUser lists a user's keys, newest first
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID string) ([]model.APIKey, error) {
+	keys := []model.APIKey{}
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &keys, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Delete revokes one of a user's keys, reporting whether it existed
+func (r *APIKeyRepository) Delete(ctx context.Context, id, userID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM api_keys WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete API key: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
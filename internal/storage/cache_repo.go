@@ -3,9 +3,14 @@ package storage
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/multi-worker/internal/model"
 )
 
 type CacheRepository struct {
@@ -33,23 +38,40 @@ func (r *CacheRepository) Exists(ctx context.Context, contentHash string) (bool,
 	return count > 0, nil
 }
 
-// ExistsForTask checks if content exists for a specific task
-func (r *CacheRepository) ExistsForTask(ctx context.Context, contentHash, taskID string) (bool, error) {
+// DedupeWindow reads a step's dedupe_window_days config for ExistsForTask;
+// 0 (the default) means items already seen are never repeated
+func DedupeWindow(config map[string]interface{}) time.Duration {
+	days, _ := config["dedupe_window_days"].(float64)
+	if days <= 0 {
+		return 0
+	}
+	return time.Duration(days * float64(24*time.Hour))
+}
+
+// ExistsForTask checks if content was seen by a specific task within the
+// given window; a zero window matches content seen at any time
+func (r *CacheRepository) ExistsForTask(ctx context.Context, contentHash, taskID string, window time.Duration) (bool, error) {
+	var since time.Time
+	if window > 0 {
+		since = time.Now().Add(-window)
+	}
+
 	var count int
-	query := `SELECT COUNT(*) FROM content_cache WHERE content_hash = $1 AND task_id = $2`
-	err := r.db.GetContext(ctx, &count, query, contentHash, taskID)
+	query := `SELECT COUNT(*) FROM content_cache WHERE content_hash = $1 AND task_id = $2 AND created_at >= $3`
+	err := r.db.GetContext(ctx, &count, query, contentHash, taskID, since)
 	if err != nil {
 		return false, fmt.Errorf("failed to check task cache: %w", err)
 	}
 	return count > 0, nil
 }
 
-// Add adds a content hash to the cache
+// Add adds a content hash to the cache, refreshing its timestamp if it was
+// already there so dedupe windows count from the latest sighting
 func (r *CacheRepository) Add(ctx context.Context, contentHash, source, taskID string) error {
 	query := `
 		INSERT INTO content_cache (content_hash, source, task_id)
 		VALUES ($1, $2, $3)
-		ON CONFLICT (content_hash, task_id) DO NOTHING
+		ON CONFLICT (content_hash, task_id) DO UPDATE SET created_at = CURRENT_TIMESTAMP
 	`
 	_, err := r.db.ExecContext(ctx, query, contentHash, source, taskID)
 	if err != nil {
@@ -58,7 +80,8 @@ func (r *CacheRepository) Add(ctx context.Context, contentHash, source, taskID s
 	return nil
 }
 
-// AddBatch adds multiple content hashes to the cache
+// AddBatch adds multiple content hashes to the cache, refreshing the
+// timestamp of any already present
 func (r *CacheRepository) AddBatch(ctx context.Context, hashes []string, source, taskID string) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -69,7 +92,7 @@ func (r *CacheRepository) AddBatch(ctx context.Context, hashes []string, source,
 	query := `
 		INSERT INTO content_cache (content_hash, source, task_id)
 		VALUES ($1, $2, $3)
-		ON CONFLICT (content_hash, task_id) DO NOTHING
+		ON CONFLICT (content_hash, task_id) DO UPDATE SET created_at = CURRENT_TIMESTAMP
 	`
 	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
@@ -94,7 +117,7 @@ func (r *CacheRepository) FilterNew(ctx context.Context, contentHashes []string,
 
 	var newHashes []string
 	for _, hash := range contentHashes {
-		exists, err := r.ExistsForTask(ctx, hash, taskID)
+		exists, err := r.ExistsForTask(ctx, hash, taskID, 0)
 		if err != nil {
 			return nil, err
 		}
@@ -106,7 +129,96 @@ func (r *CacheRepository) FilterNew(ctx context.Context, contentHashes []string,
 	return newHashes, nil
 }
 
-// CleanOld removes cache entries older than the specified duration
+// GetFeedValidators returns the ETag and Last-Modified values stored for a
+// task's feed URL, or empty strings if the feed hasn't been fetched yet
+func (r *CacheRepository) GetFeedValidators(ctx context.Context, taskID, url string) (string, string, error) {
+	var v struct {
+		ETag         string `db:"etag"`
+		LastModified string `db:"last_modified"`
+	}
+	query := `SELECT etag, last_modified FROM feed_validators WHERE task_id = $1 AND url = $2`
+	if err := r.db.GetContext(ctx, &v, query, taskID, url); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", nil
+		}
+		return "", "", fmt.Errorf("failed to get feed validators: %w", err)
+	}
+	return v.ETag, v.LastModified, nil
+}
+
+// SaveFeedValidators stores the ETag and Last-Modified values from a feed response
+func (r *CacheRepository) SaveFeedValidators(ctx context.Context, taskID, url, etag, lastModified string) error {
+	query := `
+		INSERT INTO feed_validators (task_id, url, etag, last_modified)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (task_id, url) DO UPDATE SET
+			etag = EXCLUDED.etag,
+			last_modified = EXCLUDED.last_modified,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := r.db.ExecContext(ctx, query, taskID, url, etag, lastModified); err != nil {
+		return fmt.Errorf("failed to save feed validators: %w", err)
+	}
+	return nil
+}
+
+// GetCompletion returns an unexpired AI completion and the metadata stored
+// with it, reporting false on a miss
+func (r *CacheRepository) GetCompletion(ctx context.Context, key string) (string, map[string]interface{}, bool, error) {
+	var row struct {
+		Response string `db:"response"`
+		Metadata []byte `db:"metadata"`
+	}
+	query := `SELECT response, metadata FROM ai_completion_cache WHERE cache_key = $1 AND expires_at > $2`
+	if err := r.db.GetContext(ctx, &row, query, key, time.Now()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, false, nil
+		}
+		return "", nil, false, fmt.Errorf("failed to get cached completion: %w", err)
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(row.Metadata, &metadata); err != nil {
+		return "", nil, false, fmt.Errorf("failed to decode cached completion metadata: %w", err)
+	}
+	return row.Response, metadata, true, nil
+}
+
+// SaveCompletion stores an AI completion for ttl, replacing any earlier entry
+// for the same key
+func (r *CacheRepository) SaveCompletion(ctx context.Context, key, response string, metadata map[string]interface{}, ttl time.Duration) error {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode completion metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO ai_completion_cache (cache_key, response, metadata, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cache_key) DO UPDATE SET
+			response = EXCLUDED.response,
+			metadata = EXCLUDED.metadata,
+			expires_at = EXCLUDED.expires_at,
+			created_at = CURRENT_TIMESTAMP
+	`
+	if _, err := r.db.ExecContext(ctx, query, key, response, metadataJSON, time.Now().Add(ttl)); err != nil {
+		return fmt.Errorf("failed to cache completion: %w", err)
+	}
+	return nil
+}
+
+// CleanExpiredCompletions removes AI completions past their TTL
+func (r *CacheRepository) CleanExpiredCompletions(ctx context.Context) (int64, error) {
+	query := `DELETE FROM ai_completion_cache WHERE expires_at <= $1`
+	result, err := r.db.ExecContext(ctx, query, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean expired completions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// CleanOld removes cache entries older than the specified duration. It is
+// not per task, so entries go even if a step's dedupe window is longer.
 func (r *CacheRepository) CleanOld(ctx context.Context, olderThan time.Time) (int64, error) {
 	query := `DELETE FROM content_cache WHERE created_at < $1`
 	result, err := r.db.ExecContext(ctx, query, olderThan)
@@ -116,6 +228,16 @@ func (r *CacheRepository) CleanOld(ctx context.Context, olderThan time.Time) (in
 	return result.RowsAffected()
 }
 
+// CleanSentDigestItems removes digest items flushed before olderThan
+func (r *CacheRepository) CleanSentDigestItems(ctx context.Context, olderThan time.Time) (int64, error) {
+	query := `DELETE FROM digest_items WHERE sent_at < $1`
+	result, err := r.db.ExecContext(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean sent digest items: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // CleanByTask removes all cache entries for a specific task
 func (r *CacheRepository) CleanByTask(ctx context.Context, taskID string) error {
 	query := `DELETE FROM content_cache WHERE task_id = $1`
@@ -130,3 +252,86 @@ func (r *CacheRepository) Count(ctx context.Context) (int, error) {
 	err := r.db.GetContext(ctx, &count, query)
 	return count, err
 }
+
+// HoldDigestItems stores items for a task's digest step until it flushes.
+// Items it already holds are skipped; it returns how many were added.
+func (r *CacheRepository) HoldDigestItems(ctx context.Context, taskID, stepName string, items []model.DigestItem) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO digest_items (task_id, step_name, item_key, kind, item)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (task_id, step_name, item_key) WHERE sent_at IS NULL DO NOTHING
+	`
+	added := 0
+	for _, item := range items {
+		result, err := tx.ExecContext(ctx, query, taskID, stepName, item.Key, item.Kind, []byte(item.Item))
+		if err != nil {
+			return 0, fmt.Errorf("failed to hold digest item: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		added += int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit digest items: %w", err)
+	}
+	return added, nil
+}
+
+// PendingDigest returns how many items a task's digest step holds and when
+// the oldest was stored, nil when it holds none
+func (r *CacheRepository) PendingDigest(ctx context.Context, taskID, stepName string) (int, *time.Time, error) {
+	var pending struct {
+		Count  int        `db:"count"`
+		Oldest *time.Time `db:"oldest"`
+	}
+	query := `
+		SELECT COUNT(*) AS count, MIN(created_at) AS oldest
+		FROM digest_items
+		WHERE task_id = $1 AND step_name = $2 AND sent_at IS NULL
+	`
+	if err := r.db.GetContext(ctx, &pending, query, taskID, stepName); err != nil {
+		return 0, nil, fmt.Errorf("failed to count digest items: %w", err)
+	}
+	return pending.Count, pending.Oldest, nil
+}
+
+// HeldDigest returns every item a task's digest step holds, in the order
+// they were stored. They stay held until MarkDigestSent, so items whose
+// delivery fails are flushed again.
+func (r *CacheRepository) HeldDigest(ctx context.Context, taskID, stepName string) ([]model.DigestItem, error) {
+	var items []model.DigestItem
+	query := `
+		SELECT id, item_key, kind, item, created_at
+		FROM digest_items
+		WHERE task_id = $1 AND step_name = $2 AND sent_at IS NULL
+		ORDER BY id
+	`
+	if err := r.db.SelectContext(ctx, &items, query, taskID, stepName); err != nil {
+		return nil, fmt.Errorf("failed to read digest items: %w", err)
+	}
+	return items, nil
+}
+
+// MarkDigestSent marks the items a task's digest step holds as sent, up to
+// and including throughID, leaving any held since then for the next flush
+func (r *CacheRepository) MarkDigestSent(ctx context.Context, taskID, stepName string, throughID int64) (int64, error) {
+	query := `
+		UPDATE digest_items SET sent_at = CURRENT_TIMESTAMP
+		WHERE task_id = $1 AND step_name = $2 AND sent_at IS NULL AND id <= $3
+	`
+	result, err := r.db.ExecContext(ctx, query, taskID, stepName, throughID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark digest items sent: %w", err)
+	}
+	return result.RowsAffected()
+}
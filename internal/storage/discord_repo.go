@@ -2,113 +2,40 @@ package storage
 
 import (
 	"context"
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
 	"database/sql"
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
-	"os"
+	"strings"
 	"time"
 
+	"github.com/multi-worker/internal/crypto"
 	"github.com/multi-worker/internal/model"
 )
 
 // DiscordRepository handles Discord bot and channel storage
 type DiscordRepository struct {
-	db            *Database
-	encryptionKey []byte
+	db     *Database
+	cipher *crypto.Cipher
 }
 
 // NewDiscordRepository creates a new Discord repository
-func NewDiscordRepository(db *Database) *DiscordRepository {
-	// Get encryption key from environment or generate a default
-	key := os.Getenv("ENCRYPTION_KEY")
-	if key == "" {
-		key = os.Getenv("JWT_SECRET") // Fallback to JWT secret
-	}
-	if len(key) < 32 {
-		key = key + "00000000000000000000000000000000" // Pad to 32 bytes
-	}
-
+func NewDiscordRepository(db *Database, cipher *crypto.Cipher) *DiscordRepository {
 	return &DiscordRepository{
-		db:            db,
-		encryptionKey: []byte(key[:32]),
-	}
-}
-
-// Encryption helpers
-
-func (r *DiscordRepository) encrypt(plaintext string) (string, error) {
-	if plaintext == "" {
-		return "", nil
-	}
-
-	block, err := aes.NewCipher(r.encryptionKey)
-	if err != nil {
-		return "", err
-	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", err
-	}
-
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", err
-	}
-
-	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
-}
-
-func (r *DiscordRepository) decrypt(ciphertext string) (string, error) {
-	if ciphertext == "" {
-		return "", nil
-	}
-
-	data, err := base64.StdEncoding.DecodeString(ciphertext)
-	if err != nil {
-		return "", err
-	}
-
-	block, err := aes.NewCipher(r.encryptionKey)
-	if err != nil {
-		return "", err
-	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", err
-	}
-
-	nonceSize := gcm.NonceSize()
-	if len(data) < nonceSize {
-		return "", errors.New("ciphertext too short")
-	}
-
-	nonce, cipherData := data[:nonceSize], data[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, cipherData, nil)
-	if err != nil {
-		return "", err
+		db:     db,
+		cipher: cipher,
 	}
-
-	return string(plaintext), nil
 }
 
 // Bot CRUD operations
 
 func (r *DiscordRepository) CreateBot(ctx context.Context, req *model.CreateDiscordBotRequest, userID string) (*model.DiscordBot, error) {
 	// Encrypt sensitive fields
-	encryptedToken, err := r.encrypt(req.Token)
+	encryptedToken, err := r.cipher.Encrypt(req.Token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt token: %w", err)
 	}
 
-	encryptedSecret, err := r.encrypt(req.ClientSecret)
+	encryptedSecret, err := r.cipher.Encrypt(req.ClientSecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt client secret: %w", err)
 	}
@@ -174,8 +101,8 @@ func (r *DiscordRepository) GetBotWithCredentials(ctx context.Context, id string
 	}
 
 	// Decrypt sensitive fields
-	bot.Token, _ = r.decrypt(encryptedToken)
-	bot.ClientSecret, _ = r.decrypt(encryptedSecret)
+	bot.Token, _ = r.cipher.Decrypt(encryptedToken)
+	bot.ClientSecret, _ = r.cipher.Decrypt(encryptedSecret)
 
 	return &bot, nil
 }
@@ -200,30 +127,55 @@ func (r *DiscordRepository) GetDefaultBot(ctx context.Context) (*model.DiscordBo
 		return nil, fmt.Errorf("failed to get default bot: %w", err)
 	}
 
-	bot.Token, _ = r.decrypt(encryptedToken)
-	bot.ClientSecret, _ = r.decrypt(encryptedSecret)
+	bot.Token, _ = r.cipher.Decrypt(encryptedToken)
+	bot.ClientSecret, _ = r.cipher.Decrypt(encryptedSecret)
 
 	return &bot, nil
 }
 
-func (r *DiscordRepository) ListBots(ctx context.Context, includeInactive bool) ([]model.DiscordBot, error) {
+// ListBots lists a page of bots, optionally only those whose name contains search
+func (r *DiscordRepository) ListBots(ctx context.Context, includeInactive bool, search string, limit, offset int) ([]model.DiscordBot, error) {
 	var bots []model.DiscordBot
-	query := `
+	where, args := botWhere(includeInactive, search)
+	query := fmt.Sprintf(`
 		SELECT id, name, application_id, public_key, client_id, is_active, is_default, created_by, created_at, updated_at
-		FROM discord_bots
-	`
-	if !includeInactive {
-		query += ` WHERE is_active = true`
-	}
-	query += ` ORDER BY is_default DESC, created_at DESC`
+		FROM discord_bots%s
+		ORDER BY is_default DESC, created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
 
-	err := r.db.SelectContext(ctx, &bots, query)
+	err := r.db.SelectContext(ctx, &bots, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list bots: %w", err)
 	}
 	return bots, nil
 }
 
+// CountBots counts the bots ListBots pages through
+func (r *DiscordRepository) CountBots(ctx context.Context, includeInactive bool, search string) (int, error) {
+	var count int
+	where, args := botWhere(includeInactive, search)
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM discord_bots`+where, args...)
+	return count, err
+}
+
+func botWhere(includeInactive bool, search string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if !includeInactive {
+		conditions = append(conditions, "is_active = true")
+	}
+	if search != "" {
+		args = append(args, likePattern(search))
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
 func (r *DiscordRepository) UpdateBot(ctx context.Context, id string, req *model.UpdateDiscordBotRequest) (*model.DiscordBot, error) {
 	bot, err := r.GetBot(ctx, id)
 	if err != nil || bot == nil {
@@ -249,13 +201,13 @@ func (r *DiscordRepository) UpdateBot(ctx context.Context, id string, req *model
 		argNum++
 	}
 	if req.Token != nil {
-		encryptedToken, _ := r.encrypt(*req.Token)
+		encryptedToken, _ := r.cipher.Encrypt(*req.Token)
 		updates += fmt.Sprintf(", token = $%d", argNum)
 		args = append(args, encryptedToken)
 		argNum++
 	}
 	if req.ClientSecret != nil {
-		encryptedSecret, _ := r.encrypt(*req.ClientSecret)
+		encryptedSecret, _ := r.cipher.Encrypt(*req.ClientSecret)
 		updates += fmt.Sprintf(", client_secret = $%d", argNum)
 		args = append(args, encryptedSecret)
 		argNum++
@@ -302,7 +254,7 @@ func (r *DiscordRepository) DeleteBot(ctx context.Context, id string) error {
 // Channel CRUD operations
 
 func (r *DiscordRepository) CreateChannel(ctx context.Context, req *model.CreateDiscordChannelRequest, userID string) (*model.DiscordChannel, error) {
-	encryptedWebhook, err := r.encrypt(req.WebhookURL)
+	encryptedWebhook, err := r.cipher.Encrypt(req.WebhookURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt webhook: %w", err)
 	}
@@ -359,7 +311,7 @@ func (r *DiscordRepository) GetChannelWithWebhook(ctx context.Context, id string
 	}
 
 	if encryptedWebhook.Valid {
-		channel.WebhookURL, _ = r.decrypt(encryptedWebhook.String)
+		channel.WebhookURL, _ = r.cipher.Decrypt(encryptedWebhook.String)
 	}
 
 	return &channel, nil
@@ -379,20 +331,54 @@ func (r *DiscordRepository) ListChannelsByBot(ctx context.Context, botID string)
 	return channels, nil
 }
 
-func (r *DiscordRepository) ListAllChannels(ctx context.Context) ([]model.DiscordChannel, error) {
+// ListAllChannels lists a page of active channels, optionally only a bot's
+// channels or those whose name contains search
+func (r *DiscordRepository) ListAllChannels(ctx context.Context, botID, search string, limit, offset int) ([]model.DiscordChannel, error) {
 	var channels []model.DiscordChannel
-	query := `
+	where, args := channelWhere(botID, search)
+	query := fmt.Sprintf(`
 		SELECT id, bot_id, channel_id, guild_id, name, description, webhook_id, is_active, created_by, created_at, updated_at
-		FROM discord_channels WHERE is_active = true
+		FROM discord_channels WHERE %s
 		ORDER BY name
-	`
-	err := r.db.SelectContext(ctx, &channels, query)
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	err := r.db.SelectContext(ctx, &channels, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list channels: %w", err)
 	}
 	return channels, nil
 }
 
+// CountChannels counts the channels ListAllChannels pages through
+func (r *DiscordRepository) CountChannels(ctx context.Context, botID, search string) (int, error) {
+	var count int
+	where, args := channelWhere(botID, search)
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM discord_channels WHERE `+where, args...)
+	return count, err
+}
+
+func channelWhere(botID, search string) (string, []interface{}) {
+	conditions := []string{"is_active = true"}
+	var args []interface{}
+	if botID != "" {
+		args = append(args, botID)
+		conditions = append(conditions, fmt.Sprintf("bot_id = $%d", len(args)))
+	}
+	if search != "" {
+		args = append(args, likePattern(search))
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// likePattern matches values containing s, with LIKE wildcards in s taken literally
+func likePattern(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+	return "%" + s + "%"
+}
+
 func (r *DiscordRepository) UpdateChannel(ctx context.Context, id string, req *model.UpdateDiscordChannelRequest) (*model.DiscordChannel, error) {
 	updates := "updated_at = $1"
 	args := []interface{}{time.Now()}
@@ -409,7 +395,7 @@ func (r *DiscordRepository) UpdateChannel(ctx context.Context, id string, req *m
 		argNum++
 	}
 	if req.WebhookURL != nil {
-		encryptedWebhook, _ := r.encrypt(*req.WebhookURL)
+		encryptedWebhook, _ := r.cipher.Encrypt(*req.WebhookURL)
 		updates += fmt.Sprintf(", webhook_url = $%d", argNum)
 		args = append(args, encryptedWebhook)
 		argNum++
@@ -435,6 +421,25 @@ func (r *DiscordRepository) UpdateChannel(ctx context.Context, id string, req *m
 	return &channel, nil
 }
 
+// SetChannelWebhook stores a webhook created for the channel, encrypting its URL
+func (r *DiscordRepository) SetChannelWebhook(ctx context.Context, id, webhookURL, webhookID string) (*model.DiscordChannel, error) {
+	encryptedWebhook, err := r.cipher.Encrypt(webhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt webhook: %w", err)
+	}
+
+	query := `
+		UPDATE discord_channels SET webhook_url = $1, webhook_id = $2, updated_at = $3 WHERE id = $4
+		RETURNING id, bot_id, channel_id, guild_id, name, description, webhook_id, is_active, created_by, created_at, updated_at
+	`
+	var channel model.DiscordChannel
+	err = r.db.QueryRowxContext(ctx, query, encryptedWebhook, webhookID, time.Now(), id).StructScan(&channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update channel: %w", err)
+	}
+	return &channel, nil
+}
+
 func (r *DiscordRepository) DeleteChannel(ctx context.Context, id string) error {
 	query := `DELETE FROM discord_channels WHERE id = $1`
 	result, err := r.db.ExecContext(ctx, query, id)
@@ -451,7 +456,7 @@ func (r *DiscordRepository) DeleteChannel(ctx context.Context, id string) error
 // Task Discord Config operations
 
 func (r *DiscordRepository) SetTaskConfig(ctx context.Context, taskID string, req *model.SetTaskDiscordConfigRequest) (*model.TaskDiscordConfig, error) {
-	encryptedWebhook, _ := r.encrypt(req.WebhookURL)
+	encryptedWebhook, _ := r.cipher.Encrypt(req.WebhookURL)
 
 	var config model.TaskDiscordConfig
 	query := `
@@ -499,7 +504,7 @@ func (r *DiscordRepository) GetTaskConfig(ctx context.Context, taskID string) (*
 	}
 
 	if encryptedWebhook.Valid {
-		config.WebhookURL, _ = r.decrypt(encryptedWebhook.String)
+		config.WebhookURL, _ = r.cipher.Decrypt(encryptedWebhook.String)
 	}
 
 	return &config, nil
@@ -511,28 +516,57 @@ func (r *DiscordRepository) DeleteTaskConfig(ctx context.Context, taskID string)
 	return err
 }
 
+// ListTaskIDsByChannel returns the tasks whose Discord config uses a channel
+func (r *DiscordRepository) ListTaskIDsByChannel(ctx context.Context, channelID string) ([]string, error) {
+	var taskIDs []string
+	query := `SELECT task_id FROM task_discord_configs WHERE channel_id = $1`
+	if err := r.db.SelectContext(ctx, &taskIDs, query, channelID); err != nil {
+		return nil, fmt.Errorf("failed to list tasks for channel: %w", err)
+	}
+	return taskIDs, nil
+}
+
+// ListConfiguredTaskIDs returns every task with a Discord config
+func (r *DiscordRepository) ListConfiguredTaskIDs(ctx context.Context) ([]string, error) {
+	var taskIDs []string
+	query := `SELECT task_id FROM task_discord_configs`
+	if err := r.db.SelectContext(ctx, &taskIDs, query); err != nil {
+		return nil, fmt.Errorf("failed to list configured tasks: %w", err)
+	}
+	return taskIDs, nil
+}
+
 // GetWebhookForTask resolves the webhook URL for a task, checking config hierarchy
 func (r *DiscordRepository) GetWebhookForTask(ctx context.Context, taskID string) (string, error) {
+	webhookURL, _, err := r.ResolveWebhookForTask(ctx, taskID)
+	return webhookURL, err
+}
+
+// ResolveWebhookForTask resolves the webhook URL for a task like
+// GetWebhookForTask and also reports which level of the hierarchy provided
+// it, one of the model.WebhookSource values. Both are empty when no level has
+// a webhook.
+func (r *DiscordRepository) ResolveWebhookForTask(ctx context.Context, taskID string) (string, string, error) {
 	// 1. Check task-specific config
 	config, err := r.GetTaskConfig(ctx, taskID)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	if config != nil {
 		// Direct webhook override
 		if config.WebhookURL != "" {
-			return config.WebhookURL, nil
+			return config.WebhookURL, model.WebhookSourceTask, nil
 		}
 
 		// Channel webhook
 		if config.ChannelID != nil {
 			channel, err := r.GetChannelWithWebhook(ctx, *config.ChannelID)
 			if err != nil {
-				return "", err
+				return "", "", err
 			}
 			if channel != nil && channel.WebhookURL != "" {
-				return channel.WebhookURL, nil
+				return channel.WebhookURL, model.WebhookSourceChannel, nil
 			}
 		}
 	}
@@ -540,18 +574,18 @@ func (r *DiscordRepository) GetWebhookForTask(ctx context.Context, taskID string
 	// 2. Fallback to default bot's first channel
 	defaultBot, err := r.GetDefaultBot(ctx)
 	if err != nil || defaultBot == nil {
-		return "", nil
+		return "", "", nil
 	}
 
 	channels, err := r.ListChannelsByBot(ctx, defaultBot.ID)
 	if err != nil || len(channels) == 0 {
-		return "", nil
+		return "", "", nil
 	}
 
 	channel, err := r.GetChannelWithWebhook(ctx, channels[0].ID)
-	if err != nil || channel == nil {
-		return "", nil
+	if err != nil || channel == nil || channel.WebhookURL == "" {
+		return "", "", nil
 	}
 
-	return channel.WebhookURL, nil
+	return channel.WebhookURL, model.WebhookSourceDefault, nil
 }
@@ -3,8 +3,11 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/multi-worker/internal/model"
@@ -18,14 +21,14 @@ func NewExecutionRepository(db *Database) *ExecutionRepository {
 	return &ExecutionRepository{db: db}
 }
 
-func (r *ExecutionRepository) Create(ctx context.Context, taskID, taskName, triggeredBy string) (*model.Execution, error) {
+func (r *ExecutionRepository) Create(ctx context.Context, taskID, taskName, triggeredBy string, triggerType model.TriggerType, forced bool) (*model.Execution, error) {
 	var execution model.Execution
 	query := `
-		INSERT INTO executions (task_id, task_name, status, triggered_by, step_results)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, task_id, task_name, status, started_at, finished_at, duration_ms, step_results, error, triggered_by
+		INSERT INTO executions (task_id, task_name, status, triggered_by, trigger_type, forced, step_results)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, task_id, task_name, status, started_at, finished_at, duration_ms, step_results, error, triggered_by, trigger_type, forced
 	`
-	err := r.db.QueryRowxContext(ctx, query, taskID, taskName, model.ExecutionStatusRunning, triggeredBy, model.StepResults{}).
+	err := r.db.QueryRowxContext(ctx, query, taskID, taskName, model.ExecutionStatusRunning, triggeredBy, triggerType, forced, model.StepResults{}).
 		StructScan(&execution)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create execution: %w", err)
@@ -37,7 +40,7 @@ func (r *ExecutionRepository) Create(ctx context.Context, taskID, taskName, trig
 func (r *ExecutionRepository) FindByID(ctx context.Context, id string) (*model.Execution, error) {
 	var execution model.Execution
 	query := `
-		SELECT id, task_id, task_name, status, started_at, finished_at, duration_ms, step_results, error, triggered_by
+		SELECT id, task_id, task_name, status, started_at, finished_at, duration_ms, step_results, error, triggered_by, trigger_type, forced
 		FROM executions WHERE id = $1
 	`
 	err := r.db.GetContext(ctx, &execution, query, id)
@@ -50,33 +53,98 @@ func (r *ExecutionRepository) FindByID(ctx context.Context, id string) (*model.E
 	return &execution, nil
 }
 
-func (r *ExecutionRepository) FindByTaskID(ctx context.Context, taskID string, limit, offset int) ([]model.Execution, error) {
-	var executions []model.Execution
-	query := `
-		SELECT id, task_id, task_name, status, started_at, finished_at, duration_ms, step_results, error, triggered_by
-		FROM executions WHERE task_id = $1
-		ORDER BY started_at DESC LIMIT $2 OFFSET $3
-	`
-	err := r.db.SelectContext(ctx, &executions, query, taskID, limit, offset)
+// executionColumns lists the columns scanned into model.Execution
+const executionColumns = `id, task_id, task_name, status, started_at, finished_at, duration_ms, step_results, error, triggered_by, trigger_type, forced`
+
+// executionWhere builds the WHERE clause and arguments for a filter,
+// ignoring its paging fields
+func executionWhere(f model.ExecutionFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.TaskID != "" {
+		add("task_id = $%d", f.TaskID)
+	}
+	if f.OwnerID != "" {
+		add("task_id IN (SELECT id FROM tasks WHERE created_by = $%d)", f.OwnerID)
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	if f.TriggerType != "" {
+		add("trigger_type = $%d", f.TriggerType)
+	}
+	if f.Since != nil {
+		add("started_at >= $%d", *f.Since)
+	}
+	if f.Until != nil {
+		add("started_at < $%d", *f.Until)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// FindByTaskIDFiltered lists a task's executions matching the filter,
+// newest first. A non-nil filter.Before pages by keyset on (started_at, id),
+// which stays stable while new executions arrive, and takes precedence over
+// filter.Offset.
+func (r *ExecutionRepository) FindByTaskIDFiltered(ctx context.Context, taskID string, filter model.ExecutionFilter) ([]model.Execution, error) {
+	filter.TaskID = taskID
+	executions, err := r.findFiltered(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find executions: %w", err)
 	}
 	return executions, nil
 }
 
-func (r *ExecutionRepository) FindRecent(ctx context.Context, limit int) ([]model.Execution, error) {
-	var executions []model.Execution
-	query := `
-		SELECT id, task_id, task_name, status, started_at, finished_at, duration_ms, step_results, error, triggered_by
-		FROM executions ORDER BY started_at DESC LIMIT $1
-	`
-	err := r.db.SelectContext(ctx, &executions, query, limit)
+// FindRecentFiltered lists the newest executions across tasks matching the filter
+func (r *ExecutionRepository) FindRecentFiltered(ctx context.Context, filter model.ExecutionFilter) ([]model.Execution, error) {
+	executions, err := r.findFiltered(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find recent executions: %w", err)
 	}
 	return executions, nil
 }
 
+func (r *ExecutionRepository) findFiltered(ctx context.Context, filter model.ExecutionFilter) ([]model.Execution, error) {
+	where, args := executionWhere(filter)
+	if filter.Before != nil {
+		keyset := fmt.Sprintf("(started_at, id) < ($%d, $%d::uuid)", len(args)+1, len(args)+2)
+		if where == "" {
+			where = " WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+		args = append(args, filter.Before.StartedAt, filter.Before.ID)
+		filter.Offset = 0
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := `SELECT ` + executionColumns + ` FROM executions` + where +
+		fmt.Sprintf(` ORDER BY started_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	var executions []model.Execution
+	if err := r.db.SelectContext(ctx, &executions, query, args...); err != nil {
+		return nil, err
+	}
+	return executions, nil
+}
+
+// CountFiltered counts the executions matching the filter, ignoring paging
+func (r *ExecutionRepository) CountFiltered(ctx context.Context, filter model.ExecutionFilter) (int, error) {
+	where, args := executionWhere(filter)
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM executions`+where, args...)
+	return count, err
+}
+
 func (r *ExecutionRepository) UpdateStatus(ctx context.Context, id string, status model.ExecutionStatus, errMsg *string) error {
 	now := time.Now()
 	var duration int64
@@ -100,6 +168,77 @@ func (r *ExecutionRepository) UpdateStepResults(ctx context.Context, id string,
 	return err
 }
 
+// SaveCapturedOutput stores the encoded output of a step, keyed by its
+// zero-based index in the pipeline
+func (r *ExecutionRepository) SaveCapturedOutput(ctx context.Context, id string, stepIndex int, output []byte) error {
+	query := `
+		UPDATE executions
+		SET captured_outputs = captured_outputs || jsonb_build_object($1::text, $2::jsonb)
+		WHERE id = $3
+	`
+	if _, err := r.db.ExecContext(ctx, query, strconv.Itoa(stepIndex), string(output), id); err != nil {
+		return fmt.Errorf("failed to save captured output: %w", err)
+	}
+	return nil
+}
+
+// LatestCapturedOutput returns the encoded output of the last step that
+// captured one in the task's most recent completed execution with captured
+// output, with that execution's ID. The ID is empty when no execution
+// qualifies.
+func (r *ExecutionRepository) LatestCapturedOutput(ctx context.Context, taskID string) (string, []byte, error) {
+	var row struct {
+		ID      string `db:"id"`
+		Outputs []byte `db:"captured_outputs"`
+	}
+	query := `
+		SELECT id, captured_outputs FROM executions
+		WHERE task_id = $1 AND status = $2 AND captured_outputs <> '{}'::jsonb
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+	err := r.db.GetContext(ctx, &row, query, taskID, model.ExecutionStatusCompleted)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, nil
+		}
+		return "", nil, fmt.Errorf("failed to get latest captured output: %w", err)
+	}
+
+	var outputs map[string]json.RawMessage
+	if err := json.Unmarshal(row.Outputs, &outputs); err != nil {
+		return "", nil, fmt.Errorf("failed to decode captured outputs: %w", err)
+	}
+	last := -1
+	for key := range outputs {
+		if i, err := strconv.Atoi(key); err == nil && i > last {
+			last = i
+		}
+	}
+	if last < 0 {
+		return "", nil, nil
+	}
+	return row.ID, outputs[strconv.Itoa(last)], nil
+}
+
+// GetCapturedOutput returns the encoded output captured for a step, or nil
+// if the step's output was not captured
+func (r *ExecutionRepository) GetCapturedOutput(ctx context.Context, id string, stepIndex int) ([]byte, error) {
+	var output sql.NullString
+	query := `SELECT captured_outputs -> $1 FROM executions WHERE id = $2`
+	err := r.db.GetContext(ctx, &output, query, strconv.Itoa(stepIndex), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get captured output: %w", err)
+	}
+	if !output.Valid {
+		return nil, nil
+	}
+	return []byte(output.String), nil
+}
+
 func (r *ExecutionRepository) Complete(ctx context.Context, id string, results model.StepResults) error {
 	now := time.Now()
 	exec, err := r.FindByID(ctx, id)
@@ -134,6 +273,24 @@ func (r *ExecutionRepository) Fail(ctx context.Context, id string, results model
 	return err
 }
 
+// FailUnfinished marks every execution still pending or running as failed
+// with errMsg, returning how many were. Meant for startup, when no run of
+// this process can be in progress.
+func (r *ExecutionRepository) FailUnfinished(ctx context.Context, errMsg string) (int64, error) {
+	query := `
+		UPDATE executions
+		SET status = $1, finished_at = NOW(),
+			duration_ms = (EXTRACT(EPOCH FROM NOW() - started_at) * 1000)::BIGINT,
+			error = $2
+		WHERE status IN ($3, $4)
+	`
+	result, err := r.db.ExecContext(ctx, query, model.ExecutionStatusFailed, errMsg, model.ExecutionStatusPending, model.ExecutionStatusRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail unfinished executions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 func (r *ExecutionRepository) CountByStatus(ctx context.Context, status model.ExecutionStatus) (int, error) {
 	var count int
 	query := `SELECT COUNT(*) FROM executions WHERE status = $1`
@@ -141,13 +298,6 @@ func (r *ExecutionRepository) CountByStatus(ctx context.Context, status model.Ex
 	return count, err
 }
 
-func (r *ExecutionRepository) CountByTaskID(ctx context.Context, taskID string) (int, error) {
-	var count int
-	query := `SELECT COUNT(*) FROM executions WHERE task_id = $1`
-	err := r.db.GetContext(ctx, &count, query, taskID)
-	return count, err
-}
-
 func (r *ExecutionRepository) DeleteOld(ctx context.Context, olderThan time.Time) (int64, error) {
 	query := `DELETE FROM executions WHERE started_at < $1`
 	result, err := r.db.ExecContext(ctx, query, olderThan)
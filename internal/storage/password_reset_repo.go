@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PasswordResetRepository handles the tokens mailed to users who forgot
+// their password. Like refresh tokens, only a hash of each token is stored.
+type PasswordResetRepository struct {
+	db *Database
+}
+
+func NewPasswordResetRepository(db *Database) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// Create issues a new reset token for a user
+func (r *PasswordResetRepository) Create(ctx context.Context, userID string, expiresAt time.Time) (string, error) {
+	token, err := generateAPIKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	query := `INSERT INTO password_reset_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)`
+	if _, err := r.db.ExecContext(ctx, query, hashToken(token), userID, expiresAt); err != nil {
+		return "", fmt.Errorf("failed to create reset token: %w", err)
+	}
+	return token, nil
+}
+
+// Consume marks a valid reset token used and returns the user it belongs
+// to, or "" if it doesn't exist, has expired or was already used
+func (r *PasswordResetRepository) Consume(ctx context.Context, token string) (string, error) {
+	var userID string
+	query := `
+		UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING user_id
+	`
+	if err := r.db.GetContext(ctx, &userID, query, hashToken(token)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to consume reset token: %w", err)
+	}
+	return userID, nil
+}
+
+// RevokeAllForUser invalidates a user's outstanding reset tokens, e.g. once
+// one of them has been used
+func (r *PasswordResetRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to revoke reset tokens: %w", err)
+	}
+	return nil
+}
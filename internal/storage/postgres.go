@@ -148,6 +148,151 @@ func (d *Database) RunMigrations() error {
 		// Ensure only one default bot
 		`CREATE UNIQUE INDEX IF NOT EXISTS idx_discord_bots_single_default
 		 ON discord_bots(is_default) WHERE is_default = true`,
+
+		// Per-task execution timeout (0 = scheduler default)
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS timeout_seconds INTEGER NOT NULL DEFAULT 0`,
+
+		// Let a task start a run while its previous one is still going
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS allow_overlap BOOLEAN NOT NULL DEFAULT false`,
+
+		// Run a task once on startup if the server was down over one of its fire times
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS catch_up BOOLEAN NOT NULL DEFAULT false`,
+
+		// Per-task encrypted secrets referenced from step configs
+		`CREATE TABLE IF NOT EXISTS task_secrets (
+			task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			value TEXT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (task_id, name)
+		)`,
+
+		// Daily delivered item counts for analytics
+		`CREATE TABLE IF NOT EXISTS item_stats (
+			day DATE NOT NULL,
+			task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+			source TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			item_count INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (day, task_id, source, category)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_item_stats_day ON item_stats(day)`,
+
+		// IANA timezone the task's schedule is evaluated in ('' = server local)
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT ''`,
+
+		// Full step outputs kept for capture_output steps, keyed by step index
+		`ALTER TABLE executions ADD COLUMN IF NOT EXISTS captured_outputs JSONB NOT NULL DEFAULT '{}'`,
+
+		// Normalized trigger source alongside the free-form triggered_by
+		`ALTER TABLE executions ADD COLUMN IF NOT EXISTS trigger_type VARCHAR(20) NOT NULL DEFAULT 'manual'`,
+		`UPDATE executions SET trigger_type = 'schedule' WHERE triggered_by = 'schedule' AND trigger_type <> 'schedule'`,
+		`CREATE INDEX IF NOT EXISTS idx_executions_trigger_type ON executions(trigger_type)`,
+
+		// Most recent items each task delivered, shown on share links
+		`CREATE TABLE IF NOT EXISTS delivered_items (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			url TEXT NOT NULL DEFAULT '',
+			source TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			delivered_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_delivered_items_task ON delivered_items(task_id, delivered_at DESC)`,
+
+		// Read-only public share links for tasks
+		`CREATE TABLE IF NOT EXISTS task_shares (
+			token VARCHAR(64) PRIMARY KEY,
+			task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+			created_by UUID REFERENCES users(id) ON DELETE CASCADE,
+			expires_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_shares_task ON task_shares(task_id)`,
+
+		// HTTP validators from the last feed fetch, for conditional GETs
+		`CREATE TABLE IF NOT EXISTS feed_validators (
+			task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			etag TEXT NOT NULL DEFAULT '',
+			last_modified TEXT NOT NULL DEFAULT '',
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (task_id, url)
+		)`,
+
+		// Manual runs that bypassed cached responses or dedup
+		`ALTER TABLE executions ADD COLUMN IF NOT EXISTS forced BOOLEAN NOT NULL DEFAULT FALSE`,
+
+		// Why the task's Discord webhook failed re-verification ('' = fine)
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS webhook_error TEXT NOT NULL DEFAULT ''`,
+
+		// Cron expressions the task runs on besides schedule
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS schedules JSONB NOT NULL DEFAULT '[]'`,
+
+		// Keyset pagination of a task's execution history
+		`CREATE INDEX IF NOT EXISTS idx_executions_task_started ON executions(task_id, started_at DESC, id DESC)`,
+
+		// Refresh tokens, stored as SHA-256 hashes; revoked on logout or use
+		`CREATE TABLE IF NOT EXISTS refresh_tokens (
+			token_hash VARCHAR(64) PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			revoked_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id)`,
+
+		// Named API keys limited to scopes and optionally one task
+		`CREATE TABLE IF NOT EXISTS api_keys (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			prefix VARCHAR(16) NOT NULL,
+			key_hash VARCHAR(64) UNIQUE NOT NULL,
+			scopes JSONB NOT NULL DEFAULT '[]',
+			task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+			last_used_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id)`,
+
+		// AI completions reused for identical prompts until they expire
+		`CREATE TABLE IF NOT EXISTS ai_completion_cache (
+			cache_key VARCHAR(64) PRIMARY KEY,
+			response TEXT NOT NULL,
+			metadata JSONB NOT NULL DEFAULT '{}',
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_ai_completion_cache_expires_at ON ai_completion_cache(expires_at)`,
+
+		// Password reset tokens, stored as SHA-256 hashes; single-use
+		`CREATE TABLE IF NOT EXISTS password_reset_tokens (
+			token_hash VARCHAR(64) PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			used_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id)`,
+
+		// SHA-256 hash of the secret an inbound webhook runs the task with ('' = off)
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS trigger_secret_hash VARCHAR(64) NOT NULL DEFAULT ''`,
+
+		// Items digest steps hold across runs; sent_at is set when they flush
+		`CREATE TABLE IF NOT EXISTS digest_items (
+			id BIGSERIAL PRIMARY KEY,
+			task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			step_name VARCHAR(255) NOT NULL DEFAULT '',
+			item_key VARCHAR(64) NOT NULL,
+			kind VARCHAR(20) NOT NULL,
+			item JSONB NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			sent_at TIMESTAMP WITH TIME ZONE
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_digest_items_pending ON digest_items(task_id, step_name, item_key) WHERE sent_at IS NULL`,
 	}
 
 	for _, migration := range migrations {
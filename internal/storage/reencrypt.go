@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/multi-worker/internal/crypto"
+)
+
+// encryptedColumn is a column holding values written by crypto.Cipher, with
+// the columns identifying its rows
+type encryptedColumn struct {
+	table  string
+	column string
+	keys   []string
+}
+
+// encryptedColumns lists every column encrypted at rest
+var encryptedColumns = []encryptedColumn{
+	{table: "discord_bots", column: "token", keys: []string{"id"}},
+	{table: "discord_bots", column: "client_secret", keys: []string{"id"}},
+	{table: "discord_channels", column: "webhook_url", keys: []string{"id"}},
+	{table: "task_discord_configs", column: "webhook_url", keys: []string{"id"}},
+	{table: "task_secrets", column: "value", keys: []string{"task_id", "name"}},
+}
+
+// ReEncrypt rewrites every encrypted value not already under the cipher's
+// current key version, decrypting it with whichever configured key wrote it.
+// It runs in one transaction, so a value no configured key can decrypt
+// leaves everything as it was. With dryRun the changes are rolled back. It
+// returns how many values were (or would be) rewritten per table.column.
+func ReEncrypt(ctx context.Context, db *Database, cipher *crypto.Cipher, dryRun bool) (map[string]int, error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	counts := make(map[string]int)
+	for _, col := range encryptedColumns {
+		name := col.table + "." + col.column
+
+		query := fmt.Sprintf(`SELECT %s::text, %s FROM %s WHERE %s IS NOT NULL AND %s <> '' FOR UPDATE`,
+			strings.Join(col.keys, "::text, "), col.column, col.table, col.column, col.column)
+		rows, err := tx.QueryContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		type pending struct {
+			keys  []interface{}
+			value string
+		}
+		var stale []pending
+		for rows.Next() {
+			keys := make([]string, len(col.keys))
+			dest := make([]interface{}, 0, len(keys)+1)
+			for i := range keys {
+				dest = append(dest, &keys[i])
+			}
+			var value string
+			if err := rows.Scan(append(dest, &value)...); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s: %w", name, err)
+			}
+			if crypto.KeyVersion(value) == cipher.Version() {
+				continue
+			}
+			row := pending{value: value}
+			for _, k := range keys {
+				row.keys = append(row.keys, k)
+			}
+			stale = append(stale, row)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		where := make([]string, len(col.keys))
+		for i, k := range col.keys {
+			where[i] = fmt.Sprintf("%s = $%d", k, i+2)
+		}
+		update := fmt.Sprintf(`UPDATE %s SET %s = $1 WHERE %s`, col.table, col.column, strings.Join(where, " AND "))
+
+		for _, row := range stale {
+			plaintext, err := cipher.Decrypt(row.value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt %s for %v: %w", name, row.keys, err)
+			}
+			encrypted, err := cipher.Encrypt(plaintext)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt %s for %v: %w", name, row.keys, err)
+			}
+			if _, err := tx.ExecContext(ctx, update, append([]interface{}{encrypted}, row.keys...)...); err != nil {
+				return nil, fmt.Errorf("failed to update %s for %v: %w", name, row.keys, err)
+			}
+		}
+		counts[name] = len(stale)
+	}
+
+	if dryRun {
+		return counts, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit re-encryption: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RefreshTokenRepository handles the opaque refresh tokens issued at login.
+// Only a hash of each token is stored, so a database leak doesn't hand out
+// sessions.
+type RefreshTokenRepository struct {
+	db *Database
+}
+
+func NewRefreshTokenRepository(db *Database) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create issues a new refresh token for a user
+func (r *RefreshTokenRepository) Create(ctx context.Context, userID string, expiresAt time.Time) (string, error) {
+	token, err := generateAPIKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	query := `INSERT INTO refresh_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)`
+	if _, err := r.db.ExecContext(ctx, query, hashToken(token), userID, expiresAt); err != nil {
+		return "", fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// Consume revokes a valid refresh token and returns the user it belongs to,
+// or "" if it doesn't exist, has expired or was already revoked. Tokens are
+// single-use so a stolen one stops working once its owner refreshes.
+func (r *RefreshTokenRepository) Consume(ctx context.Context, token string) (string, error) {
+	var userID string
+	query := `
+		UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING user_id
+	`
+	if err := r.db.GetContext(ctx, &userID, query, hashToken(token)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+	return userID, nil
+}
+
+// Revoke invalidates a refresh token; unknown tokens are ignored
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, token string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE token_hash = $1 AND revoked_at IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, hashToken(token)); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllForUser ends every session of a user, e.g. after a password reset
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
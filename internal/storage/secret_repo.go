@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/multi-worker/internal/crypto"
+	"github.com/multi-worker/internal/model"
+)
+
+// SecretRepository handles per-task encrypted secrets
+type SecretRepository struct {
+	db     *Database
+	cipher *crypto.Cipher
+}
+
+// NewSecretRepository creates a new secret repository
+func NewSecretRepository(db *Database, cipher *crypto.Cipher) *SecretRepository {
+	return &SecretRepository{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+// Set upserts the given secrets for a task; empty values delete the secret
+func (r *SecretRepository) Set(ctx context.Context, taskID string, secrets map[string]string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for name, value := range secrets {
+		if value == "" {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM task_secrets WHERE task_id = $1 AND name = $2`, taskID, name); err != nil {
+				return fmt.Errorf("failed to delete secret %q: %w", name, err)
+			}
+			continue
+		}
+
+		encrypted, err := r.cipher.Encrypt(value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt secret %q: %w", name, err)
+		}
+
+		query := `
+			INSERT INTO task_secrets (task_id, name, value)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (task_id, name) DO UPDATE SET
+				value = EXCLUDED.value,
+				updated_at = CURRENT_TIMESTAMP
+		`
+		if _, err := tx.ExecContext(ctx, query, taskID, name, encrypted); err != nil {
+			return fmt.Errorf("failed to store secret %q: %w", name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit secrets: %w", err)
+	}
+	return nil
+}
+
+// List returns the names of a task's secrets without their values
+func (r *SecretRepository) List(ctx context.Context, taskID string) ([]model.TaskSecret, error) {
+	var secrets []model.TaskSecret
+	query := `SELECT name, updated_at FROM task_secrets WHERE task_id = $1 ORDER BY name`
+	if err := r.db.SelectContext(ctx, &secrets, query, taskID); err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	return secrets, nil
+}
+
+// Delete removes a single secret
+func (r *SecretRepository) Delete(ctx context.Context, taskID, name string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM task_secrets WHERE task_id = $1 AND name = $2`, taskID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return nil
+}
+
+// GetDecrypted returns a task's secrets keyed by name. Only the pipeline
+// runner should call this.
+func (r *SecretRepository) GetDecrypted(ctx context.Context, taskID string) (map[string]string, error) {
+	var rows []struct {
+		Name  string `db:"name"`
+		Value string `db:"value"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, `SELECT name, value FROM task_secrets WHERE task_id = $1`, taskID); err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+
+	secrets := make(map[string]string, len(rows))
+	for _, row := range rows {
+		value, err := r.cipher.Decrypt(row.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt secret %q: %w", row.Name, err)
+		}
+		secrets[row.Name] = value
+	}
+	return secrets, nil
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/multi-worker/internal/model"
+)
+
+// ShareRepository handles read-only task share links
+type ShareRepository struct {
+	db *Database
+}
+
+func NewShareRepository(db *Database) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+// Create generates a new share token for a task. A nil expiresAt never expires.
+func (r *ShareRepository) Create(ctx context.Context, taskID, createdBy string, expiresAt *time.Time) (*model.TaskShare, error) {
+	token, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	var share model.TaskShare
+	query := `
+		INSERT INTO task_shares (token, task_id, created_by, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING token, task_id, created_by, expires_at, created_at
+	`
+	if err := r.db.QueryRowxContext(ctx, query, token, taskID, createdBy, expiresAt).StructScan(&share); err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+	return &share, nil
+}
+
+// FindValid returns the share for a token, or nil if it doesn't exist or has expired
+func (r *ShareRepository) FindValid(ctx context.Context, token string) (*model.TaskShare, error) {
+	var share model.TaskShare
+	query := `
+		SELECT token, task_id, created_by, expires_at, created_at
+		FROM task_shares
+		WHERE token = $1 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+	`
+	if err := r.db.GetContext(ctx, &share, query, token); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find share link: %w", err)
+	}
+	return &share, nil
+}
+
+// FindByTaskID lists a task's share links, newest first
+func (r *ShareRepository) FindByTaskID(ctx context.Context, taskID string) ([]model.TaskShare, error) {
+	shares := []model.TaskShare{}
+	query := `
+		SELECT token, task_id, created_by, expires_at, created_at
+		FROM task_shares WHERE task_id = $1
+		ORDER BY created_at DESC
+	`
+	if err := r.db.SelectContext(ctx, &shares, query, taskID); err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+	return shares, nil
+}
+
+// Revoke deletes a task's share link, reporting whether it existed
+func (r *ShareRepository) Revoke(ctx context.Context, taskID, token string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM task_shares WHERE task_id = $1 AND token = $2`, taskID, token)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
@@ -7,9 +7,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/multi-worker/internal/model"
 )
 
+// taskColumns lists the columns scanned into model.Task
+const taskColumns = `id, name, description, schedule, schedules, timezone, status, pipeline, timeout_seconds, allow_overlap, catch_up, last_run_at, next_run_at, webhook_error, (trigger_secret_hash <> '') AS trigger_webhook, created_by, created_at, updated_at`
+
 type TaskRepository struct {
 	db *Database
 }
@@ -23,11 +27,10 @@ func (r *TaskRepository) Create(ctx context.Context, req *model.CreateTaskReques
 
 	var task model.Task
 	query := `
-		INSERT INTO tasks (name, description, schedule, pipeline, created_by, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, name, description, schedule, status, pipeline, last_run_at, next_run_at, created_by, created_at, updated_at
-	`
-	err := r.db.QueryRowxContext(ctx, query, req.Name, req.Description, req.Schedule, pipeline, userID, model.TaskStatusEnabled).
+		INSERT INTO tasks (name, description, schedule, schedules, timezone, pipeline, timeout_seconds, allow_overlap, catch_up, created_by, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING ` + taskColumns
+	err := r.db.QueryRowxContext(ctx, query, req.Name, req.Description, req.Schedule, model.ScheduleList(req.Schedules), req.Timezone, pipeline, req.TimeoutSeconds, req.AllowOverlap, req.CatchUp, userID, model.TaskStatusEnabled).
 		StructScan(&task)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
@@ -38,10 +41,7 @@ func (r *TaskRepository) Create(ctx context.Context, req *model.CreateTaskReques
 
 func (r *TaskRepository) FindByID(ctx context.Context, id string) (*model.Task, error) {
 	var task model.Task
-	query := `
-		SELECT id, name, description, schedule, status, pipeline, last_run_at, next_run_at, created_by, created_at, updated_at
-		FROM tasks WHERE id = $1
-	`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1`
 	err := r.db.GetContext(ctx, &task, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -52,24 +52,35 @@ func (r *TaskRepository) FindByID(ctx context.Context, id string) (*model.Task,
 	return &task, nil
 }
 
+// FindByIDForUser finds a task only if userID created it
+func (r *TaskRepository) FindByIDForUser(ctx context.Context, id, userID string) (*model.Task, error) {
+	var task model.Task
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND created_by = $2`
+	err := r.db.GetContext(ctx, &task, query, id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
+	return &task, nil
+}
+
+// FindAll lists tasks with the given status, or all but archived tasks
 func (r *TaskRepository) FindAll(ctx context.Context, status *model.TaskStatus, limit, offset int) ([]model.Task, error) {
-	var tasks []model.Task
-	var query string
-	var args []interface{}
+	return r.findAll(ctx, "", status, limit, offset)
+}
 
-	if status != nil {
-		query = `
-			SELECT id, name, description, schedule, status, pipeline, last_run_at, next_run_at, created_by, created_at, updated_at
-			FROM tasks WHERE status = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
-		`
-		args = []interface{}{*status, limit, offset}
-	} else {
-		query = `
-			SELECT id, name, description, schedule, status, pipeline, last_run_at, next_run_at, created_by, created_at, updated_at
-			FROM tasks ORDER BY created_at DESC LIMIT $1 OFFSET $2
-		`
-		args = []interface{}{limit, offset}
-	}
+// FindAllForUser lists the tasks userID created, filtered like FindAll
+func (r *TaskRepository) FindAllForUser(ctx context.Context, userID string, status *model.TaskStatus, limit, offset int) ([]model.Task, error) {
+	return r.findAll(ctx, userID, status, limit, offset)
+}
+
+func (r *TaskRepository) findAll(ctx context.Context, userID string, status *model.TaskStatus, limit, offset int) ([]model.Task, error) {
+	var tasks []model.Task
+	where, args := taskWhere(userID, status)
+	query := fmt.Sprintf(`SELECT `+taskColumns+` FROM tasks WHERE %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
 
 	err := r.db.SelectContext(ctx, &tasks, query, args...)
 	if err != nil {
@@ -79,12 +90,39 @@ func (r *TaskRepository) FindAll(ctx context.Context, status *model.TaskStatus,
 	return tasks, nil
 }
 
+// FilterOwned returns the IDs among ids of tasks userID created
+func (r *TaskRepository) FilterOwned(ctx context.Context, ids []string, userID string) ([]string, error) {
+	var owned []string
+	query := `SELECT id FROM tasks WHERE id = ANY($1) AND created_by = $2`
+	if err := r.db.SelectContext(ctx, &owned, query, pq.Array(ids), userID); err != nil {
+		return nil, fmt.Errorf("failed to filter tasks: %w", err)
+	}
+	return owned, nil
+}
+
+// taskWhere builds the conditions shared by the task listings and counts.
+// An empty userID matches every owner.
+func taskWhere(userID string, status *model.TaskStatus) (string, []interface{}) {
+	var where string
+	var args []interface{}
+
+	if status != nil {
+		where = `status = $1`
+		args = []interface{}{*status}
+	} else {
+		where = `status <> $1`
+		args = []interface{}{model.TaskStatusArchived}
+	}
+	if userID != "" {
+		args = append(args, userID)
+		where += fmt.Sprintf(` AND created_by = $%d`, len(args))
+	}
+	return where, args
+}
+
 func (r *TaskRepository) FindEnabled(ctx context.Context) ([]model.Task, error) {
 	var tasks []model.Task
-	query := `
-		SELECT id, name, description, schedule, status, pipeline, last_run_at, next_run_at, created_by, created_at, updated_at
-		FROM tasks WHERE status = $1
-	`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE status = $1`
 	err := r.db.SelectContext(ctx, &tasks, query, model.TaskStatusEnabled)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find enabled tasks: %w", err)
@@ -110,19 +148,33 @@ func (r *TaskRepository) Update(ctx context.Context, id string, req *model.Updat
 	if req.Schedule != nil {
 		task.Schedule = *req.Schedule
 	}
+	if req.Schedules != nil {
+		task.Schedules = req.Schedules
+	}
+	if req.Timezone != nil {
+		task.Timezone = *req.Timezone
+	}
 	if req.Status != nil {
 		task.Status = *req.Status
 	}
 	if req.Pipeline != nil {
 		task.Pipeline = req.Pipeline
 	}
+	if req.TimeoutSeconds != nil {
+		task.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	if req.AllowOverlap != nil {
+		task.AllowOverlap = *req.AllowOverlap
+	}
+	if req.CatchUp != nil {
+		task.CatchUp = *req.CatchUp
+	}
 
 	query := `
-		UPDATE tasks SET name = $1, description = $2, schedule = $3, status = $4, pipeline = $5, updated_at = $6
-		WHERE id = $7
-		RETURNING id, name, description, schedule, status, pipeline, last_run_at, next_run_at, created_by, created_at, updated_at
-	`
-	err = r.db.QueryRowxContext(ctx, query, task.Name, task.Description, task.Schedule, task.Status, model.PipelineSteps(task.Pipeline), time.Now(), id).
+		UPDATE tasks SET name = $1, description = $2, schedule = $3, schedules = $4, timezone = $5, status = $6, pipeline = $7, timeout_seconds = $8, allow_overlap = $9, catch_up = $10, updated_at = $11
+		WHERE id = $12
+		RETURNING ` + taskColumns
+	err = r.db.QueryRowxContext(ctx, query, task.Name, task.Description, task.Schedule, task.Schedules, task.Timezone, task.Status, model.PipelineSteps(task.Pipeline), task.TimeoutSeconds, task.AllowOverlap, task.CatchUp, time.Now(), id).
 		StructScan(task)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
@@ -131,6 +183,76 @@ func (r *TaskRepository) Update(ctx context.Context, id string, req *model.Updat
 	return task, nil
 }
 
+// Clone copies a task into a new disabled task owned by userID, named with a
+// " (copy)" suffix, together with its Discord config. It returns nil if the
+// task doesn't exist. Run history and secrets aren't copied.
+func (r *TaskRepository) Clone(ctx context.Context, id, userID string) (*model.Task, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Names are limited to 100 characters, so long ones are shortened to
+	// make room for the suffix
+	var task model.Task
+	query := `
+		INSERT INTO tasks (name, description, schedule, schedules, timezone, pipeline, timeout_seconds, allow_overlap, catch_up, created_by, status)
+		SELECT LEFT(name, 93) || ' (copy)', description, schedule, schedules, timezone, pipeline, timeout_seconds, allow_overlap, catch_up, $2, $3
+		FROM tasks WHERE id = $1
+		RETURNING ` + taskColumns
+	err = tx.QueryRowxContext(ctx, query, id, userID, model.TaskStatusDisabled).StructScan(&task)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to clone task: %w", err)
+	}
+
+	// The webhook override stays encrypted as stored
+	query = `
+		INSERT INTO task_discord_configs (task_id, bot_id, channel_id, webhook_url, message_template, embed_config, username, avatar_url)
+		SELECT $2, bot_id, channel_id, webhook_url, message_template, embed_config, username, avatar_url
+		FROM task_discord_configs WHERE task_id = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, id, task.ID); err != nil {
+		return nil, fmt.Errorf("failed to clone task Discord config: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit clone: %w", err)
+	}
+	return &task, nil
+}
+
+// Archive marks a task archived, keeping it and its executions; it returns
+// false if the task doesn't exist
+func (r *TaskRepository) Archive(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE tasks SET status = $1, updated_at = $2 WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, model.TaskStatusArchived, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to archive task: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// Restore brings an archived task back as disabled. It returns nil if the
+// task doesn't exist or isn't archived.
+func (r *TaskRepository) Restore(ctx context.Context, id string) (*model.Task, error) {
+	var task model.Task
+	query := `UPDATE tasks SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4 RETURNING ` + taskColumns
+	err := r.db.GetContext(ctx, &task, query, model.TaskStatusDisabled, time.Now(), id, model.TaskStatusArchived)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to restore task: %w", err)
+	}
+	return &task, nil
+}
+
+// Delete permanently removes a task, cascading to its executions
 func (r *TaskRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM tasks WHERE id = $1`
 	result, err := r.db.ExecContext(ctx, query, id)
@@ -146,6 +268,85 @@ func (r *TaskRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// RotateTriggerSecret generates a new inbound webhook secret for a task,
+// replacing any earlier one. Only its hash is stored, so the secret is
+// returned this once; "" means the task doesn't exist.
+func (r *TaskRepository) RotateTriggerSecret(ctx context.Context, id string) (string, error) {
+	secret, err := generateAPIKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	query := `UPDATE tasks SET trigger_secret_hash = $1, updated_at = $2 WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, hashToken(secret), time.Now(), id)
+	if err != nil {
+		return "", fmt.Errorf("failed to set webhook secret: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return "", nil
+	}
+	return secret, nil
+}
+
+// ClearTriggerSecret turns a task's inbound webhook off, reporting whether
+// it was on
+func (r *TaskRepository) ClearTriggerSecret(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE tasks SET trigger_secret_hash = '', updated_at = $1 WHERE id = $2 AND trigger_secret_hash <> ''`
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to clear webhook secret: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// FindByTriggerSecret returns a task whose inbound webhook secret is secret,
+// or nil if the task doesn't exist or has another or no secret
+func (r *TaskRepository) FindByTriggerSecret(ctx context.Context, id, secret string) (*model.Task, error) {
+	var task model.Task
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND trigger_secret_hash <> '' AND trigger_secret_hash = $2`
+	if err := r.db.GetContext(ctx, &task, query, id, hashToken(secret)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
+	return &task, nil
+}
+
+// SetStatusMany sets the status of several tasks in one statement and
+// returns the tasks that exist, updated. Archived tasks are left alone.
+func (r *TaskRepository) SetStatusMany(ctx context.Context, ids []string, status model.TaskStatus) ([]model.Task, error) {
+	var tasks []model.Task
+	query := `UPDATE tasks SET status = $1, updated_at = $2 WHERE id = ANY($3) AND status <> $4 RETURNING ` + taskColumns
+	if err := r.db.SelectContext(ctx, &tasks, query, status, time.Now(), pq.Array(ids), model.TaskStatusArchived); err != nil {
+		return nil, fmt.Errorf("failed to update tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// ArchiveMany archives several tasks in one statement and returns the IDs of
+// those that exist
+func (r *TaskRepository) ArchiveMany(ctx context.Context, ids []string) ([]string, error) {
+	var archived []string
+	query := `UPDATE tasks SET status = $1, updated_at = $2 WHERE id = ANY($3) RETURNING id`
+	if err := r.db.SelectContext(ctx, &archived, query, model.TaskStatusArchived, time.Now(), pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to archive tasks: %w", err)
+	}
+	return archived, nil
+}
+
+// DeleteMany deletes several tasks in one statement and returns the IDs of
+// those that existed
+func (r *TaskRepository) DeleteMany(ctx context.Context, ids []string) ([]string, error) {
+	var deleted []string
+	query := `DELETE FROM tasks WHERE id = ANY($1) RETURNING id`
+	if err := r.db.SelectContext(ctx, &deleted, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to delete tasks: %w", err)
+	}
+	return deleted, nil
+}
+
 func (r *TaskRepository) UpdateLastRun(ctx context.Context, id string, lastRun, nextRun time.Time) error {
 	query := `UPDATE tasks SET last_run_at = $1, next_run_at = $2, updated_at = $3 WHERE id = $4`
 	_, err := r.db.ExecContext(ctx, query, lastRun, nextRun, time.Now(), id)
@@ -170,18 +371,55 @@ func (r *TaskRepository) UpdateStatus(ctx context.Context, id string, status mod
 	return err
 }
 
-func (r *TaskRepository) Count(ctx context.Context, status *model.TaskStatus) (int, error) {
-	var count int
-	var query string
-	var args []interface{}
+// SetWebhookError flags a task whose Discord webhook failed verification;
+// an empty message clears the flag
+func (r *TaskRepository) SetWebhookError(ctx context.Context, id, message string) error {
+	query := `UPDATE tasks SET webhook_error = $1 WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, message, id)
+	return err
+}
 
-	if status != nil {
-		query = `SELECT COUNT(*) FROM tasks WHERE status = $1`
-		args = []interface{}{*status}
-	} else {
-		query = `SELECT COUNT(*) FROM tasks`
+// SwapStatus changes a task's status only if it currently is from, so a
+// task paused mid-run isn't re-enabled when the run finishes
+func (r *TaskRepository) SwapStatus(ctx context.Context, id string, from, to model.TaskStatus) error {
+	query := `UPDATE tasks SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4`
+	_, err := r.db.ExecContext(ctx, query, to, time.Now(), id, from)
+	return err
+}
+
+// ResetOrphanedRunning returns tasks left running with no unfinished
+// execution to enabled, so the scheduler fires them again, returning how
+// many were
+func (r *TaskRepository) ResetOrphanedRunning(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE tasks SET status = $1, updated_at = $2
+		WHERE status = $3
+		AND NOT EXISTS (
+			SELECT 1 FROM executions e
+			WHERE e.task_id = tasks.id AND e.status IN ($4, $5)
+		)
+	`
+	result, err := r.db.ExecContext(ctx, query, model.TaskStatusEnabled, time.Now(), model.TaskStatusRunning,
+		model.ExecutionStatusPending, model.ExecutionStatusRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset orphaned running tasks: %w", err)
 	}
+	return result.RowsAffected()
+}
+
+// Count counts tasks with the given status, or all but archived tasks
+func (r *TaskRepository) Count(ctx context.Context, status *model.TaskStatus) (int, error) {
+	return r.count(ctx, "", status)
+}
+
+// CountForUser counts the tasks userID created, filtered like Count
+func (r *TaskRepository) CountForUser(ctx context.Context, userID string, status *model.TaskStatus) (int, error) {
+	return r.count(ctx, userID, status)
+}
 
-	err := r.db.GetContext(ctx, &count, query, args...)
+func (r *TaskRepository) count(ctx context.Context, userID string, status *model.TaskStatus) (int, error) {
+	var count int
+	where, args := taskWhere(userID, status)
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM tasks WHERE `+where, args...)
 	return count, err
 }
@@ -91,6 +91,55 @@ func (r *UserRepository) ValidatePassword(user *model.User, password string) boo
 	return err == nil
 }
 
+// UpdatePassword replaces a user's password with the bcrypt hash of password
+// SetActive enables or disables a user. Disabling also revokes the user's
+// refresh tokens in the same transaction, so no session outlives it. It
+// returns nil for an unknown user.
+func (r *UserRepository) SetActive(ctx context.Context, userID string, active bool) (*model.User, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var user model.User
+	query := `
+		UPDATE users SET is_active = $1, updated_at = $2 WHERE id = $3
+		RETURNING id, email, name, role, api_key, is_active, created_at, updated_at
+	`
+	if err := tx.QueryRowxContext(ctx, query, active, time.Now(), userID).StructScan(&user); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if !active {
+		revoke := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`
+		if _, err := tx.ExecContext(ctx, revoke, userID); err != nil {
+			return nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit user update: %w", err)
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	query := `UPDATE users SET password = $1, updated_at = $2 WHERE id = $3`
+	if _, err := r.db.ExecContext(ctx, query, string(hashedPassword), time.Now(), userID); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
 func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID string) error {
 	query := `UPDATE users SET updated_at = $1 WHERE id = $2`
 	_, err := r.db.ExecContext(ctx, query, time.Now(), userID)